@@ -0,0 +1,257 @@
+// Package comprx 提供 BGZF (分块 GZIP) 的读写和随机访问功能。
+//
+// BGZF 是 htslib (samtools、tabix、bgzip) 使用的 GZIP 变体，由多个不超过 64 KiB 的
+// GZIP 成员组成，任何 GZIP 工具都可以解压。该文件提供写入时记录虚拟文件偏移的写入器，
+// 按虚拟文件偏移或原始数据偏移定位的读取器，以及 .gzi 索引的构建和读写。
+//
+// 主要类型：
+//   - BgzfWriter: BGZF 写入器
+//   - BgzfReader: BGZF 读取器
+//
+// 主要功能：
+//   - 分块写入 BGZF 数据并获取虚拟文件偏移
+//   - 按虚拟文件偏移定位读取
+//   - 按原始数据偏移定位读取(需要 .gzi 索引)
+//   - 构建、读取和写入 .gzi 索引
+//
+// 使用示例：
+//
+//	// 写入并记录每条记录的虚拟文件偏移
+//	w, err := comprx.NewBgzfWriter(file, types.CompressionLevelDefault)
+//	offset := w.VirtualOffset()
+//	_, err = w.Write(record)
+//	err = w.Close()
+//
+//	// 定位到记录
+//	r := comprx.NewBgzfReader(file)
+//	err = r.Seek(offset)
+package comprx
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"gitee.com/MM-Q/comprx/internal/cxbgzf"
+	"gitee.com/MM-Q/comprx/internal/utils"
+	"gitee.com/MM-Q/comprx/types"
+)
+
+// BgzfWriter BGZF 写入器
+//
+// 写入的数据按 65280 字节分块压缩，Close 时写入 EOF 标记，不关闭底层的输出目标。
+type BgzfWriter struct {
+	writer *cxbgzf.Writer // 写入器实现
+}
+
+// NewBgzfWriter 创建BGZF写入器
+//
+// 参数:
+//   - w: 输出目标
+//   - level: 压缩等级
+//
+// 返回:
+//   - *BgzfWriter: BGZF 写入器
+//   - error: 错误信息
+//
+// 使用示例:
+//
+//	w, err := NewBgzfWriter(file, types.CompressionLevelDefault)
+//	if err != nil {
+//	    return err
+//	}
+//	defer w.Close()
+func NewBgzfWriter(w io.Writer, level types.CompressionLevel) (*BgzfWriter, error) {
+	writer, err := cxbgzf.NewWriter(w, level)
+	if err != nil {
+		return nil, err
+	}
+	return &BgzfWriter{writer: writer}, nil
+}
+
+// Write 写入数据
+//
+// 参数:
+//   - p: 要写入的数据
+//
+// 返回:
+//   - int: 写入的字节数
+//   - error: 错误信息
+func (w *BgzfWriter) Write(p []byte) (int, error) {
+	return w.writer.Write(p)
+}
+
+// Flush 结束当前数据块，之后写入的数据从新的数据块开始
+//
+// 在记录边界调用可以保证一条记录不跨越数据块。
+//
+// 返回:
+//   - error: 错误信息
+func (w *BgzfWriter) Flush() error {
+	return w.writer.Flush()
+}
+
+// VirtualOffset 返回下一个写入字节的虚拟文件偏移
+//
+// 返回:
+//   - types.BgzfOffset: 虚拟文件偏移，可用于 BgzfReader.Seek
+func (w *BgzfWriter) VirtualOffset() types.BgzfOffset {
+	return w.writer.VirtualOffset()
+}
+
+// Index 返回已写出数据块的索引
+//
+// 返回:
+//   - types.BgzfIndex: 与 bgzip -i 生成的 .gzi 一致的索引
+func (w *BgzfWriter) Index() types.BgzfIndex {
+	return w.writer.Index()
+}
+
+// Close 写出剩余数据和 EOF 标记
+//
+// 返回:
+//   - error: 错误信息
+func (w *BgzfWriter) Close() error {
+	return w.writer.Close()
+}
+
+// BgzfReader BGZF 读取器
+//
+// 顺序读取时输出解压后的数据；底层数据流实现 io.Seeker 时支持定位。
+type BgzfReader struct {
+	reader *cxbgzf.Reader // 读取器实现
+}
+
+// NewBgzfReader 创建BGZF读取器
+//
+// 参数:
+//   - r: 位于文件起始处的 BGZF 数据流
+//
+// 返回:
+//   - *BgzfReader: BGZF 读取器
+//
+// 使用示例:
+//
+//	r := NewBgzfReader(file)
+//	err := r.Seek(offset)
+//	line, err := bufio.NewReader(r).ReadString('\n')
+func NewBgzfReader(r io.Reader) *BgzfReader {
+	return &BgzfReader{reader: cxbgzf.NewReader(r)}
+}
+
+// Read 读取解压后的数据
+//
+// 参数:
+//   - p: 读取缓冲区
+//
+// 返回:
+//   - int: 读取的字节数
+//   - error: 错误信息
+func (r *BgzfReader) Read(p []byte) (int, error) {
+	return r.reader.Read(p)
+}
+
+// Tell 返回下一个读取字节的虚拟文件偏移
+//
+// 返回:
+//   - types.BgzfOffset: 虚拟文件偏移
+func (r *BgzfReader) Tell() types.BgzfOffset {
+	return r.reader.Tell()
+}
+
+// Seek 定位到虚拟文件偏移
+//
+// 参数:
+//   - offset: 虚拟文件偏移，来自 BgzfWriter.VirtualOffset、Tell 或 htslib 的索引
+//
+// 返回:
+//   - error: 底层数据流不支持定位或偏移无效时返回错误
+func (r *BgzfReader) Seek(offset types.BgzfOffset) error {
+	return r.reader.Seek(offset)
+}
+
+// SetIndex 设置按原始数据偏移定位使用的索引
+//
+// 参数:
+//   - index: .gzi 索引
+func (r *BgzfReader) SetIndex(index types.BgzfIndex) {
+	r.reader.SetIndex(index)
+}
+
+// SeekUncompressed 定位到解压后数据中的偏移
+//
+// 参数:
+//   - offset: 解压后数据中的偏移
+//
+// 返回:
+//   - error: 未设置索引或偏移超出数据范围时返回错误
+//
+// 使用示例:
+//
+//	r.SetIndex(index)
+//	err := r.SeekUncompressed(1 << 30)
+func (r *BgzfReader) SeekUncompressed(offset uint64) error {
+	return r.reader.SeekUncompressed(offset)
+}
+
+// BuildBgzfIndex 扫描BGZF文件并构建索引
+//
+// 只读取每个数据块的头尾，不解压数据。
+//
+// 参数:
+//   - archivePath: BGZF 文件路径
+//
+// 返回:
+//   - types.BgzfIndex: 与 bgzip -r 生成的 .gzi 一致的索引
+//   - error: 错误信息
+//
+// 使用示例:
+//
+//	index, err := BuildBgzfIndex("variants.vcf.gz")
+func BuildBgzfIndex(archivePath string) (types.BgzfIndex, error) {
+	absPath, err := utils.EnsureAbsPath(archivePath, "BGZF文件路径")
+	if err != nil {
+		return nil, err
+	}
+	file, err := os.Open(absPath)
+	if err != nil {
+		return nil, fmt.Errorf("打开BGZF文件失败: %w", err)
+	}
+	defer func() { _ = file.Close() }()
+
+	return cxbgzf.BuildIndex(file)
+}
+
+// ReadBgzfIndex 读取 .gzi 索引
+//
+// 参数:
+//   - r: .gzi 数据流
+//
+// 返回:
+//   - types.BgzfIndex: 索引
+//   - error: 错误信息
+//
+// 使用示例:
+//
+//	gzi, _ := os.Open("variants.vcf.gz.gzi")
+//	index, err := ReadBgzfIndex(gzi)
+func ReadBgzfIndex(r io.Reader) (types.BgzfIndex, error) {
+	return cxbgzf.ReadIndex(r)
+}
+
+// WriteBgzfIndex 写入 .gzi 索引
+//
+// 参数:
+//   - w: 输出目标
+//   - index: 索引
+//
+// 返回:
+//   - error: 错误信息
+//
+// 使用示例:
+//
+//	gzi, _ := os.Create("variants.vcf.gz.gzi")
+//	err := WriteBgzfIndex(gzi, index)
+func WriteBgzfIndex(w io.Writer, index types.BgzfIndex) error {
+	return cxbgzf.WriteIndex(w, index)
+}
@@ -0,0 +1,147 @@
+package comprx
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"gitee.com/MM-Q/comprx/types"
+)
+
+// TestPackBgzf 测试以 BGZF 模式打包并按索引随机读取
+func TestPackBgzf(t *testing.T) {
+	tempDir := t.TempDir()
+	src := filepath.Join(tempDir, "reads.sam")
+	var content bytes.Buffer
+	for i := 0; i < 30000; i++ {
+		fmt.Fprintf(&content, "read%06d\t0\tchr1\t%d\t60\t100M\n", i, i*7)
+	}
+	if err := os.WriteFile(src, content.Bytes(), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	dst := filepath.Join(tempDir, "reads.sam.gz")
+	if err := PackOptions(dst, src, DefaultOptions().WithBgzfIndex(true)); err != nil {
+		t.Fatalf("打包失败: %v", err)
+	}
+
+	// 普通 GZIP 解压结果一致
+	compressed, err := os.ReadFile(dst)
+	if err != nil {
+		t.Fatal(err)
+	}
+	plain, err := UngzipBytes(compressed)
+	if err != nil || !bytes.Equal(plain, content.Bytes()) {
+		t.Fatalf("GZIP 解压结果不一致: %v", err)
+	}
+
+	// 读取打包时生成的 .gzi，与扫描构建的索引一致
+	gzi, err := os.Open(dst + ".gzi")
+	if err != nil {
+		t.Fatalf("打开索引文件失败: %v", err)
+	}
+	defer func() { _ = gzi.Close() }()
+	index, err := ReadBgzfIndex(gzi)
+	if err != nil {
+		t.Fatalf("读取索引失败: %v", err)
+	}
+	built, err := BuildBgzfIndex(dst)
+	if err != nil {
+		t.Fatalf("构建索引失败: %v", err)
+	}
+	if len(index) != len(built) {
+		t.Fatalf("索引条目数不一致: %d != %d", len(index), len(built))
+	}
+
+	// 按原始偏移定位读取一行
+	file, err := os.Open(dst)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = file.Close() }()
+	reader := NewBgzfReader(file)
+	reader.SetIndex(index)
+	offset := bytes.Index(content.Bytes(), []byte("read024999\t"))
+	if err := reader.SeekUncompressed(uint64(offset)); err != nil {
+		t.Fatalf("定位失败: %v", err)
+	}
+	line, err := bufio.NewReader(reader).ReadString('\n')
+	if err != nil || line != "read024999\t0\tchr1\t174993\t60\t100M\n" {
+		t.Errorf("读取的行不正确: %q, %v", line, err)
+	}
+}
+
+// TestBgzfOptionsValidation 测试 BGZF 选项的冲突检查
+func TestBgzfOptionsValidation(t *testing.T) {
+	tempDir := t.TempDir()
+	src := filepath.Join(tempDir, "a.txt")
+	if err := os.WriteFile(src, []byte("a"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	opts := DefaultOptions()
+	opts.BgzfIndex = true
+	if err := PackOptions(filepath.Join(tempDir, "1.gz"), src, opts); err == nil {
+		t.Error("未启用 BGZF 时生成索引应返回错误")
+	}
+	opts = DefaultOptions().WithBgzf(true).WithGzipHeader(types.GzipHeader{Name: "a.txt"})
+	if err := PackOptions(filepath.Join(tempDir, "2.gz"), src, opts); err == nil {
+		t.Error("BGZF 与自定义文件头同时使用应返回错误")
+	}
+}
+
+// TestBgzfMemory 测试 BGZF 内存压缩和写入器的虚拟偏移
+func TestBgzfMemory(t *testing.T) {
+	data := bytes.Repeat([]byte("ACGTACGTTTGACA"), 20000)
+	compressed, err := BgzfBytesWithLevel(data, types.CompressionLevelFast)
+	if err != nil {
+		t.Fatalf("压缩失败: %v", err)
+	}
+	got, err := UnbgzfBytes(compressed)
+	if err != nil || !bytes.Equal(got, data) {
+		t.Fatalf("解压结果不一致: %v", err)
+	}
+	if _, err := UnbgzfBytes(mustGzip(t, data)); err == nil {
+		t.Error("普通 GZIP 数据应返回错误")
+	}
+
+	var buf bytes.Buffer
+	writer, err := NewBgzfWriter(&buf, types.CompressionLevelDefault)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _ = writer.Write(data[:1000])
+	if err := writer.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	offset := writer.VirtualOffset()
+	if offset.Uncompressed() != 0 || offset.Compressed() == 0 {
+		t.Errorf("Flush 后的虚拟偏移应指向新数据块起始: %d/%d", offset.Compressed(), offset.Uncompressed())
+	}
+	_, _ = writer.Write([]byte("marker"))
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	reader := NewBgzfReader(bytes.NewReader(buf.Bytes()))
+	if err := reader.Seek(offset); err != nil {
+		t.Fatalf("定位失败: %v", err)
+	}
+	marker := make([]byte, 6)
+	if _, err := reader.Read(marker); err != nil || string(marker) != "marker" {
+		t.Errorf("读取结果不正确: %q, %v", marker, err)
+	}
+}
+
+// mustGzip 使用普通 GZIP 压缩数据
+func mustGzip(t *testing.T, data []byte) []byte {
+	t.Helper()
+	compressed, err := GzipBytes(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return compressed
+}
@@ -0,0 +1,173 @@
+// Package comprx 提供压缩包构建器和 fs.FS 打包功能。
+//
+// 该文件提供了逐条目构建压缩包的 ArchiveBuilder，条目内容可来自任意 io.Reader，
+// 以及直接从 fs.FS（如 embed.FS、fstest.MapFS）打包的便捷函数，
+// 无需先将内容写入磁盘临时目录。
+//
+// 主要类型：
+//   - ArchiveBuilder: 压缩包构建器
+//
+// 主要功能：
+//   - 创建写入到文件或 io.Writer 的压缩包构建器
+//   - 逐个添加文件、目录和符号链接
+//   - 从 fs.FS 打包
+//
+// 使用示例：
+//
+//	// 逐条目构建压缩包
+//	builder, err := comprx.NewArchiveBuilder("output.zip", comprx.DefaultOptions())
+//	err = builder.AddFile("hello.txt", strings.NewReader("hello"), types.FileInfo{})
+//	err = builder.Close()
+//
+//	// 从 embed.FS 打包
+//	err := comprx.PackFS("assets.tgz", assets, comprx.DefaultOptions())
+package comprx
+
+import (
+	"io"
+	"io/fs"
+
+	"gitee.com/MM-Q/comprx/internal/core"
+	"gitee.com/MM-Q/comprx/types"
+)
+
+// ArchiveBuilder 压缩包构建器
+//
+// 支持 ZIP、TAR、TGZ 格式，条目名称使用正斜杠分隔的压缩包内路径，
+// 出现重复条目时返回错误。使用完毕后必须调用 Close 完成写入。
+type ArchiveBuilder struct {
+	builder core.ArchiveBuilder // 对应格式的构建器实现
+}
+
+// NewArchiveBuilder 创建写入到目标文件的压缩包构建器
+//
+// 参数:
+//   - dst: 目标文件路径（支持 .zip、.tar、.tgz、.tar.gz）
+//   - opts: 配置选项
+//
+// 返回:
+//   - *ArchiveBuilder: 压缩包构建器
+//   - error: 错误信息
+//
+// 使用示例:
+//
+//	builder, err := NewArchiveBuilder("output.tgz", DefaultOptions())
+//	if err != nil {
+//	    return err
+//	}
+//	defer builder.Close()
+func NewArchiveBuilder(dst string, opts Options) (*ArchiveBuilder, error) {
+	comprx, err := newPackComprx(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	builder, err := comprx.NewBuilder(dst)
+	if err != nil {
+		return nil, err
+	}
+	return &ArchiveBuilder{builder: builder}, nil
+}
+
+// NewArchiveBuilderTo 创建写入到 w 的压缩包构建器
+//
+// 参数:
+//   - w: 压缩包输出目标
+//   - format: 压缩格式（支持 zip、tar、tgz、tar.gz）
+//   - opts: 配置选项
+//
+// 返回:
+//   - *ArchiveBuilder: 压缩包构建器
+//   - error: 错误信息
+//
+// 注意:
+//   - Close 不会关闭 w
+//
+// 使用示例:
+//
+//	var buf bytes.Buffer
+//	builder, err := NewArchiveBuilderTo(&buf, types.CompressTypeZip, DefaultOptions())
+func NewArchiveBuilderTo(w io.Writer, format types.CompressType, opts Options) (*ArchiveBuilder, error) {
+	comprx, err := newPackComprx(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	builder, err := comprx.NewBuilderTo(w, format)
+	if err != nil {
+		return nil, err
+	}
+	return &ArchiveBuilder{builder: builder}, nil
+}
+
+// AddFile 添加普通文件
+//
+// 参数:
+//   - name: 压缩包内的文件名
+//   - r: 文件内容
+//   - info: 条目信息（使用 Size、Mode、ModTime 字段），Mode 为 0 时使用 0644，ModTime 为零值时使用当前时间
+//
+// 返回:
+//   - error: 错误信息
+//
+// 注意:
+//   - TAR 和 TGZ 格式需要预先知道文件大小，info.Size 为 0 时会先将内容读入内存
+func (b *ArchiveBuilder) AddFile(name string, r io.Reader, info types.FileInfo) error {
+	return b.builder.AddFile(name, r, info)
+}
+
+// AddDir 添加目录
+//
+// 参数:
+//   - name: 压缩包内的目录名
+//   - info: 条目信息（使用 Mode、ModTime 字段），Mode 为 0 时使用 0755
+//
+// 返回:
+//   - error: 错误信息
+func (b *ArchiveBuilder) AddDir(name string, info types.FileInfo) error {
+	return b.builder.AddDir(name, info)
+}
+
+// AddSymlink 添加符号链接
+//
+// 参数:
+//   - name: 压缩包内的链接名
+//   - target: 链接目标
+//   - info: 条目信息（使用 Mode、ModTime 字段）
+//
+// 返回:
+//   - error: 错误信息
+func (b *ArchiveBuilder) AddSymlink(name, target string, info types.FileInfo) error {
+	return b.builder.AddSymlink(name, target, info)
+}
+
+// Close 完成压缩包写入
+//
+// 返回:
+//   - error: 错误信息
+func (b *ArchiveBuilder) Close() error {
+	return b.builder.Close()
+}
+
+// PackFS 将 fs.FS 的全部内容压缩到压缩包根目录 - 线程安全
+//
+// 参数:
+//   - dst: 目标文件路径（支持 .zip、.tar、.tgz、.tar.gz）
+//   - fsys: 源文件系统（如 embed.FS、fstest.MapFS）
+//   - opts: 配置选项
+//
+// 返回:
+//   - error: 错误信息
+//
+// 注意:
+//   - 需要只打包 FS 中的部分路径或映射到其他位置时，使用 PackSources 并设置 types.Source.FS
+//
+// 使用示例:
+//
+//	//go:embed static
+//	var assets embed.FS
+//
+//	err := PackFS("assets.zip", assets, DefaultOptions())
+func PackFS(dst string, fsys fs.FS, opts Options) error {
+	return PackSources(dst, []types.Source{{FS: fsys, Path: ".", ArchivePath: "."}}, opts)
+}
@@ -0,0 +1,193 @@
+// Package comprx 提供统一的编解码器接口。
+//
+// 该文件将 GZIP、BGZF、ZLIB、原始 DEFLATE、LZ4 和 Snappy 的内存压缩和流式压缩
+// 封装为同一个 Codec 接口，调用方可以在不修改代码的情况下切换压缩格式。
+//
+// 主要类型：
+//   - Codec: 编解码器接口
+//
+// 主要功能：
+//   - 按格式和压缩等级创建编解码器
+//   - 字节数据和流式数据的压缩解压
+//
+// 使用示例：
+//
+//	codec := comprx.NewLz4Codec(types.CompressionLevelFast)
+//	compressed, err := codec.Compress(data)
+//	data, err = codec.Decompress(compressed)
+package comprx
+
+import (
+	"io"
+
+	"gitee.com/MM-Q/comprx/internal/cxbgzf"
+	"gitee.com/MM-Q/comprx/internal/cxflate"
+	"gitee.com/MM-Q/comprx/internal/cxgzip"
+	"gitee.com/MM-Q/comprx/internal/cxlz4"
+	"gitee.com/MM-Q/comprx/internal/cxsnappy"
+	"gitee.com/MM-Q/comprx/internal/cxzlib"
+	"gitee.com/MM-Q/comprx/types"
+)
+
+// Codec 编解码器接口
+//
+// 所有实现都可以安全地被多个 goroutine 同时使用。
+type Codec interface {
+	// Name 返回格式名称，如 "gzip"、"lz4"
+	Name() string
+
+	// Compress 压缩字节数据，输入为 nil 或空时返回错误
+	Compress(data []byte) ([]byte, error)
+
+	// Decompress 解压字节数据，输入为 nil 或空时返回错误
+	Decompress(compressedData []byte) ([]byte, error)
+
+	// CompressStream 从 src 读取数据并将压缩结果写入 dst
+	CompressStream(dst io.Writer, src io.Reader) error
+
+	// DecompressStream 从 src 读取压缩数据并将解压结果写入 dst
+	DecompressStream(dst io.Writer, src io.Reader) error
+}
+
+// codec 基于内部压缩包函数的编解码器实现
+type codec struct {
+	name             string                                                                 // 格式名称
+	level            types.CompressionLevel                                                 // 压缩等级
+	compressBytes    func(data []byte, level types.CompressionLevel) ([]byte, error)        // 字节压缩函数
+	decompressBytes  func(compressedData []byte) ([]byte, error)                            // 字节解压函数
+	compressStream   func(dst io.Writer, src io.Reader, level types.CompressionLevel) error // 流式压缩函数
+	decompressStream func(dst io.Writer, src io.Reader) error                               // 流式解压函数
+}
+
+// Name 返回格式名称
+func (c *codec) Name() string {
+	return c.name
+}
+
+// Compress 压缩字节数据
+func (c *codec) Compress(data []byte) ([]byte, error) {
+	return c.compressBytes(data, c.level)
+}
+
+// Decompress 解压字节数据
+func (c *codec) Decompress(compressedData []byte) ([]byte, error) {
+	return c.decompressBytes(compressedData)
+}
+
+// CompressStream 流式压缩数据
+func (c *codec) CompressStream(dst io.Writer, src io.Reader) error {
+	return c.compressStream(dst, src, c.level)
+}
+
+// DecompressStream 流式解压数据
+func (c *codec) DecompressStream(dst io.Writer, src io.Reader) error {
+	return c.decompressStream(dst, src)
+}
+
+// NewGzipCodec 创建GZIP编解码器
+//
+// 参数:
+//   - level: 压缩级别
+//
+// 返回:
+//   - Codec: GZIP 编解码器
+func NewGzipCodec(level types.CompressionLevel) Codec {
+	return &codec{
+		name:             "gzip",
+		level:            level,
+		compressBytes:    cxgzip.CompressBytes,
+		decompressBytes:  cxgzip.DecompressBytes,
+		compressStream:   cxgzip.CompressStream,
+		decompressStream: cxgzip.DecompressStream,
+	}
+}
+
+// NewBgzfCodec 创建BGZF编解码器
+//
+// 参数:
+//   - level: 压缩级别
+//
+// 返回:
+//   - Codec: BGZF 编解码器
+func NewBgzfCodec(level types.CompressionLevel) Codec {
+	return &codec{
+		name:             "bgzf",
+		level:            level,
+		compressBytes:    cxbgzf.CompressBytes,
+		decompressBytes:  cxbgzf.DecompressBytes,
+		compressStream:   cxbgzf.CompressStream,
+		decompressStream: cxbgzf.DecompressStream,
+	}
+}
+
+// NewZlibCodec 创建ZLIB编解码器
+//
+// 参数:
+//   - level: 压缩级别
+//
+// 返回:
+//   - Codec: ZLIB 编解码器
+func NewZlibCodec(level types.CompressionLevel) Codec {
+	return &codec{
+		name:             "zlib",
+		level:            level,
+		compressBytes:    cxzlib.CompressBytes,
+		decompressBytes:  cxzlib.DecompressBytes,
+		compressStream:   cxzlib.CompressStream,
+		decompressStream: cxzlib.DecompressStream,
+	}
+}
+
+// NewFlateCodec 创建原始DEFLATE编解码器
+//
+// 参数:
+//   - level: 压缩级别
+//
+// 返回:
+//   - Codec: 原始 DEFLATE 编解码器
+func NewFlateCodec(level types.CompressionLevel) Codec {
+	return &codec{
+		name:             "deflate",
+		level:            level,
+		compressBytes:    cxflate.CompressBytes,
+		decompressBytes:  cxflate.DecompressBytes,
+		compressStream:   cxflate.CompressStream,
+		decompressStream: cxflate.DecompressStream,
+	}
+}
+
+// NewLz4Codec 创建LZ4帧编解码器
+//
+// 参数:
+//   - level: 压缩级别，决定匹配的搜索深度，CompressionLevelNone 时以未压缩的数据块写入
+//
+// 返回:
+//   - Codec: LZ4 编解码器
+func NewLz4Codec(level types.CompressionLevel) Codec {
+	return &codec{
+		name:             "lz4",
+		level:            level,
+		compressBytes:    cxlz4.CompressBytes,
+		decompressBytes:  cxlz4.DecompressBytes,
+		compressStream:   cxlz4.CompressStream,
+		decompressStream: cxlz4.DecompressStream,
+	}
+}
+
+// NewSnappyCodec 创建Snappy分帧编解码器
+//
+// 参数:
+//   - level: 压缩级别，Snappy 没有压缩等级之分，只有 CompressionLevelNone 会以未压缩的分块写入
+//
+// 返回:
+//   - Codec: Snappy 编解码器
+func NewSnappyCodec(level types.CompressionLevel) Codec {
+	return &codec{
+		name:             "snappy",
+		level:            level,
+		compressBytes:    cxsnappy.CompressBytes,
+		decompressBytes:  cxsnappy.DecompressBytes,
+		compressStream:   cxsnappy.CompressStream,
+		decompressStream: cxsnappy.DecompressStream,
+	}
+}
@@ -0,0 +1,112 @@
+package comprx
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+
+	"gitee.com/MM-Q/comprx/types"
+)
+
+// TestCodecs 测试所有编解码器在各压缩等级下的字节和流式压缩解压
+func TestCodecs(t *testing.T) {
+	constructors := []func(types.CompressionLevel) Codec{
+		NewGzipCodec, NewBgzfCodec, NewZlibCodec, NewFlateCodec, NewLz4Codec, NewSnappyCodec,
+	}
+	levels := []types.CompressionLevel{
+		types.CompressionLevelNone,
+		types.CompressionLevelFast,
+		types.CompressionLevelDefault,
+		types.CompressionLevelBest,
+	}
+	data := []byte(strings.Repeat("comprx codec round trip\n", 5000))
+
+	for _, newCodec := range constructors {
+		for _, level := range levels {
+			codec := newCodec(level)
+			t.Run(fmt.Sprintf("%s/%d", codec.Name(), level), func(t *testing.T) {
+				compressed, err := codec.Compress(data)
+				if err != nil {
+					t.Fatalf("压缩失败: %v", err)
+				}
+				if level != types.CompressionLevelNone && len(compressed) >= len(data)/10 {
+					t.Errorf("等级 %d 的压缩率过低: %d -> %d", level, len(data), len(compressed))
+				}
+				got, err := codec.Decompress(compressed)
+				if err != nil {
+					t.Fatalf("解压失败: %v", err)
+				}
+				if !bytes.Equal(got, data) {
+					t.Fatal("解压结果不一致")
+				}
+
+				var stream, out bytes.Buffer
+				if err := codec.CompressStream(&stream, bytes.NewReader(data)); err != nil {
+					t.Fatalf("流式压缩失败: %v", err)
+				}
+				if err := codec.DecompressStream(&out, &stream); err != nil {
+					t.Fatalf("流式解压失败: %v", err)
+				}
+				if !bytes.Equal(out.Bytes(), data) {
+					t.Fatal("流式解压结果不一致")
+				}
+
+				if _, err := codec.Compress(nil); err == nil {
+					t.Error("nil 输入应返回错误")
+				}
+				if _, err := codec.Decompress([]byte{}); err == nil {
+					t.Error("空输入应返回错误")
+				}
+			})
+		}
+	}
+}
+
+// TestHeaderlessMemory 测试原始 DEFLATE、LZ4 和 Snappy 的内存压缩函数
+func TestHeaderlessMemory(t *testing.T) {
+	data := []byte(strings.Repeat("raw deflate in http, pdf and zip\n", 200))
+
+	compressed, err := FlateBytes(data)
+	if err != nil {
+		t.Fatalf("DEFLATE 压缩失败: %v", err)
+	}
+	if _, err := UnzlibBytes(compressed); err == nil {
+		t.Error("原始 DEFLATE 不应包含 ZLIB 头")
+	}
+	if got, err := UnflateBytes(compressed); err != nil || !bytes.Equal(got, data) {
+		t.Fatalf("DEFLATE 解压结果不一致: %v", err)
+	}
+
+	// 预设字典
+	dict := []byte("raw deflate in http, pdf and zip\n")
+	withDict, err := FlateBytesWithDict(dict, dict)
+	if err != nil {
+		t.Fatalf("DEFLATE 字典压缩失败: %v", err)
+	}
+	if got, err := UnflateBytesWithDict(withDict, dict); err != nil || !bytes.Equal(got, dict) {
+		t.Fatalf("DEFLATE 字典解压结果不一致: %v", err)
+	}
+
+	// LZ4 和 Snappy 的格式互不兼容
+	lz4Data, err := Lz4BytesWithLevel(data, types.CompressionLevelBest)
+	if err != nil {
+		t.Fatalf("LZ4 压缩失败: %v", err)
+	}
+	if got, err := Unlz4Bytes(lz4Data); err != nil || !bytes.Equal(got, data) {
+		t.Fatalf("LZ4 解压结果不一致: %v", err)
+	}
+	snappyData, err := SnappyBytes(data)
+	if err != nil {
+		t.Fatalf("Snappy 压缩失败: %v", err)
+	}
+	if got, err := UnsnappyBytes(snappyData); err != nil || !bytes.Equal(got, data) {
+		t.Fatalf("Snappy 解压结果不一致: %v", err)
+	}
+	if _, err := Unlz4Bytes(snappyData); err == nil {
+		t.Error("LZ4 解压 Snappy 数据应返回错误")
+	}
+	if _, err := UnsnappyBytes(lz4Data); err == nil {
+		t.Error("Snappy 解压 LZ4 数据应返回错误")
+	}
+}
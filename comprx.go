@@ -23,8 +23,11 @@
 package comprx
 
 import (
+	"crypto/ed25519"
 	"fmt"
+	"math"
 
+	"gitee.com/MM-Q/comprx/internal/config"
 	"gitee.com/MM-Q/comprx/internal/core"
 	"gitee.com/MM-Q/comprx/types"
 )
@@ -243,11 +246,58 @@ func UnpackMatch(archivePath string, keyword string, outputDir string) error {
 //	}
 //	err := PackOptions("output.zip", "input_dir", opts)
 func PackOptions(dst string, src string, opts Options) error {
+	comprx, err := newPackComprx(opts)
+	if err != nil {
+		return err
+	}
+
+	return comprx.Pack(dst, src)
+}
+
+// PackSources 将多个源压缩到同一个压缩包 - 线程安全
+//
+// 每个源可以是文件或目录，并通过 ArchivePath 映射到压缩包内的任意位置，
+// 未指定 ArchivePath 时使用源路径的基本名称。压缩包内出现重复条目时返回错误。
+//
+// 参数:
+//   - dst: 目标文件路径（支持 .zip、.tar、.tgz、.tar.gz）
+//   - sources: 打包源列表
+//   - opts: 配置选项
+//
+// 返回:
+//   - error: 错误信息
+//
+// 使用示例:
+//
+//	sources := []types.Source{
+//	    {Path: "bin", ArchivePath: "app-1.2/bin"},
+//	    {Path: "docs", ArchivePath: "app-1.2/docs"},
+//	    {Path: "build/VERSION", ArchivePath: "app-1.2/VERSION"},
+//	}
+//	err := PackSources("app-1.2.tgz", sources, DefaultOptions())
+func PackSources(dst string, sources []types.Source, opts Options) error {
+	comprx, err := newPackComprx(opts)
+	if err != nil {
+		return err
+	}
+
+	return comprx.PackSources(dst, sources)
+}
+
+// newPackComprx 根据配置选项创建用于压缩的压缩器实例
+//
+// 参数:
+//   - opts: 配置选项
+//
+// 返回:
+//   - *core.Comprx: 压缩器实例
+//   - error: 配置选项无效时返回错误
+func newPackComprx(opts Options) (*core.Comprx, error) {
 	comprx := core.New()
 
 	// 验证压缩等级
 	if !opts.CompressionLevel.IsValid() {
-		return fmt.Errorf("无效的压缩等级: %s，有效范围: -2 到 9", opts.CompressionLevel.String())
+		return nil, fmt.Errorf("无效的压缩等级: %s，有效范围: -2 到 9", opts.CompressionLevel.String())
 	}
 	comprx.Config.CompressionLevel = opts.CompressionLevel
 	comprx.Config.OverwriteExisting = opts.OverwriteExisting
@@ -255,14 +305,14 @@ func PackOptions(dst string, src string, opts Options) error {
 
 	// 验证进度条样式
 	if !opts.ProgressStyle.IsValid() {
-		return fmt.Errorf("invalid progress style: %v", opts.ProgressStyle)
+		return nil, fmt.Errorf("invalid progress style: %v", opts.ProgressStyle)
 	}
 	comprx.Config.Progress.BarStyle = opts.ProgressStyle
 	comprx.Config.DisablePathValidation = opts.DisablePathValidation
 
 	// 验证过滤器选项
 	if err := opts.Filter.Validate(); err != nil {
-		return err
+		return nil, err
 	}
 	comprx.Config.Filter = &types.FilterOptions{
 		Include: opts.Filter.Include,
@@ -271,7 +321,79 @@ func PackOptions(dst string, src string, opts Options) error {
 		MinSize: opts.Filter.MinSize,
 	}
 
-	return comprx.Pack(dst, src)
+	// 解析确定性打包的时间上限
+	if opts.Deterministic {
+		epoch, err := config.ResolveSourceDateEpoch(opts.SourceDateEpoch)
+		if err != nil {
+			return nil, err
+		}
+		comprx.Config.Deterministic = true
+		comprx.Config.SourceDateEpoch = epoch
+	}
+
+	// 验证并设置校验清单
+	comprx.Config.Checksums.Algorithms = opts.Checksums
+	comprx.Config.Checksums.Beside = opts.ChecksumsBeside
+	if err := comprx.Config.Checksums.Validate(); err != nil {
+		return nil, err
+	}
+
+	// 验证并设置加密
+	if !opts.Encryption.IsValid() {
+		return nil, fmt.Errorf("不支持的加密方式: %s，有效值: aes256", opts.Encryption)
+	}
+	if opts.Encryption != types.EncryptionNone && opts.Password == "" {
+		return nil, fmt.Errorf("启用 %s 加密时必须设置密码", opts.Encryption)
+	}
+	comprx.Config.Encryption = opts.Encryption
+	comprx.Config.Password = opts.Password
+
+	// 验证并设置加密信封
+	if err := opts.EncryptWith.Validate(); err != nil {
+		return nil, err
+	}
+	comprx.Config.EncryptWith = opts.EncryptWith
+
+	// 验证并设置文件名编码(格式转换时读取 ZIP 使用)
+	if !opts.FilenameEncoding.IsValid() {
+		return nil, fmt.Errorf("不支持的文件名编码: %s，有效值: auto, utf-8, gbk, gb18030, shift_jis, cp437", opts.FilenameEncoding)
+	}
+	comprx.Config.FilenameEncoding = opts.FilenameEncoding
+
+	// 验证并设置 ZIP 注释
+	if len(opts.ArchiveComment) > math.MaxUint16 {
+		return nil, fmt.Errorf("压缩包注释不能超过 %d 字节", math.MaxUint16)
+	}
+	for name, comment := range opts.EntryComments {
+		if len(comment) > math.MaxUint16 {
+			return nil, fmt.Errorf("条目 %s 的注释不能超过 %d 字节", name, math.MaxUint16)
+		}
+	}
+	comprx.Config.ArchiveComment = opts.ArchiveComment
+	comprx.Config.EntryComments = opts.EntryComments
+
+	// 验证并设置 GZIP 文件头
+	if opts.GzipHeader != nil {
+		if err := opts.GzipHeader.Validate(); err != nil {
+			return nil, err
+		}
+		comprx.Config.GzipHeader = opts.GzipHeader
+	}
+
+	// 验证并设置 BGZF，数据块头是固定的，虚拟偏移也只对未加密的文件有意义
+	if opts.BgzfIndex && !opts.Bgzf {
+		return nil, fmt.Errorf("生成 .gzi 索引需要启用 BGZF")
+	}
+	if opts.Bgzf && opts.GzipHeader != nil {
+		return nil, fmt.Errorf("BGZF 不支持自定义 GZIP 文件头")
+	}
+	if opts.Bgzf && opts.EncryptWith.Enabled() {
+		return nil, fmt.Errorf("BGZF 不支持加密信封")
+	}
+	comprx.Config.Bgzf = opts.Bgzf
+	comprx.Config.BgzfIndex = opts.BgzfIndex
+
+	return comprx, nil
 }
 
 // UnpackOptions 使用指定配置解压文件 - 线程安全
@@ -293,6 +415,23 @@ func PackOptions(dst string, src string, opts Options) error {
 //	}
 //	err := UnpackOptions("archive.zip", "output_dir", opts)
 func UnpackOptions(src string, dst string, opts Options) error {
+	comprx, err := newUnpackComprx(opts)
+	if err != nil {
+		return err
+	}
+
+	return comprx.Unpack(src, dst)
+}
+
+// newUnpackComprx 根据配置选项创建用于解压的压缩器实例
+//
+// 参数:
+//   - opts: 配置选项
+//
+// 返回:
+//   - *core.Comprx: 压缩器实例
+//   - error: 配置选项无效时返回错误
+func newUnpackComprx(opts Options) (*core.Comprx, error) {
 	comprx := core.New()
 
 	// 设置配置（解压时不需要验证压缩等级）
@@ -301,14 +440,14 @@ func UnpackOptions(src string, dst string, opts Options) error {
 
 	// 验证进度条样式
 	if !opts.ProgressStyle.IsValid() {
-		return fmt.Errorf("invalid progress style: %v", opts.ProgressStyle)
+		return nil, fmt.Errorf("invalid progress style: %v", opts.ProgressStyle)
 	}
 	comprx.Config.Progress.BarStyle = opts.ProgressStyle
 	comprx.Config.DisablePathValidation = opts.DisablePathValidation
 
 	// 验证并设置过滤器
 	if err := opts.Filter.Validate(); err != nil {
-		return err
+		return nil, err
 	}
 	comprx.Config.Filter = &types.FilterOptions{
 		Include: opts.Filter.Include,
@@ -317,5 +456,40 @@ func UnpackOptions(src string, dst string, opts Options) error {
 		MinSize: opts.Filter.MinSize,
 	}
 
-	return comprx.Unpack(src, dst)
+	// 验证并设置校验清单
+	comprx.Config.Checksums.Algorithms = opts.Checksums
+	comprx.Config.Checksums.Beside = opts.ChecksumsBeside
+	if err := comprx.Config.Checksums.Validate(); err != nil {
+		return nil, err
+	}
+
+	// 验证并设置签名验证
+	if opts.RequireSignature && len(opts.SignaturePublicKey) != ed25519.PublicKeySize {
+		return nil, fmt.Errorf("启用签名验证时必须提供 %d 字节的 Ed25519 公钥", ed25519.PublicKeySize)
+	}
+	comprx.Config.RequireSignature = opts.RequireSignature
+	comprx.Config.SignaturePublicKey = opts.SignaturePublicKey
+
+	// 设置解密密码
+	comprx.Config.Password = opts.Password
+
+	// 设置加密信封的口令，未单独设置时使用解密密码
+	comprx.Config.EncryptWith.Passphrase = opts.EncryptWith.Passphrase
+	if comprx.Config.EncryptWith.Passphrase == "" {
+		comprx.Config.EncryptWith.Passphrase = opts.Password
+	}
+
+	// 验证并设置文件名编码
+	if !opts.FilenameEncoding.IsValid() {
+		return nil, fmt.Errorf("不支持的文件名编码: %s，有效值: auto, utf-8, gbk, gb18030, shift_jis, cp437", opts.FilenameEncoding)
+	}
+	comprx.Config.FilenameEncoding = opts.FilenameEncoding
+
+	// 验证并设置多成员 GZIP 的解压方式
+	if !opts.GzipMembers.IsValid() {
+		return nil, fmt.Errorf("不支持的 GZIP 成员解压方式: %s，有效值: concat, split", opts.GzipMembers)
+	}
+	comprx.Config.GzipMembers = opts.GzipMembers
+
+	return comprx, nil
 }
@@ -0,0 +1,43 @@
+// Package comprx 提供压缩包格式转换功能。
+//
+// 该文件提供了在不解压到磁盘的情况下转换压缩包格式的方法，
+// 条目从源压缩包流式读取后直接写入目标格式。
+//
+// 主要功能：
+//   - 在 ZIP、TAR、TGZ 之间相互转换
+//   - 转换过程中应用过滤器
+//
+// 使用示例：
+//
+//	// 将供应商提供的 ZIP 转换为部署系统需要的 TGZ
+//	err := comprx.Convert("vendor.zip", "vendor.tgz", comprx.DefaultOptions())
+package comprx
+
+// Convert 将源压缩包转换为目标格式 - 线程安全
+//
+// 参数:
+//   - src: 源压缩包路径（支持 .zip、.tar、.tgz、.tar.gz）
+//   - dst: 目标压缩包路径，格式由扩展名决定（支持 .zip、.tar、.tgz、.tar.gz）
+//   - opts: 配置选项，支持压缩等级、覆盖策略、过滤器和确定性模式
+//
+// 返回:
+//   - error: 错误信息
+//
+// 注意:
+//   - 条目的名称、权限、修改时间和软链接目标保持不变
+//   - TAR 中的设备文件等特殊条目会被跳过，硬链接无法流式转换，遇到时返回错误
+//   - 目标文件通过同目录临时文件写入后原子替换，转换失败时不会留下不完整的文件
+//
+// 使用示例:
+//
+//	opts := DefaultOptions()
+//	opts.Filter.Exclude = []string{"__MACOSX/*", "*.DS_Store"}
+//	err := Convert("vendor.zip", "vendor.tgz", opts)
+func Convert(src string, dst string, opts Options) error {
+	comprx, err := newPackComprx(opts)
+	if err != nil {
+		return err
+	}
+
+	return comprx.Convert(src, dst)
+}
@@ -0,0 +1,516 @@
+// Package checksum 提供打包和解压过程中的文件摘要计算与校验清单功能。
+//
+// 该包在文件内容写入压缩包或解压到磁盘的同时计算摘要，打包结束后生成
+// SHA256SUMS 风格的校验清单（写入压缩包根目录或压缩包旁边），
+// 解压结束后将每个文件的摘要与清单比对，发现不一致或清单中的条目未被解压时
+// 返回包含条目名称的错误。向已有压缩包追加条目时，以已有清单为基础合并新条目的摘要。
+//
+// 主要类型：
+//   - Checksums: 摘要记录器
+//   - Manifest: 校验清单
+//
+// 清单格式：
+//   - 每行一个条目："<十六进制摘要>  <压缩包内路径>"，按路径排序
+//   - 与 sha256sum、sha512sum、b2sum 的输出格式兼容
+//
+// 使用示例：
+//
+//	sums := checksum.New()
+//	sums.Algorithms = []types.ChecksumAlgorithm{types.ChecksumSHA256}
+//
+//	// 写入文件内容的同时计算摘要
+//	w, commit := sums.Writer("app/main.go", fileWriter)
+//	if _, err := io.Copy(w, file); err == nil {
+//	    commit()
+//	}
+//
+//	// 生成校验清单
+//	manifests := sums.Manifests()
+package checksum
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+
+	"gitee.com/MM-Q/comprx/internal/utils"
+	"gitee.com/MM-Q/comprx/types"
+	"golang.org/x/crypto/blake2b"
+)
+
+// Checksums 摘要记录器
+type Checksums struct {
+	Algorithms []types.ChecksumAlgorithm // 启用的摘要算法，为空时不计算
+	Beside     bool                      // 校验清单写在压缩包旁边而不是压缩包内
+
+	parent    *Checksums                                    // 临时归档使用的记录器将摘要记录到的上级记录器
+	mu        sync.Mutex                                    // 保护以下字段
+	sums      map[types.ChecksumAlgorithm]map[string]string // 算法 -> 条目名称 -> 十六进制摘要
+	base      map[types.ChecksumAlgorithm]map[string]string // 追加时已有校验清单中的摘要
+	manifests map[types.ChecksumAlgorithm][]byte            // 解压时从压缩包中读到的校验清单
+	skipped   map[string]bool                               // 解压时被过滤器跳过的条目
+}
+
+// Manifest 校验清单
+type Manifest struct {
+	Algorithm types.ChecksumAlgorithm // 摘要算法
+	Name      string                  // 清单文件名，如 SHA256SUMS
+	Data      []byte                  // 清单内容
+}
+
+// New 创建摘要记录器
+//
+// 返回:
+//   - *Checksums: 未启用任何算法的摘要记录器
+func New() *Checksums {
+	return &Checksums{}
+}
+
+// Enabled 检查是否启用了摘要计算
+//
+// 返回:
+//   - bool: 至少启用一种算法时返回 true
+func (c *Checksums) Enabled() bool {
+	return c != nil && len(c.Algorithms) > 0
+}
+
+// Validate 校验启用的摘要算法
+//
+// 返回:
+//   - error: 存在不支持的算法或重复的算法时返回错误
+func (c *Checksums) Validate() error {
+	seen := make(map[types.ChecksumAlgorithm]bool, len(c.Algorithms))
+	for _, algorithm := range c.Algorithms {
+		if !algorithm.IsValid() {
+			return fmt.Errorf("不支持的摘要算法: %s，有效值: sha256、sha512、blake2b", algorithm)
+		}
+		if seen[algorithm] {
+			return fmt.Errorf("摘要算法 %s 重复", algorithm)
+		}
+		seen[algorithm] = true
+	}
+	return nil
+}
+
+// Reset 清空已记录的摘要和校验清单，在每次打包或解压开始时调用
+func (c *Checksums) Reset() {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.sums = nil
+	c.base = nil
+	c.manifests = nil
+	c.skipped = nil
+}
+
+// Scratch 创建供临时归档使用的记录器
+//
+// 追加条目时新条目先打包到临时归档中，临时归档不应包含校验清单。
+// 返回的记录器计算的摘要记录到当前记录器，自身不写入任何校验清单。
+//
+// 返回:
+//   - *Checksums: 只记录摘要的记录器
+func (c *Checksums) Scratch() *Checksums {
+	if !c.Enabled() {
+		return New()
+	}
+	return &Checksums{Algorithms: c.Algorithms, parent: c}
+}
+
+// Embedded 检查校验清单是否写入压缩包内
+//
+// 返回:
+//   - bool: 启用了摘要计算且清单不写在压缩包旁边时返回 true
+func (c *Checksums) Embedded() bool {
+	return c.Enabled() && !c.Beside && c.parent == nil
+}
+
+// IsManifest 检查条目是否为启用算法的校验清单
+//
+// 参数:
+//   - name: 条目在压缩包内的路径
+//
+// 返回:
+//   - bool: 是压缩包根目录下启用算法的校验清单时返回 true
+func (c *Checksums) IsManifest(name string) bool {
+	_, ok := c.ManifestAlgorithm(name)
+	return ok
+}
+
+// ManifestAlgorithm 返回条目作为校验清单时对应的摘要算法
+//
+// 参数:
+//   - name: 条目在压缩包内的路径
+//
+// 返回:
+//   - types.ChecksumAlgorithm: 对应的摘要算法
+//   - bool: 是压缩包根目录下启用算法的校验清单时返回 true
+func (c *Checksums) ManifestAlgorithm(name string) (types.ChecksumAlgorithm, bool) {
+	if !c.Enabled() {
+		return "", false
+	}
+	return c.manifestAlgorithm(normalizeName(name))
+}
+
+// Extend 以已有的校验清单为基础，之后生成的清单合并新记录的摘要
+//
+// 参数:
+//   - algorithm: 摘要算法
+//   - data: 已有的清单内容
+//
+// 返回:
+//   - error: 清单格式错误时返回错误
+//
+// 注意:
+//   - 同名条目以新记录的摘要为准
+func (c *Checksums) Extend(algorithm types.ChecksumAlgorithm, data []byte) error {
+	sums, err := ParseManifest(data)
+	if err != nil {
+		return fmt.Errorf("解析 %s 失败: %w", algorithm.ManifestName(), err)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.base == nil {
+		c.base = make(map[types.ChecksumAlgorithm]map[string]string)
+	}
+	c.base[algorithm] = sums
+	return nil
+}
+
+// Skip 记录解压时被过滤器跳过的条目，校验时不要求清单中的这些条目被解压
+//
+// 参数:
+//   - name: 条目在压缩包内的路径
+func (c *Checksums) Skip(name string) {
+	if !c.Enabled() {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.skipped == nil {
+		c.skipped = make(map[string]bool)
+	}
+	c.skipped[normalizeName(name)] = true
+}
+
+// Writer 包装写入器，在写入文件内容的同时计算摘要
+//
+// 参数:
+//   - name: 条目在压缩包内的路径
+//   - w: 文件内容的写入目标
+//
+// 返回:
+//   - io.Writer: 写入时同时计算摘要的写入器
+//   - func(): 文件内容全部写入成功后调用，记录摘要
+//
+// 注意:
+//   - 未启用摘要计算时原样返回 w
+//   - 压缩包根目录下与清单同名的文件会被当作校验清单保存，供解压后校验使用
+func (c *Checksums) Writer(name string, w io.Writer) (io.Writer, func()) {
+	if !c.Enabled() {
+		return w, func() {}
+	}
+
+	name = normalizeName(name)
+	hashes := make([]hash.Hash, len(c.Algorithms))
+	writers := make([]io.Writer, 0, len(c.Algorithms)+2)
+	writers = append(writers, w)
+	for i, algorithm := range c.Algorithms {
+		hashes[i] = newHash(algorithm)
+		writers = append(writers, hashes[i])
+	}
+
+	// 根目录下的校验清单同时保存内容
+	manifestAlgorithm, isManifest := c.manifestAlgorithm(name)
+	var manifest bytes.Buffer
+	if isManifest {
+		writers = append(writers, &manifest)
+	}
+
+	// 临时归档的摘要记录到上级记录器
+	target := c
+	if c.parent != nil {
+		target = c.parent
+	}
+	commit := func() {
+		target.mu.Lock()
+		defer target.mu.Unlock()
+
+		if isManifest {
+			if target.manifests == nil {
+				target.manifests = make(map[types.ChecksumAlgorithm][]byte)
+			}
+			target.manifests[manifestAlgorithm] = manifest.Bytes()
+			return
+		}
+
+		if target.sums == nil {
+			target.sums = make(map[types.ChecksumAlgorithm]map[string]string)
+		}
+		for i, algorithm := range c.Algorithms {
+			if target.sums[algorithm] == nil {
+				target.sums[algorithm] = make(map[string]string)
+			}
+			target.sums[algorithm][name] = hex.EncodeToString(hashes[i].Sum(nil))
+		}
+	}
+	return io.MultiWriter(writers...), commit
+}
+
+// Manifests 生成所有启用算法的校验清单
+//
+// 返回:
+//   - []Manifest: 按启用顺序排列的校验清单，通过 Extend 设置了已有清单时包含其中的条目
+func (c *Checksums) Manifests() []Manifest {
+	if !c.Enabled() {
+		return nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	manifests := make([]Manifest, 0, len(c.Algorithms))
+	for _, algorithm := range c.Algorithms {
+		sums := make(map[string]string, len(c.base[algorithm])+len(c.sums[algorithm]))
+		for name, sum := range c.base[algorithm] {
+			sums[name] = sum
+		}
+		for name, sum := range c.sums[algorithm] {
+			sums[name] = sum
+		}
+		names := make([]string, 0, len(sums))
+		for name := range sums {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		var buf bytes.Buffer
+		for _, name := range names {
+			fmt.Fprintf(&buf, "%s  %s\n", sums[name], name)
+		}
+		manifests = append(manifests, Manifest{
+			Algorithm: algorithm,
+			Name:      algorithm.ManifestName(),
+			Data:      buf.Bytes(),
+		})
+	}
+	return manifests
+}
+
+// WriteManifests 将校验清单写入压缩包根目录
+//
+// 参数:
+//   - w: 压缩包条目写入器
+//
+// 返回:
+//   - error: 清单名称与已有条目冲突或写入失败时返回错误
+//
+// 注意:
+//   - 清单设置为写在压缩包旁边或记录器由 Scratch 创建时不做任何操作
+func (c *Checksums) WriteManifests(w utils.ArchiveEntryWriter) error {
+	if c.Beside || c.parent != nil {
+		return nil
+	}
+	for _, manifest := range c.Manifests() {
+		info := types.FileInfo{Size: int64(len(manifest.Data)), Mode: 0644}
+		if err := w.AddFile(manifest.Name, bytes.NewReader(manifest.Data), info); err != nil {
+			return fmt.Errorf("写入校验清单 %s 失败: %w", manifest.Name, err)
+		}
+	}
+	return nil
+}
+
+// WriteBeside 将校验清单写在压缩包旁边
+//
+// 参数:
+//   - archivePath: 压缩包路径，清单文件为 <压缩包路径>.<清单文件名>
+//
+// 返回:
+//   - error: 写入失败时返回错误
+//
+// 注意:
+//   - 清单设置为写入压缩包内或记录器由 Scratch 创建时不做任何操作
+func (c *Checksums) WriteBeside(archivePath string) error {
+	if !c.Beside || c.parent != nil {
+		return nil
+	}
+	for _, manifest := range c.Manifests() {
+		path := BesidePath(archivePath, manifest.Algorithm)
+		if err := os.WriteFile(path, manifest.Data, 0644); err != nil {
+			return fmt.Errorf("写入校验清单 %s 失败: %w", path, err)
+		}
+	}
+	return nil
+}
+
+// Verify 将解压时计算的摘要与校验清单比对
+//
+// 参数:
+//   - archivePath: 压缩包路径，压缩包内没有校验清单时读取压缩包旁边的清单
+//
+// 返回:
+//   - error: 找不到清单、条目不在清单中、摘要不一致或清单中的条目未被解压时返回包含条目名称的错误
+//
+// 注意:
+//   - 被过滤器跳过（通过 Skip 记录）的清单条目不要求被解压
+func (c *Checksums) Verify(archivePath string) error {
+	if !c.Enabled() {
+		return nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, algorithm := range c.Algorithms {
+		// 优先使用压缩包内的清单
+		data, ok := c.manifests[algorithm]
+		if !ok {
+			path := BesidePath(archivePath, algorithm)
+			besideData, err := os.ReadFile(path)
+			if err != nil {
+				return fmt.Errorf("未找到 %s 校验清单: 压缩包内没有 %s，且读取 %s 失败: %w", algorithm, algorithm.ManifestName(), path, err)
+			}
+			data = besideData
+		}
+
+		expected, err := ParseManifest(data)
+		if err != nil {
+			return fmt.Errorf("解析 %s 失败: %w", algorithm.ManifestName(), err)
+		}
+
+		sums := c.sums[algorithm]
+		names := make([]string, 0, len(sums))
+		for name := range sums {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			want, ok := expected[name]
+			if !ok {
+				return fmt.Errorf("条目 %s 不在校验清单 %s 中", name, algorithm.ManifestName())
+			}
+			if !strings.EqualFold(want, sums[name]) {
+				return fmt.Errorf("条目 %s 的 %s 校验失败: 期望 %s, 实际 %s", name, algorithm, want, sums[name])
+			}
+		}
+
+		// 清单中的条目除被过滤器跳过外都必须被解压
+		missing := make([]string, 0)
+		for name := range expected {
+			if _, ok := sums[name]; !ok && !c.skipped[name] {
+				missing = append(missing, name)
+			}
+		}
+		if len(missing) > 0 {
+			sort.Strings(missing)
+			return fmt.Errorf("校验清单 %s 中的条目 %s 未被解压", algorithm.ManifestName(), strings.Join(missing, ", "))
+		}
+	}
+	return nil
+}
+
+// ParseManifest 解析 SHA256SUMS 风格的校验清单
+//
+// 参数:
+//   - data: 清单内容，每行 "<十六进制摘要>  <路径>" 或 "<十六进制摘要> *<路径>"
+//
+// 返回:
+//   - map[string]string: 路径到十六进制摘要的映射
+//   - error: 存在格式错误的行时返回错误
+func ParseManifest(data []byte) (map[string]string, error) {
+	sums := make(map[string]string)
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+
+		sum, name, ok := strings.Cut(line, " ")
+		if !ok || len(name) < 2 || (name[0] != ' ' && name[0] != '*') {
+			return nil, fmt.Errorf("第 %d 行格式错误: %q", lineNo, line)
+		}
+		if _, err := hex.DecodeString(sum); err != nil {
+			return nil, fmt.Errorf("第 %d 行摘要无效: %q", lineNo, sum)
+		}
+		sums[normalizeName(name[1:])] = sum
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return sums, nil
+}
+
+// BesidePath 返回写在压缩包旁边的校验清单路径
+//
+// 参数:
+//   - archivePath: 压缩包路径
+//   - algorithm: 摘要算法
+//
+// 返回:
+//   - string: 清单路径，如 release.tgz.SHA256SUMS
+func BesidePath(archivePath string, algorithm types.ChecksumAlgorithm) string {
+	return archivePath + "." + algorithm.ManifestName()
+}
+
+// manifestAlgorithm 检查条目是否为启用算法的校验清单
+//
+// 参数:
+//   - name: 规范化后的条目名称
+//
+// 返回:
+//   - types.ChecksumAlgorithm: 对应的摘要算法
+//   - bool: 是校验清单返回 true
+func (c *Checksums) manifestAlgorithm(name string) (types.ChecksumAlgorithm, bool) {
+	for _, algorithm := range c.Algorithms {
+		if name == algorithm.ManifestName() {
+			return algorithm, true
+		}
+	}
+	return "", false
+}
+
+// newHash 创建摘要算法对应的哈希对象
+//
+// 参数:
+//   - algorithm: 已校验的摘要算法
+//
+// 返回:
+//   - hash.Hash: 哈希对象
+func newHash(algorithm types.ChecksumAlgorithm) hash.Hash {
+	switch algorithm {
+	case types.ChecksumSHA512:
+		return sha512.New()
+	case types.ChecksumBLAKE2b:
+		h, _ := blake2b.New512(nil) // 不带密钥时不会返回错误
+		return h
+	default:
+		return sha256.New()
+	}
+}
+
+// normalizeName 规范化条目名称，统一使用正斜杠并去掉开头的 "./" 和 "/"
+//
+// 参数:
+//   - name: 条目名称
+//
+// 返回:
+//   - string: 规范化后的名称
+func normalizeName(name string) string {
+	name = strings.ReplaceAll(name, "\\", "/")
+	name = strings.TrimPrefix(name, "./")
+	return strings.TrimLeft(name, "/")
+}
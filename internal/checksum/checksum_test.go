@@ -0,0 +1,184 @@
+package checksum
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gitee.com/MM-Q/comprx/types"
+)
+
+// record 通过摘要写入器写入内容并提交
+func record(t *testing.T, c *Checksums, name, content string) {
+	t.Helper()
+	w, commit := c.Writer(name, io.Discard)
+	if _, err := io.WriteString(w, content); err != nil {
+		t.Fatalf("写入 %s 失败: %v", name, err)
+	}
+	commit()
+}
+
+func TestChecksums_Manifests(t *testing.T) {
+	c := New()
+	c.Algorithms = []types.ChecksumAlgorithm{types.ChecksumSHA256, types.ChecksumSHA512, types.ChecksumBLAKE2b}
+
+	record(t, c, "z.txt", "")
+	record(t, c, "./dir/a.txt", "abc")
+
+	manifests := c.Manifests()
+	if len(manifests) != 3 {
+		t.Fatalf("期望 3 个校验清单，实际 %d 个", len(manifests))
+	}
+
+	// 与 sha256sum 的输出一致，按路径排序
+	want := "ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad  dir/a.txt\n" +
+		"e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855  z.txt\n"
+	if manifests[0].Name != "SHA256SUMS" || string(manifests[0].Data) != want {
+		t.Errorf("SHA256SUMS 内容不正确:\n%s", manifests[0].Data)
+	}
+	if manifests[1].Name != "SHA512SUMS" || !strings.HasPrefix(string(manifests[1].Data), "ddaf35a193617aba") {
+		t.Errorf("SHA512SUMS 内容不正确:\n%s", manifests[1].Data)
+	}
+	if manifests[2].Name != "B2SUMS" || !strings.HasPrefix(string(manifests[2].Data), "ba80a53f981c4d0d") {
+		t.Errorf("B2SUMS 内容不正确:\n%s", manifests[2].Data)
+	}
+}
+
+func TestChecksums_Disabled(t *testing.T) {
+	c := New()
+	var buf bytes.Buffer
+	w, commit := c.Writer("a.txt", &buf)
+	if w != &buf {
+		t.Error("未启用时应原样返回写入器")
+	}
+	commit()
+	if c.Manifests() != nil {
+		t.Error("未启用时不应生成校验清单")
+	}
+	if err := c.Verify("missing.zip"); err != nil {
+		t.Errorf("未启用时不应校验: %v", err)
+	}
+}
+
+func TestChecksums_ScratchAndExtend(t *testing.T) {
+	c := New()
+	c.Algorithms = []types.ChecksumAlgorithm{types.ChecksumSHA256}
+
+	// 已有清单中的 old.txt 保留，a.txt 以新记录的摘要为准
+	existing := "0000000000000000000000000000000000000000000000000000000000000000  a.txt\n" +
+		"1111111111111111111111111111111111111111111111111111111111111111  old.txt\n"
+	if err := c.Extend(types.ChecksumSHA256, []byte(existing)); err != nil {
+		t.Fatalf("设置已有清单失败: %v", err)
+	}
+
+	// 临时归档的记录器把摘要记录到上级记录器，自身不写入清单
+	scratch := c.Scratch()
+	record(t, scratch, "a.txt", "abc")
+	if scratch.Embedded() || len(scratch.Manifests()) != 1 || len(scratch.Manifests()[0].Data) != 0 {
+		t.Error("临时归档的记录器不应生成校验清单")
+	}
+
+	want := "ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad  a.txt\n" +
+		"1111111111111111111111111111111111111111111111111111111111111111  old.txt\n"
+	if got := string(c.Manifests()[0].Data); got != want {
+		t.Errorf("合并后的清单不正确:\n%s", got)
+	}
+	if !c.IsManifest("./SHA256SUMS") || c.IsManifest("dir/SHA256SUMS") {
+		t.Error("校验清单识别不正确")
+	}
+}
+
+func TestChecksums_Validate(t *testing.T) {
+	c := New()
+	c.Algorithms = []types.ChecksumAlgorithm{"md5"}
+	if err := c.Validate(); err == nil {
+		t.Error("不支持的算法应返回错误")
+	}
+	c.Algorithms = []types.ChecksumAlgorithm{types.ChecksumSHA256, types.ChecksumSHA256}
+	if err := c.Validate(); err == nil {
+		t.Error("重复的算法应返回错误")
+	}
+}
+
+func TestChecksums_Verify(t *testing.T) {
+	archivePath := filepath.Join(t.TempDir(), "a.tar")
+
+	// 打包: 记录摘要并写在压缩包旁边
+	packer := New()
+	packer.Algorithms = []types.ChecksumAlgorithm{types.ChecksumSHA256}
+	packer.Beside = true
+	record(t, packer, "a.txt", "hello")
+	record(t, packer, "b.txt", "world")
+	if err := packer.WriteBeside(archivePath); err != nil {
+		t.Fatalf("写入校验清单失败: %v", err)
+	}
+
+	// 解压: 内容一致时通过
+	unpacker := New()
+	unpacker.Algorithms = packer.Algorithms
+	record(t, unpacker, "a.txt", "hello")
+	record(t, unpacker, "b.txt", "world")
+	if err := unpacker.Verify(archivePath); err != nil {
+		t.Fatalf("校验失败: %v", err)
+	}
+
+	// 内容不一致时返回包含条目名称的错误
+	unpacker.Reset()
+	record(t, unpacker, "a.txt", "hello")
+	record(t, unpacker, "b.txt", "w0rld")
+	if err := unpacker.Verify(archivePath); err == nil || !strings.Contains(err.Error(), "b.txt") {
+		t.Errorf("摘要不一致时应返回包含条目名称的错误: %v", err)
+	}
+
+	// 不在清单中的条目
+	unpacker.Reset()
+	record(t, unpacker, "c.txt", "new")
+	if err := unpacker.Verify(archivePath); err == nil || !strings.Contains(err.Error(), "c.txt") {
+		t.Errorf("条目不在清单中时应返回错误: %v", err)
+	}
+
+	// 压缩包内的清单优先于旁边的清单
+	if err := os.WriteFile(BesidePath(archivePath, types.ChecksumSHA256), []byte("00  c.txt\n"), 0644); err != nil {
+		t.Fatalf("写入校验清单失败: %v", err)
+	}
+	unpacker.Reset()
+	record(t, unpacker, "a.txt", "hello")
+	record(t, unpacker, "b.txt", "world")
+	record(t, unpacker, "SHA256SUMS", string(packer.Manifests()[0].Data))
+	if err := unpacker.Verify(archivePath); err != nil {
+		t.Errorf("应使用压缩包内的校验清单: %v", err)
+	}
+
+	// 清单中的条目未被解压时返回错误
+	unpacker.Reset()
+	record(t, unpacker, "a.txt", "hello")
+	record(t, unpacker, "SHA256SUMS", string(packer.Manifests()[0].Data))
+	if err := unpacker.Verify(archivePath); err == nil || !strings.Contains(err.Error(), "b.txt") {
+		t.Errorf("清单中的条目未被解压时应返回错误: %v", err)
+	}
+
+	// 被过滤器跳过的条目不要求被解压
+	unpacker.Skip("./b.txt")
+	if err := unpacker.Verify(archivePath); err != nil {
+		t.Errorf("被跳过的条目不应导致校验失败: %v", err)
+	}
+}
+
+func TestParseManifest(t *testing.T) {
+	sums, err := ParseManifest([]byte("abcd  a.txt\r\n\nEF01 *dir/b c.txt\n"))
+	if err != nil {
+		t.Fatalf("解析失败: %v", err)
+	}
+	if sums["a.txt"] != "abcd" || sums["dir/b c.txt"] != "EF01" || len(sums) != 2 {
+		t.Errorf("解析结果不正确: %v", sums)
+	}
+
+	for _, bad := range []string{"abcd", "xyz  a.txt", "abcd\ta.txt"} {
+		if _, err := ParseManifest([]byte(bad)); err == nil {
+			t.Errorf("格式错误的清单 %q 应返回错误", bad)
+		}
+	}
+}
@@ -13,6 +13,16 @@
 //   - 进度显示配置
 //   - 文件过滤配置
 //   - 路径验证配置
+//   - 确定性打包配置
+//   - 校验清单配置
+//   - 签名验证配置
+//   - ZIP 加密配置
+//   - 加密信封配置
+//   - ZIP 文件名编码配置
+//   - ZIP 注释配置
+//   - 多成员 GZIP 解压方式配置
+//   - GZIP 文件头配置
+//   - BGZF 分块压缩配置
 //
 // 使用示例：
 //
@@ -28,7 +38,10 @@ package config
 
 import (
 	"compress/gzip"
+	"crypto/ed25519"
+	"time"
 
+	"gitee.com/MM-Q/comprx/internal/checksum"
 	"gitee.com/MM-Q/comprx/internal/progress"
 	"gitee.com/MM-Q/comprx/types"
 )
@@ -40,6 +53,21 @@ type Config struct {
 	Progress              *progress.Progress     // 进度显示
 	DisablePathValidation bool                   // 是否禁用路径验证
 	Filter                *types.FilterOptions   // 文件过滤配置
+	Deterministic         bool                   // 是否生成可重现(逐字节相同)的压缩包
+	SourceDateEpoch       time.Time              // 确定性模式下修改时间的上限
+	Checksums             *checksum.Checksums    // 校验清单(未启用算法时不计算)
+	RequireSignature      bool                   // 解压前是否必须通过签名验证
+	SignaturePublicKey    ed25519.PublicKey      // 验证签名使用的 Ed25519 公钥
+	Password              string                 // ZIP 加密和解密使用的密码
+	Encryption            types.EncryptionMethod // 打包 ZIP 时的加密方式
+	EncryptWith           types.EnvelopeOptions  // 加密信封选项(tar/tgz/gz/zlib)，解压时使用其中的口令
+	FilenameEncoding      types.FilenameEncoding // 读取 ZIP 时未设置 UTF-8 标志位的文件名编码
+	ArchiveComment        string                 // 打包 ZIP 时写入的压缩包注释
+	EntryComments         map[string]string      // 打包 ZIP 时写入的条目注释(键为压缩包内的条目名称)
+	GzipMembers           types.GzipMemberMode   // 解压多成员 GZIP 时拼接还是按成员拆分
+	GzipHeader            *types.GzipHeader      // 压缩 GZIP 时写入的文件头(为 nil 时使用源文件名和修改时间)
+	Bgzf                  bool                   // 压缩 GZIP 时是否写为 BGZF 分块格式
+	BgzfIndex             bool                   // 写为 BGZF 时是否同时生成 .gzi 索引
 }
 
 // New 创建新的压缩器配置
@@ -50,6 +78,11 @@ func New() *Config {
 		Progress:              progress.New(),                // 创建进度显示
 		DisablePathValidation: false,                         // 默认启用路径验证
 		Filter:                nil,                           // 初始化空过滤器(不启用过滤时为nil)
+		Deterministic:         false,                         // 默认保留原始元数据
+		SourceDateEpoch:       DefaultSourceDateEpoch,        // 默认时间上限
+		Checksums:             checksum.New(),                // 默认不生成校验清单
+		RequireSignature:      false,                         // 默认不验证签名
+		Encryption:            types.EncryptionNone,          // 默认不加密
 	}
 }
 
@@ -78,3 +111,16 @@ func GetCompressionLevel(level types.CompressionLevel) int {
 		return gzip.DefaultCompression
 	}
 }
+
+// ForScratch 返回打包追加条目的临时归档时使用的配置
+//
+// 临时归档不写入校验清单，新条目的摘要记录到原配置的校验清单记录器中，
+// 由追加操作合并到目标压缩包的校验清单。
+//
+// 返回值:
+//   - *Config - 临时归档使用的配置副本
+func (c *Config) ForScratch() *Config {
+	scratch := *c
+	scratch.Checksums = c.Checksums.Scratch()
+	return &scratch
+}
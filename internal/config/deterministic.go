@@ -0,0 +1,100 @@
+// Package config 提供可重现打包所需的元数据规范化功能。
+//
+// 该文件实现了确定性打包模式下的时间戳钳制、权限规范化，
+// 以及 SOURCE_DATE_EPOCH 环境变量的解析，使相同内容的多次打包产生逐字节相同的结果。
+//
+// 主要功能：
+//   - 解析 SOURCE_DATE_EPOCH 环境变量
+//   - 将修改时间钳制到指定的时间点
+//   - 将文件权限规范化为 0644/0755
+//
+// 使用示例：
+//
+//	epoch, err := config.ResolveSourceDateEpoch(time.Time{})
+//	cfg.Deterministic = true
+//	cfg.SourceDateEpoch = epoch
+package config
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SourceDateEpochEnv 可重现构建使用的时间戳环境变量名
+const SourceDateEpochEnv = "SOURCE_DATE_EPOCH"
+
+// DefaultSourceDateEpoch 未指定时间点且未设置环境变量时使用的默认时间（ZIP 可表示的最早时间）
+var DefaultSourceDateEpoch = time.Date(1980, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// GzipHeaderOSUnknown 确定性模式下 GZIP 文件头使用的操作系统标识（未知）
+const GzipHeaderOSUnknown byte = 255
+
+// ResolveSourceDateEpoch 解析确定性打包使用的时间点
+//
+// 参数:
+//   - epoch: 显式指定的时间点，零值时读取 SOURCE_DATE_EPOCH 环境变量
+//
+// 返回:
+//   - time.Time: 解析后的时间点（UTC，精确到秒）
+//   - error: 环境变量不是合法的 Unix 时间戳时返回错误
+func ResolveSourceDateEpoch(epoch time.Time) (time.Time, error) {
+	if !epoch.IsZero() {
+		return epoch.UTC().Truncate(time.Second), nil
+	}
+
+	value := strings.TrimSpace(os.Getenv(SourceDateEpochEnv))
+	if value == "" {
+		return DefaultSourceDateEpoch, nil
+	}
+
+	seconds, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || seconds < 0 {
+		return time.Time{}, fmt.Errorf("无效的 %s 环境变量: %q", SourceDateEpochEnv, value)
+	}
+	return time.Unix(seconds, 0).UTC(), nil
+}
+
+// ClampModTime 在确定性模式下将修改时间钳制到 SourceDateEpoch
+//
+// 参数:
+//   - modTime: 原始修改时间
+//
+// 返回:
+//   - time.Time: 非确定性模式下原样返回；否则返回不晚于 SourceDateEpoch 的时间（UTC，精确到秒），
+//     零值时间返回 SourceDateEpoch
+func (c *Config) ClampModTime(modTime time.Time) time.Time {
+	if !c.Deterministic {
+		return modTime
+	}
+	if modTime.IsZero() || modTime.After(c.SourceDateEpoch) {
+		return c.SourceDateEpoch
+	}
+	return modTime.UTC().Truncate(time.Second)
+}
+
+// NormalizeMode 在确定性模式下规范化文件权限
+//
+// 参数:
+//   - mode: 原始文件模式
+//
+// 返回:
+//   - fs.FileMode: 非确定性模式下原样返回；否则目录和可执行文件为 0755，
+//     符号链接为 0777，其他文件为 0644，文件类型位保持不变
+func (c *Config) NormalizeMode(mode fs.FileMode) fs.FileMode {
+	if !c.Deterministic {
+		return mode
+	}
+
+	switch {
+	case mode&fs.ModeSymlink != 0:
+		return mode.Type() | 0777
+	case mode.IsDir(), mode&0111 != 0:
+		return mode.Type() | 0755
+	default:
+		return mode.Type() | 0644
+	}
+}
@@ -0,0 +1,102 @@
+package config
+
+import (
+	"io/fs"
+	"testing"
+	"time"
+)
+
+// TestResolveSourceDateEpoch 测试确定性打包时间上限的解析
+func TestResolveSourceDateEpoch(t *testing.T) {
+	explicit := time.Date(2024, 5, 1, 12, 0, 0, 500, time.Local)
+
+	t.Run("显式指定", func(t *testing.T) {
+		t.Setenv(SourceDateEpochEnv, "1")
+		got, err := ResolveSourceDateEpoch(explicit)
+		if err != nil {
+			t.Fatalf("解析失败: %v", err)
+		}
+		if !got.Equal(explicit.Truncate(time.Second)) || got.Location() != time.UTC {
+			t.Errorf("期望 %v, 实际 %v", explicit.UTC().Truncate(time.Second), got)
+		}
+	})
+
+	t.Run("环境变量", func(t *testing.T) {
+		t.Setenv(SourceDateEpochEnv, "1700000000")
+		got, err := ResolveSourceDateEpoch(time.Time{})
+		if err != nil {
+			t.Fatalf("解析失败: %v", err)
+		}
+		if got.Unix() != 1700000000 {
+			t.Errorf("期望 1700000000, 实际 %d", got.Unix())
+		}
+	})
+
+	t.Run("默认值", func(t *testing.T) {
+		t.Setenv(SourceDateEpochEnv, "")
+		got, err := ResolveSourceDateEpoch(time.Time{})
+		if err != nil {
+			t.Fatalf("解析失败: %v", err)
+		}
+		if !got.Equal(DefaultSourceDateEpoch) {
+			t.Errorf("期望 %v, 实际 %v", DefaultSourceDateEpoch, got)
+		}
+	})
+
+	t.Run("无效环境变量", func(t *testing.T) {
+		t.Setenv(SourceDateEpochEnv, "yesterday")
+		if _, err := ResolveSourceDateEpoch(time.Time{}); err == nil {
+			t.Error("无效的环境变量应返回错误")
+		}
+	})
+}
+
+// TestConfig_ClampModTime 测试修改时间钳制
+func TestConfig_ClampModTime(t *testing.T) {
+	cfg := New()
+	epoch := time.Unix(1700000000, 0).UTC()
+	later := epoch.Add(time.Hour)
+	earlier := epoch.Add(-time.Hour + 300*time.Millisecond)
+
+	// 非确定性模式下原样返回
+	if got := cfg.ClampModTime(later); !got.Equal(later) {
+		t.Errorf("非确定性模式不应修改时间: %v", got)
+	}
+
+	cfg.Deterministic = true
+	cfg.SourceDateEpoch = epoch
+	if got := cfg.ClampModTime(later); !got.Equal(epoch) {
+		t.Errorf("晚于上限的时间应钳制为 %v, 实际 %v", epoch, got)
+	}
+	if got := cfg.ClampModTime(earlier); !got.Equal(earlier.Truncate(time.Second)) {
+		t.Errorf("早于上限的时间应保留到秒, 实际 %v", got)
+	}
+	if got := cfg.ClampModTime(time.Time{}); !got.Equal(epoch) {
+		t.Errorf("零值时间应使用上限, 实际 %v", got)
+	}
+}
+
+// TestConfig_NormalizeMode 测试权限规范化
+func TestConfig_NormalizeMode(t *testing.T) {
+	cfg := New()
+	if got := cfg.NormalizeMode(0600); got != 0600 {
+		t.Errorf("非确定性模式不应修改权限: %v", got)
+	}
+
+	cfg.Deterministic = true
+	tests := []struct {
+		mode fs.FileMode
+		want fs.FileMode
+	}{
+		{0600, 0644},
+		{0700, 0755},
+		{0664, 0644},
+		{fs.ModeDir | 0700, fs.ModeDir | 0755},
+		{fs.ModeSymlink | 0755, fs.ModeSymlink | 0777},
+	}
+	for _, tt := range tests {
+		if got := cfg.NormalizeMode(tt.mode); got != tt.want {
+			t.Errorf("NormalizeMode(%v) = %v, 期望 %v", tt.mode, got, tt.want)
+		}
+	}
+}
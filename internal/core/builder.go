@@ -0,0 +1,146 @@
+// Package core 提供压缩包构建器的统一创建入口。
+//
+// 该文件定义了压缩包构建器接口，并根据压缩格式创建对应的构建器，
+// 支持写入到文件或任意 io.Writer。
+//
+// 主要类型：
+//   - ArchiveBuilder: 压缩包构建器接口
+//
+// 主要功能：
+//   - 根据目标文件扩展名创建构建器
+//   - 根据指定格式创建写入到 io.Writer 的构建器
+//
+// 使用示例：
+//
+//	builder, err := comprx.NewBuilder("output.zip")
+//	err = builder.AddFile("hello.txt", strings.NewReader("hello"), types.FileInfo{})
+//	err = builder.Close()
+package core
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"gitee.com/MM-Q/comprx/internal/cxtar"
+	"gitee.com/MM-Q/comprx/internal/cxtgz"
+	"gitee.com/MM-Q/comprx/internal/cxzip"
+	"gitee.com/MM-Q/comprx/internal/utils"
+	"gitee.com/MM-Q/comprx/types"
+)
+
+// ArchiveBuilder 压缩包构建器接口
+//
+// 支持逐个添加文件、目录和符号链接，使用完毕后必须调用 Close。
+type ArchiveBuilder interface {
+	utils.ArchiveEntryWriter
+	// Close 完成压缩包写入
+	Close() error
+}
+
+// fileBuilder 写入到文件的构建器，关闭时同时关闭文件
+type fileBuilder struct {
+	ArchiveBuilder
+	file *os.File
+}
+
+// Close 完成压缩包写入并关闭文件
+//
+// 返回:
+//   - error: 关闭失败时返回错误
+func (b *fileBuilder) Close() error {
+	err := b.ArchiveBuilder.Close()
+	if closeErr := b.file.Close(); err == nil && closeErr != nil {
+		err = fmt.Errorf("关闭压缩包文件失败: %w", closeErr)
+	}
+	return err
+}
+
+// NewBuilder 创建写入到目标文件的压缩包构建器
+//
+// 参数:
+//   - dst: 目标文件路径（支持 .zip、.tar、.tgz、.tar.gz）
+//
+// 返回:
+//   - ArchiveBuilder: 压缩包构建器
+//   - error: 错误信息
+func (c *Comprx) NewBuilder(dst string) (ArchiveBuilder, error) {
+	// 检查参数
+	if dst == "" {
+		return nil, fmt.Errorf("目标文件路径不能为空")
+	}
+
+	// 检测压缩格式并准备目标路径
+	compressType, err := c.preparePackTarget(dst)
+	if err != nil {
+		return nil, err
+	}
+	if !isArchiveType(compressType) {
+		return nil, fmt.Errorf("%s 格式只支持单文件压缩，不支持构建压缩包", compressType)
+	}
+
+	// 创建目标文件
+	file, err := os.Create(dst)
+	if err != nil {
+		return nil, fmt.Errorf("创建压缩包文件失败: %w", err)
+	}
+
+	builder, err := c.NewBuilderTo(file, compressType)
+	if err != nil {
+		_ = file.Close()
+		return nil, err
+	}
+	return &fileBuilder{ArchiveBuilder: builder, file: file}, nil
+}
+
+// NewBuilderTo 创建写入到 w 的压缩包构建器
+//
+// 参数:
+//   - w: 压缩包输出目标
+//   - format: 压缩格式（支持 zip、tar、tgz、tar.gz）
+//
+// 返回:
+//   - ArchiveBuilder: 压缩包构建器
+//   - error: 错误信息
+//
+// 注意:
+//   - Close 不会关闭 w
+func (c *Comprx) NewBuilderTo(w io.Writer, format types.CompressType) (ArchiveBuilder, error) {
+	if w == nil {
+		return nil, fmt.Errorf("输出目标不能为空")
+	}
+
+	switch format {
+	case types.CompressTypeZip: // Zip
+		return cxzip.NewBuilder(w, c.Config), nil
+
+	case types.CompressTypeTar: // Tar
+		return cxtar.NewBuilder(w, c.Config), nil
+
+	case types.CompressTypeTgz, types.CompressTypeTarGz: // Tar.gz 或 .tgz
+		builder, err := cxtgz.NewBuilder(w, c.Config)
+		if err != nil {
+			return nil, err
+		}
+		return builder, nil
+
+	default:
+		return nil, fmt.Errorf("%s 格式只支持单文件压缩，不支持构建压缩包", format)
+	}
+}
+
+// isArchiveType 检查压缩格式是否为可包含多个条目的归档格式
+//
+// 参数:
+//   - compressType: 压缩格式
+//
+// 返回:
+//   - bool: 是归档格式返回 true
+func isArchiveType(compressType types.CompressType) bool {
+	switch compressType {
+	case types.CompressTypeZip, types.CompressTypeTar, types.CompressTypeTgz, types.CompressTypeTarGz:
+		return true
+	default:
+		return false
+	}
+}
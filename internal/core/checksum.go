@@ -0,0 +1,125 @@
+// Package core 提供校验清单的格式检查和收尾处理。
+//
+// 该文件实现了打包和解压时与校验清单相关的公共逻辑：检查压缩格式是否支持校验清单、
+// 在每次操作开始时清空上一次记录的摘要、追加条目前读取已有的校验清单。
+//
+// 主要功能：
+//   - 检查校验清单配置与压缩格式是否匹配
+//   - 读取已有压缩包的校验清单，供追加时合并新条目的摘要
+//
+// 使用示例：
+//
+//	comprx := core.New()
+//	comprx.Config.Checksums.Algorithms = []types.ChecksumAlgorithm{types.ChecksumSHA256}
+//	err := comprx.Pack("release.tgz", "dist")
+package core
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"gitee.com/MM-Q/comprx/internal/checksum"
+	"gitee.com/MM-Q/comprx/internal/cxtar"
+	"gitee.com/MM-Q/comprx/internal/cxtgz"
+	"gitee.com/MM-Q/comprx/internal/cxzip"
+	"gitee.com/MM-Q/comprx/types"
+)
+
+// prepareChecksums 检查校验清单配置并清空上一次记录的摘要
+//
+// 参数:
+//   - compressType: 压缩格式
+//
+// 返回:
+//   - error: 启用了校验清单但压缩格式不是归档格式时返回错误
+func (c *Comprx) prepareChecksums(compressType types.CompressType) error {
+	if !c.Config.Checksums.Enabled() {
+		return nil
+	}
+	if !isArchiveType(compressType) {
+		return fmt.Errorf("%s 格式只支持单文件压缩，不支持校验清单", compressType)
+	}
+	c.Config.Checksums.Reset()
+	return nil
+}
+
+// loadManifests 读取已有压缩包的校验清单，追加后生成的清单在其基础上合并新条目的摘要
+//
+// 参数:
+//   - archivePath: 已有的压缩包路径
+//   - compressType: 压缩格式
+//
+// 返回:
+//   - error: 压缩包没有对应的校验清单或读取失败时返回错误
+func (c *Comprx) loadManifests(archivePath string, compressType types.CompressType) error {
+	sums := c.Config.Checksums
+	if !sums.Enabled() {
+		return nil
+	}
+
+	// 读取压缩包内的校验清单
+	found := make(map[types.ChecksumAlgorithm][]byte)
+	if !sums.Beside {
+		collector := &manifestCollector{sums: sums, found: found}
+		var err error
+		switch compressType {
+		case types.CompressTypeZip: // Zip
+			err = cxzip.WriteEntriesTo(archivePath, collector, nil, c.Config.Password, c.Config.FilenameEncoding)
+		case types.CompressTypeTar: // Tar
+			err = cxtar.WriteFileEntriesTo(archivePath, collector, nil)
+		case types.CompressTypeTgz, types.CompressTypeTarGz: // Tar.gz 或 .tgz
+			err = cxtgz.WriteEntriesTo(archivePath, collector, nil)
+		default:
+			err = fmt.Errorf("%s 格式不支持校验清单", compressType)
+		}
+		if err != nil {
+			return fmt.Errorf("读取已有校验清单失败: %w", err)
+		}
+	}
+
+	for _, algorithm := range sums.Algorithms {
+		data, ok := found[algorithm]
+		if sums.Beside {
+			path := checksum.BesidePath(archivePath, algorithm)
+			besideData, err := os.ReadFile(path)
+			if err != nil {
+				return fmt.Errorf("读取已有校验清单 %s 失败: %w", path, err)
+			}
+			data, ok = besideData, true
+		}
+		if !ok {
+			return fmt.Errorf("压缩包 %s 中没有 %s，无法在追加时更新校验清单", archivePath, algorithm.ManifestName())
+		}
+		if err := sums.Extend(algorithm, data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// manifestCollector 只收集压缩包根目录下校验清单内容的条目写入器
+type manifestCollector struct {
+	sums  *checksum.Checksums                // 摘要记录器，用于识别校验清单
+	found map[types.ChecksumAlgorithm][]byte // 摘要算法 -> 清单内容
+}
+
+// AddFile 读取校验清单的内容，其他文件直接忽略
+func (m *manifestCollector) AddFile(name string, r io.Reader, _ types.FileInfo) error {
+	algorithm, ok := m.sums.ManifestAlgorithm(name)
+	if !ok {
+		return nil
+	}
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return fmt.Errorf("读取校验清单 %s 失败: %w", name, err)
+	}
+	m.found[algorithm] = data
+	return nil
+}
+
+// AddDir 忽略目录
+func (m *manifestCollector) AddDir(string, types.FileInfo) error { return nil }
+
+// AddSymlink 忽略符号链接
+func (m *manifestCollector) AddSymlink(string, string, types.FileInfo) error { return nil }
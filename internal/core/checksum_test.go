@@ -0,0 +1,231 @@
+package core
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gitee.com/MM-Q/comprx/internal/checksum"
+	"gitee.com/MM-Q/comprx/types"
+)
+
+func TestComprx_Checksums_RoundTrip(t *testing.T) {
+	tempDir := t.TempDir()
+
+	for _, name := range []string{"sums.zip", "sums.tar", "sums.tgz"} {
+		c := New()
+		c.Config.Checksums.Algorithms = []types.ChecksumAlgorithm{types.ChecksumSHA256, types.ChecksumBLAKE2b}
+
+		dst := filepath.Join(tempDir, name)
+		packVerifySource(t, c, dst)
+
+		outDir := filepath.Join(tempDir, strings.ReplaceAll(name, ".", "_"))
+		if err := c.Unpack(dst, outDir); err != nil {
+			t.Fatalf("解压 %s 并校验失败: %v", name, err)
+		}
+
+		// 清单写在压缩包根目录
+		data, err := os.ReadFile(filepath.Join(outDir, "SHA256SUMS"))
+		if err != nil {
+			t.Fatalf("%s 中缺少 SHA256SUMS: %v", name, err)
+		}
+		sums, err := checksum.ParseManifest(data)
+		if err != nil || len(sums) != 2 || sums["data/a.txt"] == "" || sums["data/b.txt"] == "" {
+			t.Errorf("%s 的 SHA256SUMS 内容不正确: %q, %v", name, data, err)
+		}
+		if _, err := os.Stat(filepath.Join(outDir, "B2SUMS")); err != nil {
+			t.Errorf("%s 中缺少 B2SUMS: %v", name, err)
+		}
+	}
+}
+
+func TestComprx_Checksums_Mismatch(t *testing.T) {
+	tempDir := t.TempDir()
+	c := New()
+	c.Config.Checksums.Algorithms = []types.ChecksumAlgorithm{types.ChecksumSHA512}
+
+	tarFile := filepath.Join(tempDir, "bad.tar")
+	data := packVerifySource(t, c, tarFile)
+
+	// TAR 没有数据校验，篡改 b.txt 的内容后只有校验清单能发现
+	index := bytes.Index(data, []byte(strings.Repeat("b", 64)))
+	if index < 0 {
+		t.Fatal("未找到 b.txt 的数据")
+	}
+	data[index] = 'x'
+	if err := os.WriteFile(tarFile, data, 0644); err != nil {
+		t.Fatalf("写入TAR文件失败: %v", err)
+	}
+
+	err := c.Unpack(tarFile, filepath.Join(tempDir, "out"))
+	if err == nil || !strings.Contains(err.Error(), "data/b.txt") {
+		t.Errorf("摘要不一致时应返回包含条目名称的错误: %v", err)
+	}
+}
+
+func TestComprx_Checksums_Beside(t *testing.T) {
+	tempDir := t.TempDir()
+	c := New()
+	c.Config.Checksums.Algorithms = []types.ChecksumAlgorithm{types.ChecksumSHA256}
+	c.Config.Checksums.Beside = true
+
+	zipFile := filepath.Join(tempDir, "beside.zip")
+	packVerifySource(t, c, zipFile)
+
+	manifest := zipFile + ".SHA256SUMS"
+	if _, err := os.Stat(manifest); err != nil {
+		t.Fatalf("压缩包旁边缺少校验清单: %v", err)
+	}
+
+	outDir := filepath.Join(tempDir, "out")
+	if err := c.Unpack(zipFile, outDir); err != nil {
+		t.Fatalf("使用旁边的校验清单校验失败: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(outDir, "SHA256SUMS")); !os.IsNotExist(err) {
+		t.Errorf("清单写在旁边时压缩包内不应包含 SHA256SUMS: %v", err)
+	}
+
+	// 删除清单后无法校验
+	if err := os.Remove(manifest); err != nil {
+		t.Fatalf("删除校验清单失败: %v", err)
+	}
+	c.Config.OverwriteExisting = true
+	if err := c.Unpack(zipFile, outDir); err == nil {
+		t.Error("找不到校验清单时应返回错误")
+	}
+}
+
+func TestComprx_Checksums_Coverage(t *testing.T) {
+	tempDir := t.TempDir()
+	c := New()
+	c.Config.Checksums.Algorithms = []types.ChecksumAlgorithm{types.ChecksumSHA256}
+	c.Config.Checksums.Beside = true
+	c.Config.OverwriteExisting = true
+
+	tgzFile := filepath.Join(tempDir, "coverage.tgz")
+	packVerifySource(t, c, tgzFile)
+
+	// 只解压部分条目时，被过滤器跳过的清单条目不影响校验
+	c.Config.Filter = &types.FilterOptions{Exclude: []string{"b.txt"}}
+	if err := c.Unpack(tgzFile, filepath.Join(tempDir, "partial")); err != nil {
+		t.Fatalf("过滤后解压并校验失败: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(tempDir, "partial", "data", "b.txt")); !os.IsNotExist(err) {
+		t.Errorf("data/b.txt 应被过滤: %v", err)
+	}
+	c.Config.Filter = nil
+
+	// 清单中有压缩包里不存在的条目时返回错误
+	manifest := checksum.BesidePath(tgzFile, types.ChecksumSHA256)
+	data, err := os.ReadFile(manifest)
+	if err != nil {
+		t.Fatalf("读取校验清单失败: %v", err)
+	}
+	data = append(data, []byte(strings.Repeat("0", 64)+"  data/c.txt\n")...)
+	if err := os.WriteFile(manifest, data, 0644); err != nil {
+		t.Fatalf("写入校验清单失败: %v", err)
+	}
+	err = c.Unpack(tgzFile, filepath.Join(tempDir, "full"))
+	if err == nil || !strings.Contains(err.Error(), "data/c.txt") {
+		t.Errorf("清单中的条目未被解压时应返回错误: %v", err)
+	}
+}
+
+func TestComprx_Checksums_SingleFileFormat(t *testing.T) {
+	tempDir := t.TempDir()
+	c := New()
+	c.Config.Checksums.Algorithms = []types.ChecksumAlgorithm{types.ChecksumSHA256}
+
+	src := filepath.Join(tempDir, "a.txt")
+	if err := os.WriteFile(src, []byte("hello"), 0644); err != nil {
+		t.Fatalf("创建测试文件失败: %v", err)
+	}
+	if err := c.Pack(filepath.Join(tempDir, "a.txt.gz"), src); err == nil {
+		t.Error("GZIP 格式启用校验清单时应返回错误")
+	}
+}
+
+func TestComprx_Checksums_Append(t *testing.T) {
+	tempDir := t.TempDir()
+
+	extra := filepath.Join(tempDir, "extra.txt")
+	if err := os.WriteFile(extra, []byte("extra"), 0644); err != nil {
+		t.Fatalf("创建测试文件失败: %v", err)
+	}
+	later := filepath.Join(tempDir, "later.txt")
+	if err := os.WriteFile(later, []byte("later"), 0644); err != nil {
+		t.Fatalf("创建测试文件失败: %v", err)
+	}
+
+	for _, name := range []string{"append.zip", "append.tar", "append.tgz"} {
+		c := New()
+		c.Config.Checksums.Algorithms = []types.ChecksumAlgorithm{types.ChecksumSHA256}
+
+		dst := filepath.Join(tempDir, name)
+		packVerifySource(t, c, dst)
+
+		// 追加两次，默认选项下不应与已有清单冲突
+		if err := c.Append(dst, extra); err != nil {
+			t.Fatalf("向 %s 追加失败: %v", name, err)
+		}
+		if err := c.Append(dst, later); err != nil {
+			t.Fatalf("向 %s 再次追加失败: %v", name, err)
+		}
+
+		// 覆盖已有条目时清单中的摘要随之更新
+		if err := os.WriteFile(extra, []byte("extra v2"), 0644); err != nil {
+			t.Fatalf("更新测试文件失败: %v", err)
+		}
+		c.Config.OverwriteExisting = true
+		if err := c.Append(dst, extra); err != nil {
+			t.Fatalf("向 %s 覆盖追加失败: %v", name, err)
+		}
+		_ = os.WriteFile(extra, []byte("extra"), 0644)
+
+		outDir := filepath.Join(tempDir, strings.ReplaceAll(name, ".", "_"))
+		if err := c.Unpack(dst, outDir); err != nil {
+			t.Fatalf("解压 %s 并校验失败: %v", name, err)
+		}
+		data, err := os.ReadFile(filepath.Join(outDir, "SHA256SUMS"))
+		if err != nil {
+			t.Fatalf("%s 中缺少 SHA256SUMS: %v", name, err)
+		}
+		sums, err := checksum.ParseManifest(data)
+		if err != nil || len(sums) != 4 || sums["extra.txt"] == "" || sums["later.txt"] == "" {
+			t.Errorf("%s 的 SHA256SUMS 内容不正确: %q, %v", name, data, err)
+		}
+	}
+}
+
+func TestComprx_Checksums_AppendBeside(t *testing.T) {
+	tempDir := t.TempDir()
+	c := New()
+	c.Config.Checksums.Algorithms = []types.ChecksumAlgorithm{types.ChecksumSHA256}
+	c.Config.Checksums.Beside = true
+
+	tgzFile := filepath.Join(tempDir, "beside.tgz")
+	packVerifySource(t, c, tgzFile)
+
+	extra := filepath.Join(tempDir, "extra.txt")
+	if err := os.WriteFile(extra, []byte("extra"), 0644); err != nil {
+		t.Fatalf("创建测试文件失败: %v", err)
+	}
+	if err := c.Append(tgzFile, extra); err != nil {
+		t.Fatalf("追加失败: %v", err)
+	}
+	if err := c.Unpack(tgzFile, filepath.Join(tempDir, "out")); err != nil {
+		t.Fatalf("解压并校验失败: %v", err)
+	}
+
+	// 压缩包没有校验清单时不能在追加时更新清单
+	plain := New()
+	plainFile := filepath.Join(tempDir, "plain.tgz")
+	packVerifySource(t, plain, plainFile)
+	plain.Config.Checksums.Algorithms = []types.ChecksumAlgorithm{types.ChecksumSHA256}
+	err := plain.Append(plainFile, extra)
+	if err == nil || !strings.Contains(err.Error(), "SHA256SUMS") {
+		t.Errorf("压缩包没有校验清单时应返回错误: %v", err)
+	}
+}
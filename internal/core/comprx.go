@@ -35,6 +35,7 @@ package core
 
 import (
 	"fmt"
+	"os"
 	"path/filepath"
 	"strings"
 
@@ -45,6 +46,7 @@ import (
 	"gitee.com/MM-Q/comprx/internal/cxtgz"
 	"gitee.com/MM-Q/comprx/internal/cxzip"
 	"gitee.com/MM-Q/comprx/internal/cxzlib"
+	"gitee.com/MM-Q/comprx/internal/signature"
 	"gitee.com/MM-Q/comprx/internal/utils"
 	"gitee.com/MM-Q/comprx/types"
 )
@@ -85,50 +87,147 @@ func (c *Comprx) Pack(dst string, src string) error {
 		return fmt.Errorf("源文件路径或目标文件路径不能为空")
 	}
 
-	// 智能检测压缩文件格式
-	compressType, err := types.DetectCompressFormat(dst)
+	// 检测压缩格式并准备目标路径
+	compressType, err := c.preparePackTarget(dst)
 	if err != nil {
-		return fmt.Errorf("检测压缩格式失败: %v", err)
-	}
-
-	// 检查是否为.bz2格式的压缩文件，暂不支持
-	if compressType == types.CompressTypeBz2 || compressType == types.CompressTypeBzip2 {
-		return fmt.Errorf("暂不支持 %s 和 %s 格式的压缩文件", types.CompressTypeBz2.String(), types.CompressTypeBzip2.String())
+		return err
 	}
 
-	// 检查目标文件是否存在
-	if utils.Exists(dst) {
-		if !c.Config.OverwriteExisting {
-			return fmt.Errorf("文件 %s 已存在，如需覆盖请设置 OverwriteExisting 为 true", dst)
-		}
-	}
-
-	// 检查目标目录是否存在, 不存在则创建
-	targetDir := filepath.Dir(dst)
-	if err := utils.EnsureDir(targetDir); err != nil {
-		return fmt.Errorf("创建目标目录失败: %v", err)
+	// 校验清单只支持归档格式
+	if err := c.prepareChecksums(compressType); err != nil {
+		return err
 	}
 
 	// 根据压缩格式进行打包
 	switch compressType {
 	case types.CompressTypeZip: // Zip
-		return cxzip.Zip(dst, src, c.Config)
+		err = cxzip.Zip(dst, src, c.Config)
 
 	case types.CompressTypeTar: // Tar
-		return cxtar.Tar(dst, src, c.Config)
+		err = cxtar.Tar(dst, src, c.Config)
 
 	case types.CompressTypeTgz, types.CompressTypeTarGz: // Tar.gz 或 .tgz
-		return cxtgz.Tgz(dst, src, c.Config)
+		err = cxtgz.Tgz(dst, src, c.Config)
 
 	case types.CompressTypeGz: // Gz
-		return cxgzip.Gzip(dst, src, c.Config)
+		err = cxgzip.Gzip(dst, src, c.Config)
 
 	case types.CompressTypeZlib: // Zlib
-		return cxzlib.Zlib(dst, src, c.Config)
+		err = cxzlib.Zlib(dst, src, c.Config)
 
 	default:
 		return fmt.Errorf("不支持的压缩格式: %s", compressType)
 	}
+	if err != nil {
+		return err
+	}
+
+	// 按配置将校验清单写在压缩包旁边
+	return c.Config.Checksums.WriteBeside(dst)
+}
+
+// PackSources 将多个源压缩到同一个压缩包
+//
+// 参数:
+//   - dst: 目标文件路径
+//   - sources: 打包源列表，每个源可以映射到压缩包内的任意位置
+//
+// 返回:
+//   - error: 错误信息
+//
+// 注意:
+//   - 仅支持 ZIP、TAR、TGZ 等归档格式，GZIP 和 ZLIB 只支持单文件压缩
+func (c *Comprx) PackSources(dst string, sources []types.Source) error {
+	// 检查参数
+	if dst == "" {
+		return fmt.Errorf("目标文件路径不能为空")
+	}
+	if len(sources) == 0 {
+		return fmt.Errorf("打包源列表不能为空")
+	}
+
+	// 检测压缩格式并准备目标路径
+	compressType, err := c.preparePackTarget(dst)
+	if err != nil {
+		return err
+	}
+
+	// 校验清单只支持归档格式
+	if err := c.prepareChecksums(compressType); err != nil {
+		return err
+	}
+
+	// 根据压缩格式进行打包
+	switch compressType {
+	case types.CompressTypeZip: // Zip
+		err = cxzip.ZipSources(dst, sources, c.Config)
+
+	case types.CompressTypeTar: // Tar
+		err = cxtar.TarSources(dst, sources, c.Config)
+
+	case types.CompressTypeTgz, types.CompressTypeTarGz: // Tar.gz 或 .tgz
+		err = cxtgz.TgzSources(dst, sources, c.Config)
+
+	default:
+		return fmt.Errorf("%s 格式只支持单文件压缩，不支持多源打包", compressType)
+	}
+	if err != nil {
+		return err
+	}
+
+	// 按配置将校验清单写在压缩包旁边
+	return c.Config.Checksums.WriteBeside(dst)
+}
+
+// preparePackTarget 检测目标压缩格式并准备目标路径
+//
+// 参数:
+//   - dst: 目标文件路径
+//
+// 返回:
+//   - types.CompressType: 检测到的压缩格式
+//   - error: 错误信息
+func (c *Comprx) preparePackTarget(dst string) (types.CompressType, error) {
+	// 智能检测压缩文件格式
+	compressType, err := types.DetectCompressFormat(dst)
+	if err != nil {
+		return "", fmt.Errorf("检测压缩格式失败: %v", err)
+	}
+
+	// 检查是否为.bz2格式的压缩文件，暂不支持
+	if compressType == types.CompressTypeBz2 || compressType == types.CompressTypeBzip2 {
+		return "", fmt.Errorf("暂不支持 %s 和 %s 格式的压缩文件", types.CompressTypeBz2.String(), types.CompressTypeBzip2.String())
+	}
+
+	// 条目加密只支持 ZIP 格式
+	if c.Config.Encryption != types.EncryptionNone && compressType != types.CompressTypeZip {
+		return "", fmt.Errorf("%s 加密只支持 ZIP 格式，不支持 %s", c.Config.Encryption, compressType)
+	}
+
+	// 加密信封用于本身不支持加密的格式，ZIP 请使用条目加密
+	if c.Config.EncryptWith.Enabled() && compressType == types.CompressTypeZip {
+		return "", fmt.Errorf("ZIP 格式不支持加密信封，请使用 Encryption 加密条目")
+	}
+
+	// 注释只支持 ZIP 格式
+	if (c.Config.ArchiveComment != "" || len(c.Config.EntryComments) > 0) && compressType != types.CompressTypeZip {
+		return "", fmt.Errorf("%s 格式不支持注释，压缩包注释和条目注释只支持 ZIP 格式", compressType)
+	}
+
+	// 检查目标文件是否存在
+	if utils.Exists(dst) {
+		if !c.Config.OverwriteExisting {
+			return "", fmt.Errorf("文件 %s 已存在，如需覆盖请设置 OverwriteExisting 为 true", dst)
+		}
+	}
+
+	// 检查目标目录是否存在, 不存在则创建
+	targetDir := filepath.Dir(dst)
+	if err := utils.EnsureDir(targetDir); err != nil {
+		return "", fmt.Errorf("创建目标目录失败: %v", err)
+	}
+
+	return compressType, nil
 }
 
 // ==============================================
@@ -160,6 +259,17 @@ func (c *Comprx) Unpack(src string, dst string) error {
 		return fmt.Errorf("源文件 %s 不存在", src)
 	}
 
+	// 要求签名时，未签名或被篡改的压缩包拒绝解压；解压验证过的私有副本，避免验证后压缩包被替换
+	archivePath := src
+	if c.Config.RequireSignature {
+		verified, cleanup, err := c.verifiedCopy(src)
+		if err != nil {
+			return fmt.Errorf("拒绝解压: %w", err)
+		}
+		defer cleanup()
+		archivePath = verified
+	}
+
 	// 当目标目录为空时，自动生成目标目录, 如: /path/to/file.tar.gz -> /path/to/file
 	if dst == "" {
 		baseName := filepath.Base(src)
@@ -168,6 +278,11 @@ func (c *Comprx) Unpack(src string, dst string) error {
 		dst = filepath.Join(filepath.Dir(src), baseName)
 	}
 
+	// 校验清单只支持归档格式
+	if err := c.prepareChecksums(compressType); err != nil {
+		return err
+	}
+
 	// 检查目标目录是否存在, 不存在则创建
 	if err := utils.EnsureDir(dst); err != nil {
 		return fmt.Errorf("创建目标目录失败: %v", err)
@@ -176,24 +291,66 @@ func (c *Comprx) Unpack(src string, dst string) error {
 	// 根据压缩格式进行解压
 	switch compressType {
 	case types.CompressTypeZip: // Zip
-		return cxzip.Unzip(src, dst, c.Config)
+		err = cxzip.Unzip(archivePath, dst, c.Config)
 
 	case types.CompressTypeTar: // Tar
-		return cxtar.Untar(src, dst, c.Config)
+		err = cxtar.Untar(archivePath, dst, c.Config)
 
 	case types.CompressTypeTgz, types.CompressTypeTarGz: // Tgz, TarGz
-		return cxtgz.Untgz(src, dst, c.Config)
+		err = cxtgz.Untgz(archivePath, dst, c.Config)
 
 	case types.CompressTypeGz: // Gzip
-		return cxgzip.Ungzip(src, dst, c.Config)
+		err = cxgzip.Ungzip(archivePath, dst, c.Config)
 
 	case types.CompressTypeBz2, types.CompressTypeBzip2: // Bz2, Bzip2
-		return cxbzip2.Unbz2(src, dst, c.Config)
+		err = cxbzip2.Unbz2(archivePath, dst, c.Config)
 
 	case types.CompressTypeZlib: // Zlib
-		return cxzlib.Unzlib(src, dst, c.Config)
+		err = cxzlib.Unzlib(archivePath, dst, c.Config)
 
 	default:
 		return fmt.Errorf("不支持的压缩格式: %s", compressType)
 	}
+	if err != nil {
+		return err
+	}
+
+	// 将解压出的文件与校验清单比对
+	return c.Config.Checksums.Verify(src)
+}
+
+// verifiedCopy 验证压缩包签名，同时将压缩包复制到私有临时目录
+//
+// 签名验证和复制使用同一次读取，副本与验证过的内容一致，
+// 之后从副本解压，即使原压缩包在验证后被替换也不会解压未验证的数据。
+//
+// 参数:
+//   - src: 压缩包路径
+//
+// 返回:
+//   - string: 副本路径，文件名与原压缩包相同
+//   - func(): 删除副本的清理函数
+//   - error: 复制失败或签名验证失败时返回错误
+func (c *Comprx) verifiedCopy(src string) (string, func(), error) {
+	copyPath, cleanup, err := utils.NewScratchPath(filepath.Base(src))
+	if err != nil {
+		return "", nil, err
+	}
+
+	file, err := os.OpenFile(copyPath, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		cleanup()
+		return "", nil, fmt.Errorf("创建压缩包副本失败: %w", err)
+	}
+	verifyErr := signature.VerifyTo(src, signature.Path(src), c.Config.SignaturePublicKey, file)
+	closeErr := file.Close()
+	if verifyErr != nil {
+		cleanup()
+		return "", nil, verifyErr
+	}
+	if closeErr != nil {
+		cleanup()
+		return "", nil, fmt.Errorf("写入压缩包副本失败: %w", closeErr)
+	}
+	return copyPath, cleanup, nil
 }
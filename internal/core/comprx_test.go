@@ -3,6 +3,7 @@ package core
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"gitee.com/MM-Q/comprx/types"
@@ -404,3 +405,173 @@ func BenchmarkNew(b *testing.B) {
 		_ = New()
 	}
 }
+
+// TestComprx_PackSources 测试多源打包的格式分发
+func TestComprx_PackSources(t *testing.T) {
+	tempDir := t.TempDir()
+
+	srcFile := filepath.Join(tempDir, "VERSION")
+	if err := os.WriteFile(srcFile, []byte("1.2.0"), 0644); err != nil {
+		t.Fatalf("创建测试文件失败: %v", err)
+	}
+	sources := []types.Source{{Path: srcFile, ArchivePath: "app/VERSION"}}
+
+	c := New()
+	if err := c.PackSources(filepath.Join(tempDir, "out.zip"), sources); err != nil {
+		t.Errorf("ZIP 多源打包失败: %v", err)
+	}
+
+	// GZIP 只支持单文件压缩
+	if err := c.PackSources(filepath.Join(tempDir, "out.gz"), sources); err == nil {
+		t.Error("GZIP 格式不支持多源打包，应返回错误")
+	}
+
+	// 空源列表
+	if err := c.PackSources(filepath.Join(tempDir, "empty.zip"), nil); err == nil {
+		t.Error("空源列表应返回错误")
+	}
+}
+
+// TestComprx_NewBuilder 测试压缩包构建器的创建
+func TestComprx_NewBuilder(t *testing.T) {
+	tempDir := t.TempDir()
+	c := New()
+
+	dst := filepath.Join(tempDir, "built.tgz")
+	builder, err := c.NewBuilder(dst)
+	if err != nil {
+		t.Fatalf("创建构建器失败: %v", err)
+	}
+	if err := builder.AddFile("hello.txt", strings.NewReader("hello"), types.FileInfo{}); err != nil {
+		t.Fatalf("添加文件失败: %v", err)
+	}
+	if err := builder.Close(); err != nil {
+		t.Fatalf("关闭构建器失败: %v", err)
+	}
+
+	info, err := List(dst)
+	if err != nil {
+		t.Fatalf("列出压缩包内容失败: %v", err)
+	}
+	if info.TotalFiles != 1 || info.Files[0].Name != "hello.txt" {
+		t.Errorf("压缩包内容不匹配: %+v", info.Files)
+	}
+
+	// GZIP 只支持单文件压缩
+	if _, err := c.NewBuilder(filepath.Join(tempDir, "out.gz")); err == nil {
+		t.Error("GZIP 格式不支持构建压缩包，应返回错误")
+	}
+}
+
+func TestComprx_Append(t *testing.T) {
+	tempDir := t.TempDir()
+	c := New()
+
+	first := filepath.Join(tempDir, "a.txt")
+	second := filepath.Join(tempDir, "b.txt")
+	for _, path := range []string{first, second} {
+		if err := os.WriteFile(path, []byte(filepath.Base(path)), 0644); err != nil {
+			t.Fatalf("创建测试文件失败: %v", err)
+		}
+	}
+
+	for _, name := range []string{"out.zip", "out.tar", "out.tar.gz"} {
+		dst := filepath.Join(tempDir, name)
+		if err := c.Pack(dst, first); err != nil {
+			t.Fatalf("压缩 %s 失败: %v", name, err)
+		}
+		if err := c.Append(dst, second); err != nil {
+			t.Fatalf("追加到 %s 失败: %v", name, err)
+		}
+
+		info, err := List(dst)
+		if err != nil {
+			t.Fatalf("列出 %s 内容失败: %v", name, err)
+		}
+		if info.TotalFiles != 2 {
+			t.Errorf("%s 条目数量不匹配: 期望 2, 实际 %d", name, info.TotalFiles)
+		}
+	}
+
+	// 不存在的压缩包
+	if err := c.Append(filepath.Join(tempDir, "missing.zip"), second); err == nil {
+		t.Error("压缩包不存在时应返回错误")
+	}
+
+	// 单文件压缩格式不支持追加
+	gzFile := filepath.Join(tempDir, "a.txt.gz")
+	if err := c.Pack(gzFile, first); err != nil {
+		t.Fatalf("GZIP压缩失败: %v", err)
+	}
+	if err := c.Append(gzFile, second); err == nil {
+		t.Error("GZIP 格式不支持追加条目，应返回错误")
+	}
+}
+
+func TestComprx_DeleteAndRenameEntries(t *testing.T) {
+	tempDir := t.TempDir()
+	c := New()
+
+	srcDir := filepath.Join(tempDir, "src")
+	if err := os.MkdirAll(srcDir, 0755); err != nil {
+		t.Fatalf("创建目录失败: %v", err)
+	}
+	for _, name := range []string{"a.txt", "secret.env"} {
+		if err := os.WriteFile(filepath.Join(srcDir, name), []byte(name), 0644); err != nil {
+			t.Fatalf("创建测试文件失败: %v", err)
+		}
+	}
+
+	for _, name := range []string{"out.zip", "out.tar", "out.tgz"} {
+		dst := filepath.Join(tempDir, name)
+		if err := c.Pack(dst, srcDir); err != nil {
+			t.Fatalf("压缩 %s 失败: %v", name, err)
+		}
+		if err := c.DeleteEntries(dst, []string{"*.env"}); err != nil {
+			t.Fatalf("删除 %s 中的条目失败: %v", name, err)
+		}
+		if err := c.RenameEntries(dst, map[string]string{"src/a.txt": "src/b.txt"}); err != nil {
+			t.Fatalf("重命名 %s 中的条目失败: %v", name, err)
+		}
+
+		info, err := List(dst)
+		if err != nil {
+			t.Fatalf("列出 %s 内容失败: %v", name, err)
+		}
+		var names []string
+		for _, file := range info.Files {
+			names = append(names, strings.TrimSuffix(file.Name, "/"))
+		}
+		if strings.Join(names, ",") != "src,src/b.txt" {
+			t.Errorf("%s 条目列表不匹配: %v", name, names)
+		}
+	}
+
+	// 单文件压缩格式不支持修改条目
+	gzFile := filepath.Join(tempDir, "a.txt.gz")
+	if err := c.Pack(gzFile, filepath.Join(srcDir, "a.txt")); err != nil {
+		t.Fatalf("GZIP压缩失败: %v", err)
+	}
+	if err := c.DeleteEntries(gzFile, []string{"a.txt"}); err == nil {
+		t.Error("GZIP 格式不支持删除条目，应返回错误")
+	}
+}
+
+// TestListAccurate 测试 BZIP2 默认完整解压，与 ListAccurate 报告相同的原始大小
+func TestListAccurate(t *testing.T) {
+	bz2File := filepath.Join(t.TempDir(), "hello.txt.bz2")
+	if err := os.WriteFile(bz2File, sampleBzip2, 0644); err != nil {
+		t.Fatalf("创建BZ2文件失败: %v", err)
+	}
+
+	want := int64(len("hello bzip2 verify\n"))
+	for name, list := range map[string]func(string) (*types.ArchiveInfo, error){"List": List, "ListAccurate": ListAccurate} {
+		info, err := list(bz2File)
+		if err != nil {
+			t.Fatalf("%s 列出BZ2文件失败: %v", name, err)
+		}
+		if info.TotalSize != want || len(info.Warnings) != 0 {
+			t.Errorf("%s 原始大小不正确: 期望 %d, 实际 %d, 警告: %v", name, want, info.TotalSize, info.Warnings)
+		}
+	}
+}
@@ -0,0 +1,118 @@
+// Package core 提供压缩包格式转换的统一入口。
+//
+// 该文件实现了压缩包之间的格式转换：从源压缩包逐个读取条目，
+// 直接写入目标格式的构建器，不会把条目解压到磁盘。
+//
+// 主要功能：
+//   - 在 ZIP、TAR、TGZ 之间相互转换
+//   - 转换过程中应用过滤器
+//   - 原子地写入目标文件
+//
+// 使用示例：
+//
+//	comprx := core.New()
+//	err := comprx.Convert("vendor.zip", "vendor.tgz")
+package core
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"gitee.com/MM-Q/comprx/internal/cxtar"
+	"gitee.com/MM-Q/comprx/internal/cxtgz"
+	"gitee.com/MM-Q/comprx/internal/cxzip"
+	"gitee.com/MM-Q/comprx/internal/utils"
+	"gitee.com/MM-Q/comprx/types"
+)
+
+// Convert 将源压缩包转换为目标格式
+//
+// 参数:
+//   - src: 源压缩包路径（支持 .zip、.tar、.tgz、.tar.gz）
+//   - dst: 目标压缩包路径，格式由扩展名决定（支持 .zip、.tar、.tgz、.tar.gz）
+//
+// 返回:
+//   - error: 错误信息
+//
+// 注意:
+//   - 条目的名称、权限、修改时间和软链接目标保持不变
+//   - TAR/TGZ 中的硬链接以普通文件写入，内容取自其目标条目
+//   - 目标文件通过同目录临时文件写入后原子替换，转换失败时不会留下不完整的文件
+func (c *Comprx) Convert(src string, dst string) error {
+	// 检查参数
+	if src == "" {
+		return fmt.Errorf("源压缩包路径不能为空")
+	}
+	if dst == "" {
+		return fmt.Errorf("目标文件路径不能为空")
+	}
+
+	// 检查源压缩包
+	srcType, err := detectArchiveFormat(src)
+	if err != nil {
+		return err
+	}
+	if !isArchiveType(srcType) {
+		return fmt.Errorf("%s 格式只支持单文件压缩，不支持转换", srcType)
+	}
+
+	// 源文件和目标文件不能相同
+	absSrc, err := filepath.Abs(src)
+	if err != nil {
+		return fmt.Errorf("获取源文件绝对路径失败: %w", err)
+	}
+	absDst, err := filepath.Abs(dst)
+	if err != nil {
+		return fmt.Errorf("获取目标文件绝对路径失败: %w", err)
+	}
+	if absSrc == absDst {
+		return fmt.Errorf("源文件和目标文件不能相同: %s", src)
+	}
+
+	// 检测目标格式并准备目标路径
+	dstType, err := c.preparePackTarget(absDst)
+	if err != nil {
+		return err
+	}
+	if !isArchiveType(dstType) {
+		return fmt.Errorf("%s 格式只支持单文件压缩，不支持构建压缩包", dstType)
+	}
+
+	return utils.ReplaceFile(absDst, func(f *os.File) error {
+		builder, err := c.NewBuilderTo(f, dstType)
+		if err != nil {
+			return err
+		}
+		if err := c.writeEntriesTo(absSrc, srcType, builder); err != nil {
+			_ = builder.Close()
+			return err
+		}
+		return builder.Close()
+	})
+}
+
+// writeEntriesTo 根据源压缩包格式将条目写入构建器
+//
+// 参数:
+//   - src: 源压缩包路径
+//   - srcType: 源压缩包格式
+//   - builder: 目标构建器
+//
+// 返回:
+//   - error: 读取或写入失败时返回错误
+func (c *Comprx) writeEntriesTo(src string, srcType types.CompressType, builder ArchiveBuilder) error {
+	switch srcType {
+	case types.CompressTypeZip: // Zip
+		return cxzip.WriteEntriesTo(src, builder, c.Config.Filter, c.Config.Password, c.Config.FilenameEncoding)
+
+	case types.CompressTypeTar: // Tar
+		return cxtar.WriteFileEntriesTo(src, builder, c.Config.Filter)
+
+	case types.CompressTypeTgz, types.CompressTypeTarGz: // Tar.gz 或 .tgz
+		return cxtgz.WriteEntriesTo(src, builder, c.Config.Filter)
+
+	default:
+		return fmt.Errorf("%s 格式不支持转换", srcType)
+	}
+}
@@ -0,0 +1,202 @@
+package core
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"gitee.com/MM-Q/comprx/types"
+)
+
+// createConvertSource 创建包含文件、目录和软链接的源目录
+func createConvertSource(t *testing.T, dir string) time.Time {
+	t.Helper()
+
+	modTime := time.Date(2023, 6, 1, 12, 0, 0, 0, time.UTC)
+	if err := os.MkdirAll(filepath.Join(dir, "bin"), 0755); err != nil {
+		t.Fatalf("创建目录失败: %v", err)
+	}
+	files := map[string]os.FileMode{
+		"bin/app":       0755,
+		"README.md":     0644,
+		"notes.tmp":     0600,
+		"bin/config.sh": 0700,
+	}
+	for name, mode := range files {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(name), mode); err != nil {
+			t.Fatalf("创建测试文件失败: %v", err)
+		}
+		if err := os.Chmod(path, mode); err != nil {
+			t.Fatalf("设置文件权限失败: %v", err)
+		}
+		if err := os.Chtimes(path, modTime, modTime); err != nil {
+			t.Fatalf("设置修改时间失败: %v", err)
+		}
+	}
+	if runtime.GOOS != "windows" {
+		if err := os.Symlink("bin/app", filepath.Join(dir, "latest")); err != nil {
+			t.Fatalf("创建软链接失败: %v", err)
+		}
+	}
+	return modTime
+}
+
+func TestComprx_Convert(t *testing.T) {
+	tempDir := t.TempDir()
+	srcDir := filepath.Join(tempDir, "pkg")
+	modTime := createConvertSource(t, srcDir)
+
+	c := New()
+	zipFile := filepath.Join(tempDir, "vendor.zip")
+	if err := c.Pack(zipFile, srcDir); err != nil {
+		t.Fatalf("压缩失败: %v", err)
+	}
+
+	// zip -> tgz -> tar -> zip，并在第一步过滤临时文件
+	c.Config.Filter = &types.FilterOptions{Exclude: []string{"*.tmp"}}
+	tgzFile := filepath.Join(tempDir, "vendor.tgz")
+	if err := c.Convert(zipFile, tgzFile); err != nil {
+		t.Fatalf("zip 转换为 tgz 失败: %v", err)
+	}
+	c.Config.Filter = nil
+
+	tarFile := filepath.Join(tempDir, "vendor.tar")
+	if err := c.Convert(tgzFile, tarFile); err != nil {
+		t.Fatalf("tgz 转换为 tar 失败: %v", err)
+	}
+	finalZip := filepath.Join(tempDir, "final.zip")
+	if err := c.Convert(tarFile, finalZip); err != nil {
+		t.Fatalf("tar 转换为 zip 失败: %v", err)
+	}
+
+	for _, archive := range []string{tgzFile, tarFile, finalZip} {
+		info, err := List(archive)
+		if err != nil {
+			t.Fatalf("列出 %s 内容失败: %v", archive, err)
+		}
+
+		files := make(map[string]types.FileInfo)
+		for _, file := range info.Files {
+			files[file.Name] = file
+		}
+		if _, ok := files["pkg/notes.tmp"]; ok {
+			t.Errorf("%s 中的 pkg/notes.tmp 应被过滤", archive)
+		}
+
+		app, ok := files["pkg/bin/app"]
+		if !ok {
+			t.Fatalf("%s 中缺少 pkg/bin/app", archive)
+		}
+		if app.Mode.Perm() != 0755 {
+			t.Errorf("%s 中 pkg/bin/app 权限不匹配: 期望 0755, 实际 %o", archive, app.Mode.Perm())
+		}
+		if files["pkg/bin/config.sh"].Mode.Perm() != 0700 {
+			t.Errorf("%s 中 pkg/bin/config.sh 权限不匹配: 实际 %o", archive, files["pkg/bin/config.sh"].Mode.Perm())
+		}
+		if !app.ModTime.Equal(modTime) {
+			t.Errorf("%s 中 pkg/bin/app 修改时间不匹配: 期望 %v, 实际 %v", archive, modTime, app.ModTime)
+		}
+		if runtime.GOOS != "windows" {
+			if link := files["pkg/latest"]; !link.IsSymlink || link.LinkTarget != "bin/app" {
+				t.Errorf("%s 中软链接不匹配: %+v", archive, link)
+			}
+		}
+	}
+}
+
+func TestComprx_ConvertErrors(t *testing.T) {
+	tempDir := t.TempDir()
+	c := New()
+
+	src := filepath.Join(tempDir, "a.txt")
+	if err := os.WriteFile(src, []byte("a"), 0644); err != nil {
+		t.Fatalf("创建测试文件失败: %v", err)
+	}
+	zipFile := filepath.Join(tempDir, "a.zip")
+	if err := c.Pack(zipFile, src); err != nil {
+		t.Fatalf("压缩失败: %v", err)
+	}
+
+	if err := c.Convert(zipFile, zipFile); err == nil {
+		t.Error("源文件和目标文件相同时应返回错误")
+	}
+	if err := c.Convert(zipFile, filepath.Join(tempDir, "a.txt.gz")); err == nil {
+		t.Error("目标为单文件压缩格式时应返回错误")
+	}
+
+	// 目标已存在且不允许覆盖
+	existing := filepath.Join(tempDir, "exists.tar")
+	if err := os.WriteFile(existing, []byte("keep"), 0644); err != nil {
+		t.Fatalf("创建目标文件失败: %v", err)
+	}
+	if err := c.Convert(zipFile, existing); err == nil {
+		t.Error("目标文件已存在且未开启覆盖时应返回错误")
+	}
+	if data, _ := os.ReadFile(existing); string(data) != "keep" {
+		t.Error("转换失败时不应修改已存在的目标文件")
+	}
+}
+
+func TestComprx_ConvertHardlink(t *testing.T) {
+	tempDir := t.TempDir()
+
+	// 构建包含硬链接的 TGZ
+	tgzFile := filepath.Join(tempDir, "links.tgz")
+	f, err := os.Create(tgzFile)
+	if err != nil {
+		t.Fatalf("创建 TGZ 文件失败: %v", err)
+	}
+	gzipWriter := gzip.NewWriter(f)
+	tarWriter := tar.NewWriter(gzipWriter)
+	headers := []*tar.Header{
+		{Name: "bin/app", Typeflag: tar.TypeReg, Mode: 0755, Size: 3},
+		{Name: "bin/app-link", Typeflag: tar.TypeLink, Linkname: "bin/app", Mode: 0755},
+	}
+	for _, header := range headers {
+		if err := tarWriter.WriteHeader(header); err != nil {
+			t.Fatalf("写入TAR头失败: %v", err)
+		}
+		if header.Size > 0 {
+			if _, err := tarWriter.Write([]byte("app")); err != nil {
+				t.Fatalf("写入TAR数据失败: %v", err)
+			}
+		}
+	}
+	if err := tarWriter.Close(); err != nil {
+		t.Fatalf("关闭TAR写入器失败: %v", err)
+	}
+	if err := gzipWriter.Close(); err != nil {
+		t.Fatalf("关闭GZIP写入器失败: %v", err)
+	}
+	if err := f.Close(); err != nil {
+		t.Fatalf("关闭文件失败: %v", err)
+	}
+
+	// tgz -> tar -> zip，硬链接在每一步都以普通文件写入
+	c := New()
+	tarFile := filepath.Join(tempDir, "links.tar")
+	if err := c.Convert(tgzFile, tarFile); err != nil {
+		t.Fatalf("tgz 转换为 tar 失败: %v", err)
+	}
+	zipFile := filepath.Join(tempDir, "links.zip")
+	if err := c.Convert(tarFile, zipFile); err != nil {
+		t.Fatalf("tar 转换为 zip 失败: %v", err)
+	}
+
+	extractDir := filepath.Join(tempDir, "out")
+	if err := c.Unpack(zipFile, extractDir); err != nil {
+		t.Fatalf("解压失败: %v", err)
+	}
+	data, err := os.ReadFile(filepath.Join(extractDir, "bin", "app-link"))
+	if err != nil {
+		t.Fatalf("读取硬链接副本失败: %v", err)
+	}
+	if string(data) != "app" {
+		t.Errorf("硬链接副本内容不匹配: 期望 app, 实际 %q", data)
+	}
+}
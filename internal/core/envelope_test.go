@@ -0,0 +1,161 @@
+package core
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gitee.com/MM-Q/comprx/internal/envelope"
+	"gitee.com/MM-Q/comprx/types"
+)
+
+func TestComprx_EncryptWith_Archives(t *testing.T) {
+	tempDir := t.TempDir()
+
+	for _, name := range []string{"dump.tar", "dump.tgz", "dump.tar.gz"} {
+		c := New()
+		c.Config.EncryptWith = types.EnvelopeOptions{Passphrase: "secret"}
+
+		dst := filepath.Join(tempDir, name)
+		data := packVerifySource(t, c, dst)
+		if !bytes.HasPrefix(data, []byte(envelope.Magic)) || bytes.Contains(data, []byte(strings.Repeat("a", 64))) {
+			t.Fatalf("%s 未被加密", name)
+		}
+
+		// 解压时自动识别信封
+		outDir := filepath.Join(tempDir, strings.ReplaceAll(name, ".", "_"))
+		if err := c.Unpack(dst, outDir); err != nil {
+			t.Fatalf("解压 %s 失败: %v", name, err)
+		}
+		content, err := os.ReadFile(filepath.Join(outDir, "data", "b.txt"))
+		if err != nil || string(content) != strings.Repeat("b", 4096) {
+			t.Errorf("%s 解压内容不正确: %v", name, err)
+		}
+
+		// 口令错误
+		wrong := New()
+		wrong.Config.EncryptWith.Passphrase = "wrong"
+		if err := wrong.Unpack(dst, filepath.Join(outDir, "wrong")); !errors.Is(err, types.ErrWrongPassword) {
+			t.Errorf("%s 口令错误时应返回 ErrWrongPassword: %v", name, err)
+		}
+
+		// 列表和原地修改会被拒绝
+		if _, err := List(dst); err == nil || !strings.Contains(err.Error(), "加密信封") {
+			t.Errorf("%s 列表应被拒绝: %v", name, err)
+		}
+		if err := c.DeleteEntries(dst, []string{"data/a.txt"}); err == nil {
+			t.Errorf("%s 删除条目应被拒绝", name)
+		}
+	}
+}
+
+func TestComprx_EncryptWith_SingleFile(t *testing.T) {
+	tempDir := t.TempDir()
+	src := filepath.Join(tempDir, "dump.sql")
+	if err := os.WriteFile(src, []byte(strings.Repeat("INSERT 1;\n", 1000)), 0644); err != nil {
+		t.Fatalf("创建测试文件失败: %v", err)
+	}
+
+	for _, name := range []string{"dump.sql.gz", "dump.sql.zlib"} {
+		c := New()
+		c.Config.EncryptWith = types.EnvelopeOptions{
+			Passphrase: "secret",
+			KDF:        types.EnvelopeKDFArgon2id,
+			Cipher:     types.EnvelopeCipherChaCha20Poly1305,
+		}
+
+		dst := filepath.Join(tempDir, name)
+		if err := c.Pack(dst, src); err != nil {
+			t.Fatalf("压缩 %s 失败: %v", name, err)
+		}
+		if encrypted, err := envelope.IsEncrypted(dst); err != nil || !encrypted {
+			t.Fatalf("%s 未被加密: %v", name, err)
+		}
+
+		outDir := filepath.Join(tempDir, strings.ReplaceAll(name, ".", "_"))
+		if err := c.Unpack(dst, outDir); err != nil {
+			t.Fatalf("解压 %s 失败: %v", name, err)
+		}
+		if content, err := os.ReadFile(filepath.Join(outDir, "dump.sql")); err != nil || !bytes.HasPrefix(content, []byte("INSERT 1;")) || len(content) != 10000 {
+			t.Errorf("%s 解压内容不正确: %v", name, err)
+		}
+
+		// 未提供口令
+		if err := New().Unpack(dst, filepath.Join(outDir, "missing")); !errors.Is(err, types.ErrPasswordRequired) {
+			t.Errorf("%s 未提供口令时应返回 ErrPasswordRequired: %v", name, err)
+		}
+	}
+}
+
+func TestComprx_EncryptWith_Zip(t *testing.T) {
+	c := New()
+	c.Config.EncryptWith = types.EnvelopeOptions{Passphrase: "secret"}
+
+	if err := c.Pack(filepath.Join(t.TempDir(), "out.zip"), "."); err == nil {
+		t.Error("ZIP 格式使用加密信封应返回错误")
+	}
+}
+
+func TestComprx_EncryptWith_Test(t *testing.T) {
+	tempDir := t.TempDir()
+	src := filepath.Join(tempDir, "dump.sql")
+	if err := os.WriteFile(src, []byte(strings.Repeat("INSERT 1;\n", 1000)), 0644); err != nil {
+		t.Fatalf("创建测试文件失败: %v", err)
+	}
+
+	for _, name := range []string{"dump.tar", "dump.tgz", "dump.sql.gz", "dump.sql.zlib"} {
+		c := New()
+		c.Config.EncryptWith = types.EnvelopeOptions{Passphrase: "secret"}
+		dst := filepath.Join(tempDir, name)
+		if err := c.Pack(dst, src); err != nil {
+			t.Fatalf("打包 %s 失败: %v", name, err)
+		}
+
+		// 提供口令时解密后校验内部数据
+		report, err := c.Test(dst)
+		if err != nil || report.TotalEntries != 1 || report.TotalSize != 10000 {
+			t.Errorf("校验 %s 失败: %+v, %v", name, report, err)
+		}
+
+		// 未提供口令或口令错误时返回错误
+		if _, err := New().Test(dst); err == nil {
+			t.Errorf("%s 未提供口令时校验应返回错误", name)
+		}
+		wrong := New()
+		wrong.Config.EncryptWith.Passphrase = "wrong"
+		if _, err := wrong.Test(dst); !errors.Is(err, types.ErrWrongPassword) {
+			t.Errorf("%s 口令错误时应返回 ErrWrongPassword: %v", name, err)
+		}
+	}
+}
+
+func TestComprx_EncryptWith_Modify(t *testing.T) {
+	tempDir := t.TempDir()
+	c := New()
+	tarFile := filepath.Join(tempDir, "plain.tar")
+	packVerifySource(t, c, tarFile)
+
+	extra := filepath.Join(tempDir, "extra.txt")
+	if err := os.WriteFile(extra, []byte("extra"), 0644); err != nil {
+		t.Fatalf("创建测试文件失败: %v", err)
+	}
+
+	// 修改压缩包时设置了加密信封选项返回明确的错误，压缩包保持不变
+	before, _ := os.ReadFile(tarFile)
+	c.Config.EncryptWith = types.EnvelopeOptions{Passphrase: "secret"}
+	if err := c.Append(tarFile, extra); err == nil || !strings.Contains(err.Error(), "EncryptWith") {
+		t.Errorf("追加条目应拒绝加密信封选项: %v", err)
+	}
+	if err := c.DeleteEntries(tarFile, []string{"data/a.txt"}); err == nil || !strings.Contains(err.Error(), "EncryptWith") {
+		t.Errorf("删除条目应拒绝加密信封选项: %v", err)
+	}
+	if err := c.RenameEntries(tarFile, map[string]string{"data/a.txt": "data/c.txt"}); err == nil || !strings.Contains(err.Error(), "EncryptWith") {
+		t.Errorf("重命名条目应拒绝加密信封选项: %v", err)
+	}
+	if after, _ := os.ReadFile(tarFile); !bytes.Equal(before, after) {
+		t.Error("被拒绝的修改不应改动压缩包")
+	}
+}
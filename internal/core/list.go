@@ -7,6 +7,9 @@
 //   - 列出压缩包内所有文件信息
 //   - 支持限制返回文件数量
 //   - 支持文件名模式匹配过滤
+//   - 按与解压相同的过滤选项列出文件
+//   - 完整解压获取准确的 GZIP 原始大小
+//   - 逐条流式遍历压缩包条目
 //   - 自动检测压缩格式
 //   - 统一的错误处理
 //
@@ -28,10 +31,18 @@
 //
 //	// 列出匹配模式的文件
 //	info, err := core.ListMatch("archive.zip", "*.go")
+//
+//	// 按过滤选项列出文件
+//	info, err := core.ListFilter("archive.zip", &types.FilterOptions{Include: []string{"*.go"}})
+//
+//	// 逐条遍历
+//	err := core.Walk("archive.tar", func(file types.FileInfo) error { return nil })
 package core
 
 import (
+	"errors"
 	"fmt"
+	"io/fs"
 
 	"gitee.com/MM-Q/comprx/internal/cxbzip2"
 	"gitee.com/MM-Q/comprx/internal/cxgzip"
@@ -67,6 +78,11 @@ func List(archivePath string) (*types.ArchiveInfo, error) {
 		return nil, fmt.Errorf("压缩包文件 %s 不存在", archivePath)
 	}
 
+	// 加密信封不支持列出内容
+	if err := rejectEnvelope(archivePath); err != nil {
+		return nil, err
+	}
+
 	// 根据压缩格式调用对应的列表函数
 	switch compressType {
 	case types.CompressTypeZip: // Zip
@@ -92,6 +108,40 @@ func List(archivePath string) (*types.ArchiveInfo, error) {
 	}
 }
 
+// ListAccurate 列出压缩包的所有文件信息，GZIP 文件完整解压以获取准确的原始大小
+//
+// List 对 GZIP 只读取尾部的 ISIZE，原始大小超过 4 GiB 或多成员文件可能不准确；
+// 其他格式的结果与 List 相同。
+//
+// 参数:
+//   - archivePath: 压缩包文件路径
+//
+// 返回:
+//   - *types.ArchiveInfo: 压缩包信息
+//   - error: 错误信息
+func ListAccurate(archivePath string) (*types.ArchiveInfo, error) {
+	// 智能检测压缩文件格式
+	compressType, err := types.DetectCompressFormat(archivePath)
+	if err != nil {
+		return nil, fmt.Errorf("检测压缩格式失败: %v", err)
+	}
+	if compressType != types.CompressTypeGz {
+		return List(archivePath)
+	}
+
+	// 检查源文件是否存在
+	if !utils.Exists(archivePath) {
+		return nil, fmt.Errorf("压缩包文件 %s 不存在", archivePath)
+	}
+
+	// 加密信封不支持列出内容
+	if err := rejectEnvelope(archivePath); err != nil {
+		return nil, err
+	}
+
+	return cxgzip.ListGzipAccurate(archivePath)
+}
+
 // ListLimit 列出指定数量的文件信息
 //
 // 参数:
@@ -113,6 +163,11 @@ func ListLimit(archivePath string, limit int) (*types.ArchiveInfo, error) {
 		return nil, fmt.Errorf("压缩包文件 %s 不存在", archivePath)
 	}
 
+	// 加密信封不支持列出内容
+	if err := rejectEnvelope(archivePath); err != nil {
+		return nil, err
+	}
+
 	// 根据压缩格式调用对应的列表函数
 	switch compressType {
 	case types.CompressTypeZip: // Zip
@@ -159,6 +214,11 @@ func ListMatch(archivePath string, pattern string) (*types.ArchiveInfo, error) {
 		return nil, fmt.Errorf("压缩包文件 %s 不存在", archivePath)
 	}
 
+	// 加密信封不支持列出内容
+	if err := rejectEnvelope(archivePath); err != nil {
+		return nil, err
+	}
+
 	// 根据压缩格式调用对应的列表函数
 	switch compressType {
 	case types.CompressTypeZip: // Zip
@@ -183,3 +243,121 @@ func ListMatch(archivePath string, pattern string) (*types.ArchiveInfo, error) {
 		return nil, fmt.Errorf("不支持的压缩格式: %s", compressType)
 	}
 }
+
+// ListFilter 列出通过过滤选项的文件信息
+//
+// 对每个条目使用与解压相同的 ShouldSkipByParams 判断，传入条目名称、原始大小和是否为目录，
+// 因此结果与使用同一过滤选项解压时写出的条目一致。GZIP、BZIP2 和 ZLIB 解压时不应用过滤选项，
+// 列出时同样返回其唯一的条目。
+//
+// 参数:
+//   - archivePath: 压缩包文件路径
+//   - filter: 过滤选项，为 nil 或没有过滤条件时返回全部文件
+//
+// 返回:
+//   - *types.ArchiveInfo: 压缩包信息，TotalFiles 和 TotalSize 按保留的条目计算
+//   - error: 错误信息
+func ListFilter(archivePath string, filter *types.FilterOptions) (*types.ArchiveInfo, error) {
+	if filter != nil {
+		if err := filter.Validate(); err != nil {
+			return nil, err
+		}
+	}
+
+	archiveInfo, err := List(archivePath)
+	if err != nil {
+		return nil, err
+	}
+
+	// 单文件格式解压时不应用过滤选项
+	switch archiveInfo.Type {
+	case types.CompressTypeZip, types.CompressTypeTar, types.CompressTypeTgz, types.CompressTypeTarGz:
+	default:
+		return archiveInfo, nil
+	}
+	if !types.HasFilterConditions(filter) {
+		return archiveInfo, nil
+	}
+
+	files := archiveInfo.Files[:0]
+	archiveInfo.TotalFiles = 0
+	archiveInfo.TotalSize = 0
+	for _, file := range archiveInfo.Files {
+		if filter.ShouldSkipByParams(file.Name, file.Size, file.IsDir) {
+			continue
+		}
+		files = append(files, file)
+		archiveInfo.TotalFiles++
+		archiveInfo.TotalSize += file.Size
+	}
+	archiveInfo.Files = files
+	return archiveInfo, nil
+}
+
+// Walk 逐条遍历压缩包中的条目
+//
+// TAR、TGZ 和 ZIP 边读取边回调，不在内存中保留条目列表；
+// GZIP、BZIP2 和 ZLIB 只有一个条目。
+//
+// 参数:
+//   - archivePath: 压缩包文件路径
+//   - fn: 对每个条目调用的函数，返回 fs.SkipAll 时提前结束遍历，返回其他错误时中止并返回该错误
+//
+// 返回:
+//   - error: 错误信息
+func Walk(archivePath string, fn func(types.FileInfo) error) error {
+	// 智能检测压缩文件格式
+	compressType, err := types.DetectCompressFormat(archivePath)
+	if err != nil {
+		return fmt.Errorf("检测压缩格式失败: %v", err)
+	}
+
+	// 检查源文件是否存在
+	if !utils.Exists(archivePath) {
+		return fmt.Errorf("压缩包文件 %s 不存在", archivePath)
+	}
+
+	// 加密信封不支持列出内容
+	if err := rejectEnvelope(archivePath); err != nil {
+		return err
+	}
+
+	// 根据压缩格式调用对应的遍历函数
+	var archiveInfo *types.ArchiveInfo
+	switch compressType {
+	case types.CompressTypeZip: // Zip
+		return cxzip.WalkZip(archivePath, fn)
+
+	case types.CompressTypeTar: // Tar
+		return cxtar.WalkTar(archivePath, fn)
+
+	case types.CompressTypeTgz, types.CompressTypeTarGz: // Tar.gz 或 .tgz
+		return cxtgz.WalkTgz(archivePath, fn)
+
+	case types.CompressTypeGz: // Gz
+		archiveInfo, err = cxgzip.ListGzip(archivePath)
+
+	case types.CompressTypeBz2, types.CompressTypeBzip2: // Bz2
+		archiveInfo, err = cxbzip2.ListBz2(archivePath)
+
+	case types.CompressTypeZlib: // Zlib
+		archiveInfo, err = cxzlib.ListZlib(archivePath)
+
+	default:
+		return fmt.Errorf("不支持的压缩格式: %s", compressType)
+	}
+	if err != nil {
+		return err
+	}
+
+	// 单文件格式只有一个条目，直接遍历列表
+	for _, file := range archiveInfo.Files {
+		if err := fn(file); err != nil {
+			if errors.Is(err, fs.SkipAll) {
+				return nil
+			}
+			return err
+		}
+	}
+	return nil
+}
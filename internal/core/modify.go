@@ -0,0 +1,229 @@
+// Package core 提供修改已有压缩包的统一入口。
+//
+// 该文件实现了追加、删除、重命名压缩包条目的格式分发，
+// 根据压缩包扩展名调用对应格式的实现。
+//
+// 主要功能：
+//   - 向已有 ZIP、TAR、TGZ 压缩包追加文件或目录
+//   - 删除和重命名压缩包中的条目
+//
+// 使用示例：
+//
+//	comprx := core.New()
+//	err := comprx.Append("logs.zip", "app-10.log")
+package core
+
+import (
+	"fmt"
+
+	"gitee.com/MM-Q/comprx/internal/cxtar"
+	"gitee.com/MM-Q/comprx/internal/cxtgz"
+	"gitee.com/MM-Q/comprx/internal/cxzip"
+	"gitee.com/MM-Q/comprx/internal/envelope"
+	"gitee.com/MM-Q/comprx/internal/utils"
+	"gitee.com/MM-Q/comprx/types"
+)
+
+// Append 向已有压缩包追加文件或目录
+//
+// 参数:
+//   - archivePath: 已有的压缩包路径（支持 .zip、.tar、.tgz、.tar.gz）
+//   - src: 需要追加的源路径
+//
+// 返回:
+//   - error: 错误信息
+//
+// 注意:
+//   - 与已有条目同名时，OverwriteExisting 为 true 则替换已有条目，否则返回错误；同名目录会直接合并
+//   - 不支持加密信封，设置了 EncryptWith 时返回错误
+//   - 启用校验清单时，压缩包已有的清单会合并新条目的摘要后重新写入；清单在压缩包内时需要重写整个压缩包
+func (c *Comprx) Append(archivePath string, src string) error {
+	if err := c.rejectEncryptWith("追加条目"); err != nil {
+		return err
+	}
+	// 检查参数
+	if src == "" {
+		return fmt.Errorf("源文件路径不能为空")
+	}
+	compressType, err := detectArchiveFormat(archivePath)
+	if err != nil {
+		return err
+	}
+
+	// 读取已有的校验清单
+	if err := c.prepareChecksums(compressType); err != nil {
+		return err
+	}
+	if err := c.loadManifests(archivePath, compressType); err != nil {
+		return err
+	}
+
+	// 根据压缩格式进行追加
+	switch compressType {
+	case types.CompressTypeZip: // Zip
+		err = cxzip.Append(archivePath, src, c.Config)
+
+	case types.CompressTypeTar: // Tar
+		err = cxtar.Append(archivePath, src, c.Config)
+
+	case types.CompressTypeTgz, types.CompressTypeTarGz: // Tar.gz 或 .tgz
+		err = cxtgz.Append(archivePath, src, c.Config)
+
+	default:
+		return fmt.Errorf("%s 格式不支持追加条目", compressType)
+	}
+	if err != nil {
+		return err
+	}
+
+	// 更新写在压缩包旁边的校验清单
+	return c.Config.Checksums.WriteBeside(archivePath)
+}
+
+// DeleteEntries 删除压缩包中匹配模式的条目
+//
+// 参数:
+//   - archivePath: 压缩包路径（支持 .zip、.tar、.tgz、.tar.gz）
+//   - patterns: 删除模式，匹配到目录时同时删除其下所有条目
+//
+// 返回:
+//   - error: 错误信息
+func (c *Comprx) DeleteEntries(archivePath string, patterns []string) error {
+	if err := c.rejectEncryptWith("删除条目"); err != nil {
+		return err
+	}
+	compressType, err := detectArchiveFormat(archivePath)
+	if err != nil {
+		return err
+	}
+
+	// 根据压缩格式删除条目
+	switch compressType {
+	case types.CompressTypeZip: // Zip
+		return cxzip.DeleteEntries(archivePath, patterns)
+
+	case types.CompressTypeTar: // Tar
+		return cxtar.DeleteEntries(archivePath, patterns)
+
+	case types.CompressTypeTgz, types.CompressTypeTarGz: // Tar.gz 或 .tgz
+		return cxtgz.DeleteEntries(archivePath, patterns, c.Config)
+
+	default:
+		return fmt.Errorf("%s 格式不支持删除条目", compressType)
+	}
+}
+
+// RenameEntries 重命名压缩包中的条目
+//
+// 参数:
+//   - archivePath: 压缩包路径（支持 .zip、.tar、.tgz、.tar.gz）
+//   - renames: 原名称到新名称的映射，重命名目录时其下所有条目一并移动
+//
+// 返回:
+//   - error: 错误信息
+func (c *Comprx) RenameEntries(archivePath string, renames map[string]string) error {
+	if err := c.rejectEncryptWith("重命名条目"); err != nil {
+		return err
+	}
+	compressType, err := detectArchiveFormat(archivePath)
+	if err != nil {
+		return err
+	}
+
+	// 根据压缩格式重命名条目
+	switch compressType {
+	case types.CompressTypeZip: // Zip
+		return cxzip.RenameEntries(archivePath, renames)
+
+	case types.CompressTypeTar: // Tar
+		return cxtar.RenameEntries(archivePath, renames)
+
+	case types.CompressTypeTgz, types.CompressTypeTarGz: // Tar.gz 或 .tgz
+		return cxtgz.RenameEntries(archivePath, renames, c.Config)
+
+	default:
+		return fmt.Errorf("%s 格式不支持重命名条目", compressType)
+	}
+}
+
+// detectArchiveFormat 检查待修改的压缩包并检测其格式
+//
+// 参数:
+//   - archivePath: 压缩包路径
+//
+// 返回:
+//   - types.CompressType: 压缩格式
+//   - error: 路径为空、文件不存在、无法识别格式或压缩包是加密信封时返回错误
+func detectArchiveFormat(archivePath string) (types.CompressType, error) {
+	compressType, err := detectExistingFormat(archivePath)
+	if err != nil {
+		return "", err
+	}
+
+	// 加密信封只支持解压，原地修改会破坏加密数据
+	if err := rejectEnvelope(archivePath); err != nil {
+		return "", err
+	}
+	return compressType, nil
+}
+
+// detectExistingFormat 检查压缩包是否存在并检测其格式
+//
+// 参数:
+//   - archivePath: 压缩包路径
+//
+// 返回:
+//   - types.CompressType: 压缩格式
+//   - error: 路径为空、文件不存在或无法识别格式时返回错误
+func detectExistingFormat(archivePath string) (types.CompressType, error) {
+	if archivePath == "" {
+		return "", fmt.Errorf("压缩包路径不能为空")
+	}
+
+	// 检查压缩包是否存在
+	if !utils.Exists(archivePath) {
+		return "", fmt.Errorf("压缩包 %s 不存在", archivePath)
+	}
+
+	// 检测压缩格式
+	compressType, err := types.DetectCompressFormat(archivePath)
+	if err != nil {
+		return "", fmt.Errorf("检测压缩格式失败: %v", err)
+	}
+	return compressType, nil
+}
+
+// rejectEnvelope 检查压缩包是否为加密信封
+//
+// 参数:
+//   - archivePath: 压缩包路径
+//
+// 返回:
+//   - error: 是加密信封或读取失败时返回错误
+func rejectEnvelope(archivePath string) error {
+	encrypted, err := envelope.IsEncrypted(archivePath)
+	if err != nil {
+		return err
+	}
+	if encrypted {
+		return fmt.Errorf("压缩包 %s 使用了加密信封，只支持解压", archivePath)
+	}
+	return nil
+}
+
+// rejectEncryptWith 检查是否设置了加密信封选项
+//
+// 修改压缩包时新条目先打包到临时归档再合并，加密信封只能在打包时整体写入，
+// 无法用于修改已有的压缩包。
+//
+// 参数:
+//   - operation: 操作名称，用于错误信息
+//
+// 返回:
+//   - error: 设置了 EncryptWith 时返回错误
+func (c *Comprx) rejectEncryptWith(operation string) error {
+	if c.Config.EncryptWith.Enabled() {
+		return fmt.Errorf("%s不支持加密信封，请不要设置 EncryptWith", operation)
+	}
+	return nil
+}
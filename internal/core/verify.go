@@ -0,0 +1,73 @@
+// Package core 提供压缩包完整性校验的统一入口。
+//
+// 该文件实现了完整性校验的格式分发：根据压缩包扩展名调用对应格式的校验实现，
+// 解码全部数据但不写入任何文件，汇总损坏或截断的条目。
+// 加密信封使用 EncryptWith 中的口令解密后校验内部数据。
+//
+// 主要功能：
+//   - 校验 ZIP、TAR、TGZ、GZIP、BZIP2、ZLIB 文件的完整性
+//
+// 使用示例：
+//
+//	comprx := core.New()
+//	report, err := comprx.Test("backup.zip")
+package core
+
+import (
+	"fmt"
+
+	"gitee.com/MM-Q/comprx/internal/cxbzip2"
+	"gitee.com/MM-Q/comprx/internal/cxgzip"
+	"gitee.com/MM-Q/comprx/internal/cxtar"
+	"gitee.com/MM-Q/comprx/internal/cxtgz"
+	"gitee.com/MM-Q/comprx/internal/cxzip"
+	"gitee.com/MM-Q/comprx/internal/cxzlib"
+	"gitee.com/MM-Q/comprx/types"
+)
+
+// Test 校验压缩包的完整性
+//
+// 参数:
+//   - archivePath: 压缩包路径
+//
+// 返回:
+//   - *types.VerifyReport: 校验报告，能够读取压缩包时总是返回
+//   - error: 无法读取压缩包、加密信封未提供口令或口令错误、存在损坏条目时返回错误
+func (c *Comprx) Test(archivePath string) (*types.VerifyReport, error) {
+	compressType, err := detectExistingFormat(archivePath)
+	if err != nil {
+		return nil, err
+	}
+	passphrase := c.Config.EncryptWith.Passphrase
+
+	// 根据压缩格式进行校验
+	var report *types.VerifyReport
+	switch compressType {
+	case types.CompressTypeZip: // Zip
+		report, err = cxzip.Verify(archivePath, c.Config.Filter, c.Config.Password, c.Config.FilenameEncoding)
+
+	case types.CompressTypeTar: // Tar
+		report, err = cxtar.Verify(archivePath, c.Config.Filter, passphrase)
+
+	case types.CompressTypeTgz, types.CompressTypeTarGz: // Tar.gz 或 .tgz
+		report, err = cxtgz.Verify(archivePath, c.Config.Filter, passphrase)
+
+	case types.CompressTypeGz: // Gzip
+		report, err = cxgzip.Verify(archivePath, passphrase)
+
+	case types.CompressTypeBz2, types.CompressTypeBzip2: // Bzip2
+		report, err = cxbzip2.Verify(archivePath)
+
+	case types.CompressTypeZlib: // Zlib
+		report, err = cxzlib.Verify(archivePath, passphrase)
+
+	default:
+		return nil, fmt.Errorf("不支持的压缩格式: %s", compressType)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	report.Type = compressType
+	return report, report.Err()
+}
@@ -0,0 +1,183 @@
+package core
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// sampleBzip2 "hello bzip2 verify\n" 的 BZIP2 压缩数据
+var sampleBzip2 = []byte{
+	0x42, 0x5a, 0x68, 0x39, 0x31, 0x41, 0x59, 0x26, 0x53, 0x59, 0x2f, 0x2d, 0xee, 0x78, 0x00, 0x00,
+	0x04, 0x59, 0x80, 0x00, 0x10, 0x40, 0x00, 0x10, 0x00, 0x13, 0x64, 0xd1, 0x30, 0x20, 0x00, 0x31,
+	0x4c, 0x00, 0x01, 0x08, 0xd3, 0x46, 0x4f, 0x53, 0xca, 0x1e, 0xe8, 0x4d, 0x91, 0x45, 0x79, 0x34,
+	0xe6, 0x92, 0xf8, 0xbb, 0x92, 0x29, 0xc2, 0x84, 0x81, 0x79, 0x6f, 0x73, 0xc0,
+}
+
+// packVerifySource 将包含两个文件的目录打包为指定格式
+func packVerifySource(t *testing.T, c *Comprx, dst string) []byte {
+	t.Helper()
+
+	srcDir := filepath.Join(t.TempDir(), "data")
+	if err := os.MkdirAll(srcDir, 0755); err != nil {
+		t.Fatalf("创建目录失败: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(srcDir, "a.txt"), []byte(strings.Repeat("a", 4096)), 0644); err != nil {
+		t.Fatalf("创建测试文件失败: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(srcDir, "b.txt"), []byte(strings.Repeat("b", 4096)), 0644); err != nil {
+		t.Fatalf("创建测试文件失败: %v", err)
+	}
+	if err := c.Pack(dst, srcDir); err != nil {
+		t.Fatalf("压缩失败: %v", err)
+	}
+	data, err := os.ReadFile(dst)
+	if err != nil {
+		t.Fatalf("读取压缩包失败: %v", err)
+	}
+	return data
+}
+
+func TestComprx_Test_Valid(t *testing.T) {
+	tempDir := t.TempDir()
+	c := New()
+
+	for _, name := range []string{"ok.zip", "ok.tar", "ok.tgz"} {
+		dst := filepath.Join(tempDir, name)
+		packVerifySource(t, c, dst)
+
+		report, err := c.Test(dst)
+		if err != nil {
+			t.Fatalf("校验 %s 失败: %v", name, err)
+		}
+		if !report.OK() || report.TotalEntries != 3 || report.TotalSize != 8192 {
+			t.Errorf("%s 校验报告不正确: %+v", name, report)
+		}
+	}
+
+	bz2File := filepath.Join(tempDir, "ok.bz2")
+	if err := os.WriteFile(bz2File, sampleBzip2, 0644); err != nil {
+		t.Fatalf("写入BZIP2文件失败: %v", err)
+	}
+	if report, err := c.Test(bz2File); err != nil || report.TotalSize != int64(len("hello bzip2 verify\n")) {
+		t.Errorf("校验 BZIP2 失败: %+v, %v", report, err)
+	}
+}
+
+func TestComprx_Test_ZipCRC(t *testing.T) {
+	tempDir := t.TempDir()
+	c := New()
+	c.Config.CompressionLevel = 0 // 不压缩，方便直接篡改文件内容
+
+	zipFile := filepath.Join(tempDir, "bad.zip")
+	data := packVerifySource(t, c, zipFile)
+
+	// 篡改 b.txt 的数据
+	index := bytes.Index(data, []byte(strings.Repeat("b", 64)))
+	if index < 0 {
+		t.Fatal("未找到 b.txt 的数据")
+	}
+	data[index] = 'x'
+	if err := os.WriteFile(zipFile, data, 0644); err != nil {
+		t.Fatalf("写入ZIP文件失败: %v", err)
+	}
+
+	report, err := c.Test(zipFile)
+	if err == nil {
+		t.Fatal("CRC32 不匹配时应返回错误")
+	}
+	if len(report.Failures) != 1 || report.Failures[0].Name != "data/b.txt" {
+		t.Errorf("损坏条目不正确: %+v", report.Failures)
+	}
+	if report.TotalEntries != 3 {
+		t.Errorf("应继续校验其余条目: %+v", report)
+	}
+}
+
+func TestComprx_Test_Truncated(t *testing.T) {
+	tempDir := t.TempDir()
+	c := New()
+
+	for _, name := range []string{"cut.tar", "cut.tgz"} {
+		dst := filepath.Join(tempDir, name)
+		data := packVerifySource(t, c, dst)
+		if err := os.WriteFile(dst, data[:len(data)*2/3], 0644); err != nil {
+			t.Fatalf("写入截断文件失败: %v", err)
+		}
+
+		report, err := c.Test(dst)
+		if err == nil || report == nil || report.OK() {
+			t.Errorf("截断的 %s 应校验失败: %+v, %v", name, report, err)
+		}
+	}
+}
+
+func TestComprx_Test_TarHeaderChecksum(t *testing.T) {
+	tempDir := t.TempDir()
+	c := New()
+
+	tarFile := filepath.Join(tempDir, "bad.tar")
+	data := packVerifySource(t, c, tarFile)
+	data[0] ^= 0xff // 篡改第一个文件头的名称
+	if err := os.WriteFile(tarFile, data, 0644); err != nil {
+		t.Fatalf("写入TAR文件失败: %v", err)
+	}
+
+	report, err := c.Test(tarFile)
+	if err == nil || !strings.Contains(report.Failures[0].Error(), "第 1 个条目") {
+		t.Errorf("文件头校验和错误未被发现: %+v, %v", report, err)
+	}
+}
+
+func TestComprx_Test_SingleFile(t *testing.T) {
+	tempDir := t.TempDir()
+	c := New()
+
+	// GZIP 尾部 CRC32 被篡改
+	var gzipBuf bytes.Buffer
+	gzipWriter := gzip.NewWriter(&gzipBuf)
+	_, _ = gzipWriter.Write([]byte("hello gzip"))
+	_ = gzipWriter.Close()
+	gzipData := gzipBuf.Bytes()
+	gzipData[len(gzipData)-8] ^= 0xff
+
+	// ZLIB 尾部 Adler-32 被篡改
+	var zlibBuf bytes.Buffer
+	zlibWriter := zlib.NewWriter(&zlibBuf)
+	_, _ = zlibWriter.Write([]byte("hello zlib"))
+	_ = zlibWriter.Close()
+	zlibData := zlibBuf.Bytes()
+	zlibData[len(zlibData)-1] ^= 0xff
+
+	// BZIP2 数据块 CRC 被篡改
+	bzip2Data := append([]byte(nil), sampleBzip2...)
+	bzip2Data[10] ^= 0xff
+
+	files := map[string][]byte{
+		"app.log.gz":   gzipData,
+		"data.zlib":    zlibData,
+		"sample.bz2":   bzip2Data,
+		"sample2.bz2":  sampleBzip2[:30],
+		"notgzip.gz":   []byte("plain text"),
+		"notzlib.zlib": []byte("plain text"),
+	}
+	for name, data := range files {
+		path := filepath.Join(tempDir, name)
+		if err := os.WriteFile(path, data, 0644); err != nil {
+			t.Fatalf("写入测试文件失败: %v", err)
+		}
+
+		report, err := c.Test(path)
+		if err == nil || report == nil || len(report.Failures) != 1 {
+			t.Errorf("损坏的 %s 应校验失败: %+v, %v", name, report, err)
+		}
+	}
+
+	if _, err := c.Test(filepath.Join(tempDir, "missing.zip")); err == nil {
+		t.Error("文件不存在时应返回错误")
+	}
+}
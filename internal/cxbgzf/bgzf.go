@@ -0,0 +1,304 @@
+// Package cxbgzf 提供 BGZF (分块 GZIP) 格式的块解析和索引功能实现。
+//
+// BGZF 由多个 GZIP 成员组成，每个成员解压后不超过 64 KiB，压缩后连同头尾不超过 64 KiB，
+// 成员头的 FEXTRA 字段包含 SI1='B'、SI2='C' 的子字段，记录整个块的大小减一(BSIZE)。
+// 文件以一个 28 字节的空块结尾，作为 EOF 标记。BGZF 文件本身也是合法的多成员 GZIP 文件。
+//
+// 主要功能：
+//   - 识别 BGZF 成员头和数据流
+//   - 读取单个数据块
+//   - 不解压地扫描所有数据块
+//   - 构建、读取和写入 .gzi 索引
+//
+// 使用示例：
+//
+//	// 扫描数据块构建索引
+//	index, err := cxbgzf.BuildIndex(file)
+//
+//	// 写入 .gzi 索引
+//	err = cxbgzf.WriteIndex(gziFile, index)
+package cxbgzf
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+
+	"gitee.com/MM-Q/comprx/types"
+)
+
+const (
+	// BlockDataSize 每个数据块最多容纳的原始数据字节数，与 htslib 一致
+	BlockDataSize = 0xff00
+
+	// MaxBlockSize 压缩后数据块(含头尾)的最大字节数
+	MaxBlockSize = 65536
+
+	// blockHeaderSize 数据块头的长度: 12 字节固定头和 6 字节 FEXTRA
+	blockHeaderSize = 18
+
+	// blockFooterSize 数据块尾的长度: CRC32 和 ISIZE
+	blockFooterSize = 8
+)
+
+// EOFMarker BGZF 文件末尾的空数据块
+var EOFMarker = []byte{
+	0x1f, 0x8b, 0x08, 0x04, 0x00, 0x00, 0x00, 0x00, 0x00, 0xff, 0x06, 0x00, 0x42, 0x43,
+	0x02, 0x00, 0x1b, 0x00, 0x03, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+}
+
+// ErrNotBgzf 数据不是 BGZF 格式
+var ErrNotBgzf = errors.New("不是有效的 BGZF 数据块")
+
+// IsBgzfExtra 判断 GZIP 成员头的额外字段是否包含 BGZF 的 BC 子字段
+//
+// 参数:
+//   - extra: GZIP 成员头的 FEXTRA 字段
+//
+// 返回:
+//   - bool: 包含 BC 子字段返回 true
+func IsBgzfExtra(extra []byte) bool {
+	_, ok := blockSizeFromExtra(extra)
+	return ok
+}
+
+// IsBgzf 判断数据流是否以BGZF数据块开头
+//
+// 只预读第一个数据块头，不消耗数据。
+//
+// 参数:
+//   - r: 位于文件起始处的数据流
+//
+// 返回:
+//   - bool: 是 BGZF 返回 true
+func IsBgzf(r *bufio.Reader) bool {
+	header, _ := r.Peek(blockHeaderSize)
+	_, _, err := readBlockHeader(bytes.NewReader(header))
+	return err == nil
+}
+
+// blockSizeFromExtra 从额外字段的 BC 子字段中读取块大小
+//
+// 参数:
+//   - extra: GZIP 成员头的 FEXTRA 字段
+//
+// 返回:
+//   - int: 整个数据块的字节数(BSIZE + 1)
+//   - bool: 是否找到 BC 子字段
+func blockSizeFromExtra(extra []byte) (int, bool) {
+	for len(extra) >= 4 {
+		size := int(binary.LittleEndian.Uint16(extra[2:4]))
+		if len(extra) < 4+size {
+			return 0, false
+		}
+		if extra[0] == 'B' && extra[1] == 'C' && size == 2 {
+			return int(binary.LittleEndian.Uint16(extra[4:6])) + 1, true
+		}
+		extra = extra[4+size:]
+	}
+	return 0, false
+}
+
+// block 读取到的原始数据块
+type block struct {
+	Offset int64  // 数据块在压缩文件中的偏移
+	Size   int    // 数据块的字节数
+	Data   []byte // 压缩数据(不含头尾)
+	CRC32  uint32 // 原始数据的 CRC32
+	ISize  uint32 // 原始数据的字节数
+}
+
+// readBlockHeader 读取数据块头并返回整个数据块的大小和头的长度
+//
+// 参数:
+//   - r: 位于数据块起始处的读取器
+//
+// 返回:
+//   - int: 整个数据块的字节数
+//   - int: 数据块头的字节数
+//   - error: 没有更多数据块时返回 io.EOF
+func readBlockHeader(r io.Reader) (int, int, error) {
+	var fixed [12]byte
+	if n, err := io.ReadFull(r, fixed[:]); err != nil {
+		if err == io.EOF && n == 0 {
+			return 0, 0, io.EOF
+		}
+		return 0, 0, fmt.Errorf("读取 BGZF 数据块头失败: %w", err)
+	}
+	// 只允许设置 FEXTRA 标志，与 htslib 一致
+	if fixed[0] != 0x1f || fixed[1] != 0x8b || fixed[2] != 8 || fixed[3] != 4 {
+		return 0, 0, ErrNotBgzf
+	}
+
+	extraLen := int(binary.LittleEndian.Uint16(fixed[10:12]))
+	extra := make([]byte, extraLen)
+	if _, err := io.ReadFull(r, extra); err != nil {
+		return 0, 0, fmt.Errorf("读取 BGZF 额外字段失败: %w", err)
+	}
+	size, ok := blockSizeFromExtra(extra)
+	headerSize := 12 + extraLen
+	if !ok || size < headerSize+blockFooterSize {
+		return 0, 0, ErrNotBgzf
+	}
+	return size, headerSize, nil
+}
+
+// readBlock 读取一个完整的数据块
+//
+// 参数:
+//   - r: 位于数据块起始处的读取器
+//   - offset: 数据块在压缩文件中的偏移
+//
+// 返回:
+//   - *block: 数据块
+//   - error: 没有更多数据块时返回 io.EOF
+func readBlock(r io.Reader, offset int64) (*block, error) {
+	size, headerSize, err := readBlockHeader(r)
+	if err != nil {
+		return nil, err
+	}
+
+	rest := make([]byte, size-headerSize)
+	if _, err := io.ReadFull(r, rest); err != nil {
+		return nil, fmt.Errorf("读取 BGZF 数据块失败: %w", err)
+	}
+	footer := rest[len(rest)-blockFooterSize:]
+	isize := binary.LittleEndian.Uint32(footer[4:8])
+	if isize > MaxBlockSize {
+		return nil, ErrNotBgzf
+	}
+	return &block{
+		Offset: offset,
+		Size:   size,
+		Data:   rest[:len(rest)-blockFooterSize],
+		CRC32:  binary.LittleEndian.Uint32(footer[0:4]),
+		ISize:  isize,
+	}, nil
+}
+
+// ScanBlocks 不解压地依次扫描所有数据块
+//
+// r 实现 io.Seeker 时跳过压缩数据，只读取块头和块尾。
+//
+// 参数:
+//   - r: 位于文件起始处的 BGZF 数据流
+//   - fn: 对每个数据块调用的函数，参数为块偏移、块大小和原始数据字节数
+//
+// 返回:
+//   - error: 错误信息
+func ScanBlocks(r io.Reader, fn func(offset int64, size int, isize uint32) error) error {
+	seeker, canSeek := r.(io.Seeker)
+	var offset int64
+	var footer [blockFooterSize]byte
+	for {
+		size, headerSize, err := readBlockHeader(r)
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("偏移 %d 处: %w", offset, err)
+		}
+
+		// 跳过压缩数据
+		skip := int64(size - headerSize - blockFooterSize)
+		if canSeek {
+			_, err = seeker.Seek(skip, io.SeekCurrent)
+		} else {
+			_, err = io.CopyN(io.Discard, r, skip)
+		}
+		if err != nil {
+			return fmt.Errorf("跳过 BGZF 压缩数据失败: %w", err)
+		}
+		if _, err := io.ReadFull(r, footer[:]); err != nil {
+			return fmt.Errorf("读取 BGZF 数据块尾失败: %w", err)
+		}
+
+		if err := fn(offset, size, binary.LittleEndian.Uint32(footer[4:8])); err != nil {
+			return err
+		}
+		offset += int64(size)
+	}
+}
+
+// BuildIndex 扫描BGZF数据流并构建索引
+//
+// 参数:
+//   - r: 位于文件起始处的 BGZF 数据流
+//
+// 返回:
+//   - types.BgzfIndex: 与 htslib .gzi 一致的索引
+//   - error: 错误信息
+func BuildIndex(r io.Reader) (types.BgzfIndex, error) {
+	index := types.BgzfIndex{}
+	var uncompressed uint64
+	err := ScanBlocks(r, func(offset int64, size int, isize uint32) error {
+		// 空块(如 EOF 标记)不记录，第一个数据块的偏移均为 0 也不记录
+		if isize > 0 && (offset > 0 || uncompressed > 0) {
+			index = append(index, types.BgzfIndexEntry{CompressedOffset: uint64(offset), UncompressedOffset: uncompressed})
+		}
+		uncompressed += uint64(isize)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return index, nil
+}
+
+// ReadIndex 读取 .gzi 索引
+//
+// .gzi 由小端序的条目数量和若干 (压缩偏移, 原始偏移) 对组成，均为 64 位无符号整数。
+//
+// 参数:
+//   - r: .gzi 数据流
+//
+// 返回:
+//   - types.BgzfIndex: 索引
+//   - error: 错误信息
+func ReadIndex(r io.Reader) (types.BgzfIndex, error) {
+	var count uint64
+	if err := binary.Read(r, binary.LittleEndian, &count); err != nil {
+		return nil, fmt.Errorf("读取 BGZF 索引条目数量失败: %w", err)
+	}
+	if count > math.MaxInt32 {
+		return nil, fmt.Errorf("BGZF 索引条目数量无效: %d", count)
+	}
+
+	index := make(types.BgzfIndex, 0, min(count, 1<<16))
+	var pair [16]byte
+	for i := uint64(0); i < count; i++ {
+		if _, err := io.ReadFull(r, pair[:]); err != nil {
+			return nil, fmt.Errorf("读取 BGZF 索引条目 %d 失败: %w", i, err)
+		}
+		index = append(index, types.BgzfIndexEntry{
+			CompressedOffset:   binary.LittleEndian.Uint64(pair[0:8]),
+			UncompressedOffset: binary.LittleEndian.Uint64(pair[8:16]),
+		})
+	}
+	return index, nil
+}
+
+// WriteIndex 写入 .gzi 索引
+//
+// 参数:
+//   - w: 输出目标
+//   - index: 索引
+//
+// 返回:
+//   - error: 错误信息
+func WriteIndex(w io.Writer, index types.BgzfIndex) error {
+	buf := bytes.NewBuffer(make([]byte, 0, 8+16*len(index)))
+	_ = binary.Write(buf, binary.LittleEndian, uint64(len(index)))
+	for _, entry := range index {
+		_ = binary.Write(buf, binary.LittleEndian, entry.CompressedOffset)
+		_ = binary.Write(buf, binary.LittleEndian, entry.UncompressedOffset)
+	}
+	if _, err := w.Write(buf.Bytes()); err != nil {
+		return fmt.Errorf("写入 BGZF 索引失败: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,199 @@
+package cxbgzf
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/rand"
+	"io"
+	"strings"
+	"testing"
+
+	"gitee.com/MM-Q/comprx/types"
+)
+
+// writeBgzf 将数据写为 BGZF 并返回压缩结果和写入器
+func writeBgzf(t *testing.T, parts ...[]byte) ([]byte, *Writer) {
+	t.Helper()
+	var buf bytes.Buffer
+	w, err := NewWriter(&buf, types.CompressionLevelDefault)
+	if err != nil {
+		t.Fatalf("创建写入器失败: %v", err)
+	}
+	for _, part := range parts {
+		if _, err := w.Write(part); err != nil {
+			t.Fatalf("写入失败: %v", err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("关闭写入器失败: %v", err)
+	}
+	return buf.Bytes(), w
+}
+
+// TestWriter_GzipCompatible 测试 BGZF 数据是合法的多成员 GZIP
+func TestWriter_GzipCompatible(t *testing.T) {
+	text := []byte(strings.Repeat("chr1\t12345\t.\tA\tG\t50\tPASS\n", 10000))
+	random := make([]byte, 200*1024)
+	if _, err := rand.Read(random); err != nil {
+		t.Fatal(err)
+	}
+
+	for name, data := range map[string][]byte{"文本": text, "随机数据": random, "空": {}} {
+		t.Run(name, func(t *testing.T) {
+			compressed, _ := writeBgzf(t, data)
+			if !bytes.HasSuffix(compressed, EOFMarker) {
+				t.Error("应以 EOF 标记结尾")
+			}
+
+			gzipReader, err := gzip.NewReader(bytes.NewReader(compressed))
+			if err != nil {
+				t.Fatalf("GZIP 读取失败: %v", err)
+			}
+			if !IsBgzfExtra(gzipReader.Extra) {
+				t.Error("成员头应包含 BC 子字段")
+			}
+			got, err := io.ReadAll(gzipReader)
+			if err != nil {
+				t.Fatalf("GZIP 解压失败: %v", err)
+			}
+			if !bytes.Equal(got, data) {
+				t.Error("GZIP 解压结果不一致")
+			}
+
+			// 每个数据块不超过 64 KiB
+			err = ScanBlocks(bytes.NewReader(compressed), func(offset int64, size int, isize uint32) error {
+				if size > MaxBlockSize || isize > BlockDataSize {
+					t.Errorf("偏移 %d 处的数据块过大: %d 字节, 原始 %d 字节", offset, size, isize)
+				}
+				return nil
+			})
+			if err != nil {
+				t.Fatalf("扫描数据块失败: %v", err)
+			}
+		})
+	}
+}
+
+// TestReader_Seek 测试按虚拟偏移和原始偏移定位
+func TestReader_Seek(t *testing.T) {
+	var records [][]byte
+	for i := 0; i < 5000; i++ {
+		records = append(records, []byte(strings.Repeat(string(rune('a'+i%26)), 37)+"\n"))
+	}
+
+	var buf bytes.Buffer
+	w, err := NewWriter(&buf, types.CompressionLevelBest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	offsets := make([]types.BgzfOffset, len(records))
+	var plain []byte
+	for i, record := range records {
+		offsets[i] = w.VirtualOffset()
+		if _, err := w.Write(record); err != nil {
+			t.Fatal(err)
+		}
+		plain = append(plain, record...)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	compressed := buf.Bytes()
+
+	// 顺序读取
+	r := NewReader(bytes.NewReader(compressed))
+	got, err := io.ReadAll(r)
+	if err != nil || !bytes.Equal(got, plain) {
+		t.Fatalf("顺序读取结果不一致: %v", err)
+	}
+
+	// 按写入时记录的虚拟偏移读取
+	for _, i := range []int{4999, 0, 1777, 3001} {
+		if err := r.Seek(offsets[i]); err != nil {
+			t.Fatalf("定位记录 %d 失败: %v", i, err)
+		}
+		if r.Tell() != offsets[i] {
+			t.Errorf("Tell 应返回 %d, 实际 %d", offsets[i], r.Tell())
+		}
+		line := make([]byte, len(records[i]))
+		if _, err := io.ReadFull(r, line); err != nil || !bytes.Equal(line, records[i]) {
+			t.Errorf("记录 %d 不正确: %q, %v", i, line, err)
+		}
+	}
+
+	// 写入时生成的索引与扫描构建的索引一致
+	built, err := BuildIndex(bytes.NewReader(compressed))
+	if err != nil {
+		t.Fatalf("构建索引失败: %v", err)
+	}
+	index := w.Index()
+	if len(index) == 0 || len(built) != len(index) {
+		t.Fatalf("索引条目数不一致: 写入 %d, 扫描 %d", len(index), len(built))
+	}
+	for i := range index {
+		if index[i] != built[i] {
+			t.Errorf("索引条目 %d 不一致: %+v != %+v", i, index[i], built[i])
+		}
+	}
+
+	// 按原始偏移定位
+	if err := r.SeekUncompressed(10); err == nil {
+		t.Error("未设置索引时按原始偏移定位应返回错误")
+	}
+	r.SetIndex(index)
+	for _, offset := range []uint64{0, 1, BlockDataSize - 1, BlockDataSize, uint64(len(plain)) - 3} {
+		if err := r.SeekUncompressed(offset); err != nil {
+			t.Fatalf("定位原始偏移 %d 失败: %v", offset, err)
+		}
+		b := make([]byte, 3)
+		if _, err := io.ReadFull(r, b); err != nil || !bytes.Equal(b, plain[offset:offset+3]) {
+			t.Errorf("原始偏移 %d 处的数据不正确: %q, %v", offset, b, err)
+		}
+	}
+	if err := r.SeekUncompressed(uint64(len(plain)) + 1); err == nil {
+		t.Error("超出数据范围时应返回错误")
+	}
+}
+
+// TestIndex_ReadWrite 测试 .gzi 索引的读写
+func TestIndex_ReadWrite(t *testing.T) {
+	index := types.BgzfIndex{{CompressedOffset: 21344, UncompressedOffset: 65280}, {CompressedOffset: 42708, UncompressedOffset: 130560}}
+
+	var buf bytes.Buffer
+	if err := WriteIndex(&buf, index); err != nil {
+		t.Fatalf("写入索引失败: %v", err)
+	}
+	if buf.Len() != 8+16*len(index) {
+		t.Fatalf("索引长度不正确: %d", buf.Len())
+	}
+	if want := []byte{2, 0, 0, 0, 0, 0, 0, 0, 0x60, 0x53, 0, 0, 0, 0, 0, 0}; !bytes.Equal(buf.Bytes()[:16], want) {
+		t.Errorf("索引应为小端序: % x", buf.Bytes()[:16])
+	}
+
+	got, err := ReadIndex(&buf)
+	if err != nil {
+		t.Fatalf("读取索引失败: %v", err)
+	}
+	if len(got) != len(index) || got[0] != index[0] || got[1] != index[1] {
+		t.Errorf("读取的索引不一致: %+v", got)
+	}
+
+	if _, err := ReadIndex(bytes.NewReader([]byte{5, 0, 0, 0, 0, 0, 0, 0})); err == nil {
+		t.Error("截断的索引应返回错误")
+	}
+}
+
+// TestReader_NotBgzf 测试普通 GZIP 数据被拒绝
+func TestReader_NotBgzf(t *testing.T) {
+	var buf bytes.Buffer
+	gzipWriter := gzip.NewWriter(&buf)
+	_, _ = gzipWriter.Write([]byte("plain gzip"))
+	_ = gzipWriter.Close()
+
+	if _, err := io.ReadAll(NewReader(bytes.NewReader(buf.Bytes()))); err == nil {
+		t.Error("普通 GZIP 数据应返回错误")
+	}
+	if IsBgzfExtra(nil) {
+		t.Error("没有额外字段时不是 BGZF")
+	}
+}
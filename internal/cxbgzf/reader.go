@@ -0,0 +1,207 @@
+// Package cxbgzf 提供 BGZF 格式的读取和随机访问功能实现。
+//
+// Reader 逐块解压 BGZF 数据，底层实现 io.Seeker 时可以按虚拟文件偏移定位，
+// 设置 .gzi 索引后还可以按解压后数据中的偏移定位。
+//
+// 主要功能：
+//   - 顺序读取 BGZF 数据
+//   - 按虚拟文件偏移定位
+//   - 按原始数据偏移定位(需要索引)
+//   - 获取当前读取位置的虚拟文件偏移
+//
+// 使用示例：
+//
+//	r := cxbgzf.NewReader(file)
+//	err := r.Seek(types.NewBgzfOffset(blockStart, 100))
+//	n, err := r.Read(buf)
+package cxbgzf
+
+import (
+	"bytes"
+	"compress/flate"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"sort"
+
+	"gitee.com/MM-Q/comprx/types"
+)
+
+// Reader BGZF 读取器
+type Reader struct {
+	r         io.Reader       // 底层数据流
+	inflater  io.ReadCloser   // 复用的 flate 解压器
+	data      []byte          // 当前数据块解压后的数据
+	pos       int             // 在当前数据块中的读取位置
+	blockAddr int64           // 当前数据块在压缩文件中的偏移
+	nextAddr  int64           // 下一个数据块在压缩文件中的偏移
+	index     types.BgzfIndex // 按原始偏移定位使用的索引
+}
+
+// NewReader 创建BGZF读取器
+//
+// 参数:
+//   - r: 位于文件起始处的 BGZF 数据流，实现 io.Seeker 时支持定位
+//
+// 返回:
+//   - *Reader: BGZF 读取器
+func NewReader(r io.Reader) *Reader {
+	return &Reader{r: r, data: make([]byte, 0, BlockDataSize)}
+}
+
+// SetIndex 设置按原始数据偏移定位使用的索引
+//
+// 参数:
+//   - index: .gzi 索引
+func (r *Reader) SetIndex(index types.BgzfIndex) {
+	r.index = index
+}
+
+// Read 读取解压后的数据
+//
+// 参数:
+//   - p: 读取缓冲区
+//
+// 返回:
+//   - int: 读取的字节数
+//   - error: 没有更多数据时返回 io.EOF
+func (r *Reader) Read(p []byte) (int, error) {
+	if len(p) == 0 {
+		return 0, nil
+	}
+	// 当前数据块已读完时读取下一个非空数据块
+	for r.pos >= len(r.data) {
+		if err := r.loadBlock(); err != nil {
+			return 0, err
+		}
+	}
+	n := copy(p, r.data[r.pos:])
+	r.pos += n
+	return n, nil
+}
+
+// Tell 返回下一个读取字节的虚拟文件偏移
+//
+// 返回:
+//   - types.BgzfOffset: 虚拟文件偏移
+func (r *Reader) Tell() types.BgzfOffset {
+	// 当前数据块已读完时指向下一个数据块的起始
+	if r.pos >= len(r.data) && len(r.data) > 0 {
+		return types.NewBgzfOffset(uint64(r.nextAddr), 0)
+	}
+	return types.NewBgzfOffset(uint64(r.blockAddr), uint16(r.pos))
+}
+
+// Seek 定位到虚拟文件偏移
+//
+// 参数:
+//   - offset: 虚拟文件偏移，通常来自 Tell、Writer.VirtualOffset 或外部索引
+//
+// 返回:
+//   - error: 底层数据流不支持定位或偏移无效时返回错误
+func (r *Reader) Seek(offset types.BgzfOffset) error {
+	if err := r.seekBlock(int64(offset.Compressed())); err != nil {
+		return err
+	}
+	if int(offset.Uncompressed()) > len(r.data) {
+		return fmt.Errorf("虚拟偏移 %d 超出数据块范围(块内 %d 字节)", offset, len(r.data))
+	}
+	r.pos = int(offset.Uncompressed())
+	return nil
+}
+
+// SeekUncompressed 定位到解压后数据中的偏移
+//
+// 参数:
+//   - offset: 解压后数据中的偏移
+//
+// 返回:
+//   - error: 未设置索引、底层数据流不支持定位或偏移超出数据范围时返回错误
+func (r *Reader) SeekUncompressed(offset uint64) error {
+	if r.index == nil {
+		return fmt.Errorf("按原始偏移定位需要先设置 BGZF 索引")
+	}
+
+	// 查找包含该偏移的数据块，索引不含起始偏移均为 0 的第一个数据块
+	i := sort.Search(len(r.index), func(i int) bool { return r.index[i].UncompressedOffset > offset })
+	entry := types.BgzfIndexEntry{}
+	if i > 0 {
+		entry = r.index[i-1]
+	}
+	if err := r.seekBlock(int64(entry.CompressedOffset)); err != nil {
+		return err
+	}
+
+	// 跳过块内及后续块中的数据(索引不完整时可能跨越多个数据块)
+	remaining := offset - entry.UncompressedOffset
+	for remaining > uint64(len(r.data)-r.pos) {
+		remaining -= uint64(len(r.data) - r.pos)
+		r.pos = len(r.data)
+		if err := r.loadBlock(); err != nil {
+			if err == io.EOF {
+				return fmt.Errorf("原始偏移 %d 超出数据范围", offset)
+			}
+			return err
+		}
+	}
+	r.pos += int(remaining)
+	return nil
+}
+
+// seekBlock 定位到压缩文件中的数据块并读取该块
+//
+// 参数:
+//   - addr: 数据块在压缩文件中的偏移
+//
+// 返回:
+//   - error: 错误信息
+func (r *Reader) seekBlock(addr int64) error {
+	seeker, ok := r.r.(io.Seeker)
+	if !ok {
+		return fmt.Errorf("底层数据流不支持定位")
+	}
+	if _, err := seeker.Seek(addr, io.SeekStart); err != nil {
+		return fmt.Errorf("定位 BGZF 数据块失败: %w", err)
+	}
+	r.nextAddr = addr
+	r.data = r.data[:0]
+	r.pos = 0
+	if err := r.loadBlock(); err != nil && err != io.EOF {
+		return err
+	}
+	return nil
+}
+
+// loadBlock 读取并解压下一个数据块
+//
+// 返回:
+//   - error: 没有更多数据块时返回 io.EOF
+func (r *Reader) loadBlock() error {
+	blk, err := readBlock(r.r, r.nextAddr)
+	if err != nil {
+		return err
+	}
+
+	if r.inflater == nil {
+		r.inflater = flate.NewReader(bytes.NewReader(blk.Data))
+	} else if err := r.inflater.(flate.Resetter).Reset(bytes.NewReader(blk.Data), nil); err != nil {
+		return fmt.Errorf("重置 BGZF 解压器失败: %w", err)
+	}
+
+	r.data = r.data[:cap(r.data)]
+	if blk.ISize > uint32(cap(r.data)) {
+		r.data = make([]byte, blk.ISize)
+	}
+	r.data = r.data[:blk.ISize]
+	if _, err := io.ReadFull(r.inflater, r.data); err != nil {
+		return fmt.Errorf("解压偏移 %d 处的 BGZF 数据块失败: %w", blk.Offset, err)
+	}
+	if crc32.ChecksumIEEE(r.data) != blk.CRC32 {
+		return fmt.Errorf("偏移 %d 处的 BGZF 数据块校验失败", blk.Offset)
+	}
+
+	r.blockAddr = blk.Offset
+	r.nextAddr = blk.Offset + int64(blk.Size)
+	r.pos = 0
+	return nil
+}
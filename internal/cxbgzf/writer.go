@@ -0,0 +1,229 @@
+// Package cxbgzf 提供 BGZF 格式的写入功能实现。
+//
+// Writer 将写入的数据按 BlockDataSize 分块，每块压缩为一个带 BC 子字段的 GZIP 成员，
+// 关闭时追加 EOF 标记。写入过程中记录每个数据块的起始位置，可直接生成 .gzi 索引。
+//
+// 主要功能：
+//   - 分块压缩写入 BGZF 数据
+//   - 主动结束当前数据块，使后续数据从新块开始
+//   - 获取当前写入位置的虚拟文件偏移
+//   - 生成 .gzi 索引
+//
+// 使用示例：
+//
+//	w, err := cxbgzf.NewWriter(file, types.CompressionLevelDefault)
+//	_, err = w.Write(data)
+//	err = w.Close()
+//	index := w.Index()
+package cxbgzf
+
+import (
+	"bytes"
+	"compress/flate"
+	"encoding/binary"
+	"fmt"
+	"hash/crc32"
+	"io"
+
+	"gitee.com/MM-Q/comprx/internal/config"
+	"gitee.com/MM-Q/comprx/types"
+)
+
+// Writer BGZF 写入器
+type Writer struct {
+	w          io.Writer       // 输出目标
+	level      int             // flate 压缩等级
+	data       []byte          // 当前数据块中尚未压缩的数据
+	compressed bytes.Buffer    // 压缩数据块的缓冲区
+	flater     *flate.Writer   // 复用的 flate 压缩器
+	coffset    uint64          // 已写出的压缩字节数，即下一个数据块的偏移
+	uoffset    uint64          // 已写出的原始字节数
+	starts     types.BgzfIndex // 每个数据块的起始位置(含第一个数据块)
+	closed     bool            // 是否已关闭
+}
+
+// NewWriter 创建BGZF写入器
+//
+// 参数:
+//   - w: 输出目标
+//   - level: 压缩等级
+//
+// 返回:
+//   - *Writer: BGZF 写入器
+//   - error: 错误信息
+func NewWriter(w io.Writer, level types.CompressionLevel) (*Writer, error) {
+	flateLevel := config.GetCompressionLevel(level)
+	flater, err := flate.NewWriter(nil, flateLevel)
+	if err != nil {
+		return nil, fmt.Errorf("创建 BGZF 压缩器失败: %w", err)
+	}
+	return &Writer{
+		w:      w,
+		level:  flateLevel,
+		data:   make([]byte, 0, BlockDataSize),
+		flater: flater,
+	}, nil
+}
+
+// Write 写入数据，满一个数据块时压缩并写出
+//
+// 参数:
+//   - p: 要写入的数据
+//
+// 返回:
+//   - int: 写入的字节数
+//   - error: 错误信息
+func (w *Writer) Write(p []byte) (int, error) {
+	if w.closed {
+		return 0, fmt.Errorf("BGZF 写入器已关闭")
+	}
+
+	written := 0
+	for len(p) > 0 {
+		n := copy(w.data[len(w.data):BlockDataSize], p)
+		w.data = w.data[:len(w.data)+n]
+		p = p[n:]
+		written += n
+
+		if len(w.data) == BlockDataSize {
+			if err := w.writeBlock(w.data); err != nil {
+				return written, err
+			}
+			w.data = w.data[:0]
+		}
+	}
+	return written, nil
+}
+
+// Flush 结束当前数据块并写出，之后写入的数据从新的数据块开始
+//
+// 返回:
+//   - error: 错误信息
+func (w *Writer) Flush() error {
+	if w.closed {
+		return fmt.Errorf("BGZF 写入器已关闭")
+	}
+	if len(w.data) == 0 {
+		return nil
+	}
+	if err := w.writeBlock(w.data); err != nil {
+		return err
+	}
+	w.data = w.data[:0]
+	return nil
+}
+
+// Close 写出剩余数据和 EOF 标记
+//
+// 不关闭底层的输出目标。
+//
+// 返回:
+//   - error: 错误信息
+func (w *Writer) Close() error {
+	if w.closed {
+		return nil
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	w.closed = true
+	if _, err := w.w.Write(EOFMarker); err != nil {
+		return fmt.Errorf("写入 BGZF EOF 标记失败: %w", err)
+	}
+	w.coffset += uint64(len(EOFMarker))
+	return nil
+}
+
+// VirtualOffset 返回下一个写入字节的虚拟文件偏移
+//
+// 返回:
+//   - types.BgzfOffset: 虚拟文件偏移
+func (w *Writer) VirtualOffset() types.BgzfOffset {
+	return types.NewBgzfOffset(w.coffset, uint16(len(w.data)))
+}
+
+// Index 返回已写出数据块的索引
+//
+// 返回:
+//   - types.BgzfIndex: 与 htslib .gzi 一致的索引，不含第一个数据块
+func (w *Writer) Index() types.BgzfIndex {
+	if len(w.starts) <= 1 {
+		return types.BgzfIndex{}
+	}
+	index := make(types.BgzfIndex, len(w.starts)-1)
+	copy(index, w.starts[1:])
+	return index
+}
+
+// writeBlock 压缩一个数据块并写出
+//
+// 参数:
+//   - data: 原始数据，不超过 BlockDataSize 字节
+//
+// 返回:
+//   - error: 错误信息
+func (w *Writer) writeBlock(data []byte) error {
+	blockData, err := w.compressBlock(data, w.level)
+	if err != nil {
+		return err
+	}
+	// 数据无法压缩时改为存储，保证数据块不超过 64 KiB
+	if blockHeaderSize+len(blockData)+blockFooterSize > MaxBlockSize {
+		if blockData, err = w.compressBlock(data, flate.NoCompression); err != nil {
+			return err
+		}
+	}
+
+	size := blockHeaderSize + len(blockData) + blockFooterSize
+	header := [blockHeaderSize]byte{
+		0x1f, 0x8b, 8, 4, // ID1 ID2 CM FLG(FEXTRA)
+		0, 0, 0, 0, // MTIME
+		0, 0xff, // XFL OS(未知)
+		6, 0, // XLEN
+		'B', 'C', 2, 0, // SI1 SI2 SLEN
+	}
+	binary.LittleEndian.PutUint16(header[16:18], uint16(size-1))
+	var footer [blockFooterSize]byte
+	binary.LittleEndian.PutUint32(footer[0:4], crc32.ChecksumIEEE(data))
+	binary.LittleEndian.PutUint32(footer[4:8], uint32(len(data)))
+
+	for _, part := range [][]byte{header[:], blockData, footer[:]} {
+		if _, err := w.w.Write(part); err != nil {
+			return fmt.Errorf("写入 BGZF 数据块失败: %w", err)
+		}
+	}
+
+	w.starts = append(w.starts, types.BgzfIndexEntry{CompressedOffset: w.coffset, UncompressedOffset: w.uoffset})
+	w.coffset += uint64(size)
+	w.uoffset += uint64(len(data))
+	return nil
+}
+
+// compressBlock 使用指定等级压缩一个数据块
+//
+// 参数:
+//   - data: 原始数据
+//   - level: flate 压缩等级
+//
+// 返回:
+//   - []byte: 压缩数据，在下一次压缩前有效
+//   - error: 错误信息
+func (w *Writer) compressBlock(data []byte, level int) ([]byte, error) {
+	w.compressed.Reset()
+	flater := w.flater
+	if level != w.level {
+		var err error
+		if flater, err = flate.NewWriter(&w.compressed, level); err != nil {
+			return nil, fmt.Errorf("创建 BGZF 压缩器失败: %w", err)
+		}
+	} else {
+		flater.Reset(&w.compressed)
+	}
+	if _, err := flater.Write(data); err != nil {
+		return nil, fmt.Errorf("压缩 BGZF 数据块失败: %w", err)
+	}
+	if err := flater.Close(); err != nil {
+		return nil, fmt.Errorf("压缩 BGZF 数据块失败: %w", err)
+	}
+	return w.compressed.Bytes(), nil
+}
@@ -26,7 +26,6 @@
 // 性能优化：
 //   - 使用缓冲区池减少内存分配
 //   - 高效的文件大小计算方法
-//   - 错误容忍的大小估算机制
 //
 // 使用示例：
 //
@@ -93,8 +92,7 @@ func ListBz2(archivePath string) (*types.ArchiveInfo, error) {
 
 	originalSize, err := io.CopyBuffer(io.Discard, bz2Reader, buffer)
 	if err != nil {
-		// 如果读取失败，使用压缩文件大小作为估算
-		originalSize = stat.Size()
+		return nil, fmt.Errorf("解压BZ2数据失败: %w", err)
 	}
 
 	// 创建BZ2文件信息
@@ -106,6 +104,9 @@ func ListBz2(archivePath string) (*types.ArchiveInfo, error) {
 		Mode:           utils.DefaultFileMode, // BZ2不保存文件权限，使用默认权限
 		IsDir:          false,
 		IsSymlink:      false,
+		EntryType:      types.EntryTypeFile,
+		Method:         "bzip2",
+		HeaderOffset:   -1, // BZIP2 没有条目头
 	}
 
 	// 根据文件名检测压缩格式类型
@@ -0,0 +1,55 @@
+package cxbzip2
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// sampleBzip2 "hello bzip2 list\n" 的 BZIP2 压缩数据
+var sampleBzip2 = []byte{
+	0x42, 0x5a, 0x68, 0x39, 0x31, 0x41, 0x59, 0x26, 0x53, 0x59, 0xcd, 0x67, 0xa7, 0xa0, 0x00, 0x00,
+	0x03, 0x59, 0x80, 0x00, 0x10, 0x40, 0x00, 0x10, 0x00, 0x12, 0x64, 0xcc, 0x10, 0x20, 0x00, 0x22,
+	0x26, 0x98, 0x06, 0x42, 0x01, 0xa0, 0x01, 0x8f, 0xb1, 0x72, 0x43, 0xef, 0xd0, 0x21, 0x3c, 0x2e,
+	0xe4, 0x8a, 0x70, 0xa1, 0x21, 0x9a, 0xcf, 0x4f, 0x40,
+}
+
+func TestListBz2(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "hello.txt.bz2")
+	if err := os.WriteFile(path, sampleBzip2, 0644); err != nil {
+		t.Fatalf("创建测试文件失败: %v", err)
+	}
+
+	info, err := ListBz2(path)
+	if err != nil {
+		t.Fatalf("列出BZ2文件信息失败: %v", err)
+	}
+	if want := int64(len("hello bzip2 list\n")); info.Files[0].Name != "hello.txt" || info.TotalSize != want || info.Files[0].Size != want {
+		t.Errorf("文件信息不正确: 期望大小 %d, 实际 %+v", want, info.Files[0])
+	}
+	if info.Files[0].HeaderOffset != -1 {
+		t.Errorf("BZIP2 没有条目头，偏移应为 -1: %d", info.Files[0].HeaderOffset)
+	}
+}
+
+func TestListBz2_Invalid(t *testing.T) {
+	tempDir := t.TempDir()
+
+	// 文件头无效时返回错误
+	invalid := filepath.Join(tempDir, "invalid.bz2")
+	if err := os.WriteFile(invalid, []byte("not a bzip2 file"), 0644); err != nil {
+		t.Fatalf("创建测试文件失败: %v", err)
+	}
+	if _, err := ListBz2(invalid); err == nil {
+		t.Error("文件头无效时应返回错误")
+	}
+
+	// 截断的数据解压失败时返回错误，不再用压缩大小估算
+	truncated := filepath.Join(tempDir, "truncated.bz2")
+	if err := os.WriteFile(truncated, sampleBzip2[:30], 0644); err != nil {
+		t.Fatalf("创建测试文件失败: %v", err)
+	}
+	if info, err := ListBz2(truncated); err == nil || info != nil {
+		t.Errorf("解压失败时应返回错误, 实际 %+v, %v", info, err)
+	}
+}
@@ -0,0 +1,50 @@
+// Package cxbzip2 提供 BZIP2 文件的完整性校验功能实现。
+//
+// 该文件实现了 BZIP2 文件的完整性校验：解压全部数据并丢弃，
+// 由 compress/bzip2 校验每个数据块和整个流的 CRC，不会写入任何文件。
+//
+// 主要功能：
+//   - 校验数据块 CRC 和流 CRC
+//   - 发现截断的压缩数据
+//
+// 使用示例：
+//
+//	report, err := cxbzip2.Verify("data.bz2")
+package cxbzip2
+
+import (
+	"compress/bzip2"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gitee.com/MM-Q/comprx/types"
+)
+
+// Verify 校验 BZIP2 文件的完整性
+//
+// 参数:
+//   - archivePath: BZIP2 文件路径
+//
+// 返回值:
+//   - *types.VerifyReport: 校验报告，损坏时记录在 Failures 中
+//   - error: 无法打开文件时返回错误
+func Verify(archivePath string) (*types.VerifyReport, error) {
+	file, err := os.Open(archivePath)
+	if err != nil {
+		return nil, fmt.Errorf("打开 BZIP2 文件失败: %w", err)
+	}
+	defer func() { _ = file.Close() }()
+
+	report := &types.VerifyReport{Type: types.CompressTypeBz2, TotalEntries: 1}
+	name := strings.TrimSuffix(filepath.Base(archivePath), filepath.Ext(archivePath))
+
+	size, err := io.Copy(io.Discard, bzip2.NewReader(file))
+	report.TotalSize = size
+	if err != nil {
+		report.AddFailure(name, fmt.Errorf("BZIP2 数据校验失败: %w", err))
+	}
+	return report, nil
+}
@@ -0,0 +1,233 @@
+// Package cxflate 提供原始 DEFLATE 格式的内存压缩和流式压缩功能实现。
+//
+// 原始 DEFLATE (RFC 1951) 没有文件头和校验和，是 GZIP、ZLIB 和 ZIP 条目内部使用的压缩数据，
+// 部分 HTTP 实现的 deflate 内容编码也直接使用原始 DEFLATE。
+//
+// 主要功能：
+//   - DEFLATE 内存压缩：字节数组的压缩解压
+//   - DEFLATE 流式压缩：支持 io.Reader 和 io.Writer 接口
+//   - 支持自定义压缩等级
+//   - 支持预设字典
+//
+// 压缩特性：
+//   - 没有文件头和尾部，数据最紧凑
+//   - 没有校验和，无法检测数据损坏
+//   - 预设字典不记录在数据中，解压时必须自行提供相同的字典
+//
+// 使用示例：
+//
+//	// 压缩字节数据
+//	compressed, err := cxflate.CompressBytes(data, types.CompressionLevelBest)
+//
+//	// 解压字节数据
+//	decompressed, err := cxflate.DecompressBytes(compressed)
+//
+//	// 流式压缩
+//	err := cxflate.CompressStream(dst, src, types.CompressionLevelFast)
+package cxflate
+
+import (
+	"bytes"
+	"compress/flate"
+	"fmt"
+	"io"
+
+	"gitee.com/MM-Q/comprx/internal/config"
+	"gitee.com/MM-Q/comprx/types"
+)
+
+// ================================ 内存压缩API ================================
+
+// CompressBytes 压缩字节数据到内存
+//
+// 参数:
+//   - data: 要压缩的字节数据
+//   - level: 压缩级别
+//
+// 返回:
+//   - []byte: 压缩后的数据
+//   - error: 错误信息
+func CompressBytes(data []byte, level types.CompressionLevel) ([]byte, error) {
+	return CompressBytesWithDict(data, level, nil)
+}
+
+// CompressBytesWithDict 使用预设字典压缩字节数据到内存
+//
+// 参数:
+//   - data: 要压缩的字节数据
+//   - level: 压缩级别
+//   - dict: 预设字典，为空时不使用字典，超过 32 KiB 时只使用末尾部分
+//
+// 返回:
+//   - []byte: 压缩后的数据
+//   - error: 错误信息
+func CompressBytesWithDict(data []byte, level types.CompressionLevel, dict []byte) ([]byte, error) {
+	// 参数验证
+	if data == nil {
+		return nil, fmt.Errorf("输入数据不能为nil")
+	}
+	if len(data) == 0 {
+		return nil, fmt.Errorf("输入数据不能为空")
+	}
+
+	// 创建内存缓冲区 - 预分配原大小的50%
+	estimatedSize := len(data) / 2
+	if estimatedSize < 64 {
+		estimatedSize = 64 // 最小64字节
+	}
+	buf := bytes.NewBuffer(make([]byte, 0, estimatedSize))
+
+	if err := CompressStreamWithDict(buf, bytes.NewReader(data), level, dict); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// DecompressBytes 从内存解压字节数据
+//
+// 参数:
+//   - compressedData: 压缩的字节数据
+//
+// 返回:
+//   - []byte: 解压后的数据
+//   - error: 错误信息
+func DecompressBytes(compressedData []byte) ([]byte, error) {
+	return DecompressBytesWithDict(compressedData, nil)
+}
+
+// DecompressBytesWithDict 使用预设字典从内存解压字节数据
+//
+// 原始 DEFLATE 不记录字典的校验值，字典不一致时通常会得到错误的数据而不是错误信息。
+//
+// 参数:
+//   - compressedData: 压缩的字节数据
+//   - dict: 压缩时使用的预设字典
+//
+// 返回:
+//   - []byte: 解压后的数据
+//   - error: 错误信息
+func DecompressBytesWithDict(compressedData []byte, dict []byte) ([]byte, error) {
+	// 参数验证
+	if compressedData == nil {
+		return nil, fmt.Errorf("压缩数据不能为nil")
+	}
+	if len(compressedData) == 0 {
+		return nil, fmt.Errorf("压缩数据不能为空")
+	}
+
+	// 预分配解压缓冲区 - 解压通常是压缩数据的2-3倍
+	estimatedSize := len(compressedData) * 2
+	if estimatedSize < 128 {
+		estimatedSize = 128 // 最小128字节
+	}
+	buf := bytes.NewBuffer(make([]byte, 0, estimatedSize))
+
+	if err := DecompressStreamWithDict(buf, bytes.NewReader(compressedData), dict); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// ==================== 流式压缩API ====================
+
+// CompressStream 流式压缩数据
+//
+// 参数:
+//   - dst: 目标写入器
+//   - src: 源读取器
+//   - level: 压缩级别
+//
+// 返回:
+//   - error: 错误信息
+func CompressStream(dst io.Writer, src io.Reader, level types.CompressionLevel) error {
+	return CompressStreamWithDict(dst, src, level, nil)
+}
+
+// CompressStreamWithDict 使用预设字典流式压缩数据
+//
+// 参数:
+//   - dst: 目标写入器
+//   - src: 源读取器
+//   - level: 压缩级别
+//   - dict: 预设字典，为空时不使用字典
+//
+// 返回:
+//   - error: 错误信息
+func CompressStreamWithDict(dst io.Writer, src io.Reader, level types.CompressionLevel, dict []byte) (err error) {
+	// 1. 参数验证
+	if dst == nil {
+		return fmt.Errorf("目标写入器不能为nil")
+	}
+	if src == nil {
+		return fmt.Errorf("源读取器不能为nil")
+	}
+
+	// 2. 创建flate写入器
+	writer, createErr := flate.NewWriterDict(dst, config.GetCompressionLevel(level), dict)
+	if createErr != nil {
+		return fmt.Errorf("创建flate写入器失败: %w", createErr)
+	}
+	defer func() {
+		if closeErr := writer.Close(); closeErr != nil && err == nil {
+			// 只有在没有其他错误时才设置关闭错误
+			err = fmt.Errorf("关闭flate写入器失败: %w", closeErr)
+		}
+	}()
+
+	// 3. 流式复制数据
+	if _, copyErr := io.Copy(writer, src); copyErr != nil {
+		return fmt.Errorf("压缩数据失败: %w", copyErr)
+	}
+
+	// 4. 确保数据完整写入
+	if closeErr := writer.Close(); closeErr != nil {
+		return fmt.Errorf("完成压缩失败: %w", closeErr)
+	}
+	return nil
+}
+
+// DecompressStream 流式解压数据
+//
+// 参数:
+//   - dst: 目标写入器
+//   - src: 源读取器（压缩数据）
+//
+// 返回:
+//   - error: 错误信息
+func DecompressStream(dst io.Writer, src io.Reader) error {
+	return DecompressStreamWithDict(dst, src, nil)
+}
+
+// DecompressStreamWithDict 使用预设字典流式解压数据
+//
+// 参数:
+//   - dst: 目标写入器
+//   - src: 源读取器（压缩数据）
+//   - dict: 压缩时使用的预设字典
+//
+// 返回:
+//   - error: 错误信息
+func DecompressStreamWithDict(dst io.Writer, src io.Reader, dict []byte) (err error) {
+	// 1. 参数验证
+	if dst == nil {
+		return fmt.Errorf("目标写入器不能为nil")
+	}
+	if src == nil {
+		return fmt.Errorf("源读取器不能为nil")
+	}
+
+	// 2. 创建flate读取器
+	reader := flate.NewReaderDict(src, dict)
+	defer func() {
+		if closeErr := reader.Close(); closeErr != nil && err == nil {
+			// 只有在没有其他错误时才设置关闭错误
+			err = fmt.Errorf("关闭flate读取器失败: %w", closeErr)
+		}
+	}()
+
+	// 3. 流式复制数据
+	if _, copyErr := io.Copy(dst, reader); copyErr != nil {
+		return fmt.Errorf("解压数据失败: %w", copyErr)
+	}
+	return nil
+}
@@ -0,0 +1,124 @@
+package cxflate
+
+import (
+	"bytes"
+	"compress/flate"
+	"io"
+	"strings"
+	"testing"
+
+	"gitee.com/MM-Q/comprx/types"
+)
+
+func TestCompressDecompressBytes(t *testing.T) {
+	testData := []byte(strings.Repeat("Hello, raw DEFLATE memory compression test!\n", 100))
+
+	levels := []types.CompressionLevel{
+		types.CompressionLevelNone,
+		types.CompressionLevelFast,
+		types.CompressionLevelDefault,
+		types.CompressionLevelBest,
+		types.CompressionLevelHuffmanOnly,
+	}
+	for _, level := range levels {
+		compressed, err := CompressBytes(testData, level)
+		if err != nil {
+			t.Fatalf("等级 %d 压缩失败: %v", level, err)
+		}
+
+		decompressed, err := DecompressBytes(compressed)
+		if err != nil {
+			t.Fatalf("等级 %d 解压失败: %v", level, err)
+		}
+		if !bytes.Equal(decompressed, testData) {
+			t.Fatalf("等级 %d 解压数据与原始数据不匹配", level)
+		}
+	}
+}
+
+func TestCompressBytes_RawFormat(t *testing.T) {
+	testData := []byte("raw deflate has no header")
+
+	compressed, err := CompressBytes(testData, types.CompressionLevelDefault)
+	if err != nil {
+		t.Fatalf("压缩失败: %v", err)
+	}
+
+	// 标准库的 flate 读取器可以直接解压，说明没有 ZLIB 或 GZIP 头
+	decompressed, err := io.ReadAll(flate.NewReader(bytes.NewReader(compressed)))
+	if err != nil {
+		t.Fatalf("标准库解压失败: %v", err)
+	}
+	if !bytes.Equal(decompressed, testData) {
+		t.Fatal("标准库解压结果与原始数据不匹配")
+	}
+}
+
+func TestBytesWithDict(t *testing.T) {
+	dict := []byte(`{"service":"orders","level":"info","message":"`)
+	message := []byte(`{"service":"orders","level":"info","message":"created"}`)
+
+	withDict, err := CompressBytesWithDict(message, types.CompressionLevelBest, dict)
+	if err != nil {
+		t.Fatalf("使用字典压缩失败: %v", err)
+	}
+	withoutDict, err := CompressBytes(message, types.CompressionLevelBest)
+	if err != nil {
+		t.Fatalf("压缩失败: %v", err)
+	}
+	if len(withDict) >= len(withoutDict) {
+		t.Errorf("使用字典应减小输出: %d >= %d", len(withDict), len(withoutDict))
+	}
+
+	decompressed, err := DecompressBytesWithDict(withDict, dict)
+	if err != nil {
+		t.Fatalf("使用字典解压失败: %v", err)
+	}
+	if !bytes.Equal(decompressed, message) {
+		t.Fatal("使用字典解压的数据与原始数据不匹配")
+	}
+
+	// 原始 DEFLATE 不记录字典，缺少字典时不会得到原始数据
+	if got, err := DecompressBytes(withDict); err == nil && bytes.Equal(got, message) {
+		t.Error("缺少字典时不应得到原始数据")
+	}
+}
+
+func TestStreamWithDict(t *testing.T) {
+	dict := []byte("common prefix shared by every message ")
+	testData := []byte(strings.Repeat("common prefix shared by every message 42\n", 10))
+
+	var compressed bytes.Buffer
+	if err := CompressStreamWithDict(&compressed, bytes.NewReader(testData), types.CompressionLevelDefault, dict); err != nil {
+		t.Fatalf("流式压缩失败: %v", err)
+	}
+
+	var decompressed bytes.Buffer
+	if err := DecompressStreamWithDict(&decompressed, &compressed, dict); err != nil {
+		t.Fatalf("流式解压失败: %v", err)
+	}
+	if !bytes.Equal(decompressed.Bytes(), testData) {
+		t.Fatal("流式解压数据与原始数据不匹配")
+	}
+}
+
+func TestInvalidInput(t *testing.T) {
+	if _, err := CompressBytes(nil, types.CompressionLevelDefault); err == nil {
+		t.Error("nil 输入应返回错误")
+	}
+	if _, err := CompressBytes([]byte{}, types.CompressionLevelDefault); err == nil {
+		t.Error("空输入应返回错误")
+	}
+	if _, err := DecompressBytes(nil); err == nil {
+		t.Error("nil 压缩数据应返回错误")
+	}
+	if _, err := DecompressBytes([]byte{0xff, 0xff, 0xff}); err == nil {
+		t.Error("无效的压缩数据应返回错误")
+	}
+	if err := CompressStream(nil, strings.NewReader("x"), types.CompressionLevelDefault); err == nil {
+		t.Error("nil 写入器应返回错误")
+	}
+	if err := DecompressStream(io.Discard, nil); err == nil {
+		t.Error("nil 读取器应返回错误")
+	}
+}
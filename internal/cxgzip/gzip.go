@@ -8,6 +8,8 @@
 //   - 可配置的压缩等级
 //   - 进度显示支持
 //   - 文件元数据保存（文件名、修改时间）
+//   - 自定义文件头（原始文件名、注释、额外字段、操作系统标识）
+//   - BGZF 分块压缩及 .gzi 索引生成
 //   - 文件覆盖控制
 //
 // 限制：
@@ -28,11 +30,16 @@ package cxgzip
 import (
 	"compress/gzip"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
+	"time"
 
 	"gitee.com/MM-Q/comprx/internal/config"
+	"gitee.com/MM-Q/comprx/internal/cxbgzf"
+	"gitee.com/MM-Q/comprx/internal/envelope"
 	"gitee.com/MM-Q/comprx/internal/utils"
+	"gitee.com/MM-Q/comprx/types"
 )
 
 // Gzip 函数用于压缩单个文件为GZIP格式
@@ -72,6 +79,11 @@ func Gzip(dst string, src string, cfg *config.Config) error {
 			return fmt.Errorf("目标文件已存在且不允许覆盖: %s", dst)
 		}
 	}
+	if cfg.BgzfIndex {
+		if _, err := os.Stat(dst + ".gzi"); err == nil && !cfg.OverwriteExisting {
+			return fmt.Errorf("目标索引文件已存在且不允许覆盖: %s", dst+".gzi")
+		}
+	}
 
 	// 确保目标目录存在
 	if err := utils.EnsureDir(filepath.Dir(dst)); err != nil {
@@ -90,22 +102,41 @@ func Gzip(dst string, src string, cfg *config.Config) error {
 	}()
 
 	// 创建 GZIP 文件
-	gzipFile, err := os.Create(dst)
+	gzipFile, err := envelope.Create(dst, cfg.EncryptWith)
 	if err != nil {
 		return fmt.Errorf("创建 GZIP 文件失败: %w", err)
 	}
 	defer func() { _ = gzipFile.Close() }()
 
-	// 创建 GZIP 写入器
-	gzipWriter, err := gzip.NewWriterLevel(gzipFile, config.GetCompressionLevel(cfg.CompressionLevel))
-	if err != nil {
-		return fmt.Errorf("创建 GZIP 写入器失败: %w", err)
-	}
-	defer func() { _ = gzipWriter.Close() }()
+	// 创建 GZIP 写入器，BGZF 模式下使用固定文件头的分块写入器
+	var writer io.WriteCloser
+	var bgzfWriter *cxbgzf.Writer
+	if cfg.Bgzf {
+		if bgzfWriter, err = cxbgzf.NewWriter(gzipFile, cfg.CompressionLevel); err != nil {
+			return fmt.Errorf("创建 BGZF 写入器失败: %w", err)
+		}
+		writer = bgzfWriter
+	} else {
+		gzipWriter, err := gzip.NewWriterLevel(gzipFile, config.GetCompressionLevel(cfg.CompressionLevel))
+		if err != nil {
+			return fmt.Errorf("创建 GZIP 写入器失败: %w", err)
+		}
+
+		// 设置 GZIP 文件头信息
+		gzipWriter.Name = filepath.Base(src)
+		gzipWriter.ModTime = srcInfo.ModTime()
 
-	// 设置 GZIP 文件头信息
-	gzipWriter.Name = filepath.Base(src)
-	gzipWriter.ModTime = srcInfo.ModTime()
+		// 指定了文件头时按其写入，零值字段保持上面的默认值
+		applyGzipHeader(gzipWriter, cfg.GzipHeader)
+
+		// 确定性模式下固定修改时间(0 表示未知)和操作系统标识
+		if cfg.Deterministic {
+			gzipWriter.ModTime = time.Time{}
+			gzipWriter.OS = config.GzipHeaderOSUnknown
+		}
+		writer = gzipWriter
+	}
+	defer func() { _ = writer.Close() }()
 
 	// 打开源文件
 	srcFile, err := os.Open(src)
@@ -123,9 +154,46 @@ func Gzip(dst string, src string, cfg *config.Config) error {
 	cfg.Progress.Adding(src)
 
 	// 复制文件内容到GZIP写入器
-	if _, err := cfg.Progress.CopyBuffer(gzipWriter, srcFile, buffer); err != nil {
+	if _, err := cfg.Progress.CopyBuffer(writer, srcFile, buffer); err != nil {
 		return fmt.Errorf("压缩文件失败: %w", err)
 	}
 
+	// 显式关闭以返回写入 GZIP 尾部和加密末块时的错误
+	if err := writer.Close(); err != nil {
+		return fmt.Errorf("关闭 GZIP 写入器失败: %w", err)
+	}
+	if err := gzipFile.Close(); err != nil {
+		return fmt.Errorf("关闭 GZIP 文件失败: %w", err)
+	}
+
+	// 写入 .gzi 索引
+	if cfg.BgzfIndex && bgzfWriter != nil {
+		if err := writeBgzfIndexFile(dst+".gzi", bgzfWriter.Index()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeBgzfIndexFile 将BGZF索引写入 .gzi 文件
+//
+// 参数:
+//   - path: .gzi 文件路径
+//   - index: BGZF 索引
+//
+// 返回值:
+//   - error: 操作过程中遇到的错误
+func writeBgzfIndexFile(path string, index types.BgzfIndex) error {
+	indexFile, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("创建 BGZF 索引文件失败: %w", err)
+	}
+	if err := cxbgzf.WriteIndex(indexFile, index); err != nil {
+		_ = indexFile.Close()
+		return err
+	}
+	if err := indexFile.Close(); err != nil {
+		return fmt.Errorf("关闭 BGZF 索引文件失败: %w", err)
+	}
 	return nil
 }
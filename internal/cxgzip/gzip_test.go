@@ -1,11 +1,14 @@
 package cxgzip
 
 import (
+	"bytes"
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"gitee.com/MM-Q/comprx/internal/config"
+	"gitee.com/MM-Q/comprx/internal/cxbgzf"
 	"gitee.com/MM-Q/comprx/types"
 )
 
@@ -189,3 +192,149 @@ func BenchmarkGzip_SmallFile(b *testing.B) {
 		}
 	}
 }
+
+func TestGzip_Deterministic(t *testing.T) {
+	tempDir := t.TempDir()
+	srcFile := filepath.Join(tempDir, "test.txt")
+	if err := os.WriteFile(srcFile, []byte("deterministic"), 0644); err != nil {
+		t.Fatalf("创建测试文件失败: %v", err)
+	}
+
+	cfg := config.New()
+	cfg.Deterministic = true
+
+	first := filepath.Join(tempDir, "first.gz")
+	if err := Gzip(first, srcFile, cfg); err != nil {
+		t.Fatalf("第一次压缩失败: %v", err)
+	}
+	future := time.Now().Add(time.Hour)
+	if err := os.Chtimes(srcFile, future, future); err != nil {
+		t.Fatalf("修改时间戳失败: %v", err)
+	}
+	second := filepath.Join(tempDir, "second.gz")
+	if err := Gzip(second, srcFile, cfg); err != nil {
+		t.Fatalf("第二次压缩失败: %v", err)
+	}
+
+	firstData, _ := os.ReadFile(first)
+	secondData, _ := os.ReadFile(second)
+	if !bytes.Equal(firstData, secondData) {
+		t.Fatal("确定性模式下两次压缩结果应逐字节相同")
+	}
+}
+
+// TestGzip_Header 测试压缩文件时写入自定义文件头并在列表中读取
+func TestGzip_Header(t *testing.T) {
+	tempDir := t.TempDir()
+	srcFile := filepath.Join(tempDir, "export.csv")
+	if err := os.WriteFile(srcFile, []byte("a,b\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	modTime := time.Date(2024, 5, 1, 8, 0, 0, 0, time.UTC)
+	if err := os.Chtimes(srcFile, modTime, modTime); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := config.New()
+	cfg.GzipHeader = &types.GzipHeader{Comment: "build 42", Extra: []byte("PV\x02\x00ok"), OS: types.GzipOSFAT}
+	dst := filepath.Join(tempDir, "out.gz")
+	if err := Gzip(dst, srcFile, cfg); err != nil {
+		t.Fatalf("压缩失败: %v", err)
+	}
+
+	info, err := ListGzip(dst)
+	if err != nil {
+		t.Fatalf("列出失败: %v", err)
+	}
+	file := info.Files[0]
+	if file.Gzip == nil || !bytes.Equal(file.Gzip.Extra, cfg.GzipHeader.Extra) || file.Gzip.OS != types.GzipOSFAT {
+		t.Fatalf("文件头不正确: %+v", file.Gzip)
+	}
+	if file.Comment != "build 42" {
+		t.Errorf("注释不正确: %q", file.Comment)
+	}
+	// 未指定的文件名和修改时间保持源文件的值
+	if file.Name != "export.csv" || file.Gzip.Name != "export.csv" || !file.ModTime.Equal(modTime) {
+		t.Errorf("应保持源文件名和修改时间, 实际 %s %v", file.Gzip.Name, file.ModTime)
+	}
+
+	// 未指定操作系统时保持未知(255)，而不是 FAT
+	cfg.OverwriteExisting = true
+	cfg.GzipHeader = &types.GzipHeader{Name: "renamed.csv"}
+	if err := Gzip(dst, srcFile, cfg); err != nil {
+		t.Fatalf("压缩失败: %v", err)
+	}
+	info, err = ListGzip(dst)
+	if err != nil {
+		t.Fatalf("列出失败: %v", err)
+	}
+	if header := info.Files[0].Gzip; header == nil || header.Name != "renamed.csv" || header.OS != types.GzipOSUnknown {
+		t.Errorf("文件头不正确: %+v", header)
+	}
+}
+
+func TestGzip_Bgzf(t *testing.T) {
+	tempDir := t.TempDir()
+	srcFile := filepath.Join(tempDir, "variants.vcf")
+	content := bytes.Repeat([]byte("chr1\t12345\trs1\tA\tG\t50\tPASS\t.\n"), 20000)
+	if err := os.WriteFile(srcFile, content, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := config.New()
+	cfg.Bgzf = true
+	cfg.BgzfIndex = true
+	dst := filepath.Join(tempDir, "variants.vcf.gz")
+	if err := Gzip(dst, srcFile, cfg); err != nil {
+		t.Fatalf("压缩失败: %v", err)
+	}
+
+	// 生成的 .gzi 与扫描数据块构建的索引一致
+	gzi, err := os.Open(dst + ".gzi")
+	if err != nil {
+		t.Fatalf("打开索引文件失败: %v", err)
+	}
+	defer func() { _ = gzi.Close() }()
+	index, err := cxbgzf.ReadIndex(gzi)
+	if err != nil {
+		t.Fatalf("读取索引失败: %v", err)
+	}
+	archive, err := os.Open(dst)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = archive.Close() }()
+	built, err := cxbgzf.BuildIndex(archive)
+	if err != nil {
+		t.Fatalf("构建索引失败: %v", err)
+	}
+	if len(index) == 0 || len(index) != len(built) || index[len(index)-1] != built[len(built)-1] {
+		t.Errorf("索引不一致: %d 条, 扫描 %d 条", len(index), len(built))
+	}
+
+	// 整体作为一个条目列出，快速和完整两种方式大小一致
+	for name, list := range map[string]func(string) (*types.ArchiveInfo, error){"快速": ListGzip, "完整": ListGzipAccurate} {
+		info, err := list(dst)
+		if err != nil {
+			t.Fatalf("%s列出失败: %v", name, err)
+		}
+		if len(info.Files) != 1 || info.TotalSize != int64(len(content)) || info.Files[0].Method != "bgzf" {
+			t.Errorf("%s列出结果不正确: %d 个条目, 大小 %d", name, len(info.Files), info.TotalSize)
+		}
+		if info.Files[0].Name != "variants.vcf" {
+			t.Errorf("%s列出的文件名不正确: %s", name, info.Files[0].Name)
+		}
+	}
+
+	// 按成员拆分时 BGZF 仍解压为一个文件
+	unpackCfg := config.New()
+	unpackCfg.GzipMembers = types.GzipMembersSplit
+	outDir := filepath.Join(tempDir, "out")
+	if err := Ungzip(dst, outDir, unpackCfg); err != nil {
+		t.Fatalf("解压失败: %v", err)
+	}
+	got, err := os.ReadFile(filepath.Join(outDir, "variants.vcf"))
+	if err != nil || !bytes.Equal(got, content) {
+		t.Errorf("解压结果不一致: %v", err)
+	}
+}
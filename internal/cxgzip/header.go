@@ -0,0 +1,62 @@
+// Package cxgzip 提供 GZIP 成员头与 types.GzipHeader 之间的转换功能实现。
+//
+// compress/gzip 的 gzip.Header 和 types.GzipHeader 字段一一对应，
+// 该文件负责在写入时设置成员头、在读取时导出成员头。
+//
+// 主要功能：
+//   - 将 types.GzipHeader 写入 gzip.Writer
+//   - 从 gzip.Header 导出 types.GzipHeader
+//
+// 使用示例：
+//
+//	applyGzipHeader(gzipWriter, &types.GzipHeader{Name: "data.csv", OS: types.GzipOSUnix})
+//	header := gzipHeaderInfo(&gzipReader.Header)
+package cxgzip
+
+import (
+	"bytes"
+	"compress/gzip"
+
+	"gitee.com/MM-Q/comprx/types"
+)
+
+// applyGzipHeader 将文件头设置到GZIP写入器
+//
+// Name、ModTime 和 OS 为零值时保持写入器当前的值，压缩文件时即源文件名、源文件修改时间和未知的操作系统。
+//
+// 参数:
+//   - writer: GZIP写入器，必须在写入数据前调用
+//   - header: 文件头，为 nil 时保持写入器当前的文件头
+func applyGzipHeader(writer *gzip.Writer, header *types.GzipHeader) {
+	if header == nil {
+		return
+	}
+	if header.Name != "" {
+		writer.Name = header.Name
+	}
+	writer.Comment = header.Comment
+	writer.Extra = bytes.Clone(header.Extra)
+	if !header.ModTime.IsZero() {
+		writer.ModTime = header.ModTime
+	}
+	if id, ok := header.OS.Byte(); ok {
+		writer.OS = id
+	}
+}
+
+// gzipHeaderInfo 导出GZIP成员头
+//
+// 参数:
+//   - header: compress/gzip 读取到的成员头
+//
+// 返回:
+//   - *types.GzipHeader: 成员头副本
+func gzipHeaderInfo(header *gzip.Header) *types.GzipHeader {
+	return &types.GzipHeader{
+		Name:    header.Name,
+		Comment: header.Comment,
+		Extra:   bytes.Clone(header.Extra),
+		ModTime: header.ModTime,
+		OS:      types.GzipOSByte(header.OS),
+	}
+}
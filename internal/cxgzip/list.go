@@ -1,52 +1,103 @@
 // Package cxgzip 提供 GZIP 格式的压缩包内容列表功能实现。
 //
 // 该包实现了 GZIP 格式压缩包的文件信息获取功能，包括基本列表、限制数量列表和模式匹配列表。
-// 由于 GZIP 是单文件压缩格式，所有列表操作都针对单个文件进行处理。
+// GZIP 是单文件压缩格式，通常只有一个条目；由多个成员拼接而成的文件完整解压后每个成员对应一个条目。
 //
 // 主要功能：
 //   - GZIP 压缩包文件信息获取
 //   - 原始文件名和大小计算
+//   - 读取尾部 ISIZE 快速获取原始大小
+//   - 完整解压获取准确大小，多成员文件的每个成员对应一个条目
 //   - 文件修改时间获取
+//   - 成员头(注释、额外字段、操作系统标识)导出
+//   - BGZF 文件整体作为一个条目，累加数据块的 ISIZE 获取原始大小
 //   - 模式匹配过滤
 //   - 压缩率计算
 //
 // 特殊处理：
 //   - 自动从 GZIP 文件头获取原始文件名
 //   - 文件名缺失时智能推导（去除 .gz 后缀）
-//   - 通过完整读取计算原始文件大小
+//   - 默认通过尾部 ISIZE 获取原始大小，不可信时自动完整解压
 //   - 使用默认文件权限（GZIP 不保存权限信息）
 //
 // 性能优化：
 //   - 使用缓冲区池减少内存分配
-//   - 高效的文件大小计算方法
-//   - 错误容忍的大小估算机制
+//   - 列表时不解压数据，只读取文件头和尾部
 //
 // 使用示例：
 //
 //	// 获取 GZIP 文件信息
 //	info, err := cxgzip.ListGzip("archive.gz")
 //
+//	// 完整解压获取准确的原始大小
+//	info, err := cxgzip.ListGzipAccurate("archive.gz")
+//
 //	// 获取匹配模式的文件信息
 //	info, err := cxgzip.ListGzipMatch("archive.gz", "*.txt")
 //
-//	// 限制返回文件数量（对 GZIP 无实际效果）
+//	// 限制返回文件数量（只对多成员文件有效）
 //	info, err := cxgzip.ListGzipLimit("archive.gz", 10)
 package cxgzip
 
 import (
 	"compress/gzip"
+	"encoding/binary"
 	"fmt"
+	"hash/crc32"
 	"io"
 	"os"
-	"path/filepath"
-	"strings"
 
+	"gitee.com/MM-Q/comprx/internal/cxbgzf"
 	"gitee.com/MM-Q/comprx/internal/utils"
 	"gitee.com/MM-Q/comprx/types"
 )
 
+// gzipTrailerSize GZIP 成员尾部的长度(CRC32 和 ISIZE 各 4 字节)
+const gzipTrailerSize = 8
+
 // ListGzip 获取GZIP压缩包的文件信息
+//
+// 只读取文件头和尾部，原始大小取自尾部的 ISIZE 字段，CRC32 取自尾部的校验值，不解压数据。
+// ISIZE 只记录原始大小对 4 GiB 取模的值，且只对应最后一个成员。
+// 尾部记录的大小不足以产生当前的压缩数据量时，说明原始大小超过了 4 GiB 或文件包含多个成员，
+// 此时改为完整解压，为每个成员生成一个条目，并在 Warnings 中记录原因。高度可压缩的超过 4 GiB 的数据和末尾成员较大的多成员文件
+// 无法通过尾部识别，需要准确结果时使用 ListGzipAccurate。
+//
+// 参数:
+//   - archivePath: GZIP文件路径
+//
+// 返回:
+//   - *types.ArchiveInfo: 压缩包信息
+//   - error: 错误信息
 func ListGzip(archivePath string) (*types.ArchiveInfo, error) {
+	return listGzip(archivePath, false)
+}
+
+// ListGzipAccurate 完整解压GZIP文件以获取准确的文件信息
+//
+// 依次解压每个成员，多成员文件(如 cat a.gz b.gz 或日志轮转生成的文件)的每个成员对应一个条目，
+// 条目名称与按 types.GzipMembersSplit 拆分解压时的输出文件名一致。
+//
+// 参数:
+//   - archivePath: GZIP文件路径
+//
+// 返回:
+//   - *types.ArchiveInfo: 压缩包信息
+//   - error: 错误信息
+func ListGzipAccurate(archivePath string) (*types.ArchiveInfo, error) {
+	return listGzip(archivePath, true)
+}
+
+// listGzip 获取GZIP压缩包的文件信息
+//
+// 参数:
+//   - archivePath: GZIP文件路径
+//   - accurate: 是否完整解压，false 时优先读取尾部的 ISIZE
+//
+// 返回:
+//   - *types.ArchiveInfo: 压缩包信息
+//   - error: 错误信息
+func listGzip(archivePath string, accurate bool) (*types.ArchiveInfo, error) {
 	// 确保路径为绝对路径
 	absPath, err := utils.EnsureAbsPath(archivePath, "GZIP文件路径")
 	if err != nil {
@@ -72,88 +123,271 @@ func ListGzip(archivePath string) (*types.ArchiveInfo, error) {
 		return nil, fmt.Errorf("获取GZIP文件信息失败: %w", err)
 	}
 
-	// 创建GZIP读取器
+	// 创建GZIP读取器，只读取第一个成员的文件头
 	gzipReader, err := gzip.NewReader(file)
 	if err != nil {
 		return nil, fmt.Errorf("创建GZIP读取器失败: %w", err)
 	}
 	defer func() { _ = gzipReader.Close() }()
 
-	// 获取原始文件名
-	originalName := gzipReader.Name
-	if originalName == "" {
-		// 如果GZIP头中没有文件名，从压缩包文件名推导
-		baseName := filepath.Base(absPath)
-		if ext := filepath.Ext(baseName); ext == ".gz" {
-			//originalName = baseName[:len(baseName)-len(ext)]
-			// 去除.gz后缀
-			originalName = strings.TrimSuffix(baseName, ".gz")
-		} else {
-			originalName = baseName + utils.DecompressedSuffix
+	// BGZF 的数据块是同一数据流的分段，整体作为一个条目
+	if cxbgzf.IsBgzfExtra(gzipReader.Header.Extra) {
+		fileInfo, err := listBgzf(file, absPath, &gzipReader.Header, accurate)
+		if err != nil {
+			return nil, err
 		}
+		fileInfo.CompressedSize = stat.Size()
+
+		return &types.ArchiveInfo{
+			Type:           compressType,               // 类型
+			TotalFiles:     1,                          // 文件数量
+			TotalSize:      fileInfo.Size,              // 原始文件大小
+			CompressedSize: stat.Size(),                // 压缩文件大小
+			Files:          []types.FileInfo{fileInfo}, // 文件列表
+		}, nil
 	}
 
-	// GZIP是单文件压缩，需要读取整个文件来获取原始大小
-	// 使用io.CopyBuffer配合io.Discard，既高效又准确
-	buffer := utils.GetBuffer(utils.DefaultBufferSize)
-	defer utils.PutBuffer(buffer)
+	var warnings []string
+	if !accurate {
+		if originalSize, checksum, ok := readGzipTrailer(file, stat.Size(), &gzipReader.Header); ok {
+			fileInfo := newGzipFileInfo(newGzipMemberNamer(absPath).name(gzipMember{Header: gzipReader.Header}), &gzipReader.Header, originalSize, checksum)
+			fileInfo.CompressedSize = stat.Size()
 
-	originalSize, err := io.CopyBuffer(io.Discard, gzipReader, buffer)
-	if err != nil {
-		// 如果读取失败，使用压缩文件大小作为估算
-		originalSize = stat.Size()
+			return &types.ArchiveInfo{
+				Type:           compressType,               // 类型
+				TotalFiles:     1,                          // 文件数量
+				TotalSize:      originalSize,               // 原始文件大小
+				CompressedSize: stat.Size(),                // 压缩文件大小
+				Files:          []types.FileInfo{fileInfo}, // 文件列表
+			}, nil
+		}
+		warnings = append(warnings, "GZIP 尾部记录的原始大小不可信(超过 4 GiB 或包含多个成员)，已完整解压计算")
 	}
 
-	// 创建FileInfo
-	fileInfo := types.FileInfo{
-		Name:           originalName,
-		Size:           originalSize,
-		CompressedSize: stat.Size(),
-		ModTime:        gzipReader.ModTime,
-		Mode:           utils.DefaultFileMode, // GZIP不保存文件权限，使用默认权限
-		IsDir:          false,
-		IsSymlink:      false,
+	// 从头完整解压，每个成员对应一个条目
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return nil, fmt.Errorf("定位GZIP文件失败: %w", err)
+	}
+	files, err := listGzipMembers(file, absPath)
+	if err != nil {
+		return nil, err
 	}
 
 	// 创建ArchiveInfo
 	archiveInfo := &types.ArchiveInfo{
-		Type:           compressType,               // 类型
-		TotalFiles:     1,                          // 文件数量
-		TotalSize:      originalSize,               // 原始文件大小
-		CompressedSize: stat.Size(),                // 压缩文件大小
-		Files:          []types.FileInfo{fileInfo}, // 文件列表
+		Type:           compressType, // 类型
+		TotalFiles:     len(files),   // 文件数量(每个成员一个)
+		CompressedSize: stat.Size(),  // 压缩文件大小
+		Files:          files,        // 文件列表
+		Warnings:       warnings,     // 警告信息
+	}
+	for _, file := range files {
+		archiveInfo.TotalSize += file.Size // 原始文件大小
 	}
 
 	return archiveInfo, nil
 }
 
-// ListGzipLimit 获取GZIP压缩包指定数量的文件信息
-func ListGzipLimit(archivePath string, limit int) (*types.ArchiveInfo, error) {
-	archiveInfo, err := ListGzip(archivePath)
+// listBgzf 获取BGZF文件的文件信息
+//
+// 默认只扫描数据块头尾并累加每个数据块的 ISIZE，原始大小不受 4 GiB 回绕影响，但没有整体的 CRC32；
+// accurate 为 true 时完整解压计算原始大小和 CRC32。
+//
+// 参数:
+//   - file: 打开的BGZF文件
+//   - archivePath: BGZF文件路径
+//   - header: 第一个数据块的成员头
+//   - accurate: 是否完整解压
+//
+// 返回:
+//   - types.FileInfo: 文件信息
+//   - error: 错误信息
+func listBgzf(file *os.File, archivePath string, header *gzip.Header, accurate bool) (types.FileInfo, error) {
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return types.FileInfo{}, fmt.Errorf("定位BGZF文件失败: %w", err)
+	}
+
+	var size int64
+	var checksum uint32
+	if accurate {
+		gzipReader, err := gzip.NewReader(file)
+		if err != nil {
+			return types.FileInfo{}, fmt.Errorf("创建GZIP读取器失败: %w", err)
+		}
+		defer func() { _ = gzipReader.Close() }()
+
+		buffer := utils.GetBuffer(utils.DefaultBufferSize)
+		defer utils.PutBuffer(buffer)
+		hash := crc32.NewIEEE()
+		if size, err = io.CopyBuffer(hash, gzipReader, buffer); err != nil {
+			return types.FileInfo{}, fmt.Errorf("解压BGZF文件失败: %w", err)
+		}
+		checksum = hash.Sum32()
+	} else {
+		err := cxbgzf.ScanBlocks(file, func(offset int64, blockSize int, isize uint32) error {
+			size += int64(isize)
+			return nil
+		})
+		if err != nil {
+			return types.FileInfo{}, fmt.Errorf("扫描BGZF数据块失败: %w", err)
+		}
+	}
+
+	fileInfo := newGzipFileInfo(newGzipMemberNamer(archivePath).name(gzipMember{Header: *header}), header, size, checksum)
+	fileInfo.Method = "bgzf"
+	return fileInfo, nil
+}
+
+// newGzipFileInfo 根据成员头创建文件信息
+//
+// 参数:
+//   - name: 文件名
+//   - header: 成员头
+//   - size: 原始大小
+//   - checksum: 原始数据的 CRC32
+//
+// 返回:
+//   - types.FileInfo: 文件信息，HeaderOffset 为 -1，逐个成员列出时由调用方设置
+func newGzipFileInfo(name string, header *gzip.Header, size int64, checksum uint32) types.FileInfo {
+	return types.FileInfo{
+		Name:         name,
+		Size:         size,
+		ModTime:      header.ModTime,
+		Mode:         utils.DefaultFileMode, // GZIP不保存文件权限，使用默认权限
+		IsDir:        false,
+		IsSymlink:    false,
+		EntryType:    types.EntryTypeFile,
+		Method:       "deflate",
+		CRC32:        checksum,
+		Comment:      header.Comment,
+		HeaderOffset: -1, // 整个文件作为一个条目时没有单独的条目头
+		Gzip:         gzipHeaderInfo(header),
+	}
+}
+
+// listGzipMembers 完整解压GZIP文件，为每个成员生成文件信息
+//
+// 参数:
+//   - r: GZIP数据流
+//   - archivePath: GZIP文件路径，用于推导没有文件名的成员的名称
+//
+// 返回:
+//   - []types.FileInfo: 每个成员的文件信息，HeaderOffset 为成员头在文件中的偏移
+//   - error: 错误信息
+func listGzipMembers(r io.Reader, archivePath string) ([]types.FileInfo, error) {
+	buffer := utils.GetBuffer(utils.DefaultBufferSize)
+	defer utils.PutBuffer(buffer)
+
+	namer := newGzipMemberNamer(archivePath)
+	var files []types.FileInfo
+	end, err := forEachGzipMember(r, func(member gzipMember, data io.Reader) error {
+		checksum := crc32.NewIEEE()
+		size, err := io.CopyBuffer(checksum, data, buffer)
+		if err != nil {
+			return fmt.Errorf("解压GZIP成员 %d 失败: %w", member.Index+1, err)
+		}
+		fileInfo := newGzipFileInfo(namer.name(member), &member.Header, size, checksum.Sum32())
+		fileInfo.HeaderOffset = member.Offset
+		files = append(files, fileInfo)
+		return nil
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	// GZIP只有一个文件，limit不影响结果
-	return archiveInfo, nil
+	// 每个成员的压缩大小为到下一个成员头的距离
+	for i := range files {
+		next := end
+		if i+1 < len(files) {
+			next = files[i+1].HeaderOffset
+		}
+		files[i].CompressedSize = next - files[i].HeaderOffset
+	}
+	return files, nil
+}
+
+// readGzipTrailer 读取GZIP文件尾部记录的原始大小和 CRC32
+//
+// 尾部记录的大小按 DEFLATE 最坏情况(每 65535 字节一个 5 字节头的存储块)换算出压缩数据的上限，
+// 实际压缩数据超过该上限时说明 ISIZE 发生了回绕或只对应最后一个成员，视为不可信。
+//
+// 参数:
+//   - file: GZIP文件
+//   - size: GZIP文件大小
+//   - header: 第一个成员的文件头
+//
+// 返回:
+//   - int64: 原始大小
+//   - uint32: 原始数据的 CRC32
+//   - bool: 尾部是否可信
+func readGzipTrailer(file io.ReaderAt, size int64, header *gzip.Header) (int64, uint32, bool) {
+	if size < gzipTrailerSize {
+		return 0, 0, false
+	}
+
+	trailer := make([]byte, gzipTrailerSize)
+	if _, err := file.ReadAt(trailer, size-gzipTrailerSize); err != nil {
+		return 0, 0, false
+	}
+	checksum := binary.LittleEndian.Uint32(trailer[0:4])
+	isize := int64(binary.LittleEndian.Uint32(trailer[4:8]))
+
+	// 文件头长度的上限: 固定 10 字节、FEXTRA、FNAME、FCOMMENT 和 FHCRC
+	headerSize := int64(10 + 2)
+	if header.Extra != nil {
+		headerSize += int64(2 + len(header.Extra))
+	}
+	if header.Name != "" {
+		headerSize += int64(len(header.Name) + 1)
+	}
+	if header.Comment != "" {
+		headerSize += int64(len(header.Comment) + 1)
+	}
+
+	maxDeflate := isize + 5*(isize/65535+1)
+	if size-headerSize-gzipTrailerSize > maxDeflate {
+		return 0, 0, false
+	}
+	return isize, checksum, true
+}
+
+// ListGzipLimit 获取GZIP压缩包指定数量的文件信息
+func ListGzipLimit(archivePath string, limit int) (*types.ArchiveInfo, error) {
+	return filterGzipFiles(archivePath, limit, "")
 }
 
 // ListGzipMatch 获取GZIP压缩包中匹配指定模式的文件信息
 func ListGzipMatch(archivePath string, pattern string) (*types.ArchiveInfo, error) {
+	return filterGzipFiles(archivePath, 0, pattern)
+}
+
+// filterGzipFiles 按数量和模式筛选GZIP文件信息
+//
+// 单成员文件只有一个条目；多成员文件的每个成员对应一个条目。
+//
+// 参数:
+//   - archivePath: GZIP文件路径
+//   - limit: 最多返回的文件数量，小于等于 0 表示不限制
+//   - pattern: 文件名匹配模式，为空时返回全部文件
+//
+// 返回:
+//   - *types.ArchiveInfo: 压缩包信息
+//   - error: 错误信息
+func filterGzipFiles(archivePath string, limit int, pattern string) (*types.ArchiveInfo, error) {
 	archiveInfo, err := ListGzip(archivePath)
 	if err != nil {
 		return nil, err
 	}
 
-	// 检查单个文件是否匹配模式
-	if len(archiveInfo.Files) > 0 && utils.MatchPattern(archiveInfo.Files[0].Name, pattern) {
-		return archiveInfo, nil
-	}
-
-	// 如果不匹配，返回空列表
-	archiveInfo.Files = []types.FileInfo{}
+	files := archiveInfo.Files
+	archiveInfo.Files = make([]types.FileInfo, 0, len(files))
 	archiveInfo.TotalFiles = 0
 	archiveInfo.TotalSize = 0
-
+	collect := utils.CollectFiles(archiveInfo, limit, pattern)
+	for _, file := range files {
+		if err := collect(file); err != nil {
+			break
+		}
+	}
 	return archiveInfo, nil
 }
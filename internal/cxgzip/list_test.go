@@ -1,7 +1,10 @@
 package cxgzip
 
 import (
+	"bytes"
 	"compress/gzip"
+	"crypto/rand"
+	"hash/crc32"
 	"os"
 	"path/filepath"
 	"strings"
@@ -139,6 +142,10 @@ func TestListGzip(t *testing.T) {
 				if info.Files[0].IsSymlink {
 					t.Error("GZIP文件不应该是符号链接")
 				}
+				if file := info.Files[0]; file.EntryType != types.EntryTypeFile || file.Method != "deflate" ||
+					file.CRC32 != crc32.ChecksumIEEE([]byte("Hello, World! This is a test content for GZIP compression.")) {
+					t.Errorf("条目元数据不正确: %+v", file)
+				}
 				// 验证文件大小是否合理（由于GZIP读取可能失败，我们只检查是否为合理值）
 				actualSize := info.Files[0].Size
 				if actualSize <= 0 {
@@ -611,3 +618,140 @@ func TestListGzip_PathHandling(t *testing.T) {
 		}
 	})
 }
+
+// gzipMemberBytes 返回单个GZIP成员的字节
+func gzipMemberBytes(t *testing.T, name string, content []byte) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	gzipWriter := gzip.NewWriter(&buf)
+	gzipWriter.Name = name
+	if _, err := gzipWriter.Write(content); err != nil {
+		t.Fatalf("写入GZIP内容失败: %v", err)
+	}
+	if err := gzipWriter.Close(); err != nil {
+		t.Fatalf("关闭GZIP写入器失败: %v", err)
+	}
+	return buf.Bytes()
+}
+
+// TestListGzip_Trailer 测试通过尾部 ISIZE 列出与完整解压结果一致
+func TestListGzip_Trailer(t *testing.T) {
+	tempDir := t.TempDir()
+	content := strings.Repeat("log line for trailer test\n", 4000)
+	path := createTestGzipFile(t, filepath.Join(tempDir, "app.log.gz"), content, "app.log")
+
+	fast, err := ListGzip(path)
+	if err != nil {
+		t.Fatalf("ListGzip 失败: %v", err)
+	}
+	accurate, err := ListGzipAccurate(path)
+	if err != nil {
+		t.Fatalf("ListGzipAccurate 失败: %v", err)
+	}
+
+	if fast.Files[0].Size != int64(len(content)) || accurate.Files[0].Size != int64(len(content)) {
+		t.Errorf("原始大小不正确: 快速 %d, 完整 %d, 期望 %d", fast.Files[0].Size, accurate.Files[0].Size, len(content))
+	}
+	if fast.Files[0].CRC32 != crc32.ChecksumIEEE([]byte(content)) || fast.Files[0].CRC32 != accurate.Files[0].CRC32 {
+		t.Errorf("CRC32 不正确: 快速 %08x, 完整 %08x", fast.Files[0].CRC32, accurate.Files[0].CRC32)
+	}
+	if len(fast.Warnings) != 0 || len(accurate.Warnings) != 0 {
+		t.Errorf("单成员文件不应有警告: %v %v", fast.Warnings, accurate.Warnings)
+	}
+	if fast.Files[0].HeaderOffset != -1 {
+		t.Errorf("整个文件作为一个条目时偏移应为 -1: %d", fast.Files[0].HeaderOffset)
+	}
+}
+
+// TestListGzip_MultiMember 测试多成员GZIP文件按成员列出
+func TestListGzip_MultiMember(t *testing.T) {
+	tempDir := t.TempDir()
+	random := make([]byte, 64*1024)
+	if _, err := rand.Read(random); err != nil {
+		t.Fatal(err)
+	}
+	small := []byte("small member\n")
+
+	t.Run("尾部不可信时完整解压", func(t *testing.T) {
+		// 末尾成员很小，尾部的 ISIZE 不足以产生前面成员的压缩数据
+		path := filepath.Join(tempDir, "tail-small.gz")
+		first := gzipMemberBytes(t, "a.log", random)
+		data := append(first, gzipMemberBytes(t, "b.log", small)...)
+		if err := os.WriteFile(path, data, 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		info, err := ListGzip(path)
+		if err != nil {
+			t.Fatalf("ListGzip 失败: %v", err)
+		}
+		if len(info.Warnings) != 1 {
+			t.Errorf("应记录尾部不可信的警告, 实际 %v", info.Warnings)
+		}
+		if info.TotalFiles != 2 || len(info.Files) != 2 {
+			t.Fatalf("每个成员应对应一个条目, 实际 %d", len(info.Files))
+		}
+		if want := int64(len(random) + len(small)); info.TotalSize != want {
+			t.Errorf("原始大小应为全部成员之和 %d, 实际 %d", want, info.TotalSize)
+		}
+		second := info.Files[1]
+		if second.Name != "b.log" || second.Size != int64(len(small)) || second.CRC32 != crc32.ChecksumIEEE(small) {
+			t.Errorf("第二个成员信息不正确: %+v", second)
+		}
+		if second.HeaderOffset != int64(len(first)) || info.Files[0].CompressedSize != int64(len(first)) {
+			t.Errorf("成员偏移或压缩大小不正确: 偏移 %d, 第一个成员压缩大小 %d, 期望 %d",
+				second.HeaderOffset, info.Files[0].CompressedSize, len(first))
+		}
+	})
+
+	t.Run("完整解压识别所有成员", func(t *testing.T) {
+		// 末尾成员较大，只读取尾部无法识别；同名成员追加序号
+		path := filepath.Join(tempDir, "app.log.gz")
+		large := []byte(strings.Repeat("rotated log line\n", 4096))
+		data := append(gzipMemberBytes(t, "", small), gzipMemberBytes(t, "", large)...)
+		if err := os.WriteFile(path, data, 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		fast, err := ListGzip(path)
+		if err != nil {
+			t.Fatalf("ListGzip 失败: %v", err)
+		}
+		if len(fast.Files) != 1 || len(fast.Warnings) != 0 {
+			t.Errorf("只读取尾部时无法识别多成员: %+v", fast)
+		}
+
+		info, err := ListGzipAccurate(path)
+		if err != nil {
+			t.Fatalf("ListGzipAccurate 失败: %v", err)
+		}
+		if len(info.Files) != 2 || len(info.Warnings) != 0 {
+			t.Fatalf("每个成员应对应一个条目: %+v", info)
+		}
+		if info.Files[0].Name != "app.log" || info.Files[1].Name != "app.log.2" {
+			t.Errorf("成员名称不正确: %s, %s", info.Files[0].Name, info.Files[1].Name)
+		}
+
+		limited, err := ListGzipLimit(path, 1)
+		if err != nil {
+			t.Fatalf("ListGzipLimit 失败: %v", err)
+		}
+		if len(limited.Files) != 1 {
+			t.Errorf("限制数量后应只有 1 个条目, 实际 %d", len(limited.Files))
+		}
+	})
+}
+
+// TestListGzip_Truncated 测试完整解压失败时返回错误而不是估算的大小
+func TestListGzip_Truncated(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "truncated.gz")
+	data := gzipMemberBytes(t, "a.log", []byte(strings.Repeat("truncated member\n", 1000)))
+	if err := os.WriteFile(path, data[:len(data)/2], 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	info, err := ListGzipAccurate(path)
+	if err == nil || info != nil {
+		t.Errorf("解压失败时应返回错误, 实际 %+v, %v", info, err)
+	}
+}
@@ -0,0 +1,135 @@
+// Package cxgzip 提供 GZIP 多成员文件的读取功能实现。
+//
+// 一个 GZIP 文件可以由多个成员首尾相接组成，每个成员有独立的文件头、压缩数据和尾部，
+// 常见于 cat a.gz b.gz 和日志轮转。该文件提供逐个读取成员的能力，供列表和拆分解压使用。
+//
+// 主要功能：
+//   - 逐个读取 GZIP 成员并记录其在文件中的偏移
+//   - 为成员生成不重复的输出文件名
+//
+// 使用示例：
+//
+//	err := forEachGzipMember(file, func(member gzipMember, data io.Reader) error {
+//	    fmt.Println(member.Offset, member.Header.Name)
+//	    return nil
+//	})
+package cxgzip
+
+import (
+	"bufio"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"gitee.com/MM-Q/comprx/internal/utils"
+)
+
+// gzipMember GZIP 成员信息
+type gzipMember struct {
+	Index  int         // 成员序号，从 0 开始
+	Header gzip.Header // 成员头
+	Offset int64       // 成员头在 GZIP 文件中的偏移
+}
+
+// countingReader 统计已读取字节数的读取器
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+// Read 读取数据并累计字节数
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
+}
+
+// forEachGzipMember 依次读取GZIP流中的每个成员
+//
+// fn 返回后，未读完的成员数据会被读完，以校验该成员尾部的 CRC32 和长度。
+//
+// 参数:
+//   - r: GZIP数据流
+//   - fn: 对每个成员调用的函数，data 为该成员解压后的数据
+//
+// 返回:
+//   - int64: 最后一个成员结束处的偏移
+//   - error: 错误信息
+func forEachGzipMember(r io.Reader, fn func(member gzipMember, data io.Reader) error) (int64, error) {
+	counter := &countingReader{r: r}
+	// gzip.Reader 在底层实现 io.ByteReader 时不会越过成员边界多读
+	bufReader := bufio.NewReader(counter)
+	offset := func() int64 { return counter.n - int64(bufReader.Buffered()) }
+
+	gzipReader, err := gzip.NewReader(bufReader)
+	if err != nil {
+		return 0, fmt.Errorf("创建GZIP读取器失败: %w", err)
+	}
+	defer func() { _ = gzipReader.Close() }()
+
+	member := gzipMember{}
+	for {
+		gzipReader.Multistream(false)
+		member.Header = gzipReader.Header
+		if err := fn(member, gzipReader); err != nil {
+			return 0, err
+		}
+		if _, err := io.Copy(io.Discard, gzipReader); err != nil {
+			return 0, fmt.Errorf("解压GZIP成员 %d 失败: %w", member.Index+1, err)
+		}
+
+		// 读取下一个成员的文件头，没有更多成员时结束
+		member.Index++
+		member.Offset = offset()
+		if err := gzipReader.Reset(bufReader); err != nil {
+			if err == io.EOF {
+				return member.Offset, nil
+			}
+			return 0, fmt.Errorf("读取GZIP成员 %d 的文件头失败: %w", member.Index+1, err)
+		}
+	}
+}
+
+// gzipDefaultName 返回成员头中没有文件名时使用的文件名
+//
+// 参数:
+//   - archivePath: GZIP文件路径
+//
+// 返回:
+//   - string: 去除 .gz 后缀的文件名，没有该后缀时追加 utils.DecompressedSuffix
+func gzipDefaultName(archivePath string) string {
+	baseName := filepath.Base(archivePath)
+	if filepath.Ext(baseName) == ".gz" {
+		return strings.TrimSuffix(baseName, ".gz")
+	}
+	return baseName + utils.DecompressedSuffix
+}
+
+// gzipMemberNamer 为成员生成不重复的文件名
+//
+// 成员头中有文件名时使用该文件名，否则使用 gzipDefaultName；
+// 与前面的成员重名时追加成员序号(从 1 开始)，如 app.log、app.log.2。
+type gzipMemberNamer struct {
+	defaultName string
+	seen        map[string]bool
+}
+
+// newGzipMemberNamer 创建成员文件名生成器
+func newGzipMemberNamer(archivePath string) *gzipMemberNamer {
+	return &gzipMemberNamer{defaultName: gzipDefaultName(archivePath), seen: make(map[string]bool)}
+}
+
+// name 返回成员的文件名
+func (n *gzipMemberNamer) name(member gzipMember) string {
+	name := member.Header.Name
+	if name == "" {
+		name = n.defaultName
+	}
+	if n.seen[name] {
+		name = fmt.Sprintf("%s.%d", name, member.Index+1)
+	}
+	n.seen[name] = true
+	return name
+}
@@ -7,6 +7,7 @@
 //   - GZIP 内存压缩：字节数组和字符串的压缩解压
 //   - GZIP 流式压缩：支持 io.Reader 和 io.Writer 接口
 //   - 支持自定义压缩等级
+//   - 写入和读取自定义文件头
 //   - 优化的内存分配策略
 //   - 完善的错误处理和资源管理
 //
@@ -52,7 +53,21 @@ import (
 // 返回:
 //   - []byte: 压缩后的数据
 //   - error: 错误信息
-func CompressBytes(data []byte, level types.CompressionLevel) (result []byte, err error) {
+func CompressBytes(data []byte, level types.CompressionLevel) ([]byte, error) {
+	return CompressBytesWithHeader(data, level, nil)
+}
+
+// CompressBytesWithHeader 压缩字节数据到内存并写入指定的文件头
+//
+// 参数:
+//   - data: 要压缩的字节数据
+//   - level: 压缩级别
+//   - header: 文件头，为 nil 时使用 compress/gzip 的默认文件头
+//
+// 返回:
+//   - []byte: 压缩后的数据
+//   - error: 错误信息
+func CompressBytesWithHeader(data []byte, level types.CompressionLevel, header *types.GzipHeader) (result []byte, err error) {
 	// 参数验证 - 更精确的nil检查
 	if data == nil {
 		return nil, fmt.Errorf("输入数据不能为nil")
@@ -60,6 +75,11 @@ func CompressBytes(data []byte, level types.CompressionLevel) (result []byte, er
 	if len(data) == 0 {
 		return nil, fmt.Errorf("输入数据不能为空")
 	}
+	if header != nil {
+		if err := header.Validate(); err != nil {
+			return nil, err
+		}
+	}
 
 	// 创建内存缓冲区 - 预分配容量减少重分配
 	// 预分配原大小的50%
@@ -74,6 +94,7 @@ func CompressBytes(data []byte, level types.CompressionLevel) (result []byte, er
 	if err != nil {
 		return nil, fmt.Errorf("创建gzip写入器失败: %w", err)
 	}
+	applyGzipHeader(writer, header)
 
 	// 直接写入数据，无需额外缓冲区
 	if _, err = writer.Write(data); err != nil {
@@ -193,7 +214,21 @@ func DecompressString(compressedData []byte) (string, error) {
 //
 // 返回:
 //   - error: 错误信息
-func CompressStream(dst io.Writer, src io.Reader, level types.CompressionLevel) (err error) {
+func CompressStream(dst io.Writer, src io.Reader, level types.CompressionLevel) error {
+	return CompressStreamWithHeader(dst, src, level, nil)
+}
+
+// CompressStreamWithHeader 流式压缩数据并写入指定的文件头
+//
+// 参数:
+//   - dst: 目标写入器
+//   - src: 源读取器
+//   - level: 压缩级别
+//   - header: 文件头，为 nil 时使用 compress/gzip 的默认文件头
+//
+// 返回:
+//   - error: 错误信息
+func CompressStreamWithHeader(dst io.Writer, src io.Reader, level types.CompressionLevel, header *types.GzipHeader) (err error) {
 	// 1. 参数验证
 	if dst == nil {
 		err = fmt.Errorf("目标写入器不能为nil")
@@ -203,6 +238,11 @@ func CompressStream(dst io.Writer, src io.Reader, level types.CompressionLevel)
 		err = fmt.Errorf("源读取器不能为nil")
 		return
 	}
+	if header != nil {
+		if err = header.Validate(); err != nil {
+			return
+		}
+	}
 
 	// 2. 创建gzip写入器
 	writer, createErr := gzip.NewWriterLevel(dst, config.GetCompressionLevel(level))
@@ -210,6 +250,7 @@ func CompressStream(dst io.Writer, src io.Reader, level types.CompressionLevel)
 		err = fmt.Errorf("创建gzip写入器失败: %w", createErr)
 		return
 	}
+	applyGzipHeader(writer, header)
 	defer func() {
 		if closeErr := writer.Close(); closeErr != nil && err == nil {
 			// 只有在没有其他错误时才设置关闭错误
@@ -240,7 +281,21 @@ func CompressStream(dst io.Writer, src io.Reader, level types.CompressionLevel)
 //
 // 返回:
 //   - error: 错误信息
-func DecompressStream(dst io.Writer, src io.Reader) (err error) {
+func DecompressStream(dst io.Writer, src io.Reader) error {
+	_, err := DecompressStreamWithHeader(dst, src)
+	return err
+}
+
+// DecompressStreamWithHeader 流式解压数据并返回文件头
+//
+// 参数:
+//   - dst: 目标写入器
+//   - src: 源读取器（压缩数据）
+//
+// 返回:
+//   - *types.GzipHeader: 第一个成员的文件头
+//   - error: 错误信息
+func DecompressStreamWithHeader(dst io.Writer, src io.Reader) (header *types.GzipHeader, err error) {
 	// 1. 参数验证
 	if dst == nil {
 		err = fmt.Errorf("目标写入器不能为nil")
@@ -257,6 +312,7 @@ func DecompressStream(dst io.Writer, src io.Reader) (err error) {
 		err = fmt.Errorf("创建gzip读取器失败: %w", createErr)
 		return
 	}
+	header = gzipHeaderInfo(&reader.Header)
 	defer func() {
 		if closeErr := reader.Close(); closeErr != nil && err == nil {
 			// 只有在没有其他错误时才设置关闭错误
@@ -267,7 +323,7 @@ func DecompressStream(dst io.Writer, src io.Reader) (err error) {
 	// 3. 流式复制数据
 	if _, copyErr := io.Copy(dst, reader); copyErr != nil {
 		err = fmt.Errorf("解压数据失败: %w", copyErr)
-		return
+		return nil, err
 	}
 
 	return
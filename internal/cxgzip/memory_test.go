@@ -6,6 +6,7 @@ import (
 	"io"
 	"strings"
 	"testing"
+	"time"
 
 	"gitee.com/MM-Q/comprx/types"
 )
@@ -876,3 +877,54 @@ func BenchmarkStreamAPI_RoundTrip(b *testing.B) {
 		}
 	}
 }
+
+// TestCompressWithHeader 测试写入和读取自定义文件头
+func TestCompressWithHeader(t *testing.T) {
+	header := &types.GzipHeader{
+		Name:    "data.csv",
+		Comment: "nightly export",
+		Extra:   []byte("PV\x04\x00run1"),
+		ModTime: time.Date(2024, 5, 1, 8, 0, 0, 0, time.UTC),
+		OS:      types.GzipOSUnix,
+	}
+
+	compressed, err := CompressBytesWithHeader([]byte("a,b\n1,2\n"), types.CompressionLevelDefault, header)
+	if err != nil {
+		t.Fatalf("压缩失败: %v", err)
+	}
+
+	var out bytes.Buffer
+	got, err := DecompressStreamWithHeader(&out, bytes.NewReader(compressed))
+	if err != nil {
+		t.Fatalf("解压失败: %v", err)
+	}
+	if out.String() != "a,b\n1,2\n" {
+		t.Errorf("解压内容不正确: %q", out.String())
+	}
+	if got.Name != header.Name || got.Comment != header.Comment || !bytes.Equal(got.Extra, header.Extra) ||
+		!got.ModTime.Equal(header.ModTime) || got.OS != header.OS {
+		t.Errorf("文件头不一致: 期望 %+v, 实际 %+v", header, got)
+	}
+
+	// 流式压缩结果与内存压缩一致
+	var streamed bytes.Buffer
+	if err := CompressStreamWithHeader(&streamed, strings.NewReader("a,b\n1,2\n"), types.CompressionLevelDefault, header); err != nil {
+		t.Fatalf("流式压缩失败: %v", err)
+	}
+	if !bytes.Equal(streamed.Bytes(), compressed) {
+		t.Error("流式压缩与内存压缩的结果应相同")
+	}
+
+	// 无法以 Latin-1 存储的文件名、过长的额外字段和未用 GzipOSByte 构造的操作系统标识
+	invalid := []*types.GzipHeader{
+		{Name: "数据.csv"},
+		{Comment: "a\x00b"},
+		{Extra: make([]byte, 70000)},
+		{OS: 3},
+	}
+	for _, h := range invalid {
+		if _, err := CompressBytesWithHeader([]byte("x"), types.CompressionLevelDefault, h); err == nil {
+			t.Errorf("无效的文件头应返回错误: %+v", h.Name)
+		}
+	}
+}
@@ -10,6 +10,7 @@
 //   - 路径安全验证
 //   - 文件覆盖控制
 //   - 智能目标路径处理
+//   - 多成员文件拼接输出或按成员拆分(BGZF 文件始终拼接)
 //
 // 安全特性：
 //   - 路径遍历攻击防护
@@ -35,6 +36,7 @@
 package cxgzip
 
 import (
+	"bufio"
 	"compress/gzip"
 	"fmt"
 	"io"
@@ -43,6 +45,8 @@ import (
 	"strings"
 
 	"gitee.com/MM-Q/comprx/internal/config"
+	"gitee.com/MM-Q/comprx/internal/cxbgzf"
+	"gitee.com/MM-Q/comprx/internal/envelope"
 	"gitee.com/MM-Q/comprx/internal/utils"
 	"gitee.com/MM-Q/comprx/types"
 )
@@ -52,10 +56,11 @@ import (
 // 参数:
 //   - gzipFilePath: GZIP文件路径
 //   - cfg: 解压配置
+//   - opener: 加密信封打开器，与解压共用以复用派生出的密钥
 //
 // 返回值:
 //   - int64: 解压后的文件大小（字节）
-func calculateGzipTotalSize(gzipFilePath string, cfg *config.Config) int64 {
+func calculateGzipTotalSize(gzipFilePath string, cfg *config.Config, opener *envelope.Opener) int64 {
 	// 只在进度条模式下计算总大小
 	if !cfg.Progress.Enabled || cfg.Progress.BarStyle == types.ProgressStyleText {
 		return 0
@@ -68,7 +73,7 @@ func calculateGzipTotalSize(gzipFilePath string, cfg *config.Config) int64 {
 	}()
 
 	// 打开GZIP文件进行扫描
-	gzipFile, err := os.Open(gzipFilePath)
+	gzipFile, err := opener.Open(gzipFilePath)
 	if err != nil {
 		return 0
 	}
@@ -96,6 +101,10 @@ func calculateGzipTotalSize(gzipFilePath string, cfg *config.Config) int64 {
 
 // Ungzip 解压缩 GZIP 文件
 //
+// 多成员文件默认按顺序拼接写入一个文件，文件名和修改时间取自第一个成员；
+// config.GzipMembers 为 types.GzipMembersSplit 时每个成员写入目标目录下的单独文件，
+// BGZF 文件的数据块属于同一数据流，始终拼接输出。
+//
 // 参数:
 //   - gzipFilePath: 要解压缩的 GZIP 文件路径
 //   - targetPath: 解压缩后的目标文件路径
@@ -104,8 +113,11 @@ func calculateGzipTotalSize(gzipFilePath string, cfg *config.Config) int64 {
 // 返回值:
 //   - error: 解压缩过程中发生的错误
 func Ungzip(gzipFilePath string, targetPath string, config *config.Config) error {
+	// 扫描总大小和解压共用打开器，加密信封的密钥只派生一次
+	opener := envelope.NewOpener(config.EncryptWith.Passphrase)
+
 	// 在进度条模式下计算总大小
-	totalSize := calculateGzipTotalSize(gzipFilePath, config)
+	totalSize := calculateGzipTotalSize(gzipFilePath, config, opener)
 
 	// 开始进度显示
 	if err := config.Progress.Start(totalSize, gzipFilePath, fmt.Sprintf("正在解压 %s...", filepath.Base(gzipFilePath))); err != nil {
@@ -116,20 +128,36 @@ func Ungzip(gzipFilePath string, targetPath string, config *config.Config) error
 	}()
 
 	// 打开 GZIP 文件（同时检查文件是否存在）
-	gzipFile, err := os.Open(gzipFilePath)
+	gzipFile, err := opener.Open(gzipFilePath)
 	if err != nil {
 		return fmt.Errorf("打开 GZIP 文件失败: %w", err)
 	}
 	defer func() { _ = gzipFile.Close() }()
 
 	// 获取GZIP文件信息用于预验证
-	gzipInfo, err := gzipFile.Stat()
+	gzipInfo, err := os.Stat(gzipFilePath)
 	if err != nil {
 		return fmt.Errorf("获取GZIP文件信息失败: %w", err)
 	}
 
-	// 创建 GZIP 读取器
-	gzipReader, err := gzip.NewReader(gzipFile)
+	// 多成员文件按成员拆分为单独的文件
+	gzipStream := bufio.NewReader(gzipFile)
+	if config.GzipMembers == types.GzipMembersSplit {
+		if !cxbgzf.IsBgzf(gzipStream) {
+			return ungzipSplit(gzipStream, gzipFilePath, targetPath, config, utils.GetBufferSize(gzipInfo.Size()))
+		}
+
+		// BGZF 的数据块属于同一个文件，不拆分，但目标路径仍作为目录
+		if targetStat, statErr := os.Stat(targetPath); statErr == nil && !targetStat.IsDir() {
+			return fmt.Errorf("拆分 GZIP 成员时目标路径必须是目录: %s", targetPath)
+		}
+		if err := utils.EnsureDir(targetPath); err != nil {
+			return fmt.Errorf("创建目标目录失败: %w", err)
+		}
+	}
+
+	// 创建 GZIP 读取器，多成员文件按顺序拼接输出
+	gzipReader, err := gzip.NewReader(gzipStream)
 	if err != nil {
 		return fmt.Errorf("创建 GZIP 读取器失败: %w", err)
 	}
@@ -202,3 +230,86 @@ func Ungzip(gzipFilePath string, targetPath string, config *config.Config) error
 
 	return nil
 }
+
+// ungzipSplit 将GZIP文件的每个成员解压为目标目录下的单独文件
+//
+// 文件名与 ListGzipAccurate 列出的条目名称一致: 使用成员头中的原始文件名，
+// 没有时去除 .gz 后缀，与前面的成员重名时追加成员序号。
+//
+// 参数:
+//   - gzipFile: GZIP数据流
+//   - gzipFilePath: GZIP文件路径
+//   - targetDir: 目标目录，不存在时自动创建
+//   - config: 解压缩配置
+//   - bufferSize: 缓冲区大小
+//
+// 返回值:
+//   - error: 解压缩过程中发生的错误
+func ungzipSplit(gzipFile io.Reader, gzipFilePath string, targetDir string, config *config.Config, bufferSize int) error {
+	// 拆分时目标必须是目录
+	if targetStat, statErr := os.Stat(targetDir); statErr == nil && !targetStat.IsDir() {
+		return fmt.Errorf("拆分 GZIP 成员时目标路径必须是目录: %s", targetDir)
+	}
+	if err := utils.EnsureDir(targetDir); err != nil {
+		return fmt.Errorf("创建目标目录失败: %w", err)
+	}
+
+	buffer := utils.GetBuffer(bufferSize)
+	defer utils.PutBuffer(buffer)
+
+	namer := newGzipMemberNamer(gzipFilePath)
+	_, err := forEachGzipMember(gzipFile, func(member gzipMember, data io.Reader) error {
+		// 验证成员头中的文件名，并与目标目录合并
+		targetPath, err := utils.ValidatePathSimple(targetDir, namer.name(member), config.DisablePathValidation)
+		if err != nil {
+			return fmt.Errorf("GZIP成员头包含不安全的文件名: %w", err)
+		}
+		if _, statErr := os.Stat(targetPath); statErr == nil && !config.OverwriteExisting {
+			return fmt.Errorf("目标文件已存在且不允许覆盖: %s", targetPath)
+		}
+		if err := utils.EnsureDir(filepath.Dir(targetPath)); err != nil {
+			return fmt.Errorf("创建目标文件父目录失败: %w", err)
+		}
+
+		return writeGzipMember(targetPath, &member.Header, data, config, buffer)
+	})
+	return err
+}
+
+// writeGzipMember 将单个GZIP成员的数据写入目标文件
+//
+// 参数:
+//   - targetPath: 目标文件路径
+//   - header: 成员头，用于恢复修改时间
+//   - data: 成员解压后的数据
+//   - config: 解压缩配置
+//   - buffer: 复制使用的缓冲区
+//
+// 返回值:
+//   - error: 写入过程中发生的错误
+func writeGzipMember(targetPath string, header *gzip.Header, data io.Reader, config *config.Config, buffer []byte) error {
+	targetFile, err := os.Create(targetPath)
+	if err != nil {
+		return fmt.Errorf("创建目标文件失败: %w", err)
+	}
+	defer func() { _ = targetFile.Close() }()
+
+	// 打印解压缩进度
+	config.Progress.Inflating(targetPath)
+
+	if _, err := config.Progress.CopyBuffer(targetFile, data, buffer); err != nil {
+		return fmt.Errorf("解压缩文件失败: %w", err)
+	}
+	if err := targetFile.Close(); err != nil {
+		return fmt.Errorf("关闭目标文件失败: %w", err)
+	}
+
+	// 如果成员头中有修改时间信息，则设置目标文件的修改时间
+	if !header.ModTime.IsZero() {
+		if err := os.Chtimes(targetPath, header.ModTime, header.ModTime); err != nil {
+			// 设置时间失败不是致命错误，只记录警告
+			fmt.Printf("警告: 设置文件修改时间失败: %v\n", err)
+		}
+	}
+	return nil
+}
@@ -7,6 +7,7 @@ import (
 	"testing"
 
 	"gitee.com/MM-Q/comprx/internal/config"
+	"gitee.com/MM-Q/comprx/types"
 )
 
 func TestUngzip_Success(t *testing.T) {
@@ -274,3 +275,76 @@ func BenchmarkUngzip_SmallFile(b *testing.B) {
 		}
 	}
 }
+
+// TestUngzip_MultiMember 测试多成员GZIP文件的拼接和拆分解压
+func TestUngzip_MultiMember(t *testing.T) {
+	tempDir := t.TempDir()
+	gzipFile := filepath.Join(tempDir, "rotated.log.gz")
+	data := append(gzipMemberBytes(t, "app.log.1", []byte("old\n")), gzipMemberBytes(t, "app.log", []byte("new\n"))...)
+	data = append(data, gzipMemberBytes(t, "app.log", []byte("newer\n"))...)
+	if err := os.WriteFile(gzipFile, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("拼接", func(t *testing.T) {
+		target := filepath.Join(tempDir, "concat.log")
+		if err := Ungzip(gzipFile, target, config.New()); err != nil {
+			t.Fatalf("解压失败: %v", err)
+		}
+		content, err := os.ReadFile(target)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(content) != "old\nnew\nnewer\n" {
+			t.Errorf("拼接结果不正确: %q", content)
+		}
+	})
+
+	t.Run("拆分", func(t *testing.T) {
+		cfg := config.New()
+		cfg.GzipMembers = types.GzipMembersSplit
+		outDir := filepath.Join(tempDir, "split")
+		if err := Ungzip(gzipFile, outDir, cfg); err != nil {
+			t.Fatalf("拆分解压失败: %v", err)
+		}
+
+		// 拆分后的文件名与完整列表的条目名称一致
+		info, err := ListGzipAccurate(gzipFile)
+		if err != nil {
+			t.Fatalf("列出成员失败: %v", err)
+		}
+		want := []string{"old\n", "new\n", "newer\n"}
+		if len(info.Files) != len(want) {
+			t.Fatalf("期望 %d 个成员, 实际 %d", len(want), len(info.Files))
+		}
+		for i, file := range info.Files {
+			content, err := os.ReadFile(filepath.Join(outDir, file.Name))
+			if err != nil {
+				t.Fatalf("读取成员 %s 失败: %v", file.Name, err)
+			}
+			if string(content) != want[i] {
+				t.Errorf("成员 %s 内容不正确: %q", file.Name, content)
+			}
+		}
+		if info.Files[2].Name != "app.log.3" {
+			t.Errorf("重名成员应追加序号, 实际 %s", info.Files[2].Name)
+		}
+
+		// 不允许覆盖时再次拆分应失败
+		if err := Ungzip(gzipFile, outDir, cfg); err == nil {
+			t.Error("目标文件已存在且不允许覆盖时应返回错误")
+		}
+	})
+
+	t.Run("拆分拒绝不安全的文件名", func(t *testing.T) {
+		unsafe := filepath.Join(tempDir, "unsafe.gz")
+		if err := os.WriteFile(unsafe, gzipMemberBytes(t, "../escape.txt", []byte("x")), 0644); err != nil {
+			t.Fatal(err)
+		}
+		cfg := config.New()
+		cfg.GzipMembers = types.GzipMembersSplit
+		if err := Ungzip(unsafe, filepath.Join(tempDir, "unsafe-out"), cfg); err == nil {
+			t.Error("成员文件名包含路径遍历时应返回错误")
+		}
+	})
+}
@@ -0,0 +1,61 @@
+// Package cxgzip 提供 GZIP 文件的完整性校验功能实现。
+//
+// 该文件实现了 GZIP 文件的完整性校验：解压全部成员并丢弃数据，
+// 由 compress/gzip 校验每个成员尾部的 CRC32 和原始大小，不会写入任何文件。
+//
+// 主要功能：
+//   - 校验每个 GZIP 成员的 CRC32 和大小
+//   - 发现截断的压缩数据
+//
+// 使用示例：
+//
+//	report, err := cxgzip.Verify("app.log.gz", "")
+package cxgzip
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"gitee.com/MM-Q/comprx/internal/envelope"
+	"gitee.com/MM-Q/comprx/types"
+)
+
+// Verify 校验 GZIP 文件的完整性
+//
+// 参数:
+//   - archivePath: GZIP 文件路径
+//   - passphrase: 加密信封的口令，文件不是加密信封时忽略
+//
+// 返回值:
+//   - *types.VerifyReport: 校验报告，损坏时记录在 Failures 中
+//   - error: 无法打开文件、加密信封未提供口令或口令错误时返回错误
+func Verify(archivePath string, passphrase string) (*types.VerifyReport, error) {
+	file, err := envelope.Open(archivePath, passphrase)
+	if err != nil {
+		return nil, fmt.Errorf("打开GZIP文件失败: %w", err)
+	}
+	defer func() { _ = file.Close() }()
+
+	report := &types.VerifyReport{Type: types.CompressTypeGz, TotalEntries: 1}
+	name := strings.TrimSuffix(filepath.Base(archivePath), filepath.Ext(archivePath))
+
+	gzipReader, err := gzip.NewReader(file)
+	if err != nil {
+		report.AddFailure(name, fmt.Errorf("读取GZIP文件头失败: %w", err))
+		return report, nil
+	}
+	defer func() { _ = gzipReader.Close() }()
+	if gzipReader.Name != "" {
+		name = gzipReader.Name
+	}
+
+	size, err := io.Copy(io.Discard, gzipReader)
+	report.TotalSize = size
+	if err != nil {
+		report.AddFailure(name, fmt.Errorf("GZIP数据校验失败: %w", err))
+	}
+	return report, nil
+}
@@ -0,0 +1,287 @@
+// Package cxlz4 提供 LZ4 数据块格式的压缩和解压功能实现。
+//
+// LZ4 数据块由若干序列组成，每个序列包含一个标记字节、字面量和一个向前引用的匹配，
+// 最后一个序列只有字面量。压缩器使用哈希表查找 4 字节的匹配，搜索深度大于 1 时
+// 通过哈希链在 64 KiB 窗口内查找更长的匹配。
+//
+// 主要功能：
+//   - 按指定搜索深度压缩数据块
+//   - 解压数据块，支持引用之前数据块的内容(链接模式)
+//
+// 使用示例：
+//
+//	compressed := newBlockCompressor(1).compress(nil, data)
+//	out, err := decompressBlock(compressed, nil, len(data))
+package cxlz4
+
+import (
+	"encoding/binary"
+	"errors"
+)
+
+const (
+	minMatch     = 4          // 最短匹配长度
+	lastLiterals = 5          // 数据块末尾必须保留为字面量的字节数
+	mfLimit      = 12         // 最后一个匹配的起始位置距数据块末尾的最小距离
+	maxOffset    = 65535      // 匹配的最大回溯距离
+	hashLog      = 16         // 哈希表大小的对数
+	windowMask   = 1<<16 - 1  // 哈希链按窗口取模的掩码
+	hashPrime    = 2654435761 // 4 字节哈希的乘数
+)
+
+// errCorruptBlock 数据块损坏
+var errCorruptBlock = errors.New("LZ4 数据块已损坏")
+
+// blockCompressor LZ4 数据块压缩器，复用哈希表和哈希链
+type blockCompressor struct {
+	table [1 << hashLog]int32   // 每个哈希值最近出现的位置加一
+	chain [windowMask + 1]int32 // 按位置取模记录同一哈希值的上一个位置加一
+	depth int                   // 每个位置最多比较的候选匹配数
+}
+
+// newBlockCompressor 创建数据块压缩器
+//
+// 参数:
+//   - depth: 搜索深度，1 表示只比较哈希表中的最近位置
+//
+// 返回:
+//   - *blockCompressor: 数据块压缩器
+func newBlockCompressor(depth int) *blockCompressor {
+	return &blockCompressor{depth: max(depth, 1)}
+}
+
+// hash4 计算 4 字节的哈希值
+func hash4(v uint32) uint32 {
+	return v * hashPrime >> (32 - hashLog)
+}
+
+// insert 将位置加入哈希表和哈希链
+func (c *blockCompressor) insert(src []byte, pos int) int {
+	h := hash4(binary.LittleEndian.Uint32(src[pos:]))
+	prev := int(c.table[h]) - 1
+	c.table[h] = int32(pos + 1)
+	c.chain[pos&windowMask] = int32(prev + 1)
+	return prev
+}
+
+// compress 压缩一个数据块并追加到 dst
+//
+// 参数:
+//   - dst: 输出缓冲区
+//   - src: 原始数据，不超过 2 GiB
+//
+// 返回:
+//   - []byte: 追加压缩数据后的输出缓冲区
+func (c *blockCompressor) compress(dst, src []byte) []byte {
+	clear(c.table[:])
+
+	anchor := 0
+	if len(src) > mfLimit {
+		matchLimit := len(src) - lastLiterals
+		startLimit := len(src) - mfLimit
+		misses := 0
+		for pos := 0; pos < startLimit; {
+			candidate := c.insert(src, pos)
+			matchPos, matchLen := c.findMatch(src, pos, candidate, matchLimit)
+			if matchLen < minMatch {
+				// 快速模式下每连续 64 次没有匹配将步长加一，与 lz4 的加速策略一致
+				step := 1
+				if c.depth == 1 {
+					step += misses >> 6
+				}
+				misses++
+				pos += step
+				continue
+			}
+			misses = 0
+
+			// 向前扩展匹配
+			for pos > anchor && matchPos > 0 && src[pos-1] == src[matchPos-1] {
+				pos--
+				matchPos--
+				matchLen++
+			}
+
+			dst = appendSequence(dst, src[anchor:pos], pos-matchPos, matchLen)
+
+			// 将匹配内的位置加入哈希表，快速模式只加入末尾附近的一个位置
+			end := pos + matchLen
+			next := pos + 1
+			if c.depth == 1 {
+				next = max(next, end-2)
+			}
+			for ; next < end && next < startLimit; next++ {
+				c.insert(src, next)
+			}
+			pos = end
+			anchor = end
+		}
+	}
+
+	// 最后一个序列只有字面量
+	return appendSequence(dst, src[anchor:], 0, 0)
+}
+
+// findMatch 从候选位置中查找最长的匹配
+//
+// 参数:
+//   - src: 原始数据
+//   - pos: 当前位置
+//   - candidate: 哈希表中的最近位置，-1 表示没有
+//   - matchLimit: 匹配结束位置的上限
+//
+// 返回:
+//   - int: 匹配的起始位置
+//   - int: 匹配长度，小于 minMatch 表示没有匹配
+func (c *blockCompressor) findMatch(src []byte, pos, candidate, matchLimit int) (int, int) {
+	bestPos, bestLen := 0, 0
+	current := binary.LittleEndian.Uint32(src[pos:])
+	for tries := 0; tries < c.depth && candidate >= 0 && candidate < pos && pos-candidate <= maxOffset; tries++ {
+		if binary.LittleEndian.Uint32(src[candidate:]) == current {
+			length := minMatch
+			for pos+length < matchLimit && src[candidate+length] == src[pos+length] {
+				length++
+			}
+			if length > bestLen {
+				bestPos, bestLen = candidate, length
+			}
+		}
+
+		// 哈希链中的位置必须严格递减，否则说明已被窗口外的位置覆盖
+		next := int(c.chain[candidate&windowMask]) - 1
+		if next >= candidate {
+			break
+		}
+		candidate = next
+	}
+	return bestPos, bestLen
+}
+
+// appendSequence 追加一个序列
+//
+// 参数:
+//   - dst: 输出缓冲区
+//   - literals: 字面量
+//   - offset: 匹配的回溯距离，matchLen 为 0 时忽略
+//   - matchLen: 匹配长度，0 表示最后一个只有字面量的序列
+//
+// 返回:
+//   - []byte: 追加序列后的输出缓冲区
+func appendSequence(dst, literals []byte, offset, matchLen int) []byte {
+	token := byte(min(len(literals), 15)) << 4
+	if matchLen > 0 {
+		token |= byte(min(matchLen-minMatch, 15))
+	}
+	dst = append(dst, token)
+	if len(literals) >= 15 {
+		dst = appendLength(dst, len(literals)-15)
+	}
+	dst = append(dst, literals...)
+	if matchLen == 0 {
+		return dst
+	}
+
+	dst = append(dst, byte(offset), byte(offset>>8))
+	if matchLen-minMatch >= 15 {
+		dst = appendLength(dst, matchLen-minMatch-15)
+	}
+	return dst
+}
+
+// appendLength 追加超过标记字节容量的长度
+func appendLength(dst []byte, n int) []byte {
+	for ; n >= 255; n -= 255 {
+		dst = append(dst, 255)
+	}
+	return append(dst, byte(n))
+}
+
+// decompressBlock 解压一个数据块并追加到 dst
+//
+// dst 中已有的内容作为可以引用的历史数据，用于链接模式的数据块。
+//
+// 参数:
+//   - src: 压缩数据
+//   - dst: 输出缓冲区
+//   - maxSize: 解压后数据的最大字节数
+//
+// 返回:
+//   - []byte: 追加解压数据后的输出缓冲区
+//   - error: 数据块损坏或超过最大字节数时返回错误
+func decompressBlock(src, dst []byte, maxSize int) ([]byte, error) {
+	start := len(dst)
+	for i := 0; ; {
+		if i >= len(src) {
+			return nil, errCorruptBlock
+		}
+		token := src[i]
+		i++
+
+		// 字面量
+		literalLen := int(token >> 4)
+		if literalLen == 15 {
+			n, next, err := readLength(src, i)
+			if err != nil {
+				return nil, err
+			}
+			literalLen += n
+			i = next
+		}
+		if literalLen > len(src)-i || literalLen > maxSize-(len(dst)-start) {
+			return nil, errCorruptBlock
+		}
+		dst = append(dst, src[i:i+literalLen]...)
+		i += literalLen
+
+		// 最后一个序列只有字面量
+		if i == len(src) {
+			return dst, nil
+		}
+
+		// 匹配
+		if len(src)-i < 2 {
+			return nil, errCorruptBlock
+		}
+		offset := int(binary.LittleEndian.Uint16(src[i:]))
+		i += 2
+		matchLen := int(token & 15)
+		if matchLen == 15 {
+			n, next, err := readLength(src, i)
+			if err != nil {
+				return nil, err
+			}
+			matchLen += n
+			i = next
+		}
+		matchLen += minMatch
+		if offset == 0 || offset > len(dst) || matchLen > maxSize-(len(dst)-start) {
+			return nil, errCorruptBlock
+		}
+
+		from := len(dst) - offset
+		if offset >= matchLen {
+			dst = append(dst, dst[from:from+matchLen]...)
+		} else {
+			// 匹配与输出重叠时逐字节复制
+			for k := 0; k < matchLen; k++ {
+				dst = append(dst, dst[from+k])
+			}
+		}
+	}
+}
+
+// readLength 读取由若干 255 和一个结束字节组成的扩展长度
+func readLength(src []byte, i int) (int, int, error) {
+	n := 0
+	for {
+		if i >= len(src) {
+			return 0, 0, errCorruptBlock
+		}
+		b := src[i]
+		i++
+		n += int(b)
+		if b != 255 {
+			return n, i, nil
+		}
+	}
+}
@@ -0,0 +1,377 @@
+// Package cxlz4 提供 LZ4 帧格式的读写功能实现。
+//
+// LZ4 帧由魔数、帧描述符、若干数据块、结束标记和可选的内容校验组成，
+// 与 lz4 命令行工具生成的 .lz4 文件兼容。写入时使用独立数据块和内容校验，
+// 读取时支持链接模式的数据块、数据块校验、内容大小、多个拼接的帧和可跳过的帧。
+//
+// 主要功能：
+//   - 按压缩等级写入 LZ4 帧
+//   - 读取并校验 LZ4 帧
+//
+// 限制：
+//   - 不支持使用外部字典(DictID)的帧
+//   - 不支持旧版(legacy)帧格式
+//
+// 使用示例：
+//
+//	w := cxlz4.NewWriter(file, types.CompressionLevelDefault)
+//	_, err := w.Write(data)
+//	err = w.Close()
+//
+//	err = cxlz4.DecompressStream(dst, file)
+package cxlz4
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+
+	"gitee.com/MM-Q/comprx/types"
+)
+
+const (
+	frameMagic         = 0x184D2204 // LZ4 帧魔数
+	legacyMagic        = 0x184C2102 // 旧版帧魔数
+	skippableMagicMask = 0xFFFFFFF0 // 可跳过帧魔数的掩码
+	skippableMagic     = 0x184D2A50 // 可跳过帧魔数(低 4 位任意)
+
+	flagVersion       = 0x40 // 版本号 01
+	flagBlockIndep    = 0x20 // 数据块相互独立
+	flagBlockChecksum = 0x10 // 每个数据块带校验
+	flagContentSize   = 0x08 // 帧描述符中包含内容大小
+	flagContentCheck  = 0x04 // 帧末尾带内容校验
+	flagDictID        = 0x01 // 帧描述符中包含字典 ID
+
+	blockUncompressed = 0x80000000 // 数据块大小的最高位表示未压缩
+
+	// writeBlockSizeID 写入时使用的数据块最大大小编号(4 MiB)，与 lz4 命令行工具的默认值一致
+	writeBlockSizeID = 7
+)
+
+// ErrNotLz4 数据不是 LZ4 帧格式
+var ErrNotLz4 = errors.New("不是有效的 LZ4 帧")
+
+// blockMaxSize 返回数据块最大大小编号对应的字节数
+//
+// 参数:
+//   - id: 编号，4 到 7 分别表示 64 KiB、256 KiB、1 MiB、4 MiB
+//
+// 返回:
+//   - int: 字节数
+func blockMaxSize(id int) int {
+	return 1 << (8 + 2*id)
+}
+
+// searchDepth 返回压缩等级对应的匹配搜索深度
+//
+// 参数:
+//   - level: 压缩等级
+//
+// 返回:
+//   - int: 搜索深度，0 表示不压缩
+func searchDepth(level types.CompressionLevel) int {
+	switch level {
+	case types.CompressionLevelNone:
+		return 0
+	case types.CompressionLevelFast:
+		return 1
+	case types.CompressionLevelBest:
+		return 256
+	default:
+		return 16
+	}
+}
+
+// Writer LZ4 帧写入器
+type Writer struct {
+	w          io.Writer        // 输出目标
+	compressor *blockCompressor // 数据块压缩器，不压缩时为 nil
+	data       []byte           // 当前数据块中尚未写出的数据
+	block      []byte           // 压缩数据块的缓冲区
+	checksum   *xxh32           // 内容校验
+	blockSize  int              // 数据块最大大小
+	started    bool             // 是否已写入帧头
+	closed     bool             // 是否已关闭
+}
+
+// NewWriter 创建LZ4帧写入器
+//
+// 参数:
+//   - w: 输出目标
+//   - level: 压缩等级，CompressionLevelNone 时以未压缩的数据块写入
+//
+// 返回:
+//   - *Writer: LZ4 帧写入器
+func NewWriter(w io.Writer, level types.CompressionLevel) *Writer {
+	writer := &Writer{
+		w:         w,
+		checksum:  newXXH32(),
+		blockSize: blockMaxSize(writeBlockSizeID),
+	}
+	if depth := searchDepth(level); depth > 0 {
+		writer.compressor = newBlockCompressor(depth)
+	}
+	return writer
+}
+
+// Write 写入数据，满一个数据块时压缩并写出
+//
+// 参数:
+//   - p: 要写入的数据
+//
+// 返回:
+//   - int: 写入的字节数
+//   - error: 错误信息
+func (w *Writer) Write(p []byte) (int, error) {
+	if w.closed {
+		return 0, fmt.Errorf("LZ4 写入器已关闭")
+	}
+	if err := w.writeHeader(); err != nil {
+		return 0, err
+	}
+
+	written := 0
+	for len(p) > 0 {
+		n := min(len(p), w.blockSize-len(w.data))
+		w.data = append(w.data, p[:n]...)
+		p = p[n:]
+		written += n
+
+		if len(w.data) == w.blockSize {
+			if err := w.writeBlock(); err != nil {
+				return written, err
+			}
+		}
+	}
+	return written, nil
+}
+
+// Close 写出剩余数据、结束标记和内容校验
+//
+// 不关闭底层的输出目标。
+//
+// 返回:
+//   - error: 错误信息
+func (w *Writer) Close() error {
+	if w.closed {
+		return nil
+	}
+	if err := w.writeHeader(); err != nil {
+		return err
+	}
+	if len(w.data) > 0 {
+		if err := w.writeBlock(); err != nil {
+			return err
+		}
+	}
+	w.closed = true
+
+	var trailer [8]byte
+	binary.LittleEndian.PutUint32(trailer[4:], w.checksum.Sum32())
+	if _, err := w.w.Write(trailer[:]); err != nil {
+		return fmt.Errorf("写入 LZ4 结束标记失败: %w", err)
+	}
+	return nil
+}
+
+// writeHeader 写入魔数和帧描述符
+func (w *Writer) writeHeader() error {
+	if w.started {
+		return nil
+	}
+	w.started = true
+
+	header := []byte{0, 0, 0, 0, flagVersion | flagBlockIndep | flagContentCheck, writeBlockSizeID << 4, 0}
+	binary.LittleEndian.PutUint32(header, frameMagic)
+	header[6] = byte(xxhSum32(header[4:6]) >> 8)
+	if _, err := w.w.Write(header); err != nil {
+		return fmt.Errorf("写入 LZ4 帧头失败: %w", err)
+	}
+	return nil
+}
+
+// writeBlock 压缩当前数据块并写出，压缩后没有变小时以未压缩的形式写出
+func (w *Writer) writeBlock() error {
+	_, _ = w.checksum.Write(w.data)
+
+	if cap(w.block) < 4 {
+		w.block = make([]byte, 4, 4+len(w.data))
+	}
+	w.block = w.block[:4]
+	size := uint32(len(w.data)) | blockUncompressed
+	if w.compressor != nil {
+		w.block = w.compressor.compress(w.block, w.data)
+		if len(w.block)-4 < len(w.data) {
+			size = uint32(len(w.block) - 4)
+		}
+	}
+	if size&blockUncompressed != 0 {
+		w.block = append(w.block[:4], w.data...)
+	}
+	binary.LittleEndian.PutUint32(w.block, size)
+
+	if _, err := w.w.Write(w.block); err != nil {
+		return fmt.Errorf("写入 LZ4 数据块失败: %w", err)
+	}
+	w.data = w.data[:0]
+	return nil
+}
+
+// decompressFrames 解压数据流中的所有 LZ4 帧并写入 dst
+//
+// 参数:
+//   - dst: 目标写入器
+//   - src: LZ4 数据流
+//
+// 返回:
+//   - error: 错误信息
+func decompressFrames(dst io.Writer, src io.Reader) error {
+	r := bufio.NewReader(src)
+	for frames := 0; ; frames++ {
+		var magic [4]byte
+		if n, err := io.ReadFull(r, magic[:]); err != nil {
+			if err == io.EOF && n == 0 && frames > 0 {
+				return nil
+			}
+			if frames == 0 {
+				return ErrNotLz4
+			}
+			return fmt.Errorf("读取 LZ4 帧魔数失败: %w", err)
+		}
+
+		switch m := binary.LittleEndian.Uint32(magic[:]); {
+		case m == frameMagic:
+			if err := decompressFrame(dst, r); err != nil {
+				return err
+			}
+		case m&skippableMagicMask == skippableMagic:
+			var size [4]byte
+			if _, err := io.ReadFull(r, size[:]); err != nil {
+				return fmt.Errorf("读取 LZ4 可跳过帧失败: %w", err)
+			}
+			if _, err := io.CopyN(io.Discard, r, int64(binary.LittleEndian.Uint32(size[:]))); err != nil {
+				return fmt.Errorf("跳过 LZ4 可跳过帧失败: %w", err)
+			}
+		case m == legacyMagic:
+			return fmt.Errorf("不支持旧版 LZ4 帧格式")
+		default:
+			return ErrNotLz4
+		}
+	}
+}
+
+// decompressFrame 解压一个 LZ4 帧(魔数之后的部分)
+//
+// 参数:
+//   - dst: 目标写入器
+//   - r: 位于帧描述符起始处的数据流
+//
+// 返回:
+//   - error: 错误信息
+func decompressFrame(dst io.Writer, r io.Reader) error {
+	// 帧描述符
+	var descriptor [15]byte
+	if _, err := io.ReadFull(r, descriptor[:2]); err != nil {
+		return fmt.Errorf("读取 LZ4 帧描述符失败: %w", err)
+	}
+	flags, bd := descriptor[0], descriptor[1]
+	if flags>>6 != 1 || flags&0x02 != 0 || bd&0x8F != 0 {
+		return fmt.Errorf("不支持的 LZ4 帧版本或保留位: FLG=%#x BD=%#x", flags, bd)
+	}
+	if flags&flagDictID != 0 {
+		return fmt.Errorf("不支持使用外部字典的 LZ4 帧")
+	}
+	sizeID := int(bd>>4) & 7
+	if sizeID < 4 {
+		return fmt.Errorf("无效的 LZ4 数据块大小编号: %d", sizeID)
+	}
+	length := 2
+	if flags&flagContentSize != 0 {
+		length += 8
+	}
+	if _, err := io.ReadFull(r, descriptor[2:length+1]); err != nil {
+		return fmt.Errorf("读取 LZ4 帧描述符失败: %w", err)
+	}
+	if byte(xxhSum32(descriptor[:length])>>8) != descriptor[length] {
+		return fmt.Errorf("LZ4 帧描述符校验失败")
+	}
+	contentSize := int64(-1)
+	if flags&flagContentSize != 0 {
+		contentSize = int64(binary.LittleEndian.Uint64(descriptor[2:10]))
+	}
+
+	maxSize := blockMaxSize(sizeID)
+	linked := flags&flagBlockIndep == 0
+	checksum := newXXH32()
+	var compressed, out []byte
+	var total int64
+	var word [4]byte
+	for {
+		if _, err := io.ReadFull(r, word[:]); err != nil {
+			return fmt.Errorf("读取 LZ4 数据块大小失败: %w", err)
+		}
+		size := binary.LittleEndian.Uint32(word[:])
+		if size == 0 {
+			break
+		}
+		uncompressed := size&blockUncompressed != 0
+		size &^= blockUncompressed
+		if int(size) > maxSize {
+			return fmt.Errorf("LZ4 数据块超过最大大小: %d", size)
+		}
+
+		if cap(compressed) < int(size) {
+			compressed = make([]byte, size)
+		}
+		compressed = compressed[:size]
+		if _, err := io.ReadFull(r, compressed); err != nil {
+			return fmt.Errorf("读取 LZ4 数据块失败: %w", err)
+		}
+		if flags&flagBlockChecksum != 0 {
+			if _, err := io.ReadFull(r, word[:]); err != nil {
+				return fmt.Errorf("读取 LZ4 数据块校验失败: %w", err)
+			}
+			if xxhSum32(compressed) != binary.LittleEndian.Uint32(word[:]) {
+				return fmt.Errorf("LZ4 数据块校验失败")
+			}
+		}
+
+		// 链接模式下保留最近 64 KiB 作为下一个数据块可以引用的历史数据
+		history := 0
+		if linked && len(out) > 0 {
+			history = min(len(out), maxOffset)
+			copy(out, out[len(out)-history:])
+		}
+		out = out[:history]
+		if uncompressed {
+			out = append(out, compressed...)
+		} else {
+			var err error
+			if out, err = decompressBlock(compressed, out, maxSize); err != nil {
+				return err
+			}
+		}
+
+		block := out[history:]
+		_, _ = checksum.Write(block)
+		total += int64(len(block))
+		if _, err := dst.Write(block); err != nil {
+			return fmt.Errorf("写入解压数据失败: %w", err)
+		}
+	}
+
+	if contentSize >= 0 && total != contentSize {
+		return fmt.Errorf("LZ4 内容大小不一致: 帧描述符记录 %d 字节，实际 %d 字节", contentSize, total)
+	}
+	if flags&flagContentCheck != 0 {
+		if _, err := io.ReadFull(r, word[:]); err != nil {
+			return fmt.Errorf("读取 LZ4 内容校验失败: %w", err)
+		}
+		if checksum.Sum32() != binary.LittleEndian.Uint32(word[:]) {
+			return fmt.Errorf("LZ4 内容校验失败")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,240 @@
+package cxlz4
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"gitee.com/MM-Q/comprx/types"
+)
+
+// cliLinkedFrame 由 lz4 命令行工具以 -BD -BX --content-size -B4 生成的帧，
+// 包含两个链接模式的数据块、数据块校验和内容大小，原始数据见 cliLinkedData
+const cliLinkedFrame = "04224d185c400077010000000000b52a020000f1133030302074686520717569636b2062726f776e20666f78206a756d" +
+	"7073206f7665721f00c16c617a7920646f670a30303111000f3000171f3230001c1f3330001c1f3430001c1f3530001c" +
+	"1f3630001c1f3730001c1f3830001c1f3930001b1f31e0011c1f31e0011c1f31e0011c1f31e0011c1f31e0011c1f31e0" +
+	"011c1f31e0011c1f31e0011c1f31e0011c1f31e0011c1f32e0011c1f32e0011c1f32e0011c1f32e0011c1f32e0011c1f" +
+	"32e0011c1f32e0011c1f32e0011c1f32e0011c1f32e0011c1f33e0011c1f33e0011c1f33e0011c1f33e0011c1f33e001" +
+	"1c1f33e0011c1f33e0011c1f33e0011c1f33e0011c1f33e0011c1f34e0011c1f34e0011c1f34e0011c1f34e0011c1f34" +
+	"e0011c1f34e0011c1f34e0011c1f34e0011c1f34e0011c1f34e0011c0f6009ffffffffffffffffffffffffffffffffff" +
+	"ffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff" +
+	"ffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff" +
+	"ffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff" +
+	"ffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff" +
+	"ffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff7e50636b206272ff22b9" +
+	"4a850000000ff0ff0e0f20fdffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff" +
+	"ffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff" +
+	"ffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff3e5020646f670a244a59060000" +
+	"00001a7823f5"
+
+// cliLinkedData 返回 cliLinkedFrame 的原始数据
+func cliLinkedData() []byte {
+	var sb strings.Builder
+	for i := 0; i < 2000; i++ {
+		fmt.Fprintf(&sb, "%03d the quick brown fox jumps over the lazy dog\n", i%50)
+	}
+	return []byte(sb.String())
+}
+
+// TestXXH32 测试 xxHash32 的标准测试向量和分段写入
+func TestXXH32(t *testing.T) {
+	tests := []struct {
+		input string
+		want  uint32
+	}{
+		{"", 0x02CC5D05},
+		{"a", 0x550D7456},
+		{"abc", 0x32D153FF},
+		{"Nobody inspects the spammish repetition", 0xE2293B2F},
+	}
+	for _, tt := range tests {
+		if got := xxhSum32([]byte(tt.input)); got != tt.want {
+			t.Errorf("xxhSum32(%q) = %#x, 期望 %#x", tt.input, got, tt.want)
+		}
+	}
+
+	data := make([]byte, 1000)
+	if _, err := rand.Read(data); err != nil {
+		t.Fatal(err)
+	}
+	h := newXXH32()
+	for rest := data; len(rest) > 0; {
+		n := min(len(rest), 7)
+		_, _ = h.Write(rest[:n])
+		rest = rest[n:]
+	}
+	if h.Sum32() != xxhSum32(data) {
+		t.Error("分段写入的校验值应与一次性计算一致")
+	}
+}
+
+// TestRoundTrip 测试各压缩等级的压缩解压
+func TestRoundTrip(t *testing.T) {
+	random := make([]byte, 300*1024)
+	if _, err := rand.Read(random); err != nil {
+		t.Fatal(err)
+	}
+	inputs := map[string][]byte{
+		"单字节":  []byte("a"),
+		"短文本":  []byte("hello, lz4"),
+		"重复文本": bytes.Repeat([]byte("comprx lz4 frame format "), 50000),
+		"随机数据": random,
+		"零字节":  make([]byte, 5<<20),
+	}
+	levels := []types.CompressionLevel{
+		types.CompressionLevelNone,
+		types.CompressionLevelFast,
+		types.CompressionLevelDefault,
+		types.CompressionLevelBest,
+	}
+
+	for name, data := range inputs {
+		for _, level := range levels {
+			t.Run(fmt.Sprintf("%s/%d", name, level), func(t *testing.T) {
+				compressed, err := CompressBytes(data, level)
+				if err != nil {
+					t.Fatalf("压缩失败: %v", err)
+				}
+				got, err := DecompressBytes(compressed)
+				if err != nil {
+					t.Fatalf("解压失败: %v", err)
+				}
+				if !bytes.Equal(got, data) {
+					t.Fatal("解压结果不一致")
+				}
+				if level != types.CompressionLevelNone && name == "重复文本" && len(compressed) > len(data)/10 {
+					t.Errorf("重复文本压缩率过低: %d -> %d", len(data), len(compressed))
+				}
+			})
+		}
+	}
+}
+
+// TestWriter_Empty 测试没有写入数据时生成空帧
+func TestWriter_Empty(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewWriter(&buf, types.CompressionLevelDefault)
+	if err := w.Close(); err != nil {
+		t.Fatalf("关闭写入器失败: %v", err)
+	}
+
+	var out bytes.Buffer
+	if err := DecompressStream(&out, &buf); err != nil {
+		t.Fatalf("解压空帧失败: %v", err)
+	}
+	if out.Len() != 0 {
+		t.Errorf("空帧解压结果应为空, 实际 %d 字节", out.Len())
+	}
+}
+
+// TestDecompress_CliFrame 测试解压 lz4 命令行工具生成的链接模式帧
+func TestDecompress_CliFrame(t *testing.T) {
+	frame, err := hex.DecodeString(cliLinkedFrame)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := DecompressBytes(frame)
+	if err != nil {
+		t.Fatalf("解压失败: %v", err)
+	}
+	if !bytes.Equal(got, cliLinkedData()) {
+		t.Error("解压结果不一致")
+	}
+
+	// 篡改第二个数据块时数据块校验应失败
+	corrupt := bytes.Clone(frame)
+	corrupt[len(corrupt)-20] ^= 0x01
+	if _, err := DecompressBytes(corrupt); err == nil {
+		t.Error("数据块损坏时应返回错误")
+	}
+}
+
+// TestDecompress_MultipleFrames 测试拼接的帧和可跳过帧
+func TestDecompress_MultipleFrames(t *testing.T) {
+	first, err := CompressBytes([]byte("first frame, "), types.CompressionLevelFast)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := CompressBytes([]byte("second frame"), types.CompressionLevelBest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	skippable := []byte{0x5A, 0x2A, 0x4D, 0x18, 3, 0, 0, 0, 'x', 'y', 'z'}
+
+	stream := bytes.Join([][]byte{skippable, first, skippable, second}, nil)
+	got, err := DecompressBytes(stream)
+	if err != nil {
+		t.Fatalf("解压失败: %v", err)
+	}
+	if string(got) != "first frame, second frame" {
+		t.Errorf("解压结果不一致: %q", got)
+	}
+}
+
+// TestDecompress_Invalid 测试无效数据
+func TestDecompress_Invalid(t *testing.T) {
+	data := bytes.Repeat([]byte("checksum "), 1000)
+	compressed, err := CompressBytes(data, types.CompressionLevelDefault)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("非LZ4数据", func(t *testing.T) {
+		_, err := DecompressBytes([]byte("not an lz4 frame"))
+		if !errors.Is(err, ErrNotLz4) {
+			t.Errorf("应返回 ErrNotLz4, 实际 %v", err)
+		}
+	})
+
+	t.Run("内容校验", func(t *testing.T) {
+		corrupt := bytes.Clone(compressed)
+		corrupt[len(corrupt)-1] ^= 0xFF
+		if _, err := DecompressBytes(corrupt); err == nil {
+			t.Error("内容校验不一致时应返回错误")
+		}
+	})
+
+	t.Run("描述符校验", func(t *testing.T) {
+		corrupt := bytes.Clone(compressed)
+		corrupt[6] ^= 0xFF
+		if _, err := DecompressBytes(corrupt); err == nil {
+			t.Error("描述符校验不一致时应返回错误")
+		}
+	})
+
+	t.Run("截断", func(t *testing.T) {
+		if _, err := DecompressBytes(compressed[:len(compressed)/2]); err == nil {
+			t.Error("数据截断时应返回错误")
+		}
+	})
+
+	t.Run("空输入", func(t *testing.T) {
+		if _, err := CompressBytes(nil, types.CompressionLevelDefault); err == nil {
+			t.Error("nil 输入应返回错误")
+		}
+		if _, err := DecompressBytes([]byte{}); err == nil {
+			t.Error("空输入应返回错误")
+		}
+	})
+}
+
+// TestDecompressBlock_Corrupt 测试损坏的数据块不会越界
+func TestDecompressBlock_Corrupt(t *testing.T) {
+	data := bytes.Repeat([]byte("0123456789abcdef"), 256)
+	block := newBlockCompressor(16).compress(nil, data)
+	for i := range block {
+		corrupt := bytes.Clone(block)
+		corrupt[i] ^= 0xA5
+		out, err := decompressBlock(corrupt, nil, len(data))
+		if err == nil && len(out) > len(data) {
+			t.Fatalf("解压结果超过最大大小: %d", len(out))
+		}
+	}
+	for i := 0; i < len(block); i++ {
+		_, _ = decompressBlock(block[:i], nil, len(data))
+	}
+}
@@ -0,0 +1,125 @@
+// Package cxlz4 提供 LZ4 帧格式的内存压缩和流式压缩功能实现。
+//
+// 压缩结果是标准的 LZ4 帧，可以用 lz4 命令行工具解压。压缩等级决定匹配的搜索深度：
+// CompressionLevelNone 以未压缩的数据块写入，CompressionLevelFast 只比较最近的候选位置，
+// CompressionLevelBest 沿哈希链搜索更长的匹配。
+//
+// 主要功能：
+//   - LZ4 内存压缩：字节数组的压缩解压
+//   - LZ4 流式压缩：支持 io.Reader 和 io.Writer 接口
+//   - 支持自定义压缩等级
+//
+// 使用示例：
+//
+//	// 压缩字节数据
+//	compressed, err := cxlz4.CompressBytes(data, types.CompressionLevelFast)
+//
+//	// 解压字节数据
+//	decompressed, err := cxlz4.DecompressBytes(compressed)
+package cxlz4
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+
+	"gitee.com/MM-Q/comprx/types"
+)
+
+// CompressBytes 压缩字节数据到内存
+//
+// 参数:
+//   - data: 要压缩的字节数据
+//   - level: 压缩级别
+//
+// 返回:
+//   - []byte: 压缩后的数据
+//   - error: 错误信息
+func CompressBytes(data []byte, level types.CompressionLevel) ([]byte, error) {
+	if data == nil {
+		return nil, fmt.Errorf("输入数据不能为nil")
+	}
+	if len(data) == 0 {
+		return nil, fmt.Errorf("输入数据不能为空")
+	}
+
+	buf := bytes.NewBuffer(make([]byte, 0, len(data)/2+64))
+	if err := CompressStream(buf, bytes.NewReader(data), level); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// DecompressBytes 从内存解压字节数据
+//
+// 参数:
+//   - compressedData: 压缩的字节数据
+//
+// 返回:
+//   - []byte: 解压后的数据
+//   - error: 错误信息
+func DecompressBytes(compressedData []byte) ([]byte, error) {
+	if compressedData == nil {
+		return nil, fmt.Errorf("压缩数据不能为nil")
+	}
+	if len(compressedData) == 0 {
+		return nil, fmt.Errorf("压缩数据不能为空")
+	}
+
+	buf := bytes.NewBuffer(make([]byte, 0, len(compressedData)*2))
+	if err := DecompressStream(buf, bytes.NewReader(compressedData)); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// CompressStream 流式压缩数据
+//
+// 参数:
+//   - dst: 目标写入器
+//   - src: 源读取器
+//   - level: 压缩级别
+//
+// 返回:
+//   - error: 错误信息
+func CompressStream(dst io.Writer, src io.Reader, level types.CompressionLevel) error {
+	if dst == nil {
+		return fmt.Errorf("目标写入器不能为nil")
+	}
+	if src == nil {
+		return fmt.Errorf("源读取器不能为nil")
+	}
+
+	writer := NewWriter(dst, level)
+	if _, err := io.Copy(writer, src); err != nil {
+		return fmt.Errorf("压缩数据失败: %w", err)
+	}
+	if err := writer.Close(); err != nil {
+		return fmt.Errorf("完成压缩失败: %w", err)
+	}
+	return nil
+}
+
+// DecompressStream 流式解压数据
+//
+// 依次解压数据流中拼接的所有帧，跳过可跳过的帧。
+//
+// 参数:
+//   - dst: 目标写入器
+//   - src: 源读取器（压缩数据）
+//
+// 返回:
+//   - error: 错误信息
+func DecompressStream(dst io.Writer, src io.Reader) error {
+	if dst == nil {
+		return fmt.Errorf("目标写入器不能为nil")
+	}
+	if src == nil {
+		return fmt.Errorf("源读取器不能为nil")
+	}
+
+	if err := decompressFrames(dst, src); err != nil {
+		return fmt.Errorf("解压数据失败: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,128 @@
+// Package cxlz4 提供 LZ4 帧格式使用的 xxHash32 校验功能实现。
+//
+// LZ4 帧格式使用种子为 0 的 xxHash32 计算帧描述符校验、数据块校验和内容校验。
+//
+// 主要功能：
+//   - 流式计算 xxHash32
+//   - 一次性计算字节数据的 xxHash32
+//
+// 使用示例：
+//
+//	h := newXXH32()
+//	h.Write(data)
+//	sum := h.Sum32()
+package cxlz4
+
+import (
+	"encoding/binary"
+	"math/bits"
+)
+
+const (
+	xxhPrime1 uint32 = 2654435761
+	xxhPrime2 uint32 = 2246822519
+	xxhPrime3 uint32 = 3266489917
+	xxhPrime4 uint32 = 668265263
+	xxhPrime5 uint32 = 374761393
+)
+
+// xxh32 种子为 0 的 xxHash32 流式计算器
+type xxh32 struct {
+	v     [4]uint32 // 四路累加器
+	total uint64    // 已写入的总字节数
+	mem   [16]byte  // 不足 16 字节的剩余数据
+	n     int       // mem 中的字节数
+}
+
+// newXXH32 创建xxHash32计算器
+func newXXH32() *xxh32 {
+	h := &xxh32{}
+	h.Reset()
+	return h
+}
+
+// Reset 重置计算器
+func (h *xxh32) Reset() {
+	// 种子为 0 时四路累加器的初始值为 P1+P2、P2、0、-P1 (按 32 位回绕)
+	h.v = [4]uint32{xxhPrime1, xxhPrime2, 0, 0}
+	h.v[0] += xxhPrime2
+	h.v[3] -= xxhPrime1
+	h.total = 0
+	h.n = 0
+}
+
+// Write 写入数据，总是返回 len(p) 和 nil
+func (h *xxh32) Write(p []byte) (int, error) {
+	written := len(p)
+	h.total += uint64(len(p))
+
+	// 先补齐上次剩余的数据
+	if h.n > 0 {
+		n := copy(h.mem[h.n:], p)
+		h.n += n
+		p = p[n:]
+		if h.n < len(h.mem) {
+			return written, nil
+		}
+		h.rounds(h.mem[:])
+		h.n = 0
+	}
+
+	full := len(p) &^ 15
+	h.rounds(p[:full])
+	h.n = copy(h.mem[:], p[full:])
+	return written, nil
+}
+
+// rounds 处理长度为 16 的整数倍的数据
+func (h *xxh32) rounds(p []byte) {
+	for ; len(p) >= 16; p = p[16:] {
+		h.v[0] = xxhRound(h.v[0], binary.LittleEndian.Uint32(p[0:4]))
+		h.v[1] = xxhRound(h.v[1], binary.LittleEndian.Uint32(p[4:8]))
+		h.v[2] = xxhRound(h.v[2], binary.LittleEndian.Uint32(p[8:12]))
+		h.v[3] = xxhRound(h.v[3], binary.LittleEndian.Uint32(p[12:16]))
+	}
+}
+
+// Sum32 返回当前的校验值
+func (h *xxh32) Sum32() uint32 {
+	var sum uint32
+	if h.total >= 16 {
+		sum = bits.RotateLeft32(h.v[0], 1) + bits.RotateLeft32(h.v[1], 7) +
+			bits.RotateLeft32(h.v[2], 12) + bits.RotateLeft32(h.v[3], 18)
+	} else {
+		sum = xxhPrime5
+	}
+	sum += uint32(h.total)
+
+	p := h.mem[:h.n]
+	for ; len(p) >= 4; p = p[4:] {
+		sum += binary.LittleEndian.Uint32(p) * xxhPrime3
+		sum = bits.RotateLeft32(sum, 17) * xxhPrime4
+	}
+	for _, b := range p {
+		sum += uint32(b) * xxhPrime5
+		sum = bits.RotateLeft32(sum, 11) * xxhPrime1
+	}
+
+	sum ^= sum >> 15
+	sum *= xxhPrime2
+	sum ^= sum >> 13
+	sum *= xxhPrime3
+	sum ^= sum >> 16
+	return sum
+}
+
+// xxhRound 单轮累加
+func xxhRound(acc, input uint32) uint32 {
+	acc += input * xxhPrime2
+	acc = bits.RotateLeft32(acc, 13)
+	return acc * xxhPrime1
+}
+
+// xxhSum32 计算字节数据的xxHash32
+func xxhSum32(p []byte) uint32 {
+	h := newXXH32()
+	_, _ = h.Write(p)
+	return h.Sum32()
+}
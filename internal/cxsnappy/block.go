@@ -0,0 +1,248 @@
+// Package cxsnappy 提供 Snappy 数据块格式的编码和解码功能实现。
+//
+// Snappy 数据块以原始长度的变长整数开头，随后是字面量和复制两类元素。编码器使用
+// 哈希表查找 4 字节的匹配，复制元素的回溯距离不超过一个分块的大小。
+//
+// 主要功能：
+//   - 编码数据块
+//   - 解码数据块，支持 1、2、4 字节回溯距离的复制元素
+//
+// 使用示例：
+//
+//	encoded := encodeBlock(nil, data)
+//	decoded, err := decodeBlock(nil, encoded)
+package cxsnappy
+
+import (
+	"encoding/binary"
+	"errors"
+)
+
+const (
+	tagLiteral = 0x00 // 字面量元素
+	tagCopy1   = 0x01 // 长度 4 到 11、回溯距离小于 2048 的复制元素
+	tagCopy2   = 0x02 // 长度 1 到 64、回溯距离小于 65536 的复制元素
+	tagCopy4   = 0x03 // 长度 1 到 64、回溯距离为 32 位的复制元素
+
+	minMatch       = 4          // 最短匹配长度
+	inputMargin    = 15         // 数据块末尾不查找匹配的字节数
+	maxBlockSize   = 65536      // 单次编码的最大字节数，与分块大小一致
+	tableBits      = 14         // 哈希表大小的对数
+	hashMultiplier = 0x1e35a7bd // 4 字节哈希的乘数
+)
+
+// errCorruptBlock 数据块损坏
+var errCorruptBlock = errors.New("Snappy 数据块已损坏")
+
+// maxEncodedLen 返回编码 n 字节数据时输出的最大字节数
+func maxEncodedLen(n int) int {
+	return 32 + n + n/6
+}
+
+// encodeBlock 编码一个数据块并追加到 dst
+//
+// 参数:
+//   - dst: 输出缓冲区
+//   - src: 原始数据，不超过 maxBlockSize 字节
+//
+// 返回:
+//   - []byte: 追加编码数据后的输出缓冲区
+func encodeBlock(dst, src []byte) []byte {
+	dst = binary.AppendUvarint(dst, uint64(len(src)))
+
+	anchor := 0
+	if len(src) > inputMargin {
+		var table [1 << tableBits]uint16
+		limit := len(src) - inputMargin
+		misses := 0
+		for pos := 1; pos < limit; {
+			current := binary.LittleEndian.Uint32(src[pos:])
+			h := hash4(current)
+			candidate := int(table[h])
+			table[h] = uint16(pos)
+			if candidate >= pos || binary.LittleEndian.Uint32(src[candidate:]) != current {
+				// 每连续 32 次没有匹配将步长加一，加快不可压缩数据的处理
+				pos += 1 + misses>>5
+				misses++
+				continue
+			}
+			misses = 0
+
+			// 向后扩展匹配
+			length := minMatch
+			for pos+length < len(src) && src[candidate+length] == src[pos+length] {
+				length++
+			}
+
+			dst = appendLiteral(dst, src[anchor:pos])
+			dst = appendCopy(dst, pos-candidate, length)
+			pos += length
+			anchor = pos
+
+			// 将匹配末尾的位置加入哈希表
+			if pos-1 < limit {
+				table[hash4(binary.LittleEndian.Uint32(src[pos-1:]))] = uint16(pos - 1)
+			}
+		}
+	}
+	return appendLiteral(dst, src[anchor:])
+}
+
+// hash4 计算 4 字节的哈希值
+func hash4(v uint32) uint32 {
+	return v * hashMultiplier >> (32 - tableBits)
+}
+
+// appendLiteral 追加字面量元素
+func appendLiteral(dst, literal []byte) []byte {
+	if len(literal) == 0 {
+		return dst
+	}
+	n := uint32(len(literal) - 1)
+	switch {
+	case n < 60:
+		dst = append(dst, byte(n)<<2|tagLiteral)
+	case n < 1<<8:
+		dst = append(dst, 60<<2|tagLiteral, byte(n))
+	case n < 1<<16:
+		dst = append(dst, 61<<2|tagLiteral, byte(n), byte(n>>8))
+	case n < 1<<24:
+		dst = append(dst, 62<<2|tagLiteral, byte(n), byte(n>>8), byte(n>>16))
+	default:
+		dst = append(dst, 63<<2|tagLiteral, byte(n), byte(n>>8), byte(n>>16), byte(n>>24))
+	}
+	return append(dst, literal...)
+}
+
+// appendCopy 追加复制元素，长度超过 64 时拆分为多个元素
+func appendCopy(dst []byte, offset, length int) []byte {
+	// 保证最后一个元素的长度不小于 4，以便在条件允许时使用 1 字节回溯距离的编码
+	for length >= 68 {
+		dst = append(dst, 63<<2|tagCopy2, byte(offset), byte(offset>>8))
+		length -= 64
+	}
+	if length > 64 {
+		dst = append(dst, 59<<2|tagCopy2, byte(offset), byte(offset>>8))
+		length -= 60
+	}
+	if length <= 11 && offset < 2048 {
+		return append(dst, byte(offset>>8)<<5|byte(length-4)<<2|tagCopy1, byte(offset))
+	}
+	return append(dst, byte(length-1)<<2|tagCopy2, byte(offset), byte(offset>>8))
+}
+
+// decodedLen 读取数据块开头记录的原始长度
+//
+// 参数:
+//   - src: 编码数据
+//
+// 返回:
+//   - int: 原始长度
+//   - int: 长度字段占用的字节数
+//   - error: 长度字段无效时返回错误
+func decodedLen(src []byte) (int, int, error) {
+	v, n := binary.Uvarint(src)
+	if n <= 0 || v > 0xffffffff {
+		return 0, 0, errCorruptBlock
+	}
+	return int(v), n, nil
+}
+
+// decodeBlock 解码一个数据块并追加到 dst
+//
+// 参数:
+//   - dst: 输出缓冲区
+//   - src: 编码数据
+//
+// 返回:
+//   - []byte: 追加解码数据后的输出缓冲区
+//   - error: 数据块损坏时返回错误
+func decodeBlock(dst, src []byte) ([]byte, error) {
+	length, i, err := decodedLen(src)
+	if err != nil {
+		return nil, err
+	}
+	if length > len(src)*64 {
+		// 每个 3 字节的复制元素最多产生 64 字节，更大的长度只可能来自损坏的数据
+		return nil, errCorruptBlock
+	}
+
+	start := len(dst)
+	dst = growBuffer(dst, length)
+	for i < len(src) {
+		tag := src[i]
+		var offset, n int
+		switch tag & 0x03 {
+		case tagLiteral:
+			n = int(tag >> 2)
+			i++
+			if n >= 60 {
+				extra := n - 59
+				if len(src)-i < extra {
+					return nil, errCorruptBlock
+				}
+				n = 0
+				for k := extra - 1; k >= 0; k-- {
+					n = n<<8 | int(src[i+k])
+				}
+				i += extra
+			}
+			n++
+			if n > len(src)-i || n > length-(len(dst)-start) {
+				return nil, errCorruptBlock
+			}
+			dst = append(dst, src[i:i+n]...)
+			i += n
+			continue
+		case tagCopy1:
+			if len(src)-i < 2 {
+				return nil, errCorruptBlock
+			}
+			n = 4 + int(tag>>2&0x07)
+			offset = int(tag>>5)<<8 | int(src[i+1])
+			i += 2
+		case tagCopy2:
+			if len(src)-i < 3 {
+				return nil, errCorruptBlock
+			}
+			n = 1 + int(tag>>2)
+			offset = int(binary.LittleEndian.Uint16(src[i+1:]))
+			i += 3
+		case tagCopy4:
+			if len(src)-i < 5 {
+				return nil, errCorruptBlock
+			}
+			n = 1 + int(tag>>2)
+			offset = int(binary.LittleEndian.Uint32(src[i+1:]))
+			i += 5
+		}
+
+		if offset <= 0 || offset > len(dst)-start || n > length-(len(dst)-start) {
+			return nil, errCorruptBlock
+		}
+		from := len(dst) - offset
+		if offset >= n {
+			dst = append(dst, dst[from:from+n]...)
+		} else {
+			// 复制与输出重叠时逐字节复制
+			for k := 0; k < n; k++ {
+				dst = append(dst, dst[from+k])
+			}
+		}
+	}
+
+	if len(dst)-start != length {
+		return nil, errCorruptBlock
+	}
+	return dst, nil
+}
+
+// growBuffer 确保 dst 还能追加 n 个字节而不重新分配
+func growBuffer(dst []byte, n int) []byte {
+	if cap(dst)-len(dst) >= n {
+		return dst
+	}
+	grown := make([]byte, len(dst), len(dst)+n)
+	copy(grown, dst)
+	return grown
+}
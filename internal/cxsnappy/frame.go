@@ -0,0 +1,268 @@
+// Package cxsnappy 提供 Snappy 分帧格式的读写功能实现。
+//
+// Snappy 分帧格式由流标识和若干分块组成，每个数据分块最多包含 64 KiB 原始数据，
+// 并带有原始数据的掩码 CRC-32C 校验，与 python-snappy、snzip 等工具生成的
+// .sz 文件兼容。
+//
+// 主要功能：
+//   - 写入 Snappy 分帧数据，压缩后没有变小的分块以未压缩的形式写入
+//   - 读取并校验 Snappy 分帧数据，跳过填充分块和保留的可跳过分块
+//
+// 使用示例：
+//
+//	w := cxsnappy.NewWriter(file, types.CompressionLevelDefault)
+//	_, err := w.Write(data)
+//	err = w.Close()
+//
+//	err = cxsnappy.DecompressStream(dst, file)
+package cxsnappy
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+
+	"gitee.com/MM-Q/comprx/types"
+)
+
+const (
+	chunkCompressed   = 0x00 // 压缩数据分块
+	chunkUncompressed = 0x01 // 未压缩数据分块
+	chunkPadding      = 0xfe // 填充分块
+	chunkStreamID     = 0xff // 流标识分块
+
+	checksumSize = 4          // 数据分块中校验值的字节数
+	maskDelta    = 0xa282ead8 // 校验值掩码的加数
+)
+
+// streamIdentifier 流标识分块的完整内容
+var streamIdentifier = []byte{chunkStreamID, 6, 0, 0, 's', 'N', 'a', 'P', 'p', 'Y'}
+
+// ErrNotSnappy 数据不是 Snappy 分帧格式
+var ErrNotSnappy = errors.New("不是有效的 Snappy 分帧数据")
+
+// crcTable CRC-32C 查找表
+var crcTable = crc32.MakeTable(crc32.Castagnoli)
+
+// maskedCRC 计算数据的掩码 CRC-32C 校验值
+func maskedCRC(p []byte) uint32 {
+	c := crc32.Checksum(p, crcTable)
+	return (c>>15 | c<<17) + maskDelta
+}
+
+// Writer Snappy 分帧写入器
+type Writer struct {
+	w        io.Writer // 输出目标
+	compress bool      // 是否压缩数据分块
+	data     []byte    // 当前分块中尚未写出的数据
+	chunk    []byte    // 分块的输出缓冲区
+	started  bool      // 是否已写入流标识
+	closed   bool      // 是否已关闭
+}
+
+// NewWriter 创建Snappy分帧写入器
+//
+// Snappy 没有压缩等级，除 CompressionLevelNone 以未压缩的分块写入外，其他等级的效果相同。
+//
+// 参数:
+//   - w: 输出目标
+//   - level: 压缩等级
+//
+// 返回:
+//   - *Writer: Snappy 分帧写入器
+func NewWriter(w io.Writer, level types.CompressionLevel) *Writer {
+	return &Writer{
+		w:        w,
+		compress: level != types.CompressionLevelNone,
+	}
+}
+
+// Write 写入数据，满一个分块时压缩并写出
+//
+// 参数:
+//   - p: 要写入的数据
+//
+// 返回:
+//   - int: 写入的字节数
+//   - error: 错误信息
+func (w *Writer) Write(p []byte) (int, error) {
+	if w.closed {
+		return 0, fmt.Errorf("Snappy 写入器已关闭")
+	}
+	if err := w.writeStreamID(); err != nil {
+		return 0, err
+	}
+
+	written := 0
+	for len(p) > 0 {
+		n := min(len(p), maxBlockSize-len(w.data))
+		w.data = append(w.data, p[:n]...)
+		p = p[n:]
+		written += n
+
+		if len(w.data) == maxBlockSize {
+			if err := w.writeChunk(); err != nil {
+				return written, err
+			}
+		}
+	}
+	return written, nil
+}
+
+// Close 写出剩余数据
+//
+// 不关闭底层的输出目标。
+//
+// 返回:
+//   - error: 错误信息
+func (w *Writer) Close() error {
+	if w.closed {
+		return nil
+	}
+	if err := w.writeStreamID(); err != nil {
+		return err
+	}
+	if len(w.data) > 0 {
+		if err := w.writeChunk(); err != nil {
+			return err
+		}
+	}
+	w.closed = true
+	return nil
+}
+
+// writeStreamID 写入流标识
+func (w *Writer) writeStreamID() error {
+	if w.started {
+		return nil
+	}
+	w.started = true
+
+	if _, err := w.w.Write(streamIdentifier); err != nil {
+		return fmt.Errorf("写入 Snappy 流标识失败: %w", err)
+	}
+	return nil
+}
+
+// writeChunk 压缩当前分块并写出，压缩后没有变小时以未压缩的形式写出
+func (w *Writer) writeChunk() error {
+	const headerSize = 4 + checksumSize
+	if cap(w.chunk) < headerSize {
+		w.chunk = make([]byte, headerSize, headerSize+maxEncodedLen(maxBlockSize))
+	}
+	w.chunk = w.chunk[:headerSize]
+
+	chunkType := byte(chunkUncompressed)
+	if w.compress {
+		w.chunk = encodeBlock(w.chunk, w.data)
+		if len(w.chunk)-headerSize < len(w.data) {
+			chunkType = chunkCompressed
+		}
+	}
+	if chunkType == chunkUncompressed {
+		w.chunk = append(w.chunk[:headerSize], w.data...)
+	}
+
+	length := len(w.chunk) - 4
+	w.chunk[0] = chunkType
+	w.chunk[1], w.chunk[2], w.chunk[3] = byte(length), byte(length>>8), byte(length>>16)
+	binary.LittleEndian.PutUint32(w.chunk[4:], maskedCRC(w.data))
+
+	if _, err := w.w.Write(w.chunk); err != nil {
+		return fmt.Errorf("写入 Snappy 分块失败: %w", err)
+	}
+	w.data = w.data[:0]
+	return nil
+}
+
+// decompressChunks 解压 Snappy 分帧数据并写入 dst
+//
+// 流标识可以在数据中重复出现，例如多个分帧数据直接拼接的情况。
+//
+// 参数:
+//   - dst: 目标写入器
+//   - src: Snappy 分帧数据流
+//
+// 返回:
+//   - error: 错误信息
+func decompressChunks(dst io.Writer, src io.Reader) error {
+	r := bufio.NewReader(src)
+	var header [4]byte
+	var chunk, out []byte
+	for chunks := 0; ; chunks++ {
+		if n, err := io.ReadFull(r, header[:]); err != nil {
+			if err == io.EOF && n == 0 && chunks > 0 {
+				return nil
+			}
+			if chunks == 0 {
+				return ErrNotSnappy
+			}
+			return fmt.Errorf("读取 Snappy 分块头失败: %w", err)
+		}
+		chunkType := header[0]
+		length := int(header[1]) | int(header[2])<<8 | int(header[3])<<16
+		if chunks == 0 && chunkType != chunkStreamID {
+			return ErrNotSnappy
+		}
+
+		switch {
+		case chunkType == chunkStreamID:
+			if length != len(streamIdentifier)-4 {
+				return ErrNotSnappy
+			}
+		case chunkType == chunkCompressed || chunkType == chunkUncompressed:
+			if length < checksumSize || length > checksumSize+maxEncodedLen(maxBlockSize) {
+				return fmt.Errorf("Snappy 分块长度无效: %d", length)
+			}
+		case chunkType >= 0x80:
+			// 填充分块和保留的可跳过分块
+			if _, err := r.Discard(length); err != nil {
+				return fmt.Errorf("跳过 Snappy 分块失败: %w", err)
+			}
+			continue
+		default:
+			return fmt.Errorf("不支持的 Snappy 分块类型: %#x", chunkType)
+		}
+
+		if cap(chunk) < length {
+			chunk = make([]byte, length)
+		}
+		chunk = chunk[:length]
+		if _, err := io.ReadFull(r, chunk); err != nil {
+			return fmt.Errorf("读取 Snappy 分块失败: %w", err)
+		}
+
+		var data []byte
+		switch chunkType {
+		case chunkStreamID:
+			if string(chunk) != string(streamIdentifier[4:]) {
+				return ErrNotSnappy
+			}
+			continue
+		case chunkCompressed:
+			if n, _, err := decodedLen(chunk[checksumSize:]); err != nil || n > maxBlockSize {
+				return fmt.Errorf("Snappy 分块损坏: %w", errCorruptBlock)
+			}
+			var err error
+			if out, err = decodeBlock(out[:0], chunk[checksumSize:]); err != nil {
+				return fmt.Errorf("Snappy 分块损坏: %w", err)
+			}
+			data = out
+		case chunkUncompressed:
+			data = chunk[checksumSize:]
+			if len(data) > maxBlockSize {
+				return fmt.Errorf("Snappy 未压缩分块超过最大大小: %d", len(data))
+			}
+		}
+
+		if maskedCRC(data) != binary.LittleEndian.Uint32(chunk) {
+			return fmt.Errorf("Snappy 分块校验失败")
+		}
+		if _, err := dst.Write(data); err != nil {
+			return fmt.Errorf("写入解压数据失败: %w", err)
+		}
+	}
+}
@@ -0,0 +1,205 @@
+package cxsnappy
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"testing"
+
+	"gitee.com/MM-Q/comprx/types"
+)
+
+// buildChunk 构造一个数据分块
+func buildChunk(chunkType byte, data, payload []byte) []byte {
+	length := len(payload) + checksumSize
+	chunk := []byte{chunkType, byte(length), byte(length >> 8), byte(length >> 16), 0, 0, 0, 0}
+	binary.LittleEndian.PutUint32(chunk[4:], maskedCRC(data))
+	return append(chunk, payload...)
+}
+
+// TestMaskedCRC 测试 CRC-32C 和掩码计算
+func TestMaskedCRC(t *testing.T) {
+	if c := crc32.Checksum([]byte("123456789"), crcTable); c != 0xE3069283 {
+		t.Fatalf("CRC-32C 校验值错误: %#x", c)
+	}
+	// 掩码为循环右移 15 位后加上 0xa282ead8
+	if got, want := maskedCRC([]byte("123456789")), uint32(0x2507C60D+0xa282ead8); got != want {
+		t.Errorf("掩码校验值 = %#x, 期望 %#x", got, want)
+	}
+}
+
+// TestDecodeBlock 测试手工构造的数据块，覆盖各种元素编码
+func TestDecodeBlock(t *testing.T) {
+	long := bytes.Repeat([]byte{'x'}, 300)
+	tests := []struct {
+		name    string
+		encoded []byte
+		want    []byte
+	}{
+		{"短字面量", []byte{5, 4 << 2, 'h', 'e', 'l', 'l', 'o'}, []byte("hello")},
+		{"2字节长度字面量", append([]byte{0xac, 0x02, 61 << 2, 0x2b, 0x01}, long...), long},
+		{"copy1", []byte{10, 1 << 2, 'a', 'b', 4<<2 | tagCopy1, 2}, []byte("ababababab")},
+		{"copy2", []byte{7, 0, 'z', 5<<2 | tagCopy2, 1, 0}, []byte("zzzzzzz")},
+		{"copy4", []byte{6, 2 << 2, 'a', 'b', 'c', 2<<2 | tagCopy4, 3, 0, 0, 0}, []byte("abcabc")},
+		{"空数据", []byte{0}, []byte{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := decodeBlock(nil, tt.encoded)
+			if err != nil {
+				t.Fatalf("解码失败: %v", err)
+			}
+			if !bytes.Equal(got, tt.want) {
+				t.Errorf("解码结果 %q, 期望 %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestDecodeBlock_Corrupt 测试损坏的数据块
+func TestDecodeBlock_Corrupt(t *testing.T) {
+	tests := map[string][]byte{
+		"长度不足":   {10, 1 << 2, 'a', 'b'},
+		"长度超出":   {1, 1 << 2, 'a', 'b'},
+		"回溯距离为0": {4, 0, 'a', 2<<2 | tagCopy2, 0, 0},
+		"回溯越界":   {8, 0, 'a', 6<<2 | tagCopy2, 2, 0},
+		"元素截断":   {4, 0, 'a', 2<<2 | tagCopy2, 1},
+		"长度字段无效": {0xff, 0xff, 0xff, 0xff, 0xff, 0xff},
+	}
+	for name, encoded := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := decodeBlock(nil, encoded); err == nil {
+				t.Error("应返回错误")
+			}
+		})
+	}
+
+	data := bytes.Repeat([]byte("snappy block "), 500)
+	block := encodeBlock(nil, data)
+	for i := range block {
+		corrupt := bytes.Clone(block)
+		corrupt[i] ^= 0x5A
+		_, _ = decodeBlock(nil, corrupt)
+		_, _ = decodeBlock(nil, block[:i])
+	}
+}
+
+// TestEncodeBlock 测试数据块编码，包括长匹配和 64 KiB 的分块
+func TestEncodeBlock(t *testing.T) {
+	random := make([]byte, maxBlockSize)
+	if _, err := rand.Read(random); err != nil {
+		t.Fatal(err)
+	}
+	inputs := map[string][]byte{
+		"短数据":  []byte("abc"),
+		"长重复":  bytes.Repeat([]byte{'a'}, maxBlockSize),
+		"重复文本": bytes.Repeat([]byte("the quick brown fox "), 4000)[:maxBlockSize],
+		"随机数据": random,
+	}
+	for name, data := range inputs {
+		t.Run(name, func(t *testing.T) {
+			encoded := encodeBlock(nil, data)
+			if len(encoded) > maxEncodedLen(len(data)) {
+				t.Errorf("编码结果超过最大长度: %d", len(encoded))
+			}
+			got, err := decodeBlock(nil, encoded)
+			if err != nil {
+				t.Fatalf("解码失败: %v", err)
+			}
+			if !bytes.Equal(got, data) {
+				t.Error("解码结果不一致")
+			}
+		})
+	}
+}
+
+// TestRoundTrip 测试分帧格式的压缩解压
+func TestRoundTrip(t *testing.T) {
+	random := make([]byte, 200*1024)
+	if _, err := rand.Read(random); err != nil {
+		t.Fatal(err)
+	}
+	inputs := map[string][]byte{
+		"单字节":  []byte("a"),
+		"重复文本": bytes.Repeat([]byte("comprx snappy framing "), 20000),
+		"随机数据": random,
+	}
+	levels := []types.CompressionLevel{types.CompressionLevelNone, types.CompressionLevelDefault}
+
+	for name, data := range inputs {
+		for _, level := range levels {
+			t.Run(fmt.Sprintf("%s/%d", name, level), func(t *testing.T) {
+				compressed, err := CompressBytes(data, level)
+				if err != nil {
+					t.Fatalf("压缩失败: %v", err)
+				}
+				if !bytes.HasPrefix(compressed, streamIdentifier) {
+					t.Error("应以流标识开头")
+				}
+				got, err := DecompressBytes(compressed)
+				if err != nil {
+					t.Fatalf("解压失败: %v", err)
+				}
+				if !bytes.Equal(got, data) {
+					t.Fatal("解压结果不一致")
+				}
+				if level != types.CompressionLevelNone && name == "重复文本" && len(compressed) > len(data)/5 {
+					t.Errorf("重复文本压缩率过低: %d -> %d", len(data), len(compressed))
+				}
+			})
+		}
+	}
+}
+
+// TestDecompress_Chunks 测试手工构造的分块序列
+func TestDecompress_Chunks(t *testing.T) {
+	hello := []byte("hello, ")
+	world := []byte("world")
+	stream := bytes.Join([][]byte{
+		streamIdentifier,
+		buildChunk(chunkUncompressed, hello, hello),
+		{chunkPadding, 3, 0, 0, 0, 0, 0},
+		{0x80, 1, 0, 0, 0xaa},
+		streamIdentifier,
+		buildChunk(chunkCompressed, world, encodeBlock(nil, world)),
+	}, nil)
+
+	got, err := DecompressBytes(stream)
+	if err != nil {
+		t.Fatalf("解压失败: %v", err)
+	}
+	if string(got) != "hello, world" {
+		t.Errorf("解压结果不一致: %q", got)
+	}
+
+	t.Run("不可跳过的保留分块", func(t *testing.T) {
+		bad := append(bytes.Clone(streamIdentifier), 0x02, 1, 0, 0, 0)
+		if _, err := DecompressBytes(bad); err == nil {
+			t.Error("保留的不可跳过分块应返回错误")
+		}
+	})
+
+	t.Run("校验失败", func(t *testing.T) {
+		bad := bytes.Clone(stream)
+		bad[len(streamIdentifier)+8] ^= 0x01
+		if _, err := DecompressBytes(bad); err == nil {
+			t.Error("校验不一致时应返回错误")
+		}
+	})
+
+	t.Run("缺少流标识", func(t *testing.T) {
+		_, err := DecompressBytes(stream[len(streamIdentifier):])
+		if !errors.Is(err, ErrNotSnappy) {
+			t.Errorf("应返回 ErrNotSnappy, 实际 %v", err)
+		}
+	})
+
+	t.Run("截断", func(t *testing.T) {
+		if _, err := DecompressBytes(stream[:len(stream)-2]); err == nil {
+			t.Error("数据截断时应返回错误")
+		}
+	})
+}
@@ -0,0 +1,344 @@
+// Package cxtar 提供向已有 TAR 归档追加条目的功能实现。
+//
+// 该文件实现了 TAR 归档的追加操作。新条目先打包到临时归档中，再合并到已有归档：
+// 没有需要覆盖的条目时，直接回退到归档结束标记处原地写入新条目；
+// 需要覆盖已有条目时，通过临时文件原子地重写整个归档。
+//
+// 主要功能：
+//   - 向已有 TAR 归档追加文件或目录
+//   - 按覆盖策略处理同名条目
+//   - 复制 TAR 条目（供 TGZ 复用）
+//   - 扫描归档内的条目名称（供 TGZ 复用）
+//
+// 使用示例：
+//
+//	err := cxtar.Append("logs.tar", "app-10.log", cfg)
+package cxtar
+
+import (
+	"archive/tar"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gitee.com/MM-Q/comprx/internal/config"
+	"gitee.com/MM-Q/comprx/internal/utils"
+	"gitee.com/MM-Q/comprx/types"
+)
+
+// Append 向已有的 TAR 归档追加文件或目录
+//
+// 参数:
+//   - archivePath: 已有的 TAR 归档路径
+//   - src: 需要追加的源路径，以其基本名称放在归档根目录下
+//   - cfg: 压缩配置，OverwriteExisting 决定同名条目的处理方式
+//
+// 返回值:
+//   - error: 操作过程中遇到的错误
+func Append(archivePath, src string, cfg *config.Config) error {
+	// 确保路径为绝对路径
+	var absErr error
+	if archivePath, absErr = utils.EnsureAbsPath(archivePath, "TAR文件路径"); absErr != nil {
+		return absErr
+	}
+
+	// 在临时目录中将新条目打包为独立的 TAR 归档
+	scratchPath, cleanup, err := NewScratchTar(archivePath, src, cfg)
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+
+	// 扫描已有归档和新条目
+	existing, endOffset, inPlace, err := scanTarLayout(archivePath)
+	if err != nil {
+		return err
+	}
+	added, err := ScanFileEntryNames(scratchPath)
+	if err != nil {
+		return err
+	}
+
+	// 根据覆盖策略处理同名条目
+	plan, err := utils.PlanAppend(existing, added, cfg.OverwriteExisting)
+	if err != nil {
+		return err
+	}
+
+	// 没有需要覆盖的条目且校验清单不在归档内时原地追加
+	if inPlace && !plan.HasReplacements() && !cfg.Checksums.Embedded() {
+		return appendInPlace(archivePath, endOffset, scratchPath, plan)
+	}
+
+	// 否则重写整个归档，归档内的校验清单替换为合并新条目摘要后的清单
+	return utils.ReplaceFile(archivePath, func(f *os.File) error {
+		tarWriter := tar.NewWriter(f)
+		if err := copyFileEntries(tarWriter, archivePath, SkipManifests(plan.Replaces, cfg)); err != nil {
+			return err
+		}
+		if err := copyFileEntries(tarWriter, scratchPath, plan.Skips); err != nil {
+			return err
+		}
+		if err := WriteManifests(tarWriter, added, cfg); err != nil {
+			return err
+		}
+		if err := tarWriter.Close(); err != nil {
+			return fmt.Errorf("关闭 TAR 写入器失败: %w", err)
+		}
+		return nil
+	})
+}
+
+// NewScratchTar 在临时目录中将源路径打包为独立的 TAR 归档
+//
+// 参数:
+//   - archivePath: 目标压缩包路径，用于命名临时归档
+//   - src: 源路径
+//   - cfg: 压缩配置
+//
+// 返回值:
+//   - string: 临时归档路径
+//   - func(): 删除临时归档的清理函数
+//   - error: 打包失败时返回错误
+func NewScratchTar(archivePath, src string, cfg *config.Config) (string, func(), error) {
+	name := strings.TrimSuffix(filepath.Base(archivePath), filepath.Ext(archivePath)) + ".tar"
+	scratchPath, cleanup, err := utils.NewScratchPath(name)
+	if err != nil {
+		return "", nil, err
+	}
+
+	if err := TarSources(scratchPath, []types.Source{{Path: src}}, cfg.ForScratch()); err != nil {
+		cleanup()
+		return "", nil, err
+	}
+	return scratchPath, cleanup, nil
+}
+
+// SkipManifests 在跳过规则的基础上跳过归档内的校验清单（供 TGZ 复用）
+//
+// 追加时已有的校验清单由 WriteManifests 写入的合并清单替换。
+//
+// 参数:
+//   - skip: 原有的跳过规则
+//   - cfg: 压缩配置
+//
+// 返回值:
+//   - func(name string) bool: 返回 true 的条目不复制
+func SkipManifests(skip func(name string) bool, cfg *config.Config) func(name string) bool {
+	if !cfg.Checksums.Embedded() {
+		return skip
+	}
+	return func(name string) bool {
+		return skip(name) || cfg.Checksums.IsManifest(name)
+	}
+}
+
+// WriteManifests 在追加后的归档中写入合并了新条目摘要的校验清单（供 TGZ 复用）
+//
+// 参数:
+//   - tarWriter: 目标 TAR 写入器
+//   - added: 新追加的条目，用于检测与清单同名的条目
+//   - cfg: 压缩配置
+//
+// 返回值:
+//   - error: 写入失败时返回错误
+func WriteManifests(tarWriter *tar.Writer, added utils.EntryKinds, cfg *config.Config) error {
+	names := utils.NewEntryNameSet()
+	for name := range added {
+		_ = names.Add(name) // 条目名称已去重，不会返回错误
+	}
+	return cfg.Checksums.WriteManifests(newBuilder(tarWriter, cfg, names))
+}
+
+// appendInPlace 截掉归档结束标记后原地写入新条目
+//
+// 参数:
+//   - archivePath: 已有的 TAR 归档路径
+//   - endOffset: 最后一个条目数据结束的位置
+//   - scratchPath: 包含新条目的临时归档路径
+//   - plan: 冲突处理计划
+//
+// 返回值:
+//   - error: 写入失败时返回错误
+func appendInPlace(archivePath string, endOffset int64, scratchPath string, plan *utils.AppendPlan) error {
+	file, err := os.OpenFile(archivePath, os.O_RDWR, 0)
+	if err != nil {
+		return fmt.Errorf("打开 TAR 文件失败: %w", err)
+	}
+	defer func() { _ = file.Close() }()
+
+	// 回退到归档结束标记处
+	if err := file.Truncate(endOffset); err != nil {
+		return fmt.Errorf("截断 TAR 文件失败: %w", err)
+	}
+	if _, err := file.Seek(endOffset, io.SeekStart); err != nil {
+		return fmt.Errorf("定位 TAR 文件失败: %w", err)
+	}
+
+	// 写入新条目和新的归档结束标记
+	tarWriter := tar.NewWriter(file)
+	if err := copyFileEntries(tarWriter, scratchPath, plan.Skips); err != nil {
+		return err
+	}
+	if err := tarWriter.Close(); err != nil {
+		return fmt.Errorf("关闭 TAR 写入器失败: %w", err)
+	}
+	return file.Sync()
+}
+
+// scanTarLayout 扫描 TAR 归档的条目和数据结束位置
+//
+// 参数:
+//   - archivePath: TAR 归档路径
+//
+// 返回值:
+//   - utils.EntryKinds: 归档内的条目
+//   - int64: 最后一个条目数据结束的位置（归档结束标记的起始位置）
+//   - bool: 是否可以原地追加（包含稀疏文件时无法准确计算数据位置）
+//   - error: 读取失败时返回错误
+func scanTarLayout(archivePath string) (utils.EntryKinds, int64, bool, error) {
+	file, err := os.Open(archivePath)
+	if err != nil {
+		return nil, 0, false, fmt.Errorf("打开 TAR 文件失败: %w", err)
+	}
+	defer func() { _ = file.Close() }()
+
+	entries := make(utils.EntryKinds)
+	var endOffset int64
+	inPlace := true
+
+	// tar.Reader 按块读取且不预读，读完文件头后文件位置即为条目数据的起始位置
+	tarReader := tar.NewReader(file)
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, 0, false, fmt.Errorf("读取 TAR 条目失败: %w", err)
+		}
+		entries.Add(header.Name, header.Typeflag == tar.TypeDir)
+
+		dataStart, err := file.Seek(0, io.SeekCurrent)
+		if err != nil {
+			return nil, 0, false, fmt.Errorf("获取 TAR 文件位置失败: %w", err)
+		}
+		if isSparse(header) {
+			inPlace = false
+		}
+		endOffset = dataStart + utils.TarPaddedDataSize(header)
+	}
+	return entries, endOffset, inPlace, nil
+}
+
+// ScanEntryNames 扫描 TAR 流中的条目
+//
+// 参数:
+//   - r: TAR 数据流
+//
+// 返回值:
+//   - utils.EntryKinds: 条目名称及是否为目录
+//   - error: 读取失败时返回错误
+func ScanEntryNames(r io.Reader) (utils.EntryKinds, error) {
+	entries := make(utils.EntryKinds)
+	tarReader := tar.NewReader(r)
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			return entries, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("读取 TAR 条目失败: %w", err)
+		}
+		entries.Add(header.Name, header.Typeflag == tar.TypeDir)
+	}
+}
+
+// ScanFileEntryNames 扫描 TAR 文件中的条目
+//
+// 参数:
+//   - archivePath: TAR 归档路径
+//
+// 返回值:
+//   - utils.EntryKinds: 条目名称及是否为目录
+//   - error: 读取失败时返回错误
+func ScanFileEntryNames(archivePath string) (utils.EntryKinds, error) {
+	file, err := os.Open(archivePath)
+	if err != nil {
+		return nil, fmt.Errorf("打开 TAR 文件失败: %w", err)
+	}
+	defer func() { _ = file.Close() }()
+
+	return ScanEntryNames(file)
+}
+
+// CopyEntries 将 TAR 流中的条目复制到 TAR 写入器
+//
+// 参数:
+//   - tarWriter: 目标 TAR 写入器
+//   - tarReader: 源 TAR 读取器
+//   - skip: 返回 true 的条目不复制，为 nil 时复制全部条目
+//
+// 返回值:
+//   - error: 复制失败时返回错误
+func CopyEntries(tarWriter *tar.Writer, tarReader *tar.Reader, skip func(name string) bool) error {
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("读取 TAR 条目失败: %w", err)
+		}
+		if skip != nil && skip(header.Name) {
+			continue
+		}
+
+		if err := tarWriter.WriteHeader(header); err != nil {
+			return fmt.Errorf("写入 TAR 条目 '%s' 失败: %w", header.Name, err)
+		}
+		if _, err := io.Copy(tarWriter, tarReader); err != nil {
+			return fmt.Errorf("复制 TAR 条目 '%s' 失败: %w", header.Name, err)
+		}
+	}
+}
+
+// copyFileEntries 将 TAR 文件中的条目复制到 TAR 写入器
+//
+// 参数:
+//   - tarWriter: 目标 TAR 写入器
+//   - archivePath: 源 TAR 归档路径
+//   - skip: 返回 true 的条目不复制
+//
+// 返回值:
+//   - error: 复制失败时返回错误
+func copyFileEntries(tarWriter *tar.Writer, archivePath string, skip func(name string) bool) error {
+	file, err := os.Open(archivePath)
+	if err != nil {
+		return fmt.Errorf("打开 TAR 文件失败: %w", err)
+	}
+	defer func() { _ = file.Close() }()
+
+	return CopyEntries(tarWriter, tar.NewReader(file), skip)
+}
+
+// isSparse 检查条目是否为稀疏文件
+//
+// 参数:
+//   - header: TAR 文件头
+//
+// 返回值:
+//   - bool: 是稀疏文件返回 true
+func isSparse(header *tar.Header) bool {
+	if header.Typeflag == tar.TypeGNUSparse {
+		return true
+	}
+	for key := range header.PAXRecords {
+		if strings.HasPrefix(key, "GNU.sparse.") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,128 @@
+package cxtar
+
+import (
+	"archive/tar"
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gitee.com/MM-Q/comprx/internal/config"
+)
+
+// readTarEntries 读取 TAR 文件并返回条目名称列表和 文件名->内容 映射
+func readTarEntries(t *testing.T, tarFile string) ([]string, map[string]string) {
+	t.Helper()
+
+	file, err := os.Open(tarFile)
+	if err != nil {
+		t.Fatalf("打开TAR文件失败: %v", err)
+	}
+	defer func() { _ = file.Close() }()
+
+	var names []string
+	contents := make(map[string]string)
+	reader := tar.NewReader(file)
+	for {
+		header, err := reader.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("读取TAR条目失败: %v", err)
+		}
+		names = append(names, header.Name)
+		if header.Typeflag == tar.TypeReg {
+			data, _ := io.ReadAll(reader)
+			contents[header.Name] = string(data)
+		}
+	}
+	return names, contents
+}
+
+func TestAppend_InPlace(t *testing.T) {
+	tempDir := t.TempDir()
+
+	srcDir := filepath.Join(tempDir, "logs")
+	if err := os.MkdirAll(srcDir, 0755); err != nil {
+		t.Fatalf("创建目录失败: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(srcDir, "app-09.log"), []byte("09"), 0644); err != nil {
+		t.Fatalf("创建测试文件失败: %v", err)
+	}
+
+	tarFile := filepath.Join(tempDir, "logs.tar")
+	if err := Tar(tarFile, srcDir, config.New()); err != nil {
+		t.Fatalf("TAR打包失败: %v", err)
+	}
+	_, endOffset, inPlace, err := scanTarLayout(tarFile)
+	if err != nil || !inPlace {
+		t.Fatalf("扫描TAR布局失败: inPlace=%v, err=%v", inPlace, err)
+	}
+	before, _ := os.ReadFile(tarFile)
+
+	// 追加同名目录下的新文件，目录条目直接合并
+	if err := os.Remove(filepath.Join(srcDir, "app-09.log")); err != nil {
+		t.Fatalf("删除测试文件失败: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(srcDir, "app-10.log"), []byte("10"), 0644); err != nil {
+		t.Fatalf("创建测试文件失败: %v", err)
+	}
+	if err := Append(tarFile, srcDir, config.New()); err != nil {
+		t.Fatalf("追加失败: %v", err)
+	}
+
+	after, _ := os.ReadFile(tarFile)
+	if !bytes.Equal(before[:endOffset], after[:endOffset]) {
+		t.Error("原地追加改动了已有条目")
+	}
+
+	names, contents := readTarEntries(t, tarFile)
+	if strings.Join(names, ",") != "logs/,logs/app-09.log,logs/app-10.log" {
+		t.Errorf("条目列表不匹配: %v", names)
+	}
+	if contents["logs/app-09.log"] != "09" || contents["logs/app-10.log"] != "10" {
+		t.Errorf("文件内容不匹配: %v", contents)
+	}
+}
+
+func TestAppend_Overwrite(t *testing.T) {
+	tempDir := t.TempDir()
+
+	src := filepath.Join(tempDir, "app.log")
+	if err := os.WriteFile(src, []byte("old"), 0644); err != nil {
+		t.Fatalf("创建测试文件失败: %v", err)
+	}
+	tarFile := filepath.Join(tempDir, "logs.tar")
+	if err := Tar(tarFile, src, config.New()); err != nil {
+		t.Fatalf("TAR打包失败: %v", err)
+	}
+
+	if err := os.WriteFile(src, []byte("new"), 0644); err != nil {
+		t.Fatalf("更新测试文件失败: %v", err)
+	}
+
+	// 不允许覆盖时返回错误且原文件不变
+	before, _ := os.ReadFile(tarFile)
+	err := Append(tarFile, src, config.New())
+	if err == nil || !strings.Contains(err.Error(), "已存在条目") {
+		t.Fatalf("期望同名条目冲突错误, 实际: %v", err)
+	}
+	after, _ := os.ReadFile(tarFile)
+	if !bytes.Equal(before, after) {
+		t.Error("冲突时不应修改原文件")
+	}
+
+	// 允许覆盖时替换已有条目
+	cfg := config.New()
+	cfg.OverwriteExisting = true
+	if err := Append(tarFile, src, cfg); err != nil {
+		t.Fatalf("覆盖追加失败: %v", err)
+	}
+	names, contents := readTarEntries(t, tarFile)
+	if len(names) != 1 || contents["app.log"] != "new" {
+		t.Errorf("覆盖后的内容不匹配: %v, %v", names, contents)
+	}
+}
@@ -0,0 +1,230 @@
+// Package cxtar 提供 TAR 格式的增量构建功能实现。
+//
+// 该文件实现了 TAR 归档构建器，支持逐个添加文件、目录和符号链接，
+// 条目内容可来自任意 io.Reader，无需先在磁盘上准备源文件。
+// TGZ 格式的构建器同样基于该实现。
+//
+// 主要类型：
+//   - Builder: TAR 归档构建器
+//
+// 使用示例：
+//
+//	builder := cxtar.NewBuilder(w, cfg)
+//	err := builder.AddFile("docs/readme.txt", strings.NewReader("hello"), types.FileInfo{Size: 5})
+//	err = builder.Close()
+package cxtar
+
+import (
+	"archive/tar"
+	"bytes"
+	"fmt"
+	"io"
+	"io/fs"
+	"time"
+
+	"gitee.com/MM-Q/comprx/internal/config"
+	"gitee.com/MM-Q/comprx/internal/utils"
+	"gitee.com/MM-Q/comprx/types"
+)
+
+// Builder TAR 归档构建器
+//
+// 构建器会标准化条目名称并检测重复条目，使用完毕后必须调用 Close 写入归档结束标记。
+type Builder struct {
+	tarWriter *tar.Writer        // TAR 写入器
+	cfg       *config.Config     // 压缩配置
+	names     utils.EntryNameSet // 已写入的条目名称集合
+}
+
+// NewBuilder 创建写入到 w 的 TAR 归档构建器
+//
+// 参数:
+//   - w: 归档输出目标
+//   - cfg: 压缩配置
+//
+// 返回:
+//   - *Builder: TAR 归档构建器
+func NewBuilder(w io.Writer, cfg *config.Config) *Builder {
+	return newBuilder(tar.NewWriter(w), cfg, utils.NewEntryNameSet())
+}
+
+// newBuilder 基于已有的 TAR 写入器创建构建器
+//
+// 参数:
+//   - tarWriter: TAR 写入器
+//   - cfg: 压缩配置
+//   - names: 已写入的条目名称集合，与其他打包源共享以检测重复条目
+//
+// 返回:
+//   - *Builder: TAR 归档构建器
+func newBuilder(tarWriter *tar.Writer, cfg *config.Config, names utils.EntryNameSet) *Builder {
+	return &Builder{tarWriter: tarWriter, cfg: cfg, names: names}
+}
+
+// AddFile 添加普通文件
+//
+// TAR 文件头需要预先写入文件大小，info.Size 小于等于 0 时会先将内容读入内存以确定大小。
+//
+// 参数:
+//   - name: 归档内的文件名
+//   - r: 文件内容
+//   - info: 条目信息，Mode 为 0 时使用 0644，ModTime 为零值时使用当前时间
+//
+// 返回:
+//   - error: 名称不合法、条目重复或写入失败时返回错误
+func (b *Builder) AddFile(name string, r io.Reader, info types.FileInfo) error {
+	headerName, err := b.entryName(name)
+	if err != nil {
+		return err
+	}
+
+	// 未知大小时读取全部内容
+	if info.Size <= 0 {
+		data, err := io.ReadAll(r)
+		if err != nil {
+			return fmt.Errorf("处理文件 '%s' 时出错 - 读取文件内容失败: %w", headerName, err)
+		}
+		info.Size = int64(len(data))
+		r = bytes.NewReader(data)
+	}
+
+	header := newHeader(headerName, tar.TypeReg, info, 0644)
+	header.Size = info.Size
+
+	// 写入文件头
+	b.cfg.Progress.Adding(headerName)
+	if err := writeHeader(b.tarWriter, header, b.cfg); err != nil {
+		return fmt.Errorf("处理文件 '%s' 时出错 - 写入 TAR 文件头失败: %w", headerName, err)
+	}
+
+	// 获取缓冲区大小并创建缓冲区
+	buffer := utils.GetBuffer(utils.GetBufferSize(info.Size))
+	defer utils.PutBuffer(buffer)
+
+	// 复制文件内容到TAR写入器，同时计算摘要
+	sumWriter, commit := b.cfg.Checksums.Writer(headerName, b.tarWriter)
+	if _, err := b.cfg.Progress.CopyBuffer(sumWriter, r, buffer); err != nil {
+		return fmt.Errorf("处理文件 '%s' 时出错 - 写入 TAR 文件失败: %w", headerName, err)
+	}
+	commit()
+	return nil
+}
+
+// AddDir 添加目录
+//
+// 参数:
+//   - name: 归档内的目录名
+//   - info: 条目信息，Mode 为 0 时使用 0755，ModTime 为零值时使用当前时间
+//
+// 返回:
+//   - error: 名称不合法、条目重复或写入失败时返回错误
+func (b *Builder) AddDir(name string, info types.FileInfo) error {
+	headerName, err := b.entryName(name)
+	if err != nil {
+		return err
+	}
+
+	header := newHeader(headerName+"/", tar.TypeDir, info, 0755) // 目录名后添加斜杠
+
+	b.cfg.Progress.Storing(headerName)
+	if err := writeHeader(b.tarWriter, header, b.cfg); err != nil {
+		return fmt.Errorf("处理目录 '%s' 时出错 - 写入 TAR 目录头失败: %w", headerName, err)
+	}
+	return nil
+}
+
+// AddSymlink 添加符号链接
+//
+// 参数:
+//   - name: 归档内的链接名
+//   - target: 链接目标
+//   - info: 条目信息，ModTime 为零值时使用当前时间
+//
+// 返回:
+//   - error: 名称不合法、条目重复或写入失败时返回错误
+func (b *Builder) AddSymlink(name, target string, info types.FileInfo) error {
+	headerName, err := b.entryName(name)
+	if err != nil {
+		return err
+	}
+
+	header := newHeader(headerName, tar.TypeSymlink, info, 0777)
+	header.Linkname = target
+
+	b.cfg.Progress.Adding(headerName)
+	if err := writeHeader(b.tarWriter, header, b.cfg); err != nil {
+		return fmt.Errorf("处理软链接 '%s' 时出错 - 写入 TAR 软链接头失败: %w", headerName, err)
+	}
+	return nil
+}
+
+// Flush 补齐当前条目的块填充，但不写入归档结束标记
+//
+// 返回:
+//   - error: 刷新失败时返回错误
+func (b *Builder) Flush() error {
+	return b.tarWriter.Flush()
+}
+
+// Close 写入归档结束标记并关闭 TAR 写入器
+//
+// 注意: 不会关闭底层的输出目标。
+//
+// 返回:
+//   - error: 关闭失败时返回错误
+func (b *Builder) Close() error {
+	if err := b.tarWriter.Close(); err != nil {
+		return fmt.Errorf("关闭 TAR 写入器失败: %w", err)
+	}
+	return nil
+}
+
+// entryName 标准化条目名称并检测重复条目
+//
+// 参数:
+//   - name: 归档内的条目名称
+//
+// 返回:
+//   - string: 标准化后的条目名称
+//   - error: 名称不合法或条目重复时返回错误
+func (b *Builder) entryName(name string) (string, error) {
+	headerName, err := utils.NormalizeArchivePath(name)
+	if err != nil {
+		return "", err
+	}
+	if headerName == "." {
+		return "", fmt.Errorf("条目名称不能是归档根目录: %s", name)
+	}
+	if err := b.names.Add(headerName); err != nil {
+		return "", err
+	}
+	return headerName, nil
+}
+
+// newHeader 根据条目信息创建 TAR 文件头
+//
+// 参数:
+//   - name: 归档内的条目名称
+//   - typeflag: 条目类型
+//   - info: 条目信息
+//   - defaultPerm: 权限为 0 时使用的默认权限
+//
+// 返回:
+//   - *tar.Header: TAR 文件头
+func newHeader(name string, typeflag byte, info types.FileInfo, defaultPerm fs.FileMode) *tar.Header {
+	perm := info.Mode.Perm()
+	if perm == 0 {
+		perm = defaultPerm
+	}
+	modTime := info.ModTime
+	if modTime.IsZero() {
+		modTime = time.Now()
+	}
+
+	return &tar.Header{
+		Typeflag: typeflag,
+		Name:     name,
+		Mode:     int64(perm),
+		ModTime:  modTime,
+	}
+}
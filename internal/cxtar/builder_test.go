@@ -0,0 +1,94 @@
+package cxtar
+
+import (
+	"archive/tar"
+	"bytes"
+	"io"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"gitee.com/MM-Q/comprx/internal/config"
+	"gitee.com/MM-Q/comprx/types"
+)
+
+func TestBuilder_AddEntries(t *testing.T) {
+	var buf bytes.Buffer
+	builder := NewBuilder(&buf, config.New())
+
+	if err := builder.AddDir("docs", types.FileInfo{}); err != nil {
+		t.Fatalf("添加目录失败: %v", err)
+	}
+	// 未指定大小的文件会先读入内存
+	if err := builder.AddFile("docs/readme.txt", strings.NewReader("hello"), types.FileInfo{}); err != nil {
+		t.Fatalf("添加文件失败: %v", err)
+	}
+	if err := builder.AddSymlink("latest", "docs/readme.txt", types.FileInfo{}); err != nil {
+		t.Fatalf("添加软链接失败: %v", err)
+	}
+	if err := builder.AddDir("docs/", types.FileInfo{}); err == nil {
+		t.Error("期望重复条目返回错误")
+	}
+	if err := builder.Close(); err != nil {
+		t.Fatalf("关闭构建器失败: %v", err)
+	}
+
+	reader := tar.NewReader(&buf)
+	headers := make(map[string]*tar.Header)
+	for {
+		header, err := reader.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("读取TAR条目失败: %v", err)
+		}
+		headers[header.Name] = header
+		if header.Name == "docs/readme.txt" {
+			data, _ := io.ReadAll(reader)
+			if string(data) != "hello" {
+				t.Errorf("文件内容不匹配: 期望 hello, 实际 %s", string(data))
+			}
+		}
+	}
+
+	if h, ok := headers["docs/"]; !ok || h.Typeflag != tar.TypeDir || h.Mode != 0755 {
+		t.Error("目录条目 docs/ 不正确")
+	}
+	if h, ok := headers["docs/readme.txt"]; !ok || h.Size != 5 || h.Mode != 0644 {
+		t.Error("文件条目 docs/readme.txt 不正确")
+	}
+	if h, ok := headers["latest"]; !ok || h.Typeflag != tar.TypeSymlink || h.Linkname != "docs/readme.txt" {
+		t.Error("软链接条目 latest 不正确")
+	}
+}
+
+func TestTarSources_FS(t *testing.T) {
+	fsys := fstest.MapFS{
+		"a.txt":     {Data: []byte("a"), Mode: 0644},
+		"sub/b.txt": {Data: []byte("bb"), Mode: 0600},
+	}
+
+	tarPath := filepath.Join(t.TempDir(), "fs.tar")
+	sources := []types.Source{{FS: fsys, Path: ".", ArchivePath: "."}}
+	if err := TarSources(tarPath, sources, config.New()); err != nil {
+		t.Fatalf("从 fs.FS 打包失败: %v", err)
+	}
+
+	info, err := ListTar(tarPath)
+	if err != nil {
+		t.Fatalf("列出TAR内容失败: %v", err)
+	}
+
+	got := make(map[string]types.FileInfo)
+	for _, f := range info.Files {
+		got[f.Name] = f
+	}
+	if f, ok := got["sub/b.txt"]; !ok || f.Size != 2 {
+		t.Errorf("条目 sub/b.txt 不正确: %+v", f)
+	}
+	if _, ok := got["a.txt"]; !ok {
+		t.Error("缺少条目 a.txt")
+	}
+}
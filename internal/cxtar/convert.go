@@ -0,0 +1,175 @@
+// Package cxtar 提供将 TAR 归档条目流式写入其他压缩包的功能实现。
+//
+// 该文件实现了格式转换时的 TAR 读取端：顺序读取 TAR 条目，
+// 连同名称、权限、修改时间和软链接目标一起写入任意条目写入器，全程不落盘。
+//
+// 主要功能：
+//   - 将 TAR 流中的条目写入条目写入器（供 TGZ 复用）
+//   - 转换过程中应用过滤器
+//   - 重新打开源数据流取得硬链接目标的内容，以普通文件写入
+//
+// 使用示例：
+//
+//	err := cxtar.WriteFileEntriesTo("vendor.tar", builder, cfg.Filter)
+package cxtar
+
+import (
+	"archive/tar"
+	"fmt"
+	"io"
+	"os"
+	"path"
+
+	"gitee.com/MM-Q/comprx/internal/utils"
+	"gitee.com/MM-Q/comprx/types"
+)
+
+// Reopener 从头重新打开 TAR 数据流的函数
+type Reopener func() (io.ReadCloser, error)
+
+// WriteEntriesTo 将 TAR 流中的条目写入条目写入器
+//
+// 参数:
+//   - r: TAR 数据流
+//   - w: 目标条目写入器
+//   - filter: 过滤器，为 nil 时写入全部条目
+//   - reopen: 从头重新打开同一 TAR 数据流的函数，用于读取硬链接目标的内容；为 nil 时遇到硬链接返回错误
+//
+// 返回值:
+//   - error: 读取或写入失败时返回错误
+//
+// 注意:
+//   - 设备文件、命名管道等特殊文件会被跳过
+//   - 硬链接以普通文件写入，内容取自之前同名的普通文件条目（即使该条目被过滤器跳过）
+func WriteEntriesTo(r io.Reader, w utils.ArchiveEntryWriter, filter *types.FilterOptions, reopen Reopener) error {
+	tarReader := tar.NewReader(r)
+
+	// 记录每个名称最近对应的普通文件条目序号，硬链接指向其目标的序号
+	regular := make(map[string]int)
+	for index := 0; ; index++ {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("读取 TAR 文件头失败: %w", err)
+		}
+
+		key := path.Clean(header.Name)
+		target, hasTarget := regular[path.Clean(header.Linkname)]
+		switch header.Typeflag {
+		case tar.TypeReg, tar.TypeRegA:
+			regular[key] = index
+		case tar.TypeLink:
+			if hasTarget {
+				regular[key] = target
+			} else {
+				delete(regular, key)
+			}
+		default:
+			delete(regular, key)
+		}
+
+		// 应用过滤器检查
+		isDir := header.Typeflag == tar.TypeDir
+		if filter != nil && filter.ShouldSkipByParams(header.Name, header.Size, isDir) {
+			continue
+		}
+
+		info := header.FileInfo()
+		entryInfo := types.FileInfo{
+			Name:    header.Name,
+			Size:    header.Size,
+			ModTime: header.ModTime,
+			Mode:    info.Mode(),
+		}
+
+		switch header.Typeflag {
+		case tar.TypeDir: // 处理目录
+			entryInfo.IsDir = true
+			err = w.AddDir(header.Name, entryInfo)
+
+		case tar.TypeReg, tar.TypeRegA: // 处理普通文件
+			err = w.AddFile(header.Name, tarReader, entryInfo)
+
+		case tar.TypeSymlink: // 处理软链接
+			entryInfo.Mode |= os.ModeSymlink
+			entryInfo.IsSymlink = true
+			entryInfo.LinkTarget = header.Linkname
+			err = w.AddSymlink(header.Name, header.Linkname, entryInfo)
+
+		case tar.TypeLink: // 硬链接，以普通文件写入目标的内容
+			if !hasTarget {
+				return fmt.Errorf("硬链接条目 '%s' 的目标 %s 不是之前的普通文件", header.Name, header.Linkname)
+			}
+			err = writeHardlink(w, header.Name, entryInfo, target, reopen)
+
+		default:
+			// 其他类型的特殊文件跳过处理
+			continue
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// writeHardlink 重新打开 TAR 数据流，以普通文件写入硬链接目标的内容
+//
+// 参数:
+//   - w: 目标条目写入器
+//   - name: 硬链接条目名称
+//   - info: 硬链接条目信息
+//   - target: 目标普通文件条目的序号
+//   - reopen: 重新打开 TAR 数据流的函数
+//
+// 返回值:
+//   - error: 读取或写入失败时返回错误
+func writeHardlink(w utils.ArchiveEntryWriter, name string, info types.FileInfo, target int, reopen Reopener) error {
+	if reopen == nil {
+		return fmt.Errorf("不支持转换硬链接条目 '%s': 无法重新读取源数据流", name)
+	}
+	r, err := reopen()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = r.Close() }()
+
+	// 跳到目标条目
+	tarReader := tar.NewReader(r)
+	var header *tar.Header
+	for i := 0; i <= target; i++ {
+		if header, err = tarReader.Next(); err != nil {
+			return fmt.Errorf("读取硬链接 '%s' 的目标失败: %w", name, err)
+		}
+	}
+
+	info.Size = header.Size
+	return w.AddFile(name, tarReader, info)
+}
+
+// WriteFileEntriesTo 将 TAR 文件中的条目写入条目写入器
+//
+// 参数:
+//   - archivePath: TAR 归档路径
+//   - w: 目标条目写入器
+//   - filter: 过滤器，为 nil 时写入全部条目
+//
+// 返回值:
+//   - error: 读取或写入失败时返回错误
+func WriteFileEntriesTo(archivePath string, w utils.ArchiveEntryWriter, filter *types.FilterOptions) error {
+	file, err := os.Open(archivePath)
+	if err != nil {
+		return fmt.Errorf("打开 TAR 文件失败: %w", err)
+	}
+	defer func() { _ = file.Close() }()
+
+	reopen := func() (io.ReadCloser, error) {
+		f, err := os.Open(archivePath)
+		if err != nil {
+			return nil, fmt.Errorf("打开 TAR 文件失败: %w", err)
+		}
+		return f, nil
+	}
+	return WriteEntriesTo(file, w, filter, reopen)
+}
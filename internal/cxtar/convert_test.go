@@ -0,0 +1,116 @@
+package cxtar
+
+import (
+	"archive/tar"
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+
+	"gitee.com/MM-Q/comprx/types"
+)
+
+// recordingWriter 记录写入条目的条目写入器
+type recordingWriter struct {
+	entries []string
+}
+
+func (w *recordingWriter) AddFile(name string, r io.Reader, info types.FileInfo) error {
+	data, err := io.ReadAll(r)
+	w.entries = append(w.entries, "file:"+name+"="+string(data))
+	return err
+}
+
+func (w *recordingWriter) AddDir(name string, info types.FileInfo) error {
+	w.entries = append(w.entries, "dir:"+name)
+	return nil
+}
+
+func (w *recordingWriter) AddSymlink(name, target string, info types.FileInfo) error {
+	w.entries = append(w.entries, "link:"+name+"->"+target)
+	return nil
+}
+
+// buildTar 按文件头列表构建 TAR 数据
+func buildTar(t *testing.T, headers []*tar.Header, contents map[string]string) *bytes.Buffer {
+	t.Helper()
+
+	var buf bytes.Buffer
+	tarWriter := tar.NewWriter(&buf)
+	for _, header := range headers {
+		header.Size = int64(len(contents[header.Name]))
+		if err := tarWriter.WriteHeader(header); err != nil {
+			t.Fatalf("写入TAR头失败: %v", err)
+		}
+		if _, err := tarWriter.Write([]byte(contents[header.Name])); err != nil {
+			t.Fatalf("写入TAR数据失败: %v", err)
+		}
+	}
+	if err := tarWriter.Close(); err != nil {
+		t.Fatalf("关闭TAR写入器失败: %v", err)
+	}
+	return &buf
+}
+
+func TestWriteEntriesTo(t *testing.T) {
+	buf := buildTar(t, []*tar.Header{
+		{Name: "app/", Typeflag: tar.TypeDir, Mode: 0755},
+		{Name: "app/main.go", Typeflag: tar.TypeReg, Mode: 0644},
+		{Name: "app/debug.log", Typeflag: tar.TypeReg, Mode: 0644},
+		{Name: "app/latest", Typeflag: tar.TypeSymlink, Linkname: "main.go"},
+		{Name: "app/fifo", Typeflag: tar.TypeFifo, Mode: 0644},
+	}, map[string]string{"app/main.go": "package main", "app/debug.log": "debug"})
+
+	w := &recordingWriter{}
+	filter := &types.FilterOptions{Exclude: []string{"*.log"}}
+	if err := WriteEntriesTo(buf, w, filter, nil); err != nil {
+		t.Fatalf("写入条目失败: %v", err)
+	}
+
+	expected := "dir:app/,file:app/main.go=package main,link:app/latest->main.go"
+	if got := strings.Join(w.entries, ","); got != expected {
+		t.Errorf("写入的条目不匹配:\n期望 %s\n实际 %s", expected, got)
+	}
+}
+
+func TestWriteEntriesTo_Hardlink(t *testing.T) {
+	data := buildTar(t, []*tar.Header{
+		{Name: "a.txt", Typeflag: tar.TypeReg, Mode: 0644},
+		{Name: "skip.log", Typeflag: tar.TypeReg, Mode: 0644},
+		{Name: "b.txt", Typeflag: tar.TypeLink, Linkname: "a.txt"},
+		{Name: "c.txt", Typeflag: tar.TypeLink, Linkname: "b.txt"},
+		{Name: "d.txt", Typeflag: tar.TypeLink, Linkname: "skip.log"},
+	}, map[string]string{"a.txt": "a", "skip.log": "log"}).Bytes()
+	reopen := func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(data)), nil
+	}
+
+	// 目标被过滤器跳过时仍然可以取得内容
+	w := &recordingWriter{}
+	filter := &types.FilterOptions{Exclude: []string{"a.txt", "*.log"}}
+	if err := WriteEntriesTo(bytes.NewReader(data), w, filter, reopen); err != nil {
+		t.Fatalf("转换失败: %v", err)
+	}
+	want := []string{"file:b.txt=a", "file:c.txt=a", "file:d.txt=log"}
+	if strings.Join(w.entries, ",") != strings.Join(want, ",") {
+		t.Errorf("写入条目 = %v, 期望 %v", w.entries, want)
+	}
+
+	// 无法重新读取源数据流时返回错误
+	err := WriteEntriesTo(bytes.NewReader(data), &recordingWriter{}, nil, nil)
+	if err == nil || !strings.Contains(err.Error(), "硬链接") {
+		t.Errorf("期望硬链接错误, 实际: %v", err)
+	}
+}
+
+func TestWriteEntriesTo_HardlinkMissingTarget(t *testing.T) {
+	buf := buildTar(t, []*tar.Header{
+		{Name: "b.txt", Typeflag: tar.TypeLink, Linkname: "a.txt"},
+	}, nil)
+
+	reopen := func() (io.ReadCloser, error) { return nil, io.ErrUnexpectedEOF }
+	err := WriteEntriesTo(buf, &recordingWriter{}, nil, reopen)
+	if err == nil || !strings.Contains(err.Error(), "目标") {
+		t.Errorf("期望目标缺失错误, 实际: %v", err)
+	}
+}
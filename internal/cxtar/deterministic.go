@@ -0,0 +1,44 @@
+// Package cxtar 提供 TAR 格式的确定性打包支持。
+//
+// 该文件实现了 TAR 文件头的规范化：钳制修改时间、清除访问和状态变更时间、
+// 清零属主信息并规范化权限，使相同内容的多次打包产生逐字节相同的结果。
+package cxtar
+
+import (
+	"archive/tar"
+	"time"
+
+	"gitee.com/MM-Q/comprx/internal/config"
+)
+
+// writeHeader 规范化文件头后写入 TAR 条目头
+//
+// 参数:
+//   - tarWriter: TAR 写入器
+//   - header: TAR 文件头
+//   - cfg: 压缩配置
+//
+// 返回:
+//   - error: 写入失败时返回错误
+func writeHeader(tarWriter *tar.Writer, header *tar.Header, cfg *config.Config) error {
+	normalizeHeader(header, cfg)
+	return tarWriter.WriteHeader(header)
+}
+
+// normalizeHeader 在确定性模式下规范化 TAR 文件头
+//
+// 参数:
+//   - header: TAR 文件头
+//   - cfg: 压缩配置
+func normalizeHeader(header *tar.Header, cfg *config.Config) {
+	if !cfg.Deterministic {
+		return
+	}
+
+	header.ModTime = cfg.ClampModTime(header.ModTime)
+	header.AccessTime = time.Time{}
+	header.ChangeTime = time.Time{}
+	header.Uid, header.Gid = 0, 0
+	header.Uname, header.Gname = "", ""
+	header.Mode = int64(cfg.NormalizeMode(header.FileInfo().Mode()).Perm())
+}
@@ -0,0 +1,137 @@
+// Package cxtar 提供删除和重命名 TAR 归档条目的功能实现。
+//
+// 该文件实现了 TAR 归档条目的删除和重命名。保留的条目连同数据原样复制到
+// 同目录的临时文件中，最后原子地替换原文件，出错时原归档保持不变。
+//
+// 主要功能：
+//   - 按通配符模式删除条目
+//   - 重命名条目或目录
+//   - 按条目重写函数复制 TAR 条目（供 TGZ 复用）
+//
+// 使用示例：
+//
+//	err := cxtar.DeleteEntries("backup.tar", []string{"config/secret.env"})
+//	err = cxtar.RenameEntries("backup.tar", map[string]string{"old/": "new/"})
+package cxtar
+
+import (
+	"archive/tar"
+	"fmt"
+	"io"
+	"os"
+
+	"gitee.com/MM-Q/comprx/internal/utils"
+)
+
+// DeleteEntries 删除 TAR 归档中匹配模式的条目
+//
+// 参数:
+//   - archivePath: TAR 归档路径
+//   - patterns: 删除模式，匹配到目录时同时删除其下所有条目
+//
+// 返回值:
+//   - error: 操作过程中遇到的错误
+func DeleteEntries(archivePath string, patterns []string) error {
+	return rewriteEntries(archivePath, func(entries utils.EntryKinds) (utils.EntryRewrite, error) {
+		return utils.PlanDelete(entries, patterns)
+	})
+}
+
+// RenameEntries 重命名 TAR 归档中的条目
+//
+// 参数:
+//   - archivePath: TAR 归档路径
+//   - renames: 原名称到新名称的映射，重命名目录时其下所有条目一并移动
+//
+// 返回值:
+//   - error: 操作过程中遇到的错误
+func RenameEntries(archivePath string, renames map[string]string) error {
+	return rewriteEntries(archivePath, func(entries utils.EntryKinds) (utils.EntryRewrite, error) {
+		return utils.PlanRename(entries, renames)
+	})
+}
+
+// rewriteEntries 按条目重写函数原子地重写 TAR 归档
+//
+// 参数:
+//   - archivePath: TAR 归档路径
+//   - plan: 根据归档中的条目生成条目重写函数
+//
+// 返回值:
+//   - error: 操作过程中遇到的错误
+func rewriteEntries(archivePath string, plan func(utils.EntryKinds) (utils.EntryRewrite, error)) error {
+	// 确保路径为绝对路径
+	var absErr error
+	if archivePath, absErr = utils.EnsureAbsPath(archivePath, "TAR文件路径"); absErr != nil {
+		return absErr
+	}
+
+	entries, err := ScanFileEntryNames(archivePath)
+	if err != nil {
+		return err
+	}
+	rewrite, err := plan(entries)
+	if err != nil {
+		return err
+	}
+
+	return utils.ReplaceFile(archivePath, func(f *os.File) error {
+		file, err := os.Open(archivePath)
+		if err != nil {
+			return fmt.Errorf("打开 TAR 文件失败: %w", err)
+		}
+		defer func() { _ = file.Close() }()
+
+		tarWriter := tar.NewWriter(f)
+		if err := RewriteEntries(tarWriter, tar.NewReader(file), rewrite); err != nil {
+			return err
+		}
+		if err := tarWriter.Close(); err != nil {
+			return fmt.Errorf("关闭 TAR 写入器失败: %w", err)
+		}
+		return nil
+	})
+}
+
+// RewriteEntries 按条目重写函数将 TAR 流中的条目复制到 TAR 写入器
+//
+// 参数:
+//   - tarWriter: 目标 TAR 写入器
+//   - tarReader: 源 TAR 读取器
+//   - rewrite: 条目重写函数，决定条目是否保留及其新名称
+//
+// 返回值:
+//   - error: 复制失败时返回错误
+//
+// 注意:
+//   - 硬链接的目标随被链接条目一起重命名
+func RewriteEntries(tarWriter *tar.Writer, tarReader *tar.Reader, rewrite utils.EntryRewrite) error {
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("读取 TAR 条目失败: %w", err)
+		}
+
+		name := header.Name
+		newName, keep := rewrite(name)
+		if !keep {
+			continue
+		}
+		header.Name = newName
+		if header.Typeflag == tar.TypeLink {
+			if linkName, linkKept := rewrite(header.Linkname); linkKept {
+				header.Linkname = linkName
+			}
+		}
+
+		if err := tarWriter.WriteHeader(header); err != nil {
+			return fmt.Errorf("写入 TAR 条目 '%s' 失败: %w", newName, err)
+		}
+		if _, err := io.Copy(tarWriter, tarReader); err != nil {
+			return fmt.Errorf("复制 TAR 条目 '%s' 失败: %w", name, err)
+		}
+	}
+}
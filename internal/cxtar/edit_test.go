@@ -0,0 +1,106 @@
+package cxtar
+
+import (
+	"archive/tar"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// createEditTestTar 创建包含目录、文件和硬链接的测试 TAR 文件
+func createEditTestTar(t *testing.T) string {
+	t.Helper()
+
+	tarFile := filepath.Join(t.TempDir(), "backup.tar")
+	file, err := os.Create(tarFile)
+	if err != nil {
+		t.Fatalf("创建TAR文件失败: %v", err)
+	}
+	defer func() { _ = file.Close() }()
+
+	tarWriter := tar.NewWriter(file)
+	entries := []struct {
+		header  tar.Header
+		content string
+	}{
+		{tar.Header{Name: "app/", Typeflag: tar.TypeDir, Mode: 0755}, ""},
+		{tar.Header{Name: "app/main.go", Typeflag: tar.TypeReg, Mode: 0644}, "package main"},
+		{tar.Header{Name: "app/main-link.go", Typeflag: tar.TypeLink, Linkname: "app/main.go"}, ""},
+		{tar.Header{Name: "secret.env", Typeflag: tar.TypeReg, Mode: 0600}, "TOKEN=leaked"},
+	}
+	for _, entry := range entries {
+		entry.header.Size = int64(len(entry.content))
+		if err := tarWriter.WriteHeader(&entry.header); err != nil {
+			t.Fatalf("写入TAR头失败: %v", err)
+		}
+		if _, err := tarWriter.Write([]byte(entry.content)); err != nil {
+			t.Fatalf("写入TAR数据失败: %v", err)
+		}
+	}
+	if err := tarWriter.Close(); err != nil {
+		t.Fatalf("关闭TAR写入器失败: %v", err)
+	}
+	return tarFile
+}
+
+func TestDeleteEntries(t *testing.T) {
+	tarFile := createEditTestTar(t)
+
+	if err := DeleteEntries(tarFile, []string{"secret.env"}); err != nil {
+		t.Fatalf("删除条目失败: %v", err)
+	}
+	names, contents := readTarEntries(t, tarFile)
+	if strings.Join(names, ",") != "app/,app/main.go,app/main-link.go" {
+		t.Errorf("条目列表不匹配: %v", names)
+	}
+	if contents["app/main.go"] != "package main" {
+		t.Errorf("保留的条目内容不匹配: %q", contents["app/main.go"])
+	}
+
+	// 删除目录时连同其下所有条目
+	if err := DeleteEntries(tarFile, []string{"app"}); err != nil {
+		t.Fatalf("删除目录失败: %v", err)
+	}
+	if names, _ := readTarEntries(t, tarFile); len(names) != 0 {
+		t.Errorf("目录下的条目未被删除: %v", names)
+	}
+}
+
+func TestRenameEntries(t *testing.T) {
+	tarFile := createEditTestTar(t)
+
+	if err := RenameEntries(tarFile, map[string]string{"app/": "service/"}); err != nil {
+		t.Fatalf("重命名条目失败: %v", err)
+	}
+
+	file, err := os.Open(tarFile)
+	if err != nil {
+		t.Fatalf("打开TAR文件失败: %v", err)
+	}
+	defer func() { _ = file.Close() }()
+
+	headers := make(map[string]*tar.Header)
+	reader := tar.NewReader(file)
+	for {
+		header, err := reader.Next()
+		if err != nil {
+			break
+		}
+		headers[header.Name] = header
+	}
+
+	for _, name := range []string{"service/", "service/main.go", "service/main-link.go", "secret.env"} {
+		if _, ok := headers[name]; !ok {
+			t.Errorf("缺少条目 %s", name)
+		}
+	}
+	if link := headers["service/main-link.go"]; link != nil && link.Linkname != "service/main.go" {
+		t.Errorf("硬链接目标未随之重命名: %s", link.Linkname)
+	}
+
+	// 新名称与已有条目冲突
+	if err := RenameEntries(tarFile, map[string]string{"secret.env": "service/main.go"}); err == nil {
+		t.Error("新名称冲突时应返回错误")
+	}
+}
@@ -0,0 +1,105 @@
+package cxtar
+
+import (
+	"archive/tar"
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"gitee.com/MM-Q/comprx/types"
+)
+
+func TestListTar_Metadata(t *testing.T) {
+	modTime := time.Date(2024, 3, 1, 8, 0, 0, 0, time.UTC)
+	accessTime := time.Date(2024, 3, 2, 9, 30, 0, 0, time.UTC)
+	changeTime := time.Date(2024, 3, 3, 10, 45, 0, 0, time.UTC)
+
+	var buf bytes.Buffer
+	tarWriter := tar.NewWriter(&buf)
+	headers := []*tar.Header{
+		{
+			Name: "bin/app", Typeflag: tar.TypeReg, Mode: 0755, Size: 700, ModTime: modTime,
+			Uid: 1000, Gid: 100, Uname: "deploy", Gname: "users",
+			AccessTime: accessTime, ChangeTime: changeTime,
+			PAXRecords: map[string]string{"SCHILY.xattr.user.origin": "ci", "comment": "ignored"},
+			Format:     tar.FormatPAX,
+		},
+		{Name: "bin/app-link", Typeflag: tar.TypeLink, Linkname: "bin/app", ModTime: modTime},
+		{Name: "bin/current", Typeflag: tar.TypeSymlink, Linkname: "app", ModTime: modTime},
+		{Name: "run/ctl", Typeflag: tar.TypeFifo, Mode: 0600, ModTime: modTime},
+		{Name: "dev/null", Typeflag: tar.TypeChar, Mode: 0666, Devmajor: 1, Devminor: 3, ModTime: modTime},
+		{Name: "dev/sda", Typeflag: tar.TypeBlock, Mode: 0660, Devmajor: 8, ModTime: modTime},
+		{Name: "etc/", Typeflag: tar.TypeDir, Mode: 0755, ModTime: modTime},
+		{Name: "etc/hosts", Typeflag: tar.TypeReg, Mode: 0644, Size: 10, ModTime: modTime},
+	}
+	for _, header := range headers {
+		if err := tarWriter.WriteHeader(header); err != nil {
+			t.Fatalf("写入TAR头失败: %v", err)
+		}
+		if header.Size > 0 {
+			_, _ = tarWriter.Write(bytes.Repeat([]byte("x"), int(header.Size)))
+		}
+	}
+	if err := tarWriter.Close(); err != nil {
+		t.Fatalf("关闭TAR写入器失败: %v", err)
+	}
+
+	tarFile := filepath.Join(t.TempDir(), "meta.tar")
+	if err := os.WriteFile(tarFile, buf.Bytes(), 0644); err != nil {
+		t.Fatalf("写入TAR文件失败: %v", err)
+	}
+
+	info, err := ListTar(tarFile)
+	if err != nil {
+		t.Fatalf("列出TAR文件失败: %v", err)
+	}
+	if len(info.Files) != len(headers) {
+		t.Fatalf("条目数量 = %d, want %d", len(info.Files), len(headers))
+	}
+
+	app := info.Files[0]
+	if app.Uid != 1000 || app.Gid != 100 || app.Uname != "deploy" || app.Gname != "users" {
+		t.Errorf("属主信息不正确: %+v", app)
+	}
+	if !app.AccessTime.Equal(accessTime) || !app.ChangeTime.Equal(changeTime) {
+		t.Errorf("访问/变更时间不正确: %v, %v", app.AccessTime, app.ChangeTime)
+	}
+	if len(app.Xattrs) != 1 || app.Xattrs["user.origin"] != "ci" {
+		t.Errorf("扩展属性不正确: %v", app.Xattrs)
+	}
+	if app.HeaderOffset != 0 {
+		t.Errorf("第一个条目的偏移应为 0: %d", app.HeaderOffset)
+	}
+
+	wantTypes := []types.EntryType{
+		types.EntryTypeFile, types.EntryTypeHardlink, types.EntryTypeSymlink, types.EntryTypeFifo,
+		types.EntryTypeCharDevice, types.EntryTypeBlockDevice, types.EntryTypeDir, types.EntryTypeFile,
+	}
+	for i, want := range wantTypes {
+		if info.Files[i].EntryType != want {
+			t.Errorf("%s 的条目类型 = %s, want %s", info.Files[i].Name, info.Files[i].EntryType, want)
+		}
+	}
+
+	link := info.Files[1]
+	if link.HardlinkTarget != "bin/app" || link.IsSymlink || link.LinkTarget != "bin/app" {
+		t.Errorf("硬链接信息不正确: %+v", link)
+	}
+	if symlink := info.Files[2]; !symlink.IsSymlink || symlink.LinkTarget != "app" {
+		t.Errorf("符号链接信息不正确: %+v", symlink)
+	}
+
+	// 每个条目的偏移处都应能读到该条目的文件头
+	data := buf.Bytes()
+	for i, file := range info.Files {
+		if i > 0 && file.HeaderOffset <= info.Files[i-1].HeaderOffset {
+			t.Fatalf("条目偏移应递增: %s = %d", file.Name, file.HeaderOffset)
+		}
+		header, err := tar.NewReader(bytes.NewReader(data[file.HeaderOffset:])).Next()
+		if err != nil || header.Name != file.Name {
+			t.Errorf("偏移 %d 处应为 %s 的文件头: %v, %v", file.HeaderOffset, file.Name, header, err)
+		}
+	}
+}
@@ -10,6 +10,8 @@
 //   - 文件过滤功能
 //   - 文件覆盖控制
 //   - 相对路径处理
+//   - 多源打包（任意路径映射与重复条目检测）
+//   - 确定性打包（可重现的归档）
 //
 // 文件类型支持：
 //   - 普通文件：完整内容复制
@@ -42,8 +44,10 @@ import (
 	"path/filepath"
 
 	"gitee.com/MM-Q/comprx/internal/config"
+	"gitee.com/MM-Q/comprx/internal/envelope"
 	"gitee.com/MM-Q/comprx/internal/progress"
 	"gitee.com/MM-Q/comprx/internal/utils"
+	"gitee.com/MM-Q/comprx/types"
 )
 
 // Tar 函数用于创建TAR归档文件
@@ -56,13 +60,29 @@ import (
 // 返回值:
 //   - error: 操作过程中遇到的错误
 func Tar(dst string, src string, cfg *config.Config) error {
+	return TarSources(dst, []types.Source{{Path: src}}, cfg)
+}
+
+// TarSources 函数用于将多个源归档到同一个TAR文件
+//
+// 参数:
+//   - dst: 生成的TAR文件路径
+//   - sources: 打包源列表，每个源可以映射到归档内的任意位置
+//   - cfg: 压缩配置指针
+//
+// 返回值:
+//   - error: 操作过程中遇到的错误（包括归档内条目重复）
+func TarSources(dst string, sources []types.Source, cfg *config.Config) error {
 	// 确保路径为绝对路径
 	var absErr error
 	if dst, absErr = utils.EnsureAbsPath(dst, "TAR文件路径"); absErr != nil {
 		return absErr
 	}
-	if src, absErr = utils.EnsureAbsPath(src, "源路径"); absErr != nil {
-		return absErr
+
+	// 解析并检查打包源列表
+	sources, err := utils.PrepareSources(sources)
+	if err != nil {
+		return err
 	}
 
 	// 检查目标文件是否已存在
@@ -79,7 +99,7 @@ func Tar(dst string, src string, cfg *config.Config) error {
 	}
 
 	// 在进度条模式下计算源文件总大小
-	totalSize := progress.CalculateSourceTotalSizeWithProgress(src, cfg.Progress, "正在分析内容...", cfg.Filter)
+	totalSize := progress.CalculateSourcesTotalSizeWithProgress(sources, cfg.Progress, "正在分析内容...", cfg.Filter)
 
 	// 开始进度显示
 	if err := cfg.Progress.Start(totalSize, dst, fmt.Sprintf("正在压缩 %s...", filepath.Base(dst))); err != nil {
@@ -90,7 +110,7 @@ func Tar(dst string, src string, cfg *config.Config) error {
 	}()
 
 	// 创建 TAR 文件
-	tarFile, err := os.Create(dst)
+	tarFile, err := envelope.Create(dst, cfg.EncryptWith)
 	if err != nil {
 		return fmt.Errorf("创建 TAR 文件失败: %w", err)
 	}
@@ -100,33 +120,90 @@ func Tar(dst string, src string, cfg *config.Config) error {
 	tarWriter := tar.NewWriter(tarFile)
 	defer func() { _ = tarWriter.Close() }()
 
-	// 检查源路径是文件还是目录
-	srcInfo, err := os.Stat(src)
+	// 写入所有打包源
+	if err := WriteSources(tarWriter, sources, cfg); err != nil {
+		return fmt.Errorf("打包目录到 TAR 失败: %w", err)
+	}
+
+	// 显式关闭以返回写入归档结束标记和加密末块时的错误
+	if err := tarWriter.Close(); err != nil {
+		return fmt.Errorf("关闭 TAR 写入器失败: %w", err)
+	}
+	if err := tarFile.Close(); err != nil {
+		return fmt.Errorf("关闭 TAR 文件失败: %w", err)
+	}
+	return nil
+}
+
+// WriteSources 将已解析的打包源依次写入TAR写入器
+//
+// 该函数只负责写入条目，不关闭写入器，供 TGZ 等基于 TAR 的格式复用。
+//
+// 参数:
+//   - tarWriter: TAR写入器
+//   - sources: 已解析的打包源列表
+//   - cfg: 压缩配置
+//
+// 返回值:
+//   - error: 操作过程中遇到的错误（包括归档内条目重复）
+func WriteSources(tarWriter *tar.Writer, sources []types.Source, cfg *config.Config) error {
+	// 确定性模式下按归档内路径排序打包源
+	if cfg.Deterministic {
+		utils.SortSources(sources)
+	}
+
+	names := utils.NewEntryNameSet()
+	for _, source := range sources {
+		if err := addSource(tarWriter, source, cfg, names); err != nil {
+			return err
+		}
+	}
+
+	// 写入校验清单
+	return cfg.Checksums.WriteManifests(newBuilder(tarWriter, cfg, names))
+}
+
+// addSource 将单个打包源写入TAR包
+//
+// 参数:
+//   - tarWriter: TAR写入器
+//   - source: 已解析的打包源
+//   - cfg: 压缩配置
+//   - names: 已写入的条目名称集合
+//
+// 返回值:
+//   - error: 操作过程中遇到的错误
+func addSource(tarWriter *tar.Writer, source types.Source, cfg *config.Config, names utils.EntryNameSet) error {
+	// fs.FS 源：通过构建器写入条目
+	if source.FS != nil {
+		return utils.WriteFSTree(source.FS, source.Path, source.ArchivePath, cfg.Filter, newBuilder(tarWriter, cfg, names))
+	}
+
+	srcInfo, err := os.Stat(source.Path)
 	if err != nil {
 		return fmt.Errorf("获取源路径信息失败: %w", err)
 	}
 
-	// 根据源路径类型处理
-	var tarErr error
+	// 目录源：遍历目录并添加文件到 TAR 包
 	if srcInfo.IsDir() {
-		// 遍历目录并添加文件到 TAR 包
-		tarErr = walkDirectoryForTar(src, tarWriter, cfg)
-	} else {
-		// 单文件处理逻辑 - 检查是否应该跳过
-		if cfg.Filter != nil && cfg.Filter.ShouldSkipByParams(src, srcInfo.Size(), srcInfo.IsDir()) {
-			// 文件被过滤器跳过，直接返回成功
-			return nil
-		}
-		cfg.Progress.Adding(src)
-		tarErr = processRegularFile(tarWriter, src, filepath.Base(src), srcInfo, cfg)
+		return walkDirectoryForTar(source.Path, source.ArchivePath, tarWriter, cfg, names)
 	}
 
-	// 检查是否有错误发生
-	if tarErr != nil {
-		return fmt.Errorf("打包目录到 TAR 失败: %w", tarErr)
+	// 文件源必须映射到具体的文件名
+	if source.ArchivePath == "." {
+		return fmt.Errorf("文件源 '%s' 必须指定归档内的文件名", source.Path)
 	}
 
-	return nil
+	// 单文件处理逻辑 - 检查是否应该跳过
+	if cfg.Filter != nil && cfg.Filter.ShouldSkipByParams(source.Path, srcInfo.Size(), srcInfo.IsDir()) {
+		// 文件被过滤器跳过
+		return nil
+	}
+	if err := names.Add(source.ArchivePath); err != nil {
+		return err
+	}
+	cfg.Progress.Adding(source.Path)
+	return processRegularFile(tarWriter, source.Path, source.ArchivePath, srcInfo, cfg)
 }
 
 // processDirectory 处理目录
@@ -135,10 +212,11 @@ func Tar(dst string, src string, cfg *config.Config) error {
 //   - tarWriter: *tar.Writer - TAR 文件写入器
 //   - headerName: string - TAR 文件中的目录名
 //   - info: os.FileInfo - 目录信息
+//   - cfg: *config.Config - 配置
 //
 // 返回值:
 //   - error - 操作过程中遇到的错误
-func processDirectory(tarWriter *tar.Writer, headerName string, info os.FileInfo) error {
+func processDirectory(tarWriter *tar.Writer, headerName string, info os.FileInfo, cfg *config.Config) error {
 	// 创建目录文件头
 	header, err := tar.FileInfoHeader(info, "")
 	if err != nil {
@@ -148,7 +226,7 @@ func processDirectory(tarWriter *tar.Writer, headerName string, info os.FileInfo
 	header.Name = headerName + "/" // 目录名后添加斜杠
 
 	// 写入目录文件头
-	if err := tarWriter.WriteHeader(header); err != nil {
+	if err := writeHeader(tarWriter, header, cfg); err != nil {
 		return fmt.Errorf("处理目录 '%s' 时出错 - 写入 TAR 目录头失败: %w", headerName, err)
 	}
 	return nil
@@ -161,10 +239,11 @@ func processDirectory(tarWriter *tar.Writer, headerName string, info os.FileInfo
 //   - path: string - 软链接路径
 //   - headerName: string - TAR 文件中的软链接名
 //   - info: os.FileInfo - 文件信息
+//   - cfg: *config.Config - 配置
 //
 // 返回值:
 //   - error - 操作过程中遇到的错误
-func processSymlink(tarWriter *tar.Writer, path, headerName string, info os.FileInfo) error {
+func processSymlink(tarWriter *tar.Writer, path, headerName string, info os.FileInfo, cfg *config.Config) error {
 	// 读取软链接目标
 	target, err := os.Readlink(path)
 	if err != nil {
@@ -179,7 +258,7 @@ func processSymlink(tarWriter *tar.Writer, path, headerName string, info os.File
 	header.Name = headerName
 
 	// 写入软链接文件头
-	if err := tarWriter.WriteHeader(header); err != nil {
+	if err := writeHeader(tarWriter, header, cfg); err != nil {
 		return fmt.Errorf("处理软链接 '%s' 时出错 - 写入 TAR 软链接头失败: %w", path, err)
 	}
 	return nil
@@ -191,10 +270,11 @@ func processSymlink(tarWriter *tar.Writer, path, headerName string, info os.File
 //   - tarWriter: *tar.Writer - TAR 文件写入器
 //   - headerName: string - TAR 文件中的特殊文件名
 //   - info: os.FileInfo - 文件信息
+//   - cfg: *config.Config - 配置
 //
 // 返回值:
 //   - error - 操作过程中遇到的错误
-func processSpecialFile(tarWriter *tar.Writer, headerName string, info os.FileInfo) error {
+func processSpecialFile(tarWriter *tar.Writer, headerName string, info os.FileInfo, cfg *config.Config) error {
 	// 创建 TAR 文件头
 	header, err := tar.FileInfoHeader(info, "")
 	if err != nil {
@@ -203,7 +283,7 @@ func processSpecialFile(tarWriter *tar.Writer, headerName string, info os.FileIn
 	header.Name = headerName
 
 	// 写入 TAR 文件头
-	if err := tarWriter.WriteHeader(header); err != nil {
+	if err := writeHeader(tarWriter, header, cfg); err != nil {
 		return fmt.Errorf("处理特殊文件 '%s' 时出错 - 写入 TAR 特殊文件头失败: %w", headerName, err)
 	}
 	return nil
@@ -213,12 +293,14 @@ func processSpecialFile(tarWriter *tar.Writer, headerName string, info os.FileIn
 //
 // 参数:
 //   - src: string - 源目录路径
+//   - archiveBase: string - 源目录在归档中的路径，"." 表示归档根目录
 //   - tarWriter: *tar.Writer - TAR 文件写入器
 //   - cfg: *config.Config - 配置
+//   - names: utils.EntryNameSet - 已写入的条目名称集合，用于检测重复条目
 //
 // 返回值:
 //   - error - 操作过程中遇到的错误
-func walkDirectoryForTar(src string, tarWriter *tar.Writer, cfg *config.Config) error {
+func walkDirectoryForTar(src, archiveBase string, tarWriter *tar.Writer, cfg *config.Config, names utils.EntryNameSet) error {
 	return filepath.WalkDir(src, func(path string, entry os.DirEntry, err error) error {
 		if err != nil {
 			// 如果不存在则忽略
@@ -243,14 +325,22 @@ func walkDirectoryForTar(src string, tarWriter *tar.Writer, cfg *config.Config)
 			return nil // 跳过文件
 		}
 
-		// 获取相对路径，保留顶层目录
-		headerName, err := filepath.Rel(filepath.Dir(src), path)
+		// 获取相对于源目录的路径
+		relPath, err := filepath.Rel(src, path)
 		if err != nil {
 			return fmt.Errorf("处理路径 '%s' 时出错 - 获取相对路径失败: %w", path, err)
 		}
 
-		// 替换路径分隔符为正斜杠(TAR 文件格式要求)
-		headerName = filepath.ToSlash(headerName)
+		// 映射到归档内的路径(TAR 文件格式要求使用正斜杠)
+		headerName := utils.JoinArchivePath(archiveBase, relPath)
+		if headerName == "." {
+			return nil // 源目录映射到归档根目录时，不写入根目录本身
+		}
+
+		// 检测重复条目
+		if err := names.Add(headerName); err != nil {
+			return err
+		}
 
 		// 根据文件类型处理
 		switch {
@@ -262,17 +352,17 @@ func walkDirectoryForTar(src string, tarWriter *tar.Writer, cfg *config.Config)
 		// 处理目录
 		case entry.IsDir():
 			cfg.Progress.Storing(headerName) // 更新进度
-			return processDirectory(tarWriter, headerName, info)
+			return processDirectory(tarWriter, headerName, info, cfg)
 
 		// 处理符号链接
 		case entry.Type()&os.ModeSymlink != 0:
 			cfg.Progress.Adding(headerName) // 更新进度
-			return processSymlink(tarWriter, path, headerName, info)
+			return processSymlink(tarWriter, path, headerName, info, cfg)
 
 		// 处理特殊文件
 		default:
 			cfg.Progress.Adding(headerName) // 更新进度
-			return processSpecialFile(tarWriter, headerName, info)
+			return processSpecialFile(tarWriter, headerName, info, cfg)
 		}
 	})
 }
@@ -297,7 +387,7 @@ func processRegularFile(tarWriter *tar.Writer, path, headerName string, info os.
 	header.Name = headerName // 设置文件名
 
 	// 写入文件头
-	if err := tarWriter.WriteHeader(header); err != nil {
+	if err := writeHeader(tarWriter, header, cfg); err != nil {
 		return fmt.Errorf("处理文件 '%s' 时出错 - 写入 TAR 文件头失败: %w", path, err)
 	}
 
@@ -316,10 +406,12 @@ func processRegularFile(tarWriter *tar.Writer, path, headerName string, info os.
 	buffer := utils.GetBuffer(bufferSize)
 	defer utils.PutBuffer(buffer)
 
-	// 复制文件内容到TAR写入器
-	if _, err := cfg.Progress.CopyBuffer(tarWriter, file, buffer); err != nil {
+	// 复制文件内容到TAR写入器，同时计算摘要
+	sumWriter, commit := cfg.Checksums.Writer(headerName, tarWriter)
+	if _, err := cfg.Progress.CopyBuffer(sumWriter, file, buffer); err != nil {
 		return fmt.Errorf("处理文件 '%s' 时出错 - 写入 TAR 文件失败: %w", path, err)
 	}
+	commit()
 
 	return nil
 }
@@ -2,13 +2,16 @@ package cxtar
 
 import (
 	"archive/tar"
+	"bytes"
 	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"gitee.com/MM-Q/comprx/internal/config"
+	"gitee.com/MM-Q/comprx/types"
 )
 
 func TestTar_SingleFile(t *testing.T) {
@@ -311,3 +314,130 @@ func BenchmarkTar_Directory(b *testing.B) {
 		_ = Tar(tarFile, testDir, cfg)
 	}
 }
+
+func TestTarSources_RootMapping(t *testing.T) {
+	tempDir := t.TempDir()
+
+	// 创建两个源目录
+	for _, name := range []string{"site/index.html", "assets/app.css"} {
+		filePath := filepath.Join(tempDir, name)
+		if err := os.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
+			t.Fatalf("创建目录失败: %v", err)
+		}
+		if err := os.WriteFile(filePath, []byte(name), 0644); err != nil {
+			t.Fatalf("创建文件失败: %v", err)
+		}
+	}
+
+	tarFile := filepath.Join(tempDir, "site.tar")
+	sources := []types.Source{
+		{Path: filepath.Join(tempDir, "site"), ArchivePath: "."},
+		{Path: filepath.Join(tempDir, "assets"), ArchivePath: "static"},
+	}
+	if err := TarSources(tarFile, sources, config.New()); err != nil {
+		t.Fatalf("TarSources 失败: %v", err)
+	}
+
+	file, err := os.Open(tarFile)
+	if err != nil {
+		t.Fatalf("打开TAR文件失败: %v", err)
+	}
+	defer func() { _ = file.Close() }()
+
+	var names []string
+	tarReader := tar.NewReader(file)
+	for {
+		header, err := tarReader.Next()
+		if err != nil {
+			break
+		}
+		names = append(names, header.Name)
+	}
+
+	expected := []string{"index.html", "static/", "static/app.css"}
+	if strings.Join(names, ",") != strings.Join(expected, ",") {
+		t.Errorf("期望条目 %v, 实际 %v", expected, names)
+	}
+}
+
+func TestTarSources_DuplicateEntry(t *testing.T) {
+	tempDir := t.TempDir()
+
+	srcDir := filepath.Join(tempDir, "conf")
+	if err := os.MkdirAll(srcDir, 0755); err != nil {
+		t.Fatalf("创建目录失败: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(srcDir, "app.conf"), []byte("a"), 0644); err != nil {
+		t.Fatalf("创建文件失败: %v", err)
+	}
+	single := filepath.Join(tempDir, "override.conf")
+	if err := os.WriteFile(single, []byte("b"), 0644); err != nil {
+		t.Fatalf("创建文件失败: %v", err)
+	}
+
+	tarFile := filepath.Join(tempDir, "dup.tar")
+	sources := []types.Source{
+		{Path: srcDir, ArchivePath: "etc"},
+		{Path: single, ArchivePath: "etc/app.conf"},
+	}
+	if err := TarSources(tarFile, sources, config.New()); err == nil {
+		t.Error("重复条目应返回错误")
+	}
+}
+
+func TestTarSources_Deterministic(t *testing.T) {
+	tempDir := t.TempDir()
+	srcA := filepath.Join(tempDir, "a.txt")
+	srcB := filepath.Join(tempDir, "b.txt")
+	for _, p := range []string{srcA, srcB} {
+		if err := os.WriteFile(p, []byte(filepath.Base(p)), 0600); err != nil {
+			t.Fatalf("创建测试文件失败: %v", err)
+		}
+	}
+
+	cfg := config.New()
+	cfg.Deterministic = true
+	cfg.SourceDateEpoch = time.Unix(1700000000, 0).UTC()
+
+	// 两次打包使用不同的源顺序和时间戳
+	first := filepath.Join(tempDir, "first.tar")
+	if err := TarSources(first, []types.Source{{Path: srcA}, {Path: srcB}}, cfg); err != nil {
+		t.Fatalf("第一次打包失败: %v", err)
+	}
+	future := time.Now().Add(time.Hour)
+	if err := os.Chtimes(srcA, future, future); err != nil {
+		t.Fatalf("修改时间戳失败: %v", err)
+	}
+	second := filepath.Join(tempDir, "second.tar")
+	if err := TarSources(second, []types.Source{{Path: srcB}, {Path: srcA}}, cfg); err != nil {
+		t.Fatalf("第二次打包失败: %v", err)
+	}
+
+	firstData, _ := os.ReadFile(first)
+	secondData, _ := os.ReadFile(second)
+	if !bytes.Equal(firstData, secondData) {
+		t.Fatal("确定性模式下两次打包结果应逐字节相同")
+	}
+
+	reader := tar.NewReader(bytes.NewReader(firstData))
+	var names []string
+	for {
+		header, err := reader.Next()
+		if err != nil {
+			break
+		}
+		names = append(names, header.Name)
+		if header.Uid != 0 || header.Gid != 0 || header.Uname != "" || header.Gname != "" {
+			t.Errorf("条目 %s 的属主信息应被清零", header.Name)
+		}
+		if header.Mode != 0644 {
+			t.Errorf("条目 %s 权限应为 0644, 实际 %o", header.Name, header.Mode)
+		}
+		if !header.ModTime.Equal(cfg.SourceDateEpoch) {
+			t.Errorf("条目 %s 修改时间应为 %v, 实际 %v", header.Name, cfg.SourceDateEpoch, header.ModTime)
+		}
+	}
+	if strings.Join(names, ",") != "a.txt,b.txt" {
+		t.Errorf("条目应按字典序排列, 实际 %v", names)
+	}
+}
@@ -47,6 +47,7 @@ import (
 	"path/filepath"
 
 	"gitee.com/MM-Q/comprx/internal/config"
+	"gitee.com/MM-Q/comprx/internal/envelope"
 	"gitee.com/MM-Q/comprx/internal/utils"
 	"gitee.com/MM-Q/comprx/types"
 )
@@ -61,11 +62,14 @@ import (
 // 返回值:
 //   - error: 解压缩过程中发生的错误
 func Untar(tarFilePath string, targetDir string, cfg *config.Config) error {
+	// 扫描总大小和解压共用打开器，加密信封的密钥只派生一次
+	opener := envelope.NewOpener(cfg.EncryptWith.Passphrase)
+
 	// 在进度条模式下计算总大小
-	totalSize := calculateTarTotalSize(tarFilePath, cfg)
+	totalSize := calculateTarTotalSize(tarFilePath, cfg, opener)
 
 	// 打开 TAR 文件
-	tarFile, err := os.Open(tarFilePath)
+	tarFile, err := opener.Open(tarFilePath)
 	if err != nil {
 		return fmt.Errorf("打开 TAR 文件失败: %w", err)
 	}
@@ -102,6 +106,7 @@ func Untar(tarFilePath string, targetDir string, cfg *config.Config) error {
 			// 使用通用的过滤方法，传入文件路径、大小和是否为目录
 			isDir := header.Typeflag == tar.TypeDir
 			if cfg.Filter.ShouldSkipByParams(header.Name, header.Size, isDir) {
+				cfg.Checksums.Skip(header.Name)
 				continue // 跳过此文件
 			}
 		}
@@ -152,10 +157,11 @@ func Untar(tarFilePath string, targetDir string, cfg *config.Config) error {
 // 参数:
 //   - tarFilePath: TAR文件路径
 //   - cfg: 解压配置
+//   - opener: 加密信封打开器，与解压共用以复用派生出的密钥
 //
 // 返回值:
 //   - int64: 普通文件的总大小（字节）
-func calculateTarTotalSize(tarFilePath string, cfg *config.Config) int64 {
+func calculateTarTotalSize(tarFilePath string, cfg *config.Config, opener *envelope.Opener) int64 {
 	var totalSize int64
 
 	// 只在进度条模式下计算总大小
@@ -170,7 +176,7 @@ func calculateTarTotalSize(tarFilePath string, cfg *config.Config) int64 {
 	}()
 
 	// 打开TAR文件进行扫描
-	tarFile, err := os.Open(tarFilePath)
+	tarFile, err := opener.Open(tarFilePath)
 	if err != nil {
 		return 0
 	}
@@ -258,6 +264,10 @@ func extractRegularFile(tarReader *tar.Reader, targetPath string, header *tar.He
 			return fmt.Errorf("处理文件 '%s' 时出错 - 创建空文件失败: %w", header.Name, err)
 		}
 		defer func() { _ = emptyFile.Close() }()
+
+		// 空文件同样需要记录摘要
+		_, commit := cfg.Checksums.Writer(header.Name, emptyFile)
+		commit()
 		return nil
 	}
 
@@ -275,10 +285,12 @@ func extractRegularFile(tarReader *tar.Reader, targetPath string, header *tar.He
 	buffer := utils.GetBuffer(bufferSize)
 	defer utils.PutBuffer(buffer)
 
-	// 将文件内容写入目标文件
-	if _, err := cfg.Progress.CopyBuffer(fileWriter, tarReader, buffer); err != nil {
+	// 将文件内容写入目标文件，同时计算摘要
+	sumWriter, commit := cfg.Checksums.Writer(header.Name, fileWriter)
+	if _, err := cfg.Progress.CopyBuffer(sumWriter, tarReader, buffer); err != nil {
 		return fmt.Errorf("处理文件 '%s' 时出错 - 写入文件失败: %w", header.Name, err)
 	}
+	commit()
 
 	return nil
 }
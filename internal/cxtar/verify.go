@@ -0,0 +1,83 @@
+// Package cxtar 提供 TAR 归档的完整性校验功能实现。
+//
+// 该文件实现了 TAR 归档的完整性校验：顺序读取每个条目，由 archive/tar
+// 校验文件头校验和，并读完条目数据以发现截断，不会写入任何文件。
+//
+// 主要功能：
+//   - 校验文件头校验和
+//   - 发现截断的条目数据
+//   - 校验 TAR 流（供 TGZ 复用）
+//
+// 使用示例：
+//
+//	report, err := cxtar.Verify("backup.tar", nil, "")
+package cxtar
+
+import (
+	"archive/tar"
+	"fmt"
+	"io"
+
+	"gitee.com/MM-Q/comprx/internal/envelope"
+	"gitee.com/MM-Q/comprx/types"
+)
+
+// Verify 校验 TAR 归档的完整性
+//
+// 参数:
+//   - archivePath: TAR 归档路径
+//   - filter: 过滤器，为 nil 时校验全部条目
+//   - passphrase: 加密信封的口令，文件不是加密信封时忽略
+//
+// 返回值:
+//   - *types.VerifyReport: 校验报告，损坏的条目记录在 Failures 中
+//   - error: 无法打开文件、加密信封未提供口令或口令错误时返回错误
+func Verify(archivePath string, filter *types.FilterOptions, passphrase string) (*types.VerifyReport, error) {
+	file, err := envelope.Open(archivePath, passphrase)
+	if err != nil {
+		return nil, fmt.Errorf("打开 TAR 文件失败: %w", err)
+	}
+	defer func() { _ = file.Close() }()
+
+	report := &types.VerifyReport{Type: types.CompressTypeTar}
+	VerifyEntries(file, report, filter)
+	return report, nil
+}
+
+// VerifyEntries 校验 TAR 流中的所有条目并记录到报告中
+//
+// 参数:
+//   - r: TAR 数据流
+//   - report: 校验报告
+//   - filter: 过滤器，为 nil 时校验全部条目
+//
+// 注意:
+//   - 文件头损坏或数据截断后无法继续定位后续条目，校验在第一个此类错误处停止
+func VerifyEntries(r io.Reader, report *types.VerifyReport, filter *types.FilterOptions) {
+	tarReader := tar.NewReader(r)
+	for index := 1; ; index++ {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			return
+		}
+		if err != nil {
+			report.AddFailure("", fmt.Errorf("读取第 %d 个条目的文件头失败: %w", index, err))
+			return
+		}
+
+		// 应用过滤器检查
+		isDir := header.Typeflag == tar.TypeDir
+		if filter != nil && filter.ShouldSkipByParams(header.Name, header.Size, isDir) {
+			continue
+		}
+
+		// 读完条目数据以发现截断
+		report.TotalEntries++
+		size, err := io.Copy(io.Discard, tarReader)
+		report.TotalSize += size
+		if err != nil {
+			report.AddFailure(header.Name, fmt.Errorf("读取条目数据失败: %w", err))
+			return
+		}
+	}
+}
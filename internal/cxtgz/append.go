@@ -0,0 +1,412 @@
+// Package cxtgz 提供向已有 TGZ 压缩包追加条目的功能实现。
+//
+// 该文件实现了 TGZ 压缩包的追加操作。经过追加的 TGZ 文件在 TAR 条目之后写入
+// 条目索引成员和单独的归档结束标记成员(见 append_index.go)。再次追加时只读取
+// 文件末尾的这两部分得到已有条目，截掉它们后写入包含新条目的 GZIP 成员，
+// 再重新写入索引和结束标记，无需解压和重新压缩已有内容。
+// 首次追加(包括 Tgz 生成的单成员文件和其他工具生成的文件)或需要覆盖已有条目时，
+// 通过临时文件原子地重写整个压缩包，重写后的文件支持之后的原地追加。
+//
+// 主要功能：
+//   - 向已有 TGZ 压缩包追加文件或目录
+//   - 按覆盖策略处理同名条目
+//   - 定位 GZIP 成员边界
+//
+// 使用示例：
+//
+//	err := cxtgz.Append("logs.tgz", "app-10.log", cfg)
+package cxtgz
+
+import (
+	"archive/tar"
+	"bufio"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+
+	"gitee.com/MM-Q/comprx/internal/config"
+	"gitee.com/MM-Q/comprx/internal/cxtar"
+	"gitee.com/MM-Q/comprx/internal/utils"
+)
+
+// Append 向已有的 TGZ 压缩包追加文件或目录
+//
+// 参数:
+//   - archivePath: 已有的 TGZ 压缩包路径
+//   - src: 需要追加的源路径，以其基本名称放在压缩包根目录下
+//   - cfg: 压缩配置，OverwriteExisting 决定同名条目的处理方式
+//
+// 返回值:
+//   - error: 操作过程中遇到的错误
+func Append(archivePath, src string, cfg *config.Config) error {
+	// 确保路径为绝对路径
+	var absErr error
+	if archivePath, absErr = utils.EnsureAbsPath(archivePath, "TGZ文件路径"); absErr != nil {
+		return absErr
+	}
+
+	// 在临时目录中将新条目打包为独立的 TAR 归档
+	scratchPath, cleanup, err := cxtar.NewScratchTar(archivePath, src, cfg)
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+
+	// 读取已有压缩包的条目和 GZIP 成员布局，再扫描新条目
+	layout, err := scanTgz(archivePath)
+	if err != nil {
+		return err
+	}
+	added, err := cxtar.ScanFileEntryNames(scratchPath)
+	if err != nil {
+		return err
+	}
+
+	// 根据覆盖策略处理同名条目
+	plan, err := utils.PlanAppend(layout.entries, added, cfg.OverwriteExisting)
+	if err != nil {
+		return err
+	}
+
+	// 追加后压缩包中的全部条目，写入条目索引
+	merged := make(utils.EntryKinds, len(layout.entries)+len(added))
+	for name, isDir := range layout.entries {
+		merged[name] = isDir
+	}
+	for name, isDir := range added {
+		merged[name] = isDir
+	}
+
+	// 最后一个 GZIP 成员只包含归档结束标记、没有需要覆盖的条目且校验清单不在压缩包内时原地追加
+	if layout.appendable && !plan.HasReplacements() && !cfg.Checksums.Embedded() {
+		return appendInPlace(archivePath, layout.tailOffset, scratchPath, plan, merged, cfg)
+	}
+
+	// 否则重写整个压缩包，压缩包内的校验清单替换为合并新条目摘要后的清单
+	return utils.ReplaceFile(archivePath, func(f *os.File) error {
+		gzipWriter, err := newGzipWriter(f, cfg)
+		if err != nil {
+			return err
+		}
+		tarWriter := tar.NewWriter(gzipWriter)
+
+		if err := copyTgzEntries(tarWriter, archivePath, cxtar.SkipManifests(plan.Replaces, cfg)); err != nil {
+			_ = gzipWriter.Close()
+			return err
+		}
+		if err := copyTarEntries(tarWriter, scratchPath, plan.Skips); err != nil {
+			_ = gzipWriter.Close()
+			return err
+		}
+		if err := cxtar.WriteManifests(tarWriter, added, cfg); err != nil {
+			_ = gzipWriter.Close()
+			return err
+		}
+		return finishAppendableTgz(tarWriter, gzipWriter, f, merged)
+	})
+}
+
+// appendInPlace 截掉条目索引和归档结束标记成员后原地写入新的 GZIP 成员
+//
+// 参数:
+//   - archivePath: 已有的 TGZ 压缩包路径
+//   - tailOffset: 条目索引成员(没有索引时为归档结束标记成员)的起始位置
+//   - scratchPath: 包含新条目的临时 TAR 归档路径
+//   - plan: 冲突处理计划
+//   - entries: 追加后压缩包中的全部条目
+//   - cfg: 压缩配置
+//
+// 返回值:
+//   - error: 写入失败时返回错误
+func appendInPlace(archivePath string, tailOffset int64, scratchPath string, plan *utils.AppendPlan, entries utils.EntryKinds, cfg *config.Config) error {
+	file, err := os.OpenFile(archivePath, os.O_RDWR, 0)
+	if err != nil {
+		return fmt.Errorf("打开 TGZ 文件失败: %w", err)
+	}
+	defer func() { _ = file.Close() }()
+
+	// 截掉条目索引和归档结束标记成员
+	if err := file.Truncate(tailOffset); err != nil {
+		return fmt.Errorf("截断 TGZ 文件失败: %w", err)
+	}
+	if _, err := file.Seek(tailOffset, io.SeekStart); err != nil {
+		return fmt.Errorf("定位 TGZ 文件失败: %w", err)
+	}
+
+	// 在新的 GZIP 成员中写入新条目
+	gzipWriter, err := newGzipWriter(file, cfg)
+	if err != nil {
+		return err
+	}
+	tarWriter := tar.NewWriter(gzipWriter)
+	if err := copyTarEntries(tarWriter, scratchPath, plan.Skips); err != nil {
+		_ = gzipWriter.Close()
+		return err
+	}
+	if err := finishAppendableTgz(tarWriter, gzipWriter, file, entries); err != nil {
+		return err
+	}
+	return file.Sync()
+}
+
+// finishAppendableTgz 以支持原地追加的布局结束 TGZ 压缩流
+//
+// TAR 条目所在的 GZIP 成员结束后，写入条目索引成员和单独的归档结束标记成员。
+// 这样的文件仍是标准的 tar.gz，下次追加时只需读取并截掉末尾的这些成员即可在原文件上继续写入。
+//
+// 参数:
+//   - flusher: TAR 写入器，用于补齐最后一个条目的块填充
+//   - gzipWriter: TAR 条目所在的 GZIP 写入器
+//   - f: TGZ 文件，当前位置为 GZIP 成员的写入位置
+//   - entries: 压缩包中的全部条目
+//
+// 返回值:
+//   - error: 写入失败时返回错误
+func finishAppendableTgz(flusher interface{ Flush() error }, gzipWriter *gzip.Writer, f *os.File, entries utils.EntryKinds) error {
+	if err := flusher.Flush(); err != nil {
+		_ = gzipWriter.Close()
+		return fmt.Errorf("刷新 TAR 写入器失败: %w", err)
+	}
+	if err := gzipWriter.Close(); err != nil {
+		return fmt.Errorf("关闭 GZIP 写入器失败: %w", err)
+	}
+
+	indexOffset, err := f.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return fmt.Errorf("获取 TGZ 文件位置失败: %w", err)
+	}
+	indexed, err := writeIndex(f, entries)
+	if err != nil {
+		return fmt.Errorf("写入条目索引失败: %w", err)
+	}
+
+	// 写入只包含归档结束标记的 GZIP 成员，无法写入索引时下次追加改为完整扫描
+	if indexed {
+		if _, err := f.Write(trailerMember(indexOffset)); err != nil {
+			return fmt.Errorf("写入 TAR 归档结束标记失败: %w", err)
+		}
+		return nil
+	}
+	trailerWriter := gzip.NewWriter(f)
+	if _, err := trailerWriter.Write(make([]byte, tarTrailerSize)); err != nil {
+		_ = trailerWriter.Close()
+		return fmt.Errorf("写入 TAR 归档结束标记失败: %w", err)
+	}
+	if err := trailerWriter.Close(); err != nil {
+		return fmt.Errorf("关闭 GZIP 写入器失败: %w", err)
+	}
+	return nil
+}
+
+// scanEntryNames 扫描 TGZ 压缩包中的条目
+//
+// 参数:
+//   - archivePath: TGZ 压缩包路径
+//
+// 返回值:
+//   - utils.EntryKinds: 条目名称及是否为目录
+//   - error: 读取失败时返回错误
+func scanEntryNames(archivePath string) (utils.EntryKinds, error) {
+	file, err := os.Open(archivePath)
+	if err != nil {
+		return nil, fmt.Errorf("打开 TGZ 文件失败: %w", err)
+	}
+	defer func() { _ = file.Close() }()
+
+	gzipReader, err := gzip.NewReader(file)
+	if err != nil {
+		return nil, fmt.Errorf("创建 GZIP 读取器失败: %w", err)
+	}
+	defer func() { _ = gzipReader.Close() }()
+
+	return cxtar.ScanEntryNames(gzipReader)
+}
+
+// copyTgzEntries 将 TGZ 压缩包中的条目复制到 TAR 写入器
+//
+// 参数:
+//   - tarWriter: 目标 TAR 写入器
+//   - archivePath: 源 TGZ 压缩包路径
+//   - skip: 返回 true 的条目不复制
+//
+// 返回值:
+//   - error: 复制失败时返回错误
+func copyTgzEntries(tarWriter *tar.Writer, archivePath string, skip func(name string) bool) error {
+	file, err := os.Open(archivePath)
+	if err != nil {
+		return fmt.Errorf("打开 TGZ 文件失败: %w", err)
+	}
+	defer func() { _ = file.Close() }()
+
+	gzipReader, err := gzip.NewReader(file)
+	if err != nil {
+		return fmt.Errorf("创建 GZIP 读取器失败: %w", err)
+	}
+	defer func() { _ = gzipReader.Close() }()
+
+	return cxtar.CopyEntries(tarWriter, tar.NewReader(gzipReader), skip)
+}
+
+// copyTarEntries 将 TAR 归档中的条目复制到 TAR 写入器
+//
+// 参数:
+//   - tarWriter: 目标 TAR 写入器
+//   - archivePath: 源 TAR 归档路径
+//   - skip: 返回 true 的条目不复制
+//
+// 返回值:
+//   - error: 复制失败时返回错误
+func copyTarEntries(tarWriter *tar.Writer, archivePath string, skip func(name string) bool) error {
+	file, err := os.Open(archivePath)
+	if err != nil {
+		return fmt.Errorf("打开 TAR 文件失败: %w", err)
+	}
+	defer func() { _ = file.Close() }()
+
+	return cxtar.CopyEntries(tarWriter, tar.NewReader(file), skip)
+}
+
+// countingReader 统计已读取字节数的读取器
+//
+// 实现 io.ByteReader，使 GZIP 解压时不会预读超出当前成员的数据，
+// 从而可以得到每个 GZIP 成员的准确边界。
+type countingReader struct {
+	r *bufio.Reader // 带缓冲的底层读取器
+	n int64         // 已读取的字节数
+}
+
+// Read 读取数据并计数
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
+}
+
+// ReadByte 读取一个字节并计数
+func (c *countingReader) ReadByte() (byte, error) {
+	b, err := c.r.ReadByte()
+	if err == nil {
+		c.n++
+	}
+	return b, err
+}
+
+// memberReader 逐个解压 GZIP 成员的读取器
+//
+// 对外表现为所有成员解压后拼接的数据流，同时记录末尾连续的空成员(条目索引)
+// 和最后一个成员的起始位置、最后一个成员解压后的字节数以及内容是否全部为零。
+type memberReader struct {
+	src        *countingReader // 压缩数据
+	gzipReader *gzip.Reader    // 当前成员的 GZIP 读取器
+	started    bool            // 是否已打开第一个成员
+	members    int             // 已打开的成员数
+	tailOffset int64           // 末尾连续的空成员和最后一个成员的起始位置
+	lastSize   int64           // 最后一个成员解压后的字节数
+	nonZero    bool            // 最后一个成员是否包含非零字节
+}
+
+// Read 读取解压数据，当前成员结束时自动切换到下一个成员
+func (m *memberReader) Read(p []byte) (int, error) {
+	for {
+		if !m.started {
+			m.started = true
+			if err := m.nextMember(); err != nil {
+				return 0, err
+			}
+		}
+
+		n, err := m.gzipReader.Read(p)
+		m.lastSize += int64(n)
+		if !m.nonZero {
+			for _, b := range p[:n] {
+				if b != 0 {
+					m.nonZero = true
+					break
+				}
+			}
+		}
+		if err != io.EOF {
+			return n, err
+		}
+		if n > 0 {
+			return n, nil
+		}
+		if err := m.nextMember(); err != nil {
+			return 0, err
+		}
+	}
+}
+
+// nextMember 打开下一个 GZIP 成员，没有更多成员时返回 io.EOF
+func (m *memberReader) nextMember() error {
+	offset := m.src.n
+	if err := m.gzipReader.Reset(m.src); err == io.EOF {
+		return io.EOF
+	} else if err != nil {
+		return fmt.Errorf("读取 GZIP 成员失败: %w", err)
+	}
+	m.gzipReader.Multistream(false)
+
+	// 前一个成员有数据时，末尾部分从当前成员开始
+	if m.members == 0 || m.lastSize > 0 {
+		m.tailOffset = offset
+	}
+	m.members++
+	m.lastSize = 0
+	m.nonZero = false
+	return nil
+}
+
+// tgzLayout TGZ 压缩包的条目和 GZIP 成员布局
+type tgzLayout struct {
+	entries    utils.EntryKinds // 条目名称及是否为目录
+	tailOffset int64            // 原地追加时的截断位置：条目索引成员或归档结束标记成员的起始位置
+	appendable bool             // 最后一个成员是否只包含归档结束标记，可以原地追加
+}
+
+// scanTgz 获取 TGZ 压缩包的条目和 GZIP 成员布局
+//
+// 优先从文件末尾的条目索引读取，不解压已有内容；布局无法识别时一次解压扫描整个压缩包。
+//
+// 参数:
+//   - archivePath: TGZ 压缩包路径
+//
+// 返回值:
+//   - *tgzLayout: 条目名称和原地追加时的截断位置
+//   - error: 读取失败时返回错误
+func scanTgz(archivePath string) (*tgzLayout, error) {
+	file, err := os.Open(archivePath)
+	if err != nil {
+		return nil, fmt.Errorf("打开 TGZ 文件失败: %w", err)
+	}
+	defer func() { _ = file.Close() }()
+
+	stat, err := file.Stat()
+	if err != nil {
+		return nil, fmt.Errorf("获取 TGZ 文件信息失败: %w", err)
+	}
+	if layout, ok := readIndexedLayout(file, stat.Size()); ok {
+		return layout, nil
+	}
+
+	reader := &memberReader{
+		src:        &countingReader{r: bufio.NewReader(file)},
+		gzipReader: new(gzip.Reader),
+	}
+	entries, err := cxtar.ScanEntryNames(reader)
+	if err != nil {
+		return nil, err
+	}
+
+	// TAR 读取器在归档结束标记处停止，继续读完剩余的成员以得到最后一个成员的位置
+	if _, err := io.Copy(io.Discard, reader); err != nil {
+		return nil, fmt.Errorf("解压 GZIP 成员失败: %w", err)
+	}
+
+	return &tgzLayout{
+		entries:    entries,
+		tailOffset: reader.tailOffset,
+		appendable: reader.members > 1 && reader.lastSize == tarTrailerSize && !reader.nonZero,
+	}, nil
+}
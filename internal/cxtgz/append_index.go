@@ -0,0 +1,205 @@
+// Package cxtgz 提供支持原地追加的 TGZ 布局中条目索引的读写功能实现。
+//
+// 支持原地追加的 TGZ 文件在 TAR 条目所在的 GZIP 成员之后，依次写入：
+//   - 若干不含数据的 GZIP 成员，文件头的额外字段(子字段 "CX")保存全部条目名称
+//   - 手工构造的定长 GZIP 成员，内容为 TAR 归档结束标记，额外字段(子字段 "CT")记录索引成员的起始位置
+//
+// 不含数据的成员解压后为空，标准的 gzip 和 tar 工具读取时会忽略它们。
+// 追加时只需读取文件末尾的定长成员和索引成员即可得到已有条目和截断位置，
+// 不需要解压已有内容；布局无法识别时回退为完整解压扫描。
+//
+// 主要功能：
+//   - 写入条目索引成员和归档结束标记成员
+//   - 从文件末尾读取条目索引
+//
+// 使用示例：
+//
+//	layout, ok := readIndexedLayout(file, size)
+package cxtgz
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"hash/crc32"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"gitee.com/MM-Q/comprx/internal/utils"
+)
+
+const (
+	// tarTrailerSize TAR 归档结束标记(两个全零块)的大小
+	tarTrailerSize = 2 * 512
+	// subfieldHeaderSize GZIP 额外字段中子字段头(标识 2 字节、长度 2 字节)的大小
+	subfieldHeaderSize = 4
+	// maxIndexChunk 单个索引成员中保存的条目名称数据上限(额外字段总长不超过 65535 字节)
+	maxIndexChunk = 65535 - subfieldHeaderSize
+	// trailerMemberSize 归档结束标记成员的长度：
+	// 文件头 10 字节、额外字段长度 2 字节、"CT" 子字段 12 字节、存储块头 5 字节、结束标记、CRC32 和 ISIZE
+	trailerMemberSize = 10 + 2 + subfieldHeaderSize + 8 + 5 + tarTrailerSize + 8
+)
+
+var (
+	// indexSubfieldID 保存条目名称的子字段标识
+	indexSubfieldID = [2]byte{'C', 'X'}
+	// trailerSubfieldID 记录索引成员起始位置的子字段标识
+	trailerSubfieldID = [2]byte{'C', 'T'}
+)
+
+// trailerMember 构造只包含 TAR 归档结束标记的 GZIP 成员
+//
+// 成员以存储块写入、不压缩，长度固定且与 Go 版本和压缩等级无关，
+// 追加时按固定长度从文件末尾读取即可识别。
+//
+// 参数:
+//   - indexOffset: 索引成员的起始位置
+//
+// 返回值:
+//   - []byte: GZIP 成员数据
+func trailerMember(indexOffset int64) []byte {
+	member := make([]byte, 0, trailerMemberSize)
+
+	// 文件头：魔数、DEFLATE、FEXTRA 标志、修改时间 0、XFL 0、操作系统未知
+	member = append(member, 0x1f, 0x8b, 8, 0x04, 0, 0, 0, 0, 0, 255)
+	member = binary.LittleEndian.AppendUint16(member, subfieldHeaderSize+8)
+	member = append(member, trailerSubfieldID[0], trailerSubfieldID[1])
+	member = binary.LittleEndian.AppendUint16(member, 8)
+	member = binary.LittleEndian.AppendUint64(member, uint64(indexOffset))
+
+	// 最后一个存储块：BFINAL=1、BTYPE=00，随后是 LEN 和 NLEN
+	member = append(member, 0x01)
+	member = binary.LittleEndian.AppendUint16(member, tarTrailerSize)
+	member = binary.LittleEndian.AppendUint16(member, ^uint16(tarTrailerSize))
+	zeros := make([]byte, tarTrailerSize)
+	member = append(member, zeros...)
+
+	member = binary.LittleEndian.AppendUint32(member, crc32.ChecksumIEEE(zeros))
+	member = binary.LittleEndian.AppendUint32(member, tarTrailerSize)
+	return member
+}
+
+// writeIndex 写入保存条目名称的 GZIP 成员
+//
+// 参数:
+//   - w: 输出目标
+//   - entries: 压缩包中的全部条目
+//
+// 返回值:
+//   - bool: 条目名称包含换行符无法写入索引时返回 false，此时不写入任何数据
+//   - error: 写入失败时返回错误
+func writeIndex(w io.Writer, entries utils.EntryKinds) (bool, error) {
+	names := make([]string, 0, len(entries))
+	for name, isDir := range entries {
+		if strings.Contains(name, "\n") {
+			return false, nil
+		}
+		if isDir {
+			name += "/"
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	data := []byte(strings.Join(names, "\n"))
+
+	// 额外字段长度有限，名称较多时拆分到多个成员
+	for len(data) > 0 {
+		chunk := data[:min(len(data), maxIndexChunk)]
+		data = data[len(chunk):]
+
+		extra := make([]byte, 0, subfieldHeaderSize+len(chunk))
+		extra = append(extra, indexSubfieldID[0], indexSubfieldID[1])
+		extra = binary.LittleEndian.AppendUint16(extra, uint16(len(chunk)))
+		extra = append(extra, chunk...)
+
+		gzipWriter := gzip.NewWriter(w)
+		gzipWriter.Extra = extra
+		if err := gzipWriter.Close(); err != nil {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+// readIndexedLayout 从文件末尾的归档结束标记成员和索引成员读取 TGZ 布局
+//
+// 参数:
+//   - file: TGZ 文件
+//   - size: 文件大小
+//
+// 返回值:
+//   - *tgzLayout: 条目名称和索引成员的起始位置
+//   - bool: 文件末尾不是可识别的索引布局时返回 false
+func readIndexedLayout(file *os.File, size int64) (*tgzLayout, bool) {
+	trailerOffset := size - trailerMemberSize
+	if trailerOffset <= 0 {
+		return nil, false
+	}
+
+	// 读取并校验归档结束标记成员
+	tail := make([]byte, trailerMemberSize)
+	if _, err := file.ReadAt(tail, trailerOffset); err != nil {
+		return nil, false
+	}
+	indexOffset := int64(binary.LittleEndian.Uint64(tail[16:24]))
+	if indexOffset <= 0 || indexOffset > trailerOffset || !bytes.Equal(tail, trailerMember(indexOffset)) {
+		return nil, false
+	}
+
+	// 依次读取索引成员额外字段中的条目名称
+	var data []byte
+	reader := bufio.NewReader(io.NewSectionReader(file, indexOffset, trailerOffset-indexOffset))
+	if indexOffset < trailerOffset {
+		gzipReader, err := gzip.NewReader(reader)
+		if err != nil {
+			return nil, false
+		}
+		for {
+			gzipReader.Multistream(false)
+			chunk, ok := indexChunk(gzipReader.Extra)
+			if !ok {
+				return nil, false
+			}
+			if n, err := io.Copy(io.Discard, gzipReader); err != nil || n != 0 {
+				return nil, false
+			}
+			data = append(data, chunk...)
+
+			if err := gzipReader.Reset(reader); err == io.EOF {
+				break
+			} else if err != nil {
+				return nil, false
+			}
+		}
+	}
+
+	entries := make(utils.EntryKinds)
+	for _, name := range strings.Split(string(data), "\n") {
+		if name != "" {
+			entries.Add(name, strings.HasSuffix(name, "/"))
+		}
+	}
+	return &tgzLayout{entries: entries, tailOffset: indexOffset, appendable: true}, true
+}
+
+// indexChunk 从 GZIP 额外字段中取出索引子字段的数据
+//
+// 参数:
+//   - extra: GZIP 成员头的额外字段
+//
+// 返回值:
+//   - []byte: 条目名称数据
+//   - bool: 额外字段不是单个索引子字段时返回 false
+func indexChunk(extra []byte) ([]byte, bool) {
+	if len(extra) < subfieldHeaderSize || extra[0] != indexSubfieldID[0] || extra[1] != indexSubfieldID[1] {
+		return nil, false
+	}
+	n := int(binary.LittleEndian.Uint16(extra[2:4]))
+	if len(extra) != subfieldHeaderSize+n {
+		return nil, false
+	}
+	return extra[subfieldHeaderSize:], true
+}
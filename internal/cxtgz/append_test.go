@@ -0,0 +1,272 @@
+package cxtgz
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gitee.com/MM-Q/comprx/internal/config"
+)
+
+// readTgzFiles 解压 TGZ 文件并返回 文件名->内容 映射
+func readTgzFiles(t *testing.T, tgzFile string) map[string]string {
+	t.Helper()
+
+	extractDir := filepath.Join(t.TempDir(), "out")
+	if err := Untgz(tgzFile, extractDir, config.New()); err != nil {
+		t.Fatalf("解压失败: %v", err)
+	}
+
+	files := make(map[string]string)
+	err := filepath.Walk(extractDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return err
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		rel, _ := filepath.Rel(extractDir, path)
+		files[filepath.ToSlash(rel)] = string(data)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("遍历解压目录失败: %v", err)
+	}
+	return files
+}
+
+func TestAppend_InPlace(t *testing.T) {
+	tempDir := t.TempDir()
+
+	var sources []string
+	for _, hour := range []string{"09", "10", "11"} {
+		src := filepath.Join(tempDir, "app-"+hour+".log")
+		if err := os.WriteFile(src, []byte(hour), 0644); err != nil {
+			t.Fatalf("创建测试文件失败: %v", err)
+		}
+		sources = append(sources, src)
+	}
+
+	tgzFile := filepath.Join(tempDir, "logs.tgz")
+	if err := Tgz(tgzFile, sources[0], config.New()); err != nil {
+		t.Fatalf("TGZ压缩失败: %v", err)
+	}
+
+	// Tgz 生成标准的单成员 TGZ，不使用追加布局
+	layout, err := scanTgz(tgzFile)
+	if err != nil || layout.appendable {
+		t.Fatalf("Tgz 生成的文件不应包含单独的结束标记成员: appendable=%v, err=%v", layout != nil && layout.appendable, err)
+	}
+
+	// 首次追加重写为支持原地追加的布局
+	if err := Append(tgzFile, sources[1], config.New()); err != nil {
+		t.Fatalf("追加失败: %v", err)
+	}
+	layout, err = scanTgz(tgzFile)
+	if err != nil || !layout.appendable {
+		t.Fatalf("追加后的TGZ应支持原地追加: err=%v", err)
+	}
+	if len(layout.entries) != 2 {
+		t.Errorf("应扫描到2个条目, 实际: %v", layout.entries)
+	}
+	before, _ := os.ReadFile(tgzFile)
+
+	if err := Append(tgzFile, sources[2], config.New()); err != nil {
+		t.Fatalf("追加失败: %v", err)
+	}
+
+	// 原地追加不应改动已有条目所在的 GZIP 成员
+	after, _ := os.ReadFile(tgzFile)
+	if !bytes.Equal(before[:layout.tailOffset], after[:layout.tailOffset]) {
+		t.Error("原地追加改动了已有的GZIP成员")
+	}
+
+	files := readTgzFiles(t, tgzFile)
+	if files["app-09.log"] != "09" || files["app-10.log"] != "10" || files["app-11.log"] != "11" {
+		t.Errorf("追加后的内容不匹配: %v", files)
+	}
+}
+
+func TestAppend_Conflict(t *testing.T) {
+	tempDir := t.TempDir()
+
+	src := filepath.Join(tempDir, "app.log")
+	if err := os.WriteFile(src, []byte("old"), 0644); err != nil {
+		t.Fatalf("创建测试文件失败: %v", err)
+	}
+	tgzFile := filepath.Join(tempDir, "logs.tgz")
+	if err := Tgz(tgzFile, src, config.New()); err != nil {
+		t.Fatalf("TGZ压缩失败: %v", err)
+	}
+
+	// 不允许覆盖时返回错误
+	if err := os.WriteFile(src, []byte("new"), 0644); err != nil {
+		t.Fatalf("更新测试文件失败: %v", err)
+	}
+	err := Append(tgzFile, src, config.New())
+	if err == nil || !strings.Contains(err.Error(), "已存在条目") {
+		t.Fatalf("期望同名条目冲突错误, 实际: %v", err)
+	}
+
+	// 允许覆盖时替换已有条目
+	cfg := config.New()
+	cfg.OverwriteExisting = true
+	if err := Append(tgzFile, src, cfg); err != nil {
+		t.Fatalf("覆盖追加失败: %v", err)
+	}
+	files := readTgzFiles(t, tgzFile)
+	if len(files) != 1 || files["app.log"] != "new" {
+		t.Errorf("覆盖后的内容不匹配: %v", files)
+	}
+
+	// 重写后的文件仍支持原地追加
+	if layout, err := scanTgz(tgzFile); err != nil || !layout.appendable {
+		t.Errorf("重写后的TGZ应支持原地追加: err=%v", err)
+	}
+}
+
+func TestAppend_ForeignLayout(t *testing.T) {
+	tempDir := t.TempDir()
+
+	// 构造单个 GZIP 成员的 TGZ 文件（其他工具的常见布局）
+	var buf bytes.Buffer
+	gzipWriter := gzip.NewWriter(&buf)
+	tarWriter := tar.NewWriter(gzipWriter)
+	if err := tarWriter.WriteHeader(&tar.Header{Name: "a.txt", Mode: 0644, Size: 1, Typeflag: tar.TypeReg}); err != nil {
+		t.Fatalf("写入TAR头失败: %v", err)
+	}
+	_, _ = tarWriter.Write([]byte("a"))
+	_ = tarWriter.Close()
+	_ = gzipWriter.Close()
+
+	tgzFile := filepath.Join(tempDir, "foreign.tgz")
+	if err := os.WriteFile(tgzFile, buf.Bytes(), 0644); err != nil {
+		t.Fatalf("写入TGZ文件失败: %v", err)
+	}
+	if layout, err := scanTgz(tgzFile); err != nil || layout.appendable {
+		t.Fatalf("单成员TGZ不应支持原地追加: err=%v", err)
+	}
+
+	src := filepath.Join(tempDir, "b.txt")
+	if err := os.WriteFile(src, []byte("b"), 0644); err != nil {
+		t.Fatalf("创建测试文件失败: %v", err)
+	}
+	if err := Append(tgzFile, src, config.New()); err != nil {
+		t.Fatalf("追加失败: %v", err)
+	}
+
+	files := readTgzFiles(t, tgzFile)
+	if files["a.txt"] != "a" || files["b.txt"] != "b" {
+		t.Errorf("追加后的内容不匹配: %v", files)
+	}
+}
+
+func TestAppend_Index(t *testing.T) {
+	tempDir := t.TempDir()
+
+	srcDir := filepath.Join(tempDir, "logs")
+	if err := os.MkdirAll(srcDir, 0755); err != nil {
+		t.Fatalf("创建测试目录失败: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(srcDir, "a.log"), []byte("a"), 0644); err != nil {
+		t.Fatalf("创建测试文件失败: %v", err)
+	}
+	src := filepath.Join(tempDir, "b.log")
+	if err := os.WriteFile(src, []byte("b"), 0644); err != nil {
+		t.Fatalf("创建测试文件失败: %v", err)
+	}
+
+	tgzFile := filepath.Join(tempDir, "logs.tgz")
+	if err := Tgz(tgzFile, srcDir, config.New()); err != nil {
+		t.Fatalf("TGZ压缩失败: %v", err)
+	}
+	if err := Append(tgzFile, src, config.New()); err != nil {
+		t.Fatalf("追加失败: %v", err)
+	}
+
+	// 追加后的文件末尾带有条目索引，读取结果与完整扫描一致
+	file, err := os.Open(tgzFile)
+	if err != nil {
+		t.Fatalf("打开TGZ文件失败: %v", err)
+	}
+	defer func() { _ = file.Close() }()
+	stat, _ := file.Stat()
+	layout, ok := readIndexedLayout(file, stat.Size())
+	if !ok {
+		t.Fatal("追加后的TGZ应带有可识别的条目索引")
+	}
+	scanned, err := scanEntryNames(tgzFile)
+	if err != nil {
+		t.Fatalf("扫描条目失败: %v", err)
+	}
+	if len(layout.entries) != len(scanned) {
+		t.Fatalf("索引条目与扫描结果不一致: %v, %v", layout.entries, scanned)
+	}
+	for name, isDir := range scanned {
+		if got, exists := layout.entries[name]; !exists || got != isDir {
+			t.Errorf("索引中的条目 %s 不匹配: exists=%v, isDir=%v", name, exists, got)
+		}
+	}
+
+	// 索引中的条目用于冲突检测
+	err = Append(tgzFile, src, config.New())
+	if err == nil || !strings.Contains(err.Error(), "已存在条目") {
+		t.Errorf("期望同名条目冲突错误, 实际: %v", err)
+	}
+}
+
+func TestAppend_IndexFallback(t *testing.T) {
+	tempDir := t.TempDir()
+
+	// 条目名称包含换行符时不写入索引，追加时回退为完整扫描
+	src := filepath.Join(tempDir, "line\nbreak.log")
+	if err := os.WriteFile(src, []byte("a"), 0644); err != nil {
+		t.Skipf("文件系统不支持包含换行符的文件名: %v", err)
+	}
+	other := filepath.Join(tempDir, "b.log")
+	if err := os.WriteFile(other, []byte("b"), 0644); err != nil {
+		t.Fatalf("创建测试文件失败: %v", err)
+	}
+	last := filepath.Join(tempDir, "c.log")
+	if err := os.WriteFile(last, []byte("c"), 0644); err != nil {
+		t.Fatalf("创建测试文件失败: %v", err)
+	}
+
+	tgzFile := filepath.Join(tempDir, "logs.tgz")
+	if err := Tgz(tgzFile, src, config.New()); err != nil {
+		t.Fatalf("TGZ压缩失败: %v", err)
+	}
+	if err := Append(tgzFile, other, config.New()); err != nil {
+		t.Fatalf("追加失败: %v", err)
+	}
+
+	file, err := os.Open(tgzFile)
+	if err != nil {
+		t.Fatalf("打开TGZ文件失败: %v", err)
+	}
+	stat, _ := file.Stat()
+	_, ok := readIndexedLayout(file, stat.Size())
+	_ = file.Close()
+	if ok {
+		t.Fatal("条目名称包含换行符时不应写入索引")
+	}
+
+	// 完整扫描仍能识别原地追加布局
+	layout, err := scanTgz(tgzFile)
+	if err != nil || !layout.appendable || len(layout.entries) != 2 {
+		t.Fatalf("完整扫描结果不正确: layout=%+v, err=%v", layout, err)
+	}
+	if err := Append(tgzFile, last, config.New()); err != nil {
+		t.Fatalf("追加失败: %v", err)
+	}
+
+	files := readTgzFiles(t, tgzFile)
+	if files["line\nbreak.log"] != "a" || files["b.log"] != "b" || files["c.log"] != "c" {
+		t.Errorf("追加后的内容不匹配: %v", files)
+	}
+}
@@ -0,0 +1,108 @@
+// Package cxtgz 提供 TGZ (tar.gz) 格式的增量构建功能实现。
+//
+// 该文件实现了 TGZ 压缩包构建器，在 GZIP 压缩流上复用 TAR 归档构建器，
+// 支持逐个添加文件、目录和符号链接。
+//
+// 主要类型：
+//   - Builder: TGZ 压缩包构建器
+//
+// 使用示例：
+//
+//	builder, err := cxtgz.NewBuilder(w, cfg)
+//	err = builder.AddFile("docs/readme.txt", strings.NewReader("hello"), types.FileInfo{Size: 5})
+//	err = builder.Close()
+package cxtgz
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+
+	"gitee.com/MM-Q/comprx/internal/config"
+	"gitee.com/MM-Q/comprx/internal/cxtar"
+	"gitee.com/MM-Q/comprx/types"
+)
+
+// Builder TGZ 压缩包构建器
+//
+// 使用完毕后必须调用 Close 写入归档结束标记并刷新 GZIP 压缩流。
+type Builder struct {
+	gzipWriter *gzip.Writer   // GZIP 写入器
+	tarBuilder *cxtar.Builder // TAR 归档构建器
+}
+
+// NewBuilder 创建写入到 w 的 TGZ 压缩包构建器
+//
+// 参数:
+//   - w: 压缩包输出目标
+//   - cfg: 压缩配置
+//
+// 返回:
+//   - *Builder: TGZ 压缩包构建器
+//   - error: 创建 GZIP 写入器失败时返回错误
+func NewBuilder(w io.Writer, cfg *config.Config) (*Builder, error) {
+	gzipWriter, err := newGzipWriter(w, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Builder{
+		gzipWriter: gzipWriter,
+		tarBuilder: cxtar.NewBuilder(gzipWriter, cfg),
+	}, nil
+}
+
+// AddFile 添加普通文件
+//
+// 参数:
+//   - name: 压缩包内的文件名
+//   - r: 文件内容
+//   - info: 条目信息
+//
+// 返回:
+//   - error: 名称不合法、条目重复或写入失败时返回错误
+func (b *Builder) AddFile(name string, r io.Reader, info types.FileInfo) error {
+	return b.tarBuilder.AddFile(name, r, info)
+}
+
+// AddDir 添加目录
+//
+// 参数:
+//   - name: 压缩包内的目录名
+//   - info: 条目信息
+//
+// 返回:
+//   - error: 名称不合法、条目重复或写入失败时返回错误
+func (b *Builder) AddDir(name string, info types.FileInfo) error {
+	return b.tarBuilder.AddDir(name, info)
+}
+
+// AddSymlink 添加符号链接
+//
+// 参数:
+//   - name: 压缩包内的链接名
+//   - target: 链接目标
+//   - info: 条目信息
+//
+// 返回:
+//   - error: 名称不合法、条目重复或写入失败时返回错误
+func (b *Builder) AddSymlink(name, target string, info types.FileInfo) error {
+	return b.tarBuilder.AddSymlink(name, target, info)
+}
+
+// Close 写入归档结束标记并关闭 GZIP 压缩流
+//
+// 注意: 不会关闭底层的输出目标。
+//
+// 返回:
+//   - error: 关闭失败时返回错误
+func (b *Builder) Close() error {
+	if err := b.tarBuilder.Close(); err != nil {
+		_ = b.gzipWriter.Close()
+		return err
+	}
+	if err := b.gzipWriter.Close(); err != nil {
+		return fmt.Errorf("关闭 GZIP 写入器失败: %w", err)
+	}
+	return nil
+}
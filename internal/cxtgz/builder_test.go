@@ -0,0 +1,59 @@
+package cxtgz
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"io"
+	"strings"
+	"testing"
+
+	"gitee.com/MM-Q/comprx/internal/config"
+	"gitee.com/MM-Q/comprx/types"
+)
+
+func TestBuilder_RoundTrip(t *testing.T) {
+	var buf bytes.Buffer
+	builder, err := NewBuilder(&buf, config.New())
+	if err != nil {
+		t.Fatalf("创建构建器失败: %v", err)
+	}
+
+	if err := builder.AddDir("app", types.FileInfo{}); err != nil {
+		t.Fatalf("添加目录失败: %v", err)
+	}
+	if err := builder.AddFile("app/main.txt", strings.NewReader("content"), types.FileInfo{Size: 7}); err != nil {
+		t.Fatalf("添加文件失败: %v", err)
+	}
+	if err := builder.Close(); err != nil {
+		t.Fatalf("关闭构建器失败: %v", err)
+	}
+
+	gzipReader, err := gzip.NewReader(&buf)
+	if err != nil {
+		t.Fatalf("创建GZIP读取器失败: %v", err)
+	}
+	reader := tar.NewReader(gzipReader)
+
+	var names []string
+	for {
+		header, err := reader.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("读取TAR条目失败: %v", err)
+		}
+		names = append(names, header.Name)
+		if header.Name == "app/main.txt" {
+			data, _ := io.ReadAll(reader)
+			if string(data) != "content" {
+				t.Errorf("文件内容不匹配: 期望 content, 实际 %s", string(data))
+			}
+		}
+	}
+
+	if strings.Join(names, ",") != "app/,app/main.txt" {
+		t.Errorf("条目列表不匹配: %v", names)
+	}
+}
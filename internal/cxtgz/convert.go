@@ -0,0 +1,83 @@
+// Package cxtgz 提供将 TGZ 压缩包条目流式写入其他压缩包的功能实现。
+//
+// 该文件实现了格式转换时的 TGZ 读取端：边解压边读取 TAR 条目并写入任意条目写入器，
+// 全程不落盘。
+//
+// 主要功能：
+//   - 将 TGZ 条目流式写入条目写入器
+//
+// 使用示例：
+//
+//	err := cxtgz.WriteEntriesTo("vendor.tgz", builder, cfg.Filter)
+package cxtgz
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+
+	"gitee.com/MM-Q/comprx/internal/cxtar"
+	"gitee.com/MM-Q/comprx/internal/utils"
+	"gitee.com/MM-Q/comprx/types"
+)
+
+// WriteEntriesTo 将 TGZ 压缩包中的条目写入条目写入器
+//
+// 参数:
+//   - archivePath: TGZ 压缩包路径
+//   - w: 目标条目写入器
+//   - filter: 过滤器，为 nil 时写入全部条目
+//
+// 返回值:
+//   - error: 读取或写入失败时返回错误
+func WriteEntriesTo(archivePath string, w utils.ArchiveEntryWriter, filter *types.FilterOptions) error {
+	file, err := os.Open(archivePath)
+	if err != nil {
+		return fmt.Errorf("打开 TGZ 文件失败: %w", err)
+	}
+	defer func() { _ = file.Close() }()
+
+	gzipReader, err := gzip.NewReader(file)
+	if err != nil {
+		return fmt.Errorf("创建 GZIP 读取器失败: %w", err)
+	}
+	defer func() { _ = gzipReader.Close() }()
+
+	return cxtar.WriteEntriesTo(gzipReader, w, filter, func() (io.ReadCloser, error) {
+		return openTgzStream(archivePath)
+	})
+}
+
+// tgzStream 关闭时同时关闭 GZIP 读取器和文件的 TGZ 解压流
+type tgzStream struct {
+	*gzip.Reader
+	file *os.File // 底层 TGZ 文件
+}
+
+// Close 关闭 GZIP 读取器和文件
+func (s *tgzStream) Close() error {
+	_ = s.Reader.Close()
+	return s.file.Close()
+}
+
+// openTgzStream 打开 TGZ 文件并返回解压后的 TAR 数据流
+//
+// 参数:
+//   - archivePath: TGZ 压缩包路径
+//
+// 返回值:
+//   - io.ReadCloser: TAR 数据流
+//   - error: 打开失败时返回错误
+func openTgzStream(archivePath string) (io.ReadCloser, error) {
+	file, err := os.Open(archivePath)
+	if err != nil {
+		return nil, fmt.Errorf("打开 TGZ 文件失败: %w", err)
+	}
+	gzipReader, err := gzip.NewReader(file)
+	if err != nil {
+		_ = file.Close()
+		return nil, fmt.Errorf("创建 GZIP 读取器失败: %w", err)
+	}
+	return &tgzStream{Reader: gzipReader, file: file}, nil
+}
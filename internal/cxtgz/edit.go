@@ -0,0 +1,107 @@
+// Package cxtgz 提供删除和重命名 TGZ 压缩包条目的功能实现。
+//
+// 该文件实现了 TGZ 压缩包条目的删除和重命名。GZIP 流无法跳过中间的数据，
+// 因此解压后按条目过滤并重新压缩写入同目录的临时文件，最后原子地替换原文件；
+// 重写后的文件采用支持原地追加的布局。
+//
+// 主要功能：
+//   - 按通配符模式删除条目
+//   - 重命名条目或目录
+//
+// 使用示例：
+//
+//	err := cxtgz.DeleteEntries("backup.tgz", []string{"config/secret.env"}, cfg)
+//	err = cxtgz.RenameEntries("backup.tgz", map[string]string{"old/": "new/"}, cfg)
+package cxtgz
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"fmt"
+	"os"
+
+	"gitee.com/MM-Q/comprx/internal/config"
+	"gitee.com/MM-Q/comprx/internal/cxtar"
+	"gitee.com/MM-Q/comprx/internal/utils"
+)
+
+// DeleteEntries 删除 TGZ 压缩包中匹配模式的条目
+//
+// 参数:
+//   - archivePath: TGZ 压缩包路径
+//   - patterns: 删除模式，匹配到目录时同时删除其下所有条目
+//   - cfg: 压缩配置，用于重新压缩
+//
+// 返回值:
+//   - error: 操作过程中遇到的错误
+func DeleteEntries(archivePath string, patterns []string, cfg *config.Config) error {
+	return rewriteEntries(archivePath, cfg, func(entries utils.EntryKinds) (utils.EntryRewrite, error) {
+		return utils.PlanDelete(entries, patterns)
+	})
+}
+
+// RenameEntries 重命名 TGZ 压缩包中的条目
+//
+// 参数:
+//   - archivePath: TGZ 压缩包路径
+//   - renames: 原名称到新名称的映射，重命名目录时其下所有条目一并移动
+//   - cfg: 压缩配置，用于重新压缩
+//
+// 返回值:
+//   - error: 操作过程中遇到的错误
+func RenameEntries(archivePath string, renames map[string]string, cfg *config.Config) error {
+	return rewriteEntries(archivePath, cfg, func(entries utils.EntryKinds) (utils.EntryRewrite, error) {
+		return utils.PlanRename(entries, renames)
+	})
+}
+
+// rewriteEntries 按条目重写函数原子地重写 TGZ 压缩包
+//
+// 参数:
+//   - archivePath: TGZ 压缩包路径
+//   - cfg: 压缩配置
+//   - plan: 根据压缩包中的条目生成条目重写函数
+//
+// 返回值:
+//   - error: 操作过程中遇到的错误
+func rewriteEntries(archivePath string, cfg *config.Config, plan func(utils.EntryKinds) (utils.EntryRewrite, error)) error {
+	// 确保路径为绝对路径
+	var absErr error
+	if archivePath, absErr = utils.EnsureAbsPath(archivePath, "TGZ文件路径"); absErr != nil {
+		return absErr
+	}
+
+	entries, err := scanEntryNames(archivePath)
+	if err != nil {
+		return err
+	}
+	rewrite, err := plan(entries)
+	if err != nil {
+		return err
+	}
+
+	return utils.ReplaceFile(archivePath, func(f *os.File) error {
+		file, err := os.Open(archivePath)
+		if err != nil {
+			return fmt.Errorf("打开 TGZ 文件失败: %w", err)
+		}
+		defer func() { _ = file.Close() }()
+
+		gzipReader, err := gzip.NewReader(file)
+		if err != nil {
+			return fmt.Errorf("创建 GZIP 读取器失败: %w", err)
+		}
+		defer func() { _ = gzipReader.Close() }()
+
+		gzipWriter, err := newGzipWriter(f, cfg)
+		if err != nil {
+			return err
+		}
+		tarWriter := tar.NewWriter(gzipWriter)
+		if err := cxtar.RewriteEntries(tarWriter, tar.NewReader(gzipReader), rewrite); err != nil {
+			_ = gzipWriter.Close()
+			return err
+		}
+		return closeTgz(tarWriter, gzipWriter)
+	})
+}
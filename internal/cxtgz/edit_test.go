@@ -0,0 +1,50 @@
+package cxtgz
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"gitee.com/MM-Q/comprx/internal/config"
+)
+
+func TestDeleteAndRenameEntries(t *testing.T) {
+	tempDir := t.TempDir()
+
+	srcDir := filepath.Join(tempDir, "backup")
+	files := map[string]string{
+		"main.go":           "package main",
+		"config/secret.env": "TOKEN=leaked",
+	}
+	for name, content := range files {
+		filePath := filepath.Join(srcDir, name)
+		if err := os.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
+			t.Fatalf("创建目录失败: %v", err)
+		}
+		if err := os.WriteFile(filePath, []byte(content), 0644); err != nil {
+			t.Fatalf("创建测试文件失败: %v", err)
+		}
+	}
+
+	tgzFile := filepath.Join(tempDir, "backup.tgz")
+	if err := Tgz(tgzFile, srcDir, config.New()); err != nil {
+		t.Fatalf("TGZ压缩失败: %v", err)
+	}
+
+	if err := DeleteEntries(tgzFile, []string{"backup/config/secret.env"}, config.New()); err != nil {
+		t.Fatalf("删除条目失败: %v", err)
+	}
+	if err := RenameEntries(tgzFile, map[string]string{"backup": "release"}, config.New()); err != nil {
+		t.Fatalf("重命名条目失败: %v", err)
+	}
+
+	got := readTgzFiles(t, tgzFile)
+	if len(got) != 1 || got["release/main.go"] != "package main" {
+		t.Errorf("修改后的内容不匹配: %v", got)
+	}
+
+	// 重写后仍是标准的单成员 TGZ
+	if layout, err := scanTgz(tgzFile); err != nil || layout.appendable {
+		t.Errorf("重写后的TGZ不应包含单独的结束标记成员: err=%v", err)
+	}
+}
@@ -7,7 +7,8 @@
 //   - TGZ 压缩包完整文件列表获取
 //   - 限制数量的文件列表获取
 //   - 模式匹配的文件列表过滤
-//   - 多种文件类型支持（普通文件、目录、符号链接、硬链接）
+//   - 流式遍历条目，内存占用与条目数量无关
+//   - 多种文件类型支持（普通文件、目录、符号链接、硬链接、管道、设备）
 //   - 文件元数据完整保存
 //
 // 文件类型支持：
@@ -22,7 +23,9 @@
 //   - 修改时间
 //   - 文件权限模式
 //   - 文件类型标识
-//   - 符号链接目标
+//   - 符号链接目标和硬链接目标
+//   - 属主(UID/GID、用户名/组名)、访问和变更时间
+//   - PAX 扩展属性和条目头偏移
 //
 // 压缩特性：
 //   - 整体压缩：TGZ 对整个 TAR 归档进行压缩
@@ -39,13 +42,20 @@
 //
 //	// 获取匹配 *.go 模式的文件
 //	info, err := cxtgz.ListTgzMatch("archive.tar.gz", "*.go")
+//
+//	// 逐条遍历，返回 fs.SkipAll 提前结束
+//	err := cxtgz.WalkTgz("archive.tar.gz", func(file types.FileInfo) error {
+//	    fmt.Println(file.Name)
+//	    return nil
+//	})
 package cxtgz
 
 import (
-	"archive/tar"
 	"compress/gzip"
+	"errors"
 	"fmt"
 	"io"
+	"io/fs"
 	"os"
 
 	"gitee.com/MM-Q/comprx/internal/utils"
@@ -54,35 +64,42 @@ import (
 
 // ListTgz 获取TGZ压缩包的所有文件信息
 func ListTgz(archivePath string) (*types.ArchiveInfo, error) {
+	return listTgz(archivePath, 0, "")
+}
+
+// ListTgzLimit 获取TGZ压缩包指定数量的文件信息
+func ListTgzLimit(archivePath string, limit int) (*types.ArchiveInfo, error) {
+	return listTgz(archivePath, limit, "")
+}
+
+// ListTgzMatch 获取TGZ压缩包中匹配指定模式的文件信息
+func ListTgzMatch(archivePath string, pattern string) (*types.ArchiveInfo, error) {
+	return listTgz(archivePath, 0, pattern)
+}
+
+// listTgz 遍历TGZ压缩包并收集文件信息
+//
+// 参数:
+//   - archivePath: TGZ文件路径
+//   - limit: 最多返回的文件数量，小于等于 0 表示不限制
+//   - pattern: 文件名匹配模式，为空时返回全部文件
+//
+// 返回:
+//   - *types.ArchiveInfo: 压缩包信息
+//   - error: 错误信息
+func listTgz(archivePath string, limit int, pattern string) (*types.ArchiveInfo, error) {
 	// 确保路径为绝对路径
 	absPath, err := utils.EnsureAbsPath(archivePath, "TGZ文件路径")
 	if err != nil {
 		return nil, err
 	}
 
-	// 打开TGZ文件
-	file, err := os.Open(absPath)
-	if err != nil {
-		return nil, fmt.Errorf("打开TGZ文件失败: %w", err)
-	}
-	defer func() { _ = file.Close() }()
-
 	// 获取压缩包文件信息
-	stat, err := file.Stat()
+	stat, err := os.Stat(absPath)
 	if err != nil {
 		return nil, fmt.Errorf("获取TGZ文件信息失败: %w", err)
 	}
 
-	// 创建GZIP读取器
-	gzipReader, err := gzip.NewReader(file)
-	if err != nil {
-		return nil, fmt.Errorf("创建GZIP读取器失败: %w", err)
-	}
-	defer func() { _ = gzipReader.Close() }()
-
-	// 创建TAR读取器
-	tarReader := tar.NewReader(gzipReader)
-
 	// 根据文件名检测压缩格式类型
 	compressType, err := types.DetectCompressFormat(absPath)
 	if err != nil {
@@ -96,139 +113,64 @@ func ListTgz(archivePath string) (*types.ArchiveInfo, error) {
 		Files:          make([]types.FileInfo, 0, utils.DefaultFileCapacity),
 	}
 
-	// 遍历TAR文件中的每个条目
-	for {
-		header, err := tarReader.Next()
-		if err == io.EOF {
-			break
-		}
-		if err != nil {
-			return nil, fmt.Errorf("读取TGZ条目失败: %w", err)
-		}
-
-		fileInfo := types.FileInfo{
-			Name:           header.Name,
-			Size:           header.Size,
-			CompressedSize: 0, // TGZ整体压缩，单个文件压缩大小无法准确计算
-			ModTime:        header.ModTime,
-			Mode:           header.FileInfo().Mode(),
-			IsDir:          header.FileInfo().IsDir(),
-			IsSymlink:      header.Typeflag == tar.TypeSymlink || header.Typeflag == tar.TypeLink,
-		}
-
-		// 如果是符号链接，设置链接目标
-		if fileInfo.IsSymlink {
-			fileInfo.LinkTarget = header.Linkname
-		}
-
-		archiveInfo.Files = append(archiveInfo.Files, fileInfo)
-		archiveInfo.TotalSize += fileInfo.Size
-		archiveInfo.TotalFiles++
+	if err := WalkTgz(absPath, utils.CollectFiles(archiveInfo, limit, pattern)); err != nil {
+		return nil, err
 	}
-
 	return archiveInfo, nil
 }
 
-// ListTgzLimit 获取TGZ压缩包指定数量的文件信息
-func ListTgzLimit(archivePath string, limit int) (*types.ArchiveInfo, error) {
+// WalkTgz 逐条遍历TGZ压缩包中的条目
+//
+// 边解压边读取文件头，条目交给回调处理后即被丢弃，不会在内存中保留条目列表。
+//
+// 参数:
+//   - archivePath: TGZ文件路径
+//   - fn: 对每个条目调用的函数，返回 fs.SkipAll 时提前结束遍历，返回其他错误时中止并返回该错误
+//
+// 返回:
+//   - error: 错误信息
+func WalkTgz(archivePath string, fn func(types.FileInfo) error) error {
 	// 确保路径为绝对路径
 	absPath, err := utils.EnsureAbsPath(archivePath, "TGZ文件路径")
 	if err != nil {
-		return nil, err
+		return err
 	}
 
 	// 打开TGZ文件
 	file, err := os.Open(absPath)
 	if err != nil {
-		return nil, fmt.Errorf("打开TGZ文件失败: %w", err)
+		return fmt.Errorf("打开TGZ文件失败: %w", err)
 	}
 	defer func() { _ = file.Close() }()
 
-	// 获取压缩包文件信息
-	stat, err := file.Stat()
-	if err != nil {
-		return nil, fmt.Errorf("获取TGZ文件信息失败: %w", err)
-	}
-
 	// 创建GZIP读取器
 	gzipReader, err := gzip.NewReader(file)
 	if err != nil {
-		return nil, fmt.Errorf("创建GZIP读取器失败: %w", err)
+		return fmt.Errorf("创建GZIP读取器失败: %w", err)
 	}
 	defer func() { _ = gzipReader.Close() }()
 
 	// 创建TAR读取器
-	tarReader := tar.NewReader(gzipReader)
+	tarReader := utils.NewTarEntryReader(gzipReader)
 
-	// 根据文件名检测压缩格式类型
-	compressType, err := types.DetectCompressFormat(absPath)
-	if err != nil {
-		return nil, fmt.Errorf("检测压缩格式失败: %w", err)
-	}
-
-	// 创建 ArchiveInfo 结构体
-	archiveInfo := &types.ArchiveInfo{
-		Type:           compressType,
-		CompressedSize: stat.Size(),
-		Files:          make([]types.FileInfo, 0, utils.DefaultFileCapacity),
-	}
-
-	// 遍历TAR文件中的每个条目，但限制数量
-	count := 0
+	// 遍历TAR文件中的每个条目
 	for {
-		header, err := tarReader.Next()
+		header, offset, err := tarReader.Next()
 		if err == io.EOF {
-			break
+			return nil
 		}
 		if err != nil {
-			return nil, fmt.Errorf("读取TGZ条目失败: %w", err)
+			return fmt.Errorf("读取TGZ条目失败: %w", err)
 		}
 
-		// 达到限制数量就提前退出
-		if limit > 0 && count >= limit {
-			break
-		}
-
-		fileInfo := types.FileInfo{
-			Name:           header.Name,
-			Size:           header.Size,
-			CompressedSize: 0, // TGZ整体压缩，单个文件压缩大小无法准确计算
-			ModTime:        header.ModTime,
-			Mode:           header.FileInfo().Mode(),
-			IsDir:          header.FileInfo().IsDir(),
-			IsSymlink:      header.Typeflag == tar.TypeSymlink || header.Typeflag == tar.TypeLink,
-		}
+		fileInfo := utils.TarFileInfo(header, offset)
+		fileInfo.CompressedSize = 0 // TGZ整体压缩，单个文件压缩大小无法准确计算
 
-		// 如果是符号链接，设置链接目标
-		if fileInfo.IsSymlink {
-			fileInfo.LinkTarget = header.Linkname
+		if err := fn(fileInfo); err != nil {
+			if errors.Is(err, fs.SkipAll) {
+				return nil
+			}
+			return err
 		}
-
-		archiveInfo.Files = append(archiveInfo.Files, fileInfo)
-		archiveInfo.TotalSize += fileInfo.Size
-		count++
-	}
-
-	archiveInfo.TotalFiles = count
-	return archiveInfo, nil
-}
-
-// ListTgzMatch 获取TGZ压缩包中匹配指定模式的文件信息
-func ListTgzMatch(archivePath string, pattern string) (*types.ArchiveInfo, error) {
-	archiveInfo, err := ListTgz(archivePath)
-	if err != nil {
-		return nil, err
 	}
-
-	archiveInfo.Files = utils.FilterFilesByPattern(archiveInfo.Files, pattern)
-	archiveInfo.TotalFiles = len(archiveInfo.Files)
-
-	// 重新计算总大小
-	var totalSize int64
-	for _, file := range archiveInfo.Files {
-		totalSize += file.Size
-	}
-	archiveInfo.TotalSize = totalSize
-
-	return archiveInfo, nil
 }
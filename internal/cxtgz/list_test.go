@@ -365,3 +365,50 @@ func BenchmarkListTgz_Directory(b *testing.B) {
 		_, _ = ListTgz(tgzFile)
 	}
 }
+
+func TestListTgz_Metadata(t *testing.T) {
+	tempDir := t.TempDir()
+	srcDir := filepath.Join(tempDir, "src")
+	if err := os.MkdirAll(srcDir, 0755); err != nil {
+		t.Fatalf("创建目录失败: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(srcDir, "a.txt"), []byte(strings.Repeat("a", 600)), 0644); err != nil {
+		t.Fatalf("创建测试文件失败: %v", err)
+	}
+	if err := os.Symlink("a.txt", filepath.Join(srcDir, "b.txt")); err != nil {
+		t.Skipf("当前系统不支持符号链接: %v", err)
+	}
+
+	tgzFile := filepath.Join(tempDir, "src.tgz")
+	if err := Tgz(tgzFile, srcDir, config.New()); err != nil {
+		t.Fatalf("TGZ压缩失败: %v", err)
+	}
+
+	info, err := ListTgz(tgzFile)
+	if err != nil {
+		t.Fatalf("列出TGZ文件失败: %v", err)
+	}
+
+	// 偏移是解压后 TAR 流中的位置，每个条目头都按 512 字节对齐
+	offsets := map[string]int64{}
+	for _, file := range info.Files {
+		if file.HeaderOffset%512 != 0 {
+			t.Errorf("%s 的偏移未按块对齐: %d", file.Name, file.HeaderOffset)
+		}
+		offsets[file.Name] = file.HeaderOffset
+
+		switch file.Name {
+		case "src/":
+			if file.EntryType != types.EntryTypeDir {
+				t.Errorf("目录的条目类型 = %s", file.EntryType)
+			}
+		case "src/b.txt":
+			if file.EntryType != types.EntryTypeSymlink || file.LinkTarget != "a.txt" {
+				t.Errorf("符号链接信息不正确: %+v", file)
+			}
+		}
+	}
+	if a, b := offsets["src/a.txt"], offsets["src/b.txt"]; a < b && b-a != 512+1024 || len(offsets) != 3 {
+		t.Errorf("a.txt 之后的条目偏移应跳过两个数据块: %d -> %d", a, b)
+	}
+}
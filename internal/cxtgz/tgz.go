@@ -11,6 +11,8 @@
 //   - 文件过滤功能
 //   - 文件覆盖控制
 //   - 相对路径处理
+//   - 多源打包（任意路径映射与重复条目检测）
+//   - 确定性打包（固定 GZIP 文件头时间戳和操作系统标识）
 //
 // 压缩流程：
 //  1. 创建 GZIP 压缩流
@@ -46,13 +48,17 @@ import (
 	"archive/tar"
 	"compress/gzip"
 	"fmt"
-	"io/fs"
+	"io"
 	"os"
 	"path/filepath"
+	"time"
 
 	"gitee.com/MM-Q/comprx/internal/config"
+	"gitee.com/MM-Q/comprx/internal/cxtar"
+	"gitee.com/MM-Q/comprx/internal/envelope"
 	"gitee.com/MM-Q/comprx/internal/progress"
 	"gitee.com/MM-Q/comprx/internal/utils"
+	"gitee.com/MM-Q/comprx/types"
 )
 
 // Tgz 函数用于创建TGZ(tar.gz)压缩文件
@@ -65,19 +71,29 @@ import (
 // 返回值:
 //   - error: 操作过程中遇到的错误
 func Tgz(dst string, src string, cfg *config.Config) error {
+	return TgzSources(dst, []types.Source{{Path: src}}, cfg)
+}
+
+// TgzSources 函数用于将多个源压缩到同一个TGZ(tar.gz)文件
+//
+// 参数:
+//   - dst: 生成的TGZ文件路径
+//   - sources: 打包源列表，每个源可以映射到压缩包内的任意位置
+//   - cfg: 压缩配置指针
+//
+// 返回值:
+//   - error: 操作过程中遇到的错误（包括压缩包内条目重复）
+func TgzSources(dst string, sources []types.Source, cfg *config.Config) error {
 	// 确保路径为绝对路径
 	var absErr error
 	if dst, absErr = utils.EnsureAbsPath(dst, "TGZ文件路径"); absErr != nil {
 		return absErr
 	}
-	if src, absErr = utils.EnsureAbsPath(src, "源路径"); absErr != nil {
-		return absErr
-	}
 
-	// 检查源路径是文件还是目录
-	srcInfo, err := os.Stat(src)
+	// 解析并检查打包源列表
+	sources, err := utils.PrepareSources(sources)
 	if err != nil {
-		return fmt.Errorf("获取源路径信息失败: %w", err)
+		return err
 	}
 
 	// 检查目标文件是否已存在
@@ -94,7 +110,7 @@ func Tgz(dst string, src string, cfg *config.Config) error {
 	}
 
 	// 在进度条模式下计算源文件总大小
-	totalSize := progress.CalculateSourceTotalSizeWithProgress(src, cfg.Progress, "正在分析内容...", cfg.Filter)
+	totalSize := progress.CalculateSourcesTotalSizeWithProgress(sources, cfg.Progress, "正在分析内容...", cfg.Filter)
 
 	// 开始进度显示
 	if err := cfg.Progress.Start(totalSize, dst, fmt.Sprintf("正在压缩 %s...", filepath.Base(dst))); err != nil {
@@ -105,237 +121,78 @@ func Tgz(dst string, src string, cfg *config.Config) error {
 	}()
 
 	// 创建 TGZ 文件
-	tgzFile, err := os.Create(dst)
+	tgzFile, err := envelope.Create(dst, cfg.EncryptWith)
 	if err != nil {
 		return fmt.Errorf("创建 TGZ 文件失败: %w", err)
 	}
 	defer func() { _ = tgzFile.Close() }()
 
 	// 创建 GZIP 写入器
-	gzipWriter, err := gzip.NewWriterLevel(tgzFile, config.GetCompressionLevel(cfg.CompressionLevel))
+	gzipWriter, err := newGzipWriter(tgzFile, cfg)
 	if err != nil {
-		return fmt.Errorf("创建 GZIP 写入器失败: %w", err)
+		return err
 	}
-	defer func() { _ = gzipWriter.Close() }()
 
 	// 创建 TAR 写入器
 	tarWriter := tar.NewWriter(gzipWriter)
-	defer func() { _ = tarWriter.Close() }()
 
-	// 根据源路径类型处理
-	var tgzErr error
-	if srcInfo.IsDir() {
-		// 遍历目录并添加文件到 TGZ 包
-		tgzErr = walkDirectoryForTgz(src, tarWriter, cfg)
-	} else {
-		// 单文件处理逻辑 - 检查是否应该跳过
-		if cfg.Filter != nil && cfg.Filter.ShouldSkipByParams(src, srcInfo.Size(), srcInfo.IsDir()) {
-			// 文件被过滤器跳过，直接返回成功
-			return nil
-		}
-		cfg.Progress.Adding(src) // 添加文件到进度条
-		tgzErr = processRegularFile(tarWriter, src, filepath.Base(src), srcInfo, cfg)
+	// 写入所有打包源(TAR 条目的写入逻辑与 cxtar 共用)
+	if err := cxtar.WriteSources(tarWriter, sources, cfg); err != nil {
+		_ = gzipWriter.Close()
+		return fmt.Errorf("打包目录到 TGZ 失败: %w", err)
 	}
 
-	// 检查是否有错误发生
-	if tgzErr != nil {
-		return fmt.Errorf("打包目录到 TGZ 失败: %w", tgzErr)
+	// 结束 TGZ 压缩流
+	if err := closeTgz(tarWriter, gzipWriter); err != nil {
+		return err
 	}
-
-	return nil
-}
-
-// processRegularFile 处理普通文件
-//
-// 参数:
-//   - tarWriter: *tar.Writer - TAR 文件写入器
-//   - path: string - 文件路径
-//   - headerName: string - TAR 文件中的文件名
-//   - info: os.FileInfo - 文件信息
-//   - cfg: 压缩配置
-//
-// 返回值:
-//   - error - 操作过程中遇到的错误
-func processRegularFile(tarWriter *tar.Writer, path, headerName string, info os.FileInfo, cfg *config.Config) error {
-	// 创建文件头
-	header, err := tar.FileInfoHeader(info, "")
-	if err != nil {
-		return fmt.Errorf("处理文件 '%s' 时出错 - 创建 TAR 文件头失败: %w", path, err)
-	}
-	header.Name = headerName // 设置文件名
-
-	// 写入文件头
-	if err := tarWriter.WriteHeader(header); err != nil {
-		return fmt.Errorf("处理文件 '%s' 时出错 - 写入 TAR 文件头失败: %w", path, err)
+	if err := tgzFile.Close(); err != nil {
+		return fmt.Errorf("关闭 TGZ 文件失败: %w", err)
 	}
-
-	// 打开文件
-	file, err := os.Open(path)
-	if err != nil {
-		return fmt.Errorf("处理文件 '%s' 时出错 - 打开文件失败: %w", path, err)
-	}
-	defer func() { _ = file.Close() }()
-
-	// 获取文件大小
-	fileSize := info.Size()
-
-	// 获取缓冲区大小并创建缓冲区
-	bufferSize := utils.GetBufferSize(fileSize)
-	buffer := utils.GetBuffer(bufferSize)
-	defer utils.PutBuffer(buffer)
-
-	// 复制文件内容到TAR写入器
-	if _, err := cfg.Progress.CopyBuffer(tarWriter, file, buffer); err != nil {
-		return fmt.Errorf("处理文件 '%s' 时出错 - 写入 TAR 文件失败: %w", path, err)
-	}
-
 	return nil
 }
 
-// processDirectory 处理目录
+// closeTgz 结束 TGZ 压缩流
 //
-// 参数:
-//   - tarWriter: *tar.Writer - TAR 文件写入器
-//   - headerName: string - TAR 文件中的目录名
-//   - info: os.FileInfo - 目录信息
-//
-// 返回值:
-//   - error - 操作过程中遇到的错误
-func processDirectory(tarWriter *tar.Writer, headerName string, info os.FileInfo) error {
-	// 创建目录文件头
-	header, err := tar.FileInfoHeader(info, "")
-	if err != nil {
-		return fmt.Errorf("处理目录 '%s' 时出错 - 创建 TAR 文件头失败: %w", headerName, err)
-	}
-	// 设置目录名
-	header.Name = headerName + "/" // 目录名后添加斜杠
-
-	// 写入目录文件头
-	if err := tarWriter.WriteHeader(header); err != nil {
-		return fmt.Errorf("处理目录 '%s' 时出错 - 写入 TAR 目录头失败: %w", headerName, err)
-	}
-	return nil
-}
-
-// processSymlink 处理软链接
+// 依次写入 TAR 归档结束标记并关闭 GZIP 写入器，整个压缩包只有一个 GZIP 成员。
 //
 // 参数:
-//   - tarWriter: *tar.Writer - TAR 文件写入器
-//   - path: string - 软链接路径
-//   - headerName: string - TAR 文件中的软链接名
-//   - info: os.FileInfo - 文件信息
+//   - tarCloser: TAR 写入器（或构建器），关闭时写入归档结束标记
+//   - gzipWriter: TAR 条目所在的 GZIP 写入器
 //
 // 返回值:
-//   - error - 操作过程中遇到的错误
-func processSymlink(tarWriter *tar.Writer, path, headerName string, info os.FileInfo) error {
-	// 读取软链接目标
-	target, err := os.Readlink(path)
-	if err != nil {
-		return fmt.Errorf("处理软链接 '%s' 时出错 - 读取软链接目标失败: %w", path, err)
+//   - error: 写入失败时返回错误
+func closeTgz(tarCloser io.Closer, gzipWriter *gzip.Writer) error {
+	if err := tarCloser.Close(); err != nil {
+		_ = gzipWriter.Close()
+		return fmt.Errorf("写入 TAR 归档结束标记失败: %w", err)
 	}
-
-	// 创建软链接文件头
-	header, err := tar.FileInfoHeader(info, target)
-	if err != nil {
-		return fmt.Errorf("处理软链接 '%s' 时出错 - 创建 TAR 文件头失败: %w", path, err)
-	}
-	header.Name = headerName
-
-	// 写入软链接文件头
-	if err := tarWriter.WriteHeader(header); err != nil {
-		return fmt.Errorf("处理软链接 '%s' 时出错 - 写入 TAR 软链接头失败: %w", path, err)
+	if err := gzipWriter.Close(); err != nil {
+		return fmt.Errorf("关闭 GZIP 写入器失败: %w", err)
 	}
 	return nil
 }
 
-// processSpecialFile 处理特殊文件类型
+// newGzipWriter 创建 TGZ 使用的 GZIP 写入器
+//
+// 确定性模式下 GZIP 文件头的修改时间固定为 0（未知），操作系统标识固定为 255（未知）。
 //
 // 参数:
-//   - tarWriter: *tar.Writer - TAR 文件写入器
-//   - headerName: string - TAR 文件中的特殊文件名
-//   - info: os.FileInfo - 文件信息
+//   - w: 输出目标
+//   - cfg: 压缩配置
 //
 // 返回值:
-//   - error - 操作过程中遇到的错误
-func processSpecialFile(tarWriter *tar.Writer, headerName string, info os.FileInfo) error {
-	// 创建 TAR 文件头
-	header, err := tar.FileInfoHeader(info, "")
+//   - *gzip.Writer: GZIP 写入器
+//   - error: 创建失败时返回错误
+func newGzipWriter(w io.Writer, cfg *config.Config) (*gzip.Writer, error) {
+	gzipWriter, err := gzip.NewWriterLevel(w, config.GetCompressionLevel(cfg.CompressionLevel))
 	if err != nil {
-		return fmt.Errorf("处理特殊文件 '%s' 时出错 - 创建 TAR 文件头失败: %w", headerName, err)
+		return nil, fmt.Errorf("创建 GZIP 写入器失败: %w", err)
 	}
-	header.Name = headerName
 
-	// 写入 TAR 文件头
-	if err := tarWriter.WriteHeader(header); err != nil {
-		return fmt.Errorf("处理特殊文件 '%s' 时出错 - 写入 TAR 特殊文件头失败: %w", headerName, err)
+	if cfg.Deterministic {
+		gzipWriter.ModTime = time.Time{}
+		gzipWriter.OS = config.GzipHeaderOSUnknown
 	}
-	return nil
-}
-
-// walkDirectoryForTgz 遍历目录并处理文件到TGZ包
-//
-// 参数:
-//   - src: 源目录路径
-//   - tarWriter: TAR写入器
-//   - cfg: 压缩配置
-//
-// 返回值:
-//   - error: 遍历过程中发生的错误
-func walkDirectoryForTgz(src string, tarWriter *tar.Writer, cfg *config.Config) error {
-	return filepath.WalkDir(src, func(path string, entry fs.DirEntry, err error) error {
-		if err != nil {
-			// 如果不存在则忽略
-			if os.IsNotExist(err) {
-				return nil
-			}
-			// 其他错误
-			return fmt.Errorf("遍历路径 '%s' 时出错: %w", path, err)
-		}
-
-		// 获取文件信息用于过滤检查
-		info, err := entry.Info()
-		if err != nil {
-			return fmt.Errorf("处理路径 '%s' 时出错 - 获取文件信息失败: %w", path, err)
-		}
-
-		// 应用过滤器检查
-		if cfg.Filter != nil && cfg.Filter.ShouldSkipByParams(path, info.Size(), info.IsDir()) {
-			if info.IsDir() {
-				return filepath.SkipDir // 跳过整个目录
-			}
-			return nil // 跳过文件
-		}
-
-		// 获取相对路径，保留顶层目录
-		headerName, err := filepath.Rel(filepath.Dir(src), path)
-		if err != nil {
-			return fmt.Errorf("处理路径 '%s' 时出错 - 获取相对路径失败: %w", path, err)
-		}
-
-		// 替换路径分隔符为正斜杠(TAR 文件格式要求)
-		headerName = filepath.ToSlash(headerName)
-
-		// 根据文件类型处理
-		switch {
-		// 处理普通文件
-		case entry.Type().IsRegular():
-			cfg.Progress.Adding(headerName) // 更新进度
-			return processRegularFile(tarWriter, path, headerName, info, cfg)
-
-		// 处理目录
-		case entry.IsDir():
-			cfg.Progress.Storing(headerName) // 更新进度
-			return processDirectory(tarWriter, headerName, info)
-
-		// 处理符号链接
-		case entry.Type()&os.ModeSymlink != 0:
-			cfg.Progress.Adding(headerName) // 更新进度
-			return processSymlink(tarWriter, path, headerName, info)
-
-		// 处理特殊文件
-		default:
-			cfg.Progress.Adding(headerName) // 更新进度
-			return processSpecialFile(tarWriter, headerName, info)
-		}
-	})
+	return gzipWriter, nil
 }
@@ -1,11 +1,14 @@
 package cxtgz
 
 import (
+	"bytes"
+	"encoding/binary"
 	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"gitee.com/MM-Q/comprx/internal/config"
 	"gitee.com/MM-Q/comprx/types"
@@ -273,3 +276,87 @@ func BenchmarkTgz_Directory(b *testing.B) {
 		_ = Tgz(tgzFile, testDir, cfg)
 	}
 }
+
+func TestTgzSources_RoundTrip(t *testing.T) {
+	tempDir := t.TempDir()
+
+	files := map[string]string{
+		"bin/app":       "binary",
+		"docs/guide.md": "guide",
+		"VERSION":       "1.2.0",
+	}
+	for name, content := range files {
+		filePath := filepath.Join(tempDir, "src", name)
+		if err := os.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
+			t.Fatalf("创建目录失败: %v", err)
+		}
+		if err := os.WriteFile(filePath, []byte(content), 0644); err != nil {
+			t.Fatalf("创建文件失败: %v", err)
+		}
+	}
+
+	tgzFile := filepath.Join(tempDir, "app-1.2.tgz")
+	sources := []types.Source{
+		{Path: filepath.Join(tempDir, "src", "bin"), ArchivePath: "app-1.2/bin"},
+		{Path: filepath.Join(tempDir, "src", "docs"), ArchivePath: "app-1.2/docs"},
+		{Path: filepath.Join(tempDir, "src", "VERSION"), ArchivePath: "app-1.2/VERSION"},
+	}
+	if err := TgzSources(tgzFile, sources, config.New()); err != nil {
+		t.Fatalf("TgzSources 失败: %v", err)
+	}
+
+	// 解压并验证内容
+	extractDir := filepath.Join(tempDir, "out")
+	if err := Untgz(tgzFile, extractDir, config.New()); err != nil {
+		t.Fatalf("解压失败: %v", err)
+	}
+	for name, content := range files {
+		data, err := os.ReadFile(filepath.Join(extractDir, "app-1.2", name))
+		if err != nil {
+			t.Errorf("读取解压文件 %s 失败: %v", name, err)
+			continue
+		}
+		if string(data) != content {
+			t.Errorf("文件 %s 内容不匹配: 期望 %q, 实际 %q", name, content, string(data))
+		}
+	}
+}
+
+func TestTgz_Deterministic(t *testing.T) {
+	tempDir := t.TempDir()
+	srcDir := filepath.Join(tempDir, "src")
+	if err := os.MkdirAll(filepath.Join(srcDir, "sub"), 0700); err != nil {
+		t.Fatalf("创建测试目录失败: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(srcDir, "sub", "file.txt"), []byte("content"), 0640); err != nil {
+		t.Fatalf("创建测试文件失败: %v", err)
+	}
+
+	cfg := config.New()
+	cfg.Deterministic = true
+	cfg.SourceDateEpoch = time.Unix(1700000000, 0).UTC()
+
+	first := filepath.Join(tempDir, "first.tgz")
+	if err := Tgz(first, srcDir, cfg); err != nil {
+		t.Fatalf("第一次打包失败: %v", err)
+	}
+	future := time.Now().Add(time.Hour)
+	if err := os.Chtimes(filepath.Join(srcDir, "sub"), future, future); err != nil {
+		t.Fatalf("修改时间戳失败: %v", err)
+	}
+	second := filepath.Join(tempDir, "second.tgz")
+	if err := Tgz(second, srcDir, cfg); err != nil {
+		t.Fatalf("第二次打包失败: %v", err)
+	}
+
+	firstData, _ := os.ReadFile(first)
+	secondData, _ := os.ReadFile(second)
+	if !bytes.Equal(firstData, secondData) {
+		t.Fatal("确定性模式下两次打包结果应逐字节相同")
+	}
+
+	// GZIP 文件头: MTIME(4-7 字节) 为 0，OS(第 9 字节) 为 255
+	if binary.LittleEndian.Uint32(firstData[4:8]) != 0 || firstData[9] != config.GzipHeaderOSUnknown {
+		t.Errorf("GZIP 文件头未固定: mtime=%d os=%d", binary.LittleEndian.Uint32(firstData[4:8]), firstData[9])
+	}
+}
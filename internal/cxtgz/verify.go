@@ -0,0 +1,61 @@
+// Package cxtgz 提供 TGZ 压缩包的完整性校验功能实现。
+//
+// 该文件实现了 TGZ 压缩包的完整性校验：边解压边校验 TAR 条目，
+// 最后读完整个 GZIP 流以校验每个成员尾部的 CRC32 和原始大小，不会写入任何文件。
+//
+// 主要功能：
+//   - 校验 GZIP 成员的 CRC32 和大小
+//   - 校验 TAR 文件头校验和并发现截断的条目
+//
+// 使用示例：
+//
+//	report, err := cxtgz.Verify("backup.tgz", nil, "")
+package cxtgz
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+
+	"gitee.com/MM-Q/comprx/internal/cxtar"
+	"gitee.com/MM-Q/comprx/internal/envelope"
+	"gitee.com/MM-Q/comprx/types"
+)
+
+// Verify 校验 TGZ 压缩包的完整性
+//
+// 参数:
+//   - archivePath: TGZ 压缩包路径
+//   - filter: 过滤器，为 nil 时校验全部条目
+//   - passphrase: 加密信封的口令，文件不是加密信封时忽略
+//
+// 返回值:
+//   - *types.VerifyReport: 校验报告，损坏的条目记录在 Failures 中
+//   - error: 无法打开文件、加密信封未提供口令或口令错误时返回错误
+func Verify(archivePath string, filter *types.FilterOptions, passphrase string) (*types.VerifyReport, error) {
+	file, err := envelope.Open(archivePath, passphrase)
+	if err != nil {
+		return nil, fmt.Errorf("打开 TGZ 文件失败: %w", err)
+	}
+	defer func() { _ = file.Close() }()
+
+	report := &types.VerifyReport{Type: types.CompressTypeTgz}
+
+	gzipReader, err := gzip.NewReader(file)
+	if err != nil {
+		report.AddFailure("", fmt.Errorf("读取 GZIP 文件头失败: %w", err))
+		return report, nil
+	}
+	defer func() { _ = gzipReader.Close() }()
+
+	cxtar.VerifyEntries(gzipReader, report, filter)
+	if !report.OK() {
+		return report, nil
+	}
+
+	// 读完剩余数据，校验 GZIP 尾部
+	if _, err := io.Copy(io.Discard, gzipReader); err != nil {
+		report.AddFailure("", fmt.Errorf("GZIP 数据校验失败: %w", err))
+	}
+	return report, nil
+}
@@ -0,0 +1,557 @@
+// Package cxzip 提供 WinZip AES 加密 ZIP 条目的读写实现。
+//
+// 该文件实现了 WinZip AES 加密规范（AE-1/AE-2）：使用 PBKDF2-HMAC-SHA1 从密码派生密钥，
+// AES-CTR（小端计数器）加密压缩后的数据，并以截断到 10 字节的 HMAC-SHA1 认证密文。
+// 写入时固定使用 AES-256 和 AE-2（不写入 CRC32，由认证码保证完整性），
+// 读取时支持 AES-128/192/256 以及 AE-1 和 AE-2。
+//
+// 条目数据布局：
+//   - 盐值（AES-128/192/256 分别为 8/12/16 字节）
+//   - 2 字节密码校验值
+//   - 加密后的压缩数据
+//   - 10 字节认证码
+//
+// 主要功能：
+//   - 创建 AES-256 加密条目
+//   - 打开已加密条目并校验密码和认证码
+package cxzip
+
+import (
+	"archive/zip"
+	"bytes"
+	"compress/flate"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha1"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash"
+	"hash/crc32"
+	"io"
+
+	"gitee.com/MM-Q/comprx/internal/config"
+	"gitee.com/MM-Q/comprx/types"
+)
+
+const (
+	// zipFlagEncrypted 通用标志位中表示条目已加密的位
+	zipFlagEncrypted = 0x1
+	// zipFlagDataDescriptor 通用标志位中表示大小和 CRC32 写在数据描述符中的位
+	zipFlagDataDescriptor = 0x8
+
+	// methodWinZipAES WinZip AES 加密条目的压缩方法编号
+	methodWinZipAES = 99
+	// winZipAESExtraID WinZip AES 额外字段的标识
+	winZipAESExtraID = 0x9901
+	// extTimeExtraID 扩展时间戳额外字段的标识
+	extTimeExtraID = 0x5455
+
+	aesVersionAE1 = 1 // AE-1: 保留 CRC32
+	aesVersionAE2 = 2 // AE-2: CRC32 固定为 0
+
+	aesStrength256      = 3    // AES-256 的强度编号
+	aesVerifierLen      = 2    // 密码校验值长度
+	aesAuthCodeLen      = 10   // 认证码长度
+	aesKeyIterations    = 1000 // PBKDF2 迭代次数
+	zipVersionWinZipAES = 51   // 读取 AES 加密条目所需的最低版本(5.1)
+)
+
+// nopWriteCloser 为不需要收尾的写入器提供空的 Close 方法
+type nopWriteCloser struct {
+	io.Writer
+}
+
+// Close 不做任何操作
+func (nopWriteCloser) Close() error { return nil }
+
+// createFileHeader 创建普通文件条目，按配置决定是否加密
+//
+// 参数:
+//   - zipWriter: ZIP 写入器
+//   - header: ZIP 文件头
+//   - cfg: 压缩配置
+//
+// 返回:
+//   - io.WriteCloser: 条目内容写入器，写完内容后必须调用 Close 完成条目
+//   - error: 创建失败时返回错误
+func createFileHeader(zipWriter *zip.Writer, header *zip.FileHeader, cfg *config.Config) (io.WriteCloser, error) {
+	if cfg.Encryption != types.EncryptionAES256 {
+		w, err := createHeader(zipWriter, header, cfg)
+		if err != nil {
+			return nil, err
+		}
+		return nopWriteCloser{w}, nil
+	}
+	return createAESHeader(zipWriter, header, cfg)
+}
+
+// createAESHeader 创建 WinZip AES-256 (AE-2) 加密条目
+//
+// 条目以原始模式写入，大小在写完内容后才能确定，因此设置数据描述符标志位，
+// 由 Close 回填文件头中的大小，archive/zip 在下一个条目开始时写出数据描述符。
+//
+// 参数:
+//   - zipWriter: ZIP 写入器
+//   - header: ZIP 文件头，Method 为实际使用的压缩方法
+//   - cfg: 压缩配置
+//
+// 返回:
+//   - io.WriteCloser: 条目内容写入器
+//   - error: 未设置密码或创建失败时返回错误
+func createAESHeader(zipWriter *zip.Writer, header *zip.FileHeader, cfg *config.Config) (io.WriteCloser, error) {
+	if cfg.Password == "" {
+		return nil, fmt.Errorf("加密条目 %s 失败: %w", header.Name, types.ErrPasswordRequired)
+	}
+	method := header.Method
+	if method != zip.Store && method != zip.Deflate {
+		return nil, fmt.Errorf("加密条目 %s 失败: 不支持的压缩方法 %d", header.Name, method)
+	}
+
+	// 原始模式不会处理修改时间和文件名编码，这里按 CreateHeader 的方式补齐
+	normalizeHeader(header, cfg)
+	applyEntryComment(header, cfg)
+	prepareRawHeader(header)
+
+	header.Method = methodWinZipAES
+	header.Flags |= zipFlagEncrypted | zipFlagDataDescriptor
+	header.CreatorVersion = header.CreatorVersion&0xff00 | zipVersionWinZipAES
+	header.ReaderVersion = zipVersionWinZipAES
+	header.CRC32 = 0 // AE-2 不写入 CRC32
+	header.CompressedSize64 = 0
+	header.UncompressedSize64 = 0
+	header.Extra = append(header.Extra, aesExtraField(method)...)
+
+	// 生成盐值并派生密钥
+	salt := make([]byte, aesSaltLen(aesStrength256))
+	if _, err := rand.Read(salt); err != nil {
+		return nil, fmt.Errorf("生成盐值失败: %w", err)
+	}
+	encKey, authKey, verifier, err := deriveAESKeys(cfg.Password, salt, aesKeyLen(aesStrength256))
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(encKey)
+	if err != nil {
+		return nil, fmt.Errorf("创建 AES 加密器失败: %w", err)
+	}
+
+	raw, err := zipWriter.CreateRaw(header)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := raw.Write(append(salt, verifier...)); err != nil {
+		return nil, err
+	}
+
+	w := &aesWriter{
+		header: header,
+		raw:    raw,
+		stream: newWinZipCTR(block),
+		mac:    hmac.New(sha1.New, authKey),
+		count:  int64(len(salt) + aesVerifierLen),
+	}
+	if method == zip.Deflate {
+		fw, err := flate.NewWriter(&w.encrypter, config.GetCompressionLevel(cfg.CompressionLevel))
+		if err != nil {
+			return nil, fmt.Errorf("创建 DEFLATE 压缩器失败: %w", err)
+		}
+		w.comp = fw
+	} else {
+		w.comp = nopWriteCloser{&w.encrypter}
+	}
+	w.encrypter.w = w
+	return w, nil
+}
+
+// aesWriter WinZip AES 加密条目写入器
+//
+// 写入的明文先经过压缩器，压缩后的数据由 encrypter 加密并计算认证码后写入 ZIP。
+type aesWriter struct {
+	header    *zip.FileHeader
+	raw       io.Writer      // 原始条目写入器
+	comp      io.WriteCloser // 压缩器
+	encrypter aesEncrypter   // 加密压缩后的数据
+	stream    cipher.Stream  // AES-CTR 密钥流
+	mac       hash.Hash      // HMAC-SHA1 认证码
+	size      int64          // 未压缩大小
+	count     int64          // 已写入的原始数据大小
+	closed    bool
+}
+
+// Write 写入明文
+func (w *aesWriter) Write(p []byte) (int, error) {
+	n, err := w.comp.Write(p)
+	w.size += int64(n)
+	return n, err
+}
+
+// Close 结束压缩、写入认证码并回填文件头中的大小
+func (w *aesWriter) Close() error {
+	if w.closed {
+		return nil
+	}
+	w.closed = true
+
+	if err := w.comp.Close(); err != nil {
+		return err
+	}
+	if _, err := w.raw.Write(w.mac.Sum(nil)[:aesAuthCodeLen]); err != nil {
+		return err
+	}
+	w.count += aesAuthCodeLen
+
+	w.header.CompressedSize64 = uint64(w.count)
+	w.header.UncompressedSize64 = uint64(w.size)
+	w.header.CompressedSize = uint32(min(w.header.CompressedSize64, uint64(^uint32(0))))
+	w.header.UncompressedSize = uint32(min(w.header.UncompressedSize64, uint64(^uint32(0))))
+	return nil
+}
+
+// aesEncrypter 加密压缩后的数据并写入原始条目
+type aesEncrypter struct {
+	w   *aesWriter
+	buf []byte
+}
+
+// Write 加密数据并计算认证码
+func (e *aesEncrypter) Write(p []byte) (int, error) {
+	if cap(e.buf) < len(p) {
+		e.buf = make([]byte, len(p))
+	}
+	buf := e.buf[:len(p)]
+	e.w.stream.XORKeyStream(buf, p)
+	e.w.mac.Write(buf)
+	n, err := e.w.raw.Write(buf)
+	e.w.count += int64(n)
+	return n, err
+}
+
+// openFile 打开条目内容，已加密的条目使用密码解密
+//
+// 压缩方法为 99 的条目按 WinZip AES 解密，其余加密条目按传统 PKWARE 加密（ZipCrypto）解密。
+//
+// 参数:
+//   - file: ZIP 条目
+//   - password: 解密密码
+//
+// 返回:
+//   - io.ReadCloser: 解压后的条目内容
+//   - error: 缺少密码或密码错误时返回错误
+func openFile(file *zip.File, password string) (io.ReadCloser, error) {
+	if file.Flags&zipFlagEncrypted == 0 {
+		return file.Open()
+	}
+	if password == "" {
+		return nil, types.EntryError{Name: file.Name, Err: types.ErrPasswordRequired}
+	}
+	if file.Method == methodWinZipAES {
+		return openAESFile(file, password)
+	}
+	return openZipCryptoFile(file, password)
+}
+
+// openAESFile 打开 WinZip AES 加密条目
+//
+// 参数:
+//   - file: 使用 WinZip AES 加密的 ZIP 条目
+//   - password: 解密密码
+//
+// 返回:
+//   - io.ReadCloser: 解压后的条目内容，读到末尾时校验认证码
+//   - error: 密码错误或条目格式错误时返回错误
+func openAESFile(file *zip.File, password string) (io.ReadCloser, error) {
+	version, strength, method, err := parseAESExtra(file.Extra)
+	if err != nil {
+		return nil, fmt.Errorf("条目 %s 的 AES 额外字段无效: %w", file.Name, err)
+	}
+
+	saltLen := aesSaltLen(strength)
+	dataLen := int64(file.CompressedSize64) - int64(saltLen+aesVerifierLen+aesAuthCodeLen)
+	if dataLen < 0 {
+		return nil, fmt.Errorf("条目 %s 的加密数据被截断", file.Name)
+	}
+
+	raw, err := file.OpenRaw()
+	if err != nil {
+		return nil, err
+	}
+
+	// 读取盐值和密码校验值
+	prefix := make([]byte, saltLen+aesVerifierLen)
+	if _, err := io.ReadFull(raw, prefix); err != nil {
+		return nil, fmt.Errorf("读取条目 %s 的加密头失败: %w", file.Name, err)
+	}
+	encKey, authKey, verifier, err := deriveAESKeys(password, prefix[:saltLen], aesKeyLen(strength))
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(verifier, prefix[saltLen:]) {
+		return nil, types.EntryError{Name: file.Name, Err: types.ErrWrongPassword}
+	}
+
+	block, err := aes.NewCipher(encKey)
+	if err != nil {
+		return nil, fmt.Errorf("创建 AES 解密器失败: %w", err)
+	}
+	decrypted := &aesReader{
+		name:   file.Name,
+		r:      io.LimitReader(raw, dataLen),
+		raw:    raw,
+		stream: newWinZipCTR(block),
+		mac:    hmac.New(sha1.New, authKey),
+	}
+
+	// 解压
+	var rc io.ReadCloser
+	switch method {
+	case zip.Store:
+		rc = io.NopCloser(decrypted)
+	case zip.Deflate:
+		rc = flate.NewReader(decrypted)
+	default:
+		return nil, fmt.Errorf("条目 %s 使用了不支持的压缩方法: %d", file.Name, method)
+	}
+
+	// AE-1 保留了 CRC32，需要额外校验
+	if version == aesVersionAE1 {
+		rc = &crcReader{ReadCloser: rc, name: file.Name, want: file.CRC32, hash: crc32.NewIEEE()}
+	}
+	return &authReadCloser{ReadCloser: rc, data: decrypted}, nil
+}
+
+// authReadCloser 解压结束时读完剩余的加密数据，确保认证码得到校验
+//
+// DEFLATE 解压器读到结束块后不会继续读取底层数据，认证码只有在加密数据被读完时才会校验。
+type authReadCloser struct {
+	io.ReadCloser
+	data *aesReader
+}
+
+// Read 读取解压后的数据
+func (r *authReadCloser) Read(p []byte) (int, error) {
+	n, err := r.ReadCloser.Read(p)
+	if err == io.EOF {
+		if _, drainErr := io.Copy(io.Discard, r.data); drainErr != nil {
+			return n, drainErr
+		}
+	}
+	return n, err
+}
+
+// aesReader 解密 WinZip AES 条目数据，读到末尾时校验认证码
+type aesReader struct {
+	name   string
+	r      io.Reader // 加密数据
+	raw    io.Reader // 原始条目数据，用于读取末尾的认证码
+	stream cipher.Stream
+	mac    hash.Hash
+	err    error
+}
+
+// Read 读取并解密数据
+func (r *aesReader) Read(p []byte) (int, error) {
+	if r.err != nil {
+		return 0, r.err
+	}
+
+	n, err := r.r.Read(p)
+	r.mac.Write(p[:n])
+	r.stream.XORKeyStream(p[:n], p[:n])
+
+	if err == io.EOF {
+		r.err = r.checkAuthCode()
+		err = r.err
+	} else if err != nil {
+		r.err = err
+	}
+	return n, err
+}
+
+// checkAuthCode 校验认证码
+//
+// 返回:
+//   - error: 认证码一致时返回 io.EOF，否则返回数据损坏错误
+func (r *aesReader) checkAuthCode() error {
+	code := make([]byte, aesAuthCodeLen)
+	if _, err := io.ReadFull(r.raw, code); err != nil {
+		return fmt.Errorf("读取条目 %s 的认证码失败: %w", r.name, err)
+	}
+	if !hmac.Equal(code, r.mac.Sum(nil)[:aesAuthCodeLen]) {
+		return fmt.Errorf("条目 %s 认证失败，数据已损坏或被篡改: %w", r.name, zip.ErrChecksum)
+	}
+	return io.EOF
+}
+
+// crcReader 读到末尾时校验 CRC32
+type crcReader struct {
+	io.ReadCloser
+	name string
+	want uint32
+	hash hash.Hash32
+}
+
+// Read 读取数据并计算 CRC32
+func (r *crcReader) Read(p []byte) (int, error) {
+	n, err := r.ReadCloser.Read(p)
+	r.hash.Write(p[:n])
+	if errors.Is(err, io.EOF) && r.hash.Sum32() != r.want {
+		return n, fmt.Errorf("条目 %s 的 CRC32 校验失败: %w", r.name, zip.ErrChecksum)
+	}
+	return n, err
+}
+
+// deriveAESKeys 使用 PBKDF2-HMAC-SHA1 从密码派生加密密钥、认证密钥和密码校验值
+//
+// 参数:
+//   - password: 密码
+//   - salt: 盐值
+//   - keyLen: AES 密钥长度
+//
+// 返回:
+//   - []byte: AES 加密密钥
+//   - []byte: HMAC-SHA1 认证密钥
+//   - []byte: 2 字节密码校验值
+//   - error: 派生失败时返回错误
+func deriveAESKeys(password string, salt []byte, keyLen int) ([]byte, []byte, []byte, error) {
+	key, err := pbkdf2.Key(sha1.New, password, salt, aesKeyIterations, 2*keyLen+aesVerifierLen)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("派生密钥失败: %w", err)
+	}
+	return key[:keyLen], key[keyLen : 2*keyLen], key[2*keyLen:], nil
+}
+
+// aesExtraField 生成 WinZip AES-256 (AE-2) 额外字段
+//
+// 参数:
+//   - method: 实际使用的压缩方法
+//
+// 返回:
+//   - []byte: 额外字段数据
+func aesExtraField(method uint16) []byte {
+	buf := make([]byte, 11)
+	binary.LittleEndian.PutUint16(buf[0:], winZipAESExtraID)
+	binary.LittleEndian.PutUint16(buf[2:], 7)
+	binary.LittleEndian.PutUint16(buf[4:], aesVersionAE2)
+	copy(buf[6:], "AE")
+	buf[8] = aesStrength256
+	binary.LittleEndian.PutUint16(buf[9:], method)
+	return buf
+}
+
+// parseAESExtra 从额外字段中解析 WinZip AES 参数
+//
+// 参数:
+//   - extra: 条目的额外字段
+//
+// 返回:
+//   - uint16: AE 版本
+//   - byte: 密钥强度（1/2/3 分别对应 AES-128/192/256）
+//   - uint16: 实际使用的压缩方法
+//   - error: 找不到或格式错误时返回错误
+func parseAESExtra(extra []byte) (uint16, byte, uint16, error) {
+	for len(extra) >= 4 {
+		id := binary.LittleEndian.Uint16(extra[0:])
+		size := int(binary.LittleEndian.Uint16(extra[2:]))
+		if len(extra) < 4+size {
+			break
+		}
+		data := extra[4 : 4+size]
+		extra = extra[4+size:]
+		if id != winZipAESExtraID {
+			continue
+		}
+
+		if size < 7 || string(data[2:4]) != "AE" {
+			return 0, 0, 0, fmt.Errorf("格式错误")
+		}
+		version := binary.LittleEndian.Uint16(data[0:])
+		strength := data[4]
+		if version != aesVersionAE1 && version != aesVersionAE2 {
+			return 0, 0, 0, fmt.Errorf("不支持的 AE 版本: %d", version)
+		}
+		if aesKeyLen(strength) == 0 {
+			return 0, 0, 0, fmt.Errorf("不支持的密钥强度: %d", strength)
+		}
+		return version, strength, binary.LittleEndian.Uint16(data[5:]), nil
+	}
+	return 0, 0, 0, fmt.Errorf("缺少 AES 额外字段")
+}
+
+// aesKeyLen 返回密钥强度对应的 AES 密钥长度
+func aesKeyLen(strength byte) int {
+	switch strength {
+	case 1:
+		return 16
+	case 2:
+		return 24
+	case 3:
+		return 32
+	default:
+		return 0
+	}
+}
+
+// aesSaltLen 返回密钥强度对应的盐值长度
+func aesSaltLen(strength byte) int {
+	return aesKeyLen(strength) / 2
+}
+
+// winZipCTR WinZip AES 使用的 CTR 模式
+//
+// 与 cipher.NewCTR 不同，计数器按小端序递增，且从 1 开始。
+type winZipCTR struct {
+	block     cipher.Block
+	counter   [aes.BlockSize]byte
+	keystream [aes.BlockSize]byte
+	pos       int
+}
+
+// newWinZipCTR 创建 WinZip AES CTR 密钥流
+func newWinZipCTR(block cipher.Block) *winZipCTR {
+	return &winZipCTR{block: block, pos: aes.BlockSize}
+}
+
+// XORKeyStream 使用密钥流异或数据
+func (c *winZipCTR) XORKeyStream(dst, src []byte) {
+	for i := range src {
+		if c.pos == aes.BlockSize {
+			for j := range c.counter {
+				c.counter[j]++
+				if c.counter[j] != 0 {
+					break
+				}
+			}
+			c.block.Encrypt(c.keystream[:], c.counter[:])
+			c.pos = 0
+		}
+		dst[i] = src[i] ^ c.keystream[c.pos]
+		c.pos++
+	}
+}
+
+// prepareRawHeader 补齐原始模式条目的修改时间和文件名编码标志
+//
+// 与 zip.Writer.CreateHeader 的处理保持一致：Modified 非零时写入 MS-DOS 时间
+// 和扩展时间戳额外字段，文件名需要 UTF-8 编码时设置 UTF-8 标志位。
+//
+// 参数:
+//   - header: ZIP 文件头
+func prepareRawHeader(header *zip.FileHeader) {
+	markUTF8(header)
+
+	if header.Modified.IsZero() {
+		return
+	}
+	header.ModifiedDate, header.ModifiedTime = msDosTime(header.Modified)
+
+	extra := make([]byte, 9)
+	binary.LittleEndian.PutUint16(extra[0:], extTimeExtraID)
+	binary.LittleEndian.PutUint16(extra[2:], 5)
+	extra[4] = 1 // 只包含修改时间
+	binary.LittleEndian.PutUint32(extra[5:], uint32(header.Modified.Unix()))
+	header.Extra = append(header.Extra, extra...)
+}
@@ -0,0 +1,212 @@
+package cxzip
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gitee.com/MM-Q/comprx/internal/config"
+	"gitee.com/MM-Q/comprx/types"
+)
+
+// libarchiveAESZip 由 libarchive 生成的 AES-256 (AE-1) 加密 ZIP，
+// 密码为 "secret"，包含 msg.txt: "hello from libarchive\n"
+const libarchiveAESZip = "504b03041400090063005c91525d00000000000000000000000007002b00" +
+	"6d73672e74787475780b0001040000000004000000000199070001004145" +
+	"03080055540d0007b00bd56ab00bd56ab00bd56a7b4d664594514b3266b3" +
+	"92d0daf0e1abb5530379d914186e6c59e92bd2b5024789b470d89281b5bd" +
+	"12136ed4ec959e54d1ba689a504b0708717d4c113400000016000000504b" +
+	"010214031400090063005c91525d717d4c11340000001600000007002300" +
+	"0000000000000000a481000000006d73672e74787475780b000104000000" +
+	"00040000000001990700010041450308005554050001b00bd56a504b0506" +
+	"000000000100010058000000940000000000"
+
+// packEncryptedZip 使用 AES-256 加密打包测试目录
+func packEncryptedZip(t *testing.T, cfg *config.Config) (string, map[string]string) {
+	t.Helper()
+	tempDir := t.TempDir()
+	srcDir := filepath.Join(tempDir, "src")
+	files := map[string]string{
+		"a.txt":      strings.Repeat("encrypted content ", 500),
+		"empty.txt":  "",
+		"sub/数据.txt": "unicode name",
+	}
+	for name, content := range files {
+		path := filepath.Join(srcDir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("创建目录失败: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("创建测试文件失败: %v", err)
+		}
+	}
+
+	zipFile := filepath.Join(tempDir, "secret.zip")
+	if err := Zip(zipFile, srcDir, cfg); err != nil {
+		t.Fatalf("加密打包失败: %v", err)
+	}
+	return zipFile, files
+}
+
+func TestZip_AES256RoundTrip(t *testing.T) {
+	for _, level := range []types.CompressionLevel{types.CompressionLevelDefault, types.CompressionLevelNone} {
+		cfg := config.New()
+		cfg.Progress.Enabled = false
+		cfg.CompressionLevel = level
+		cfg.Password = "correct horse"
+		cfg.Encryption = types.EncryptionAES256
+
+		zipFile, files := packEncryptedZip(t, cfg)
+
+		// 普通文件使用 WinZip AES 加密，不包含明文
+		data, err := os.ReadFile(zipFile)
+		if err != nil {
+			t.Fatalf("读取ZIP文件失败: %v", err)
+		}
+		if bytes.Contains(data, []byte("encrypted content")) || bytes.Contains(data, []byte("unicode name")) {
+			t.Error("加密后的ZIP中不应包含明文")
+		}
+		reader, err := zip.OpenReader(zipFile)
+		if err != nil {
+			t.Fatalf("打开ZIP文件失败: %v", err)
+		}
+		for _, file := range reader.File {
+			if file.Mode().IsDir() {
+				continue
+			}
+			if file.Method != methodWinZipAES || file.Flags&zipFlagEncrypted == 0 {
+				t.Errorf("条目 %s 未加密: method=%d flags=%#x", file.Name, file.Method, file.Flags)
+			}
+		}
+		_ = reader.Close()
+
+		// 使用正确的密码解压
+		outDir := filepath.Join(t.TempDir(), "out")
+		if err := Unzip(zipFile, outDir, cfg); err != nil {
+			t.Fatalf("解压失败: %v", err)
+		}
+		for name, content := range files {
+			got, err := os.ReadFile(filepath.Join(outDir, "src", name))
+			if err != nil || string(got) != content {
+				t.Errorf("%s 内容不正确: %v", name, err)
+			}
+		}
+
+		// 列表中标记为已加密
+		info, err := ListZip(zipFile)
+		if err != nil {
+			t.Fatalf("列出ZIP失败: %v", err)
+		}
+		for _, file := range info.Files {
+			if file.Name == "src/a.txt" && (!file.Encrypted || file.Size != int64(len(files["a.txt"]))) {
+				t.Errorf("列表信息不正确: %+v", file)
+			}
+		}
+	}
+}
+
+func TestUnzip_AESPasswordErrors(t *testing.T) {
+	cfg := config.New()
+	cfg.Progress.Enabled = false
+	cfg.Password = "right"
+	cfg.Encryption = types.EncryptionAES256
+	zipFile, _ := packEncryptedZip(t, cfg)
+
+	// 密码错误
+	wrong := config.New()
+	wrong.Progress.Enabled = false
+	wrong.Password = "wrong"
+	err := Unzip(zipFile, t.TempDir(), wrong)
+	if !errors.Is(err, types.ErrWrongPassword) {
+		t.Errorf("密码错误时应返回 ErrWrongPassword: %v", err)
+	}
+	var entryErr types.EntryError
+	if !errors.As(err, &entryErr) || !strings.HasPrefix(entryErr.Name, "src/") || strings.Count(err.Error(), entryErr.Name) != 1 {
+		t.Errorf("错误中应只包含一次条目名称: %v", err)
+	}
+
+	// 未提供密码
+	none := config.New()
+	none.Progress.Enabled = false
+	if err := Unzip(zipFile, t.TempDir(), none); !errors.Is(err, types.ErrPasswordRequired) {
+		t.Errorf("未提供密码时应返回 ErrPasswordRequired: %v", err)
+	}
+
+	// 完整性校验同样需要密码
+	report, err := Verify(zipFile, nil, "wrong", "")
+	if err != nil || report.OK() {
+		t.Fatalf("密码错误时校验应失败: %+v, %v", report, err)
+	}
+	for _, failure := range report.Failures {
+		if !errors.Is(failure, types.ErrWrongPassword) || strings.Count(failure.Error(), failure.Name) != 1 {
+			t.Errorf("校验错误中条目名称应只出现一次: %v", failure)
+		}
+	}
+	if report, err := Verify(zipFile, nil, "right", ""); err != nil || !report.OK() {
+		t.Errorf("密码正确时校验应通过: %+v, %v", report, err)
+	}
+}
+
+func TestUnzip_AESTampered(t *testing.T) {
+	cfg := config.New()
+	cfg.Progress.Enabled = false
+	cfg.CompressionLevel = types.CompressionLevelNone
+	cfg.Password = "pw"
+	cfg.Encryption = types.EncryptionAES256
+	zipFile, _ := packEncryptedZip(t, cfg)
+
+	// 篡改 a.txt 的密文(位于盐值和密码校验值之后)
+	reader, err := zip.OpenReader(zipFile)
+	if err != nil {
+		t.Fatalf("打开ZIP文件失败: %v", err)
+	}
+	var offset int64
+	for _, file := range reader.File {
+		if file.Name == "src/a.txt" {
+			offset, _ = file.DataOffset()
+		}
+	}
+	_ = reader.Close()
+
+	data, err := os.ReadFile(zipFile)
+	if err != nil {
+		t.Fatalf("读取ZIP文件失败: %v", err)
+	}
+	data[offset+16+2+100] ^= 0x01
+	if err := os.WriteFile(zipFile, data, 0644); err != nil {
+		t.Fatalf("写入ZIP文件失败: %v", err)
+	}
+
+	err = Unzip(zipFile, t.TempDir(), cfg)
+	if !errors.Is(err, zip.ErrChecksum) {
+		t.Errorf("密文被篡改时应认证失败: %v", err)
+	}
+}
+
+func TestUnzip_AESFromLibarchive(t *testing.T) {
+	data, err := hex.DecodeString(libarchiveAESZip)
+	if err != nil {
+		t.Fatalf("解码测试数据失败: %v", err)
+	}
+	zipFile := filepath.Join(t.TempDir(), "ext.zip")
+	if err := os.WriteFile(zipFile, data, 0644); err != nil {
+		t.Fatalf("写入ZIP文件失败: %v", err)
+	}
+
+	cfg := config.New()
+	cfg.Progress.Enabled = false
+	cfg.Password = "secret"
+	outDir := t.TempDir()
+	if err := Unzip(zipFile, outDir, cfg); err != nil {
+		t.Fatalf("解压 libarchive 生成的加密ZIP失败: %v", err)
+	}
+	got, err := os.ReadFile(filepath.Join(outDir, "msg.txt"))
+	if err != nil || string(got) != "hello from libarchive\n" {
+		t.Errorf("解密内容不正确: %q, %v", got, err)
+	}
+}
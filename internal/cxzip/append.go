@@ -0,0 +1,170 @@
+// Package cxzip 提供向已有 ZIP 压缩包追加条目的功能实现。
+//
+// 该文件实现了 ZIP 压缩包的追加操作。新条目先打包到临时压缩包中，
+// 再与已有条目一起以原始(已压缩)形式复制到新文件并重写中央目录，
+// 整个过程不会解压或重新压缩任何条目，最后原子地替换原文件。
+//
+// 主要功能：
+//   - 向已有 ZIP 压缩包追加文件或目录
+//   - 按覆盖策略处理同名条目
+//   - 保留压缩包注释
+//
+// 使用示例：
+//
+//	err := cxzip.Append("logs.zip", "app-10.log", cfg)
+package cxzip
+
+import (
+	"archive/zip"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"gitee.com/MM-Q/comprx/internal/config"
+	"gitee.com/MM-Q/comprx/internal/utils"
+	"gitee.com/MM-Q/comprx/types"
+)
+
+// Append 向已有的 ZIP 压缩包追加文件或目录
+//
+// 参数:
+//   - archivePath: 已有的 ZIP 压缩包路径
+//   - src: 需要追加的源路径，以其基本名称放在压缩包根目录下
+//   - cfg: 压缩配置，OverwriteExisting 决定同名条目的处理方式
+//
+// 返回值:
+//   - error: 操作过程中遇到的错误
+func Append(archivePath, src string, cfg *config.Config) error {
+	// 确保路径为绝对路径
+	var absErr error
+	if archivePath, absErr = utils.EnsureAbsPath(archivePath, "ZIP文件路径"); absErr != nil {
+		return absErr
+	}
+
+	// 在临时目录中将新条目打包为独立的 ZIP 压缩包
+	scratchPath, cleanup, err := utils.NewScratchPath(filepath.Base(archivePath))
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+	if err := ZipSources(scratchPath, []types.Source{{Path: src}}, cfg.ForScratch()); err != nil {
+		return err
+	}
+
+	// 打开已有压缩包和新条目
+	existingReader, err := zip.OpenReader(archivePath)
+	if err != nil {
+		return fmt.Errorf("打开 ZIP 文件失败: %w", err)
+	}
+	defer func() { _ = existingReader.Close() }()
+
+	addedReader, err := zip.OpenReader(scratchPath)
+	if err != nil {
+		return fmt.Errorf("打开临时 ZIP 文件失败: %w", err)
+	}
+	defer func() { _ = addedReader.Close() }()
+
+	// 根据覆盖策略处理同名条目
+	added := entryKinds(addedReader.File)
+	plan, err := utils.PlanAppend(entryKinds(existingReader.File), added, cfg.OverwriteExisting)
+	if err != nil {
+		return err
+	}
+
+	// 原样复制已有条目和新条目，并重写中央目录
+	return utils.ReplaceFile(archivePath, func(f *os.File) error {
+		zipWriter := zip.NewWriter(f)
+		if err := copyRawEntries(zipWriter, existingReader.File, skipManifests(plan.Replaces, cfg)); err != nil {
+			return err
+		}
+		if err := copyRawEntries(zipWriter, addedReader.File, plan.Skips); err != nil {
+			return err
+		}
+		// 已有的校验清单替换为合并新条目摘要后的清单
+		if err := writeManifests(zipWriter, added, cfg); err != nil {
+			return err
+		}
+		// 未配置新注释时保留原有的压缩包注释
+		comment := existingReader.Comment
+		if cfg.ArchiveComment != "" {
+			comment = cfg.ArchiveComment
+		}
+		if err := setArchiveComment(zipWriter, comment); err != nil {
+			return err
+		}
+		if err := zipWriter.Close(); err != nil {
+			return fmt.Errorf("关闭 ZIP 写入器失败: %w", err)
+		}
+		return nil
+	})
+}
+
+// copyRawEntries 以原始(已压缩)形式复制 ZIP 条目
+//
+// 参数:
+//   - zipWriter: 目标 ZIP 写入器
+//   - files: 源 ZIP 条目列表
+//   - skip: 返回 true 的条目不复制，为 nil 时复制全部条目
+//
+// 返回值:
+//   - error: 复制失败时返回错误
+func copyRawEntries(zipWriter *zip.Writer, files []*zip.File, skip func(name string) bool) error {
+	for _, file := range files {
+		if skip != nil && skip(file.Name) {
+			continue
+		}
+		if err := zipWriter.Copy(file); err != nil {
+			return fmt.Errorf("复制 ZIP 条目 '%s' 失败: %w", file.Name, err)
+		}
+	}
+	return nil
+}
+
+// skipManifests 在跳过规则的基础上跳过压缩包内的校验清单
+//
+// 参数:
+//   - skip: 原有的跳过规则
+//   - cfg: 压缩配置
+//
+// 返回值:
+//   - func(name string) bool: 返回 true 的条目不复制
+func skipManifests(skip func(name string) bool, cfg *config.Config) func(name string) bool {
+	if !cfg.Checksums.Embedded() {
+		return skip
+	}
+	return func(name string) bool {
+		return skip(name) || cfg.Checksums.IsManifest(name)
+	}
+}
+
+// writeManifests 在追加后的压缩包中写入合并了新条目摘要的校验清单
+//
+// 参数:
+//   - zipWriter: 目标 ZIP 写入器
+//   - added: 新追加的条目，用于检测与清单同名的条目
+//   - cfg: 压缩配置
+//
+// 返回值:
+//   - error: 写入失败时返回错误
+func writeManifests(zipWriter *zip.Writer, added utils.EntryKinds, cfg *config.Config) error {
+	names := utils.NewEntryNameSet()
+	for name := range added {
+		_ = names.Add(name) // 条目名称已去重，不会返回错误
+	}
+	return cfg.Checksums.WriteManifests(newBuilder(zipWriter, cfg, names))
+}
+
+// entryKinds 收集 ZIP 条目的名称及是否为目录
+//
+// 参数:
+//   - files: ZIP 条目列表
+//
+// 返回值:
+//   - utils.EntryKinds: 条目名称及是否为目录
+func entryKinds(files []*zip.File) utils.EntryKinds {
+	entries := make(utils.EntryKinds, len(files))
+	for _, file := range files {
+		entries.Add(file.Name, file.FileInfo().IsDir())
+	}
+	return entries
+}
@@ -0,0 +1,117 @@
+package cxzip
+
+import (
+	"archive/zip"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gitee.com/MM-Q/comprx/internal/config"
+)
+
+func TestAppend_RawCopy(t *testing.T) {
+	tempDir := t.TempDir()
+
+	first := filepath.Join(tempDir, "app-09.log")
+	second := filepath.Join(tempDir, "app-10.log")
+	if err := os.WriteFile(first, []byte(strings.Repeat("09", 100)), 0644); err != nil {
+		t.Fatalf("创建测试文件失败: %v", err)
+	}
+	if err := os.WriteFile(second, []byte("10"), 0644); err != nil {
+		t.Fatalf("创建测试文件失败: %v", err)
+	}
+
+	zipFile := filepath.Join(tempDir, "logs.zip")
+	if err := Zip(zipFile, first, config.New()); err != nil {
+		t.Fatalf("ZIP压缩失败: %v", err)
+	}
+
+	// 记录已有条目的文件头
+	reader, err := zip.OpenReader(zipFile)
+	if err != nil {
+		t.Fatalf("打开ZIP文件失败: %v", err)
+	}
+	original := reader.File[0].FileHeader
+	_ = reader.Close()
+
+	if err := Append(zipFile, second, config.New()); err != nil {
+		t.Fatalf("追加失败: %v", err)
+	}
+
+	reader, err = zip.OpenReader(zipFile)
+	if err != nil {
+		t.Fatalf("打开ZIP文件失败: %v", err)
+	}
+	defer func() { _ = reader.Close() }()
+
+	if len(reader.File) != 2 {
+		t.Fatalf("条目数量不匹配: 期望 2, 实际 %d", len(reader.File))
+	}
+
+	// 已有条目以原始形式复制，压缩数据保持不变
+	copied := reader.File[0]
+	if copied.Name != original.Name || copied.CRC32 != original.CRC32 || copied.CompressedSize64 != original.CompressedSize64 {
+		t.Errorf("已有条目被修改: %+v", copied.FileHeader)
+	}
+
+	rc, err := reader.File[1].Open()
+	if err != nil {
+		t.Fatalf("打开追加的条目失败: %v", err)
+	}
+	data, _ := io.ReadAll(rc)
+	_ = rc.Close()
+	if reader.File[1].Name != "app-10.log" || string(data) != "10" {
+		t.Errorf("追加的条目不匹配: %s=%q", reader.File[1].Name, string(data))
+	}
+}
+
+func TestAppend_Overwrite(t *testing.T) {
+	tempDir := t.TempDir()
+
+	src := filepath.Join(tempDir, "app.log")
+	if err := os.WriteFile(src, []byte("old"), 0644); err != nil {
+		t.Fatalf("创建测试文件失败: %v", err)
+	}
+	zipFile := filepath.Join(tempDir, "logs.zip")
+	if err := Zip(zipFile, src, config.New()); err != nil {
+		t.Fatalf("ZIP压缩失败: %v", err)
+	}
+
+	if err := os.WriteFile(src, []byte("new"), 0644); err != nil {
+		t.Fatalf("更新测试文件失败: %v", err)
+	}
+
+	// 不允许覆盖时返回错误
+	err := Append(zipFile, src, config.New())
+	if err == nil || !strings.Contains(err.Error(), "已存在条目") {
+		t.Fatalf("期望同名条目冲突错误, 实际: %v", err)
+	}
+
+	// 允许覆盖时替换已有条目
+	cfg := config.New()
+	cfg.OverwriteExisting = true
+	if err := Append(zipFile, src, cfg); err != nil {
+		t.Fatalf("覆盖追加失败: %v", err)
+	}
+
+	reader, err := zip.OpenReader(zipFile)
+	if err != nil {
+		t.Fatalf("打开ZIP文件失败: %v", err)
+	}
+	defer func() { _ = reader.Close() }()
+
+	if len(reader.File) != 1 {
+		t.Fatalf("条目数量不匹配: 期望 1, 实际 %d", len(reader.File))
+	}
+	rc, err := reader.File[0].Open()
+	if err != nil {
+		t.Fatalf("打开条目失败: %v", err)
+	}
+	data, _ := io.ReadAll(rc)
+	_ = rc.Close()
+	if string(data) != "new" {
+		t.Errorf("覆盖后的内容不匹配: 期望 new, 实际 %q", string(data))
+	}
+}
@@ -0,0 +1,220 @@
+// Package cxzip 提供 ZIP 格式的增量构建功能实现。
+//
+// 该文件实现了 ZIP 压缩包构建器，支持逐个添加文件、目录和符号链接，
+// 条目内容可来自任意 io.Reader，无需先在磁盘上准备源文件。
+//
+// 主要类型：
+//   - Builder: ZIP 压缩包构建器
+//
+// 使用示例：
+//
+//	builder := cxzip.NewBuilder(w, cfg)
+//	err := builder.AddFile("docs/readme.txt", strings.NewReader("hello"), types.FileInfo{})
+//	err = builder.Close()
+package cxzip
+
+import (
+	"archive/zip"
+	"fmt"
+	"io"
+	"io/fs"
+	"time"
+
+	"gitee.com/MM-Q/comprx/internal/config"
+	"gitee.com/MM-Q/comprx/internal/utils"
+	"gitee.com/MM-Q/comprx/types"
+)
+
+// Builder ZIP 压缩包构建器
+//
+// 构建器会标准化条目名称并检测重复条目，使用完毕后必须调用 Close 写入中央目录。
+type Builder struct {
+	zipWriter *zip.Writer        // ZIP 写入器
+	cfg       *config.Config     // 压缩配置
+	names     utils.EntryNameSet // 已写入的条目名称集合
+}
+
+// NewBuilder 创建写入到 w 的 ZIP 压缩包构建器
+//
+// 参数:
+//   - w: 压缩包输出目标
+//   - cfg: 压缩配置
+//
+// 返回:
+//   - *Builder: ZIP 压缩包构建器
+func NewBuilder(w io.Writer, cfg *config.Config) *Builder {
+	return newBuilder(zip.NewWriter(w), cfg, utils.NewEntryNameSet())
+}
+
+// newBuilder 基于已有的 ZIP 写入器创建构建器
+//
+// 参数:
+//   - zipWriter: ZIP 写入器
+//   - cfg: 压缩配置
+//   - names: 已写入的条目名称集合，与其他打包源共享以检测重复条目
+//
+// 返回:
+//   - *Builder: ZIP 压缩包构建器
+func newBuilder(zipWriter *zip.Writer, cfg *config.Config, names utils.EntryNameSet) *Builder {
+	return &Builder{zipWriter: zipWriter, cfg: cfg, names: names}
+}
+
+// AddFile 添加普通文件
+//
+// 参数:
+//   - name: 压缩包内的文件名
+//   - r: 文件内容
+//   - info: 条目信息，Mode 为 0 时使用 0644，ModTime 为零值时使用当前时间
+//
+// 返回:
+//   - error: 名称不合法、条目重复或写入失败时返回错误
+func (b *Builder) AddFile(name string, r io.Reader, info types.FileInfo) error {
+	headerName, err := b.entryName(name)
+	if err != nil {
+		return err
+	}
+
+	header := newHeader(headerName, info, 0644)
+	header.Method = getCompressionMethod(b.cfg) // 使用配置的压缩方法
+
+	// 创建 ZIP 写入器(按配置加密)
+	b.cfg.Progress.Adding(headerName)
+	fileWriter, err := createFileHeader(b.zipWriter, header, b.cfg)
+	if err != nil {
+		return fmt.Errorf("处理文件 '%s' 时出错 - 创建 ZIP 写入器失败: %w", headerName, err)
+	}
+
+	// 获取缓冲区大小并创建缓冲区
+	buffer := utils.GetBuffer(utils.GetBufferSize(info.Size))
+	defer utils.PutBuffer(buffer)
+
+	// 复制文件内容到ZIP写入器，同时计算摘要
+	sumWriter, commit := b.cfg.Checksums.Writer(headerName, fileWriter)
+	if _, err := b.cfg.Progress.CopyBuffer(sumWriter, r, buffer); err != nil {
+		return fmt.Errorf("处理文件 '%s' 时出错 - 写入 ZIP 文件失败: %w", headerName, err)
+	}
+	if err := fileWriter.Close(); err != nil {
+		return fmt.Errorf("处理文件 '%s' 时出错 - 完成 ZIP 条目失败: %w", headerName, err)
+	}
+	commit()
+	return nil
+}
+
+// AddDir 添加目录
+//
+// 参数:
+//   - name: 压缩包内的目录名
+//   - info: 条目信息，Mode 为 0 时使用 0755，ModTime 为零值时使用当前时间
+//
+// 返回:
+//   - error: 名称不合法、条目重复或写入失败时返回错误
+func (b *Builder) AddDir(name string, info types.FileInfo) error {
+	headerName, err := b.entryName(name)
+	if err != nil {
+		return err
+	}
+
+	header := newHeader(headerName+"/", info, fs.ModeDir|0755) // 目录名后添加斜杠
+	header.Method = zip.Store                                  // 使用不压缩的方法
+
+	b.cfg.Progress.Storing(headerName)
+	if _, err := createHeader(b.zipWriter, header, b.cfg); err != nil {
+		return fmt.Errorf("处理目录 '%s' 时出错 - 创建 ZIP 目录失败: %w", headerName, err)
+	}
+	return nil
+}
+
+// AddSymlink 添加符号链接
+//
+// 参数:
+//   - name: 压缩包内的链接名
+//   - target: 链接目标
+//   - info: 条目信息，ModTime 为零值时使用当前时间
+//
+// 返回:
+//   - error: 名称不合法、条目重复或写入失败时返回错误
+func (b *Builder) AddSymlink(name, target string, info types.FileInfo) error {
+	headerName, err := b.entryName(name)
+	if err != nil {
+		return err
+	}
+
+	header := newHeader(headerName, info, fs.ModeSymlink|0777)
+	header.Method = zip.Store
+
+	b.cfg.Progress.Adding(headerName)
+	writer, err := createHeader(b.zipWriter, header, b.cfg)
+	if err != nil {
+		return fmt.Errorf("处理软链接 '%s' 时出错 - 创建 ZIP 软链接失败: %w", headerName, err)
+	}
+	if _, err := writer.Write([]byte(target)); err != nil {
+		return fmt.Errorf("处理软链接 '%s' 时出错 - 写入软链接目标失败: %w", headerName, err)
+	}
+	return nil
+}
+
+// Close 写入中央目录并关闭 ZIP 写入器
+//
+// 注意: 不会关闭底层的输出目标。
+//
+// 返回:
+//   - error: 关闭失败时返回错误
+func (b *Builder) Close() error {
+	if err := setArchiveComment(b.zipWriter, b.cfg.ArchiveComment); err != nil {
+		return err
+	}
+	if err := b.zipWriter.Close(); err != nil {
+		return fmt.Errorf("关闭 ZIP 写入器失败: %w", err)
+	}
+	return nil
+}
+
+// entryName 标准化条目名称并检测重复条目
+//
+// 参数:
+//   - name: 压缩包内的条目名称
+//
+// 返回:
+//   - string: 标准化后的条目名称
+//   - error: 名称不合法或条目重复时返回错误
+func (b *Builder) entryName(name string) (string, error) {
+	headerName, err := utils.NormalizeArchivePath(name)
+	if err != nil {
+		return "", err
+	}
+	if headerName == "." {
+		return "", fmt.Errorf("条目名称不能是压缩包根目录: %s", name)
+	}
+	if err := b.names.Add(headerName); err != nil {
+		return "", err
+	}
+	return headerName, nil
+}
+
+// newHeader 根据条目信息创建 ZIP 文件头
+//
+// 参数:
+//   - name: 压缩包内的条目名称
+//   - info: 条目信息
+//   - defaultMode: 条目类型位及权限为 0 时使用的默认权限
+//
+// 返回:
+//   - *zip.FileHeader: ZIP 文件头
+func newHeader(name string, info types.FileInfo, defaultMode fs.FileMode) *zip.FileHeader {
+	perm := info.Mode.Perm()
+	if perm == 0 {
+		perm = defaultMode.Perm()
+	}
+	modTime := info.ModTime
+	if modTime.IsZero() {
+		modTime = time.Now()
+	}
+
+	header := &zip.FileHeader{
+		Name:     name,
+		Modified: modTime,
+		Comment:  info.Comment,
+	}
+	header.SetMode(defaultMode.Type() | perm)
+	return header
+}
@@ -0,0 +1,119 @@
+package cxzip
+
+import (
+	"archive/zip"
+	"bytes"
+	"io"
+	"io/fs"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"gitee.com/MM-Q/comprx/internal/config"
+	"gitee.com/MM-Q/comprx/types"
+)
+
+func TestBuilder_AddEntries(t *testing.T) {
+	var buf bytes.Buffer
+	builder := NewBuilder(&buf, config.New())
+
+	if err := builder.AddDir("docs", types.FileInfo{}); err != nil {
+		t.Fatalf("添加目录失败: %v", err)
+	}
+	if err := builder.AddFile("docs/readme.txt", strings.NewReader("hello"), types.FileInfo{Mode: 0600}); err != nil {
+		t.Fatalf("添加文件失败: %v", err)
+	}
+	if err := builder.AddSymlink("latest", "docs/readme.txt", types.FileInfo{}); err != nil {
+		t.Fatalf("添加软链接失败: %v", err)
+	}
+	if err := builder.AddFile("docs/readme.txt", strings.NewReader("again"), types.FileInfo{}); err == nil {
+		t.Error("期望重复条目返回错误")
+	}
+	if err := builder.Close(); err != nil {
+		t.Fatalf("关闭构建器失败: %v", err)
+	}
+
+	reader, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatalf("读取ZIP失败: %v", err)
+	}
+	if len(reader.File) != 3 {
+		t.Fatalf("期望 3 个条目, 实际 %d 个", len(reader.File))
+	}
+
+	entries := make(map[string]*zip.File)
+	for _, f := range reader.File {
+		entries[f.Name] = f
+	}
+	if f, ok := entries["docs/"]; !ok || !f.FileInfo().IsDir() {
+		t.Error("缺少目录条目 docs/")
+	}
+	file, ok := entries["docs/readme.txt"]
+	if !ok {
+		t.Fatal("缺少文件条目 docs/readme.txt")
+	}
+	if file.Mode().Perm() != 0600 {
+		t.Errorf("文件权限不匹配: 期望 0600, 实际 %o", file.Mode().Perm())
+	}
+	rc, err := file.Open()
+	if err != nil {
+		t.Fatalf("打开文件条目失败: %v", err)
+	}
+	data, _ := io.ReadAll(rc)
+	_ = rc.Close()
+	if string(data) != "hello" {
+		t.Errorf("文件内容不匹配: 期望 hello, 实际 %s", string(data))
+	}
+	link, ok := entries["latest"]
+	if !ok {
+		t.Fatal("缺少软链接条目 latest")
+	}
+	if link.Mode()&fs.ModeSymlink == 0 {
+		t.Errorf("条目 latest 应为软链接, 实际模式 %v", link.Mode())
+	}
+}
+
+func TestBuilder_InvalidName(t *testing.T) {
+	builder := NewBuilder(io.Discard, config.New())
+	defer func() { _ = builder.Close() }()
+
+	for _, name := range []string{"", "/", "../evil.txt"} {
+		if err := builder.AddFile(name, strings.NewReader("x"), types.FileInfo{}); err == nil {
+			t.Errorf("期望名称 %q 返回错误", name)
+		}
+	}
+}
+
+func TestZipSources_FS(t *testing.T) {
+	fsys := fstest.MapFS{
+		"static/index.html":  {Data: []byte("<html></html>"), Mode: 0644},
+		"static/css/app.css": {Data: []byte("body{}"), Mode: 0644},
+		"VERSION":            {Data: []byte("1.2"), Mode: 0644},
+	}
+
+	zipPath := filepath.Join(t.TempDir(), "fs.zip")
+	sources := []types.Source{
+		{FS: fsys, Path: "static", ArchivePath: "app/static"},
+		{FS: fsys, Path: "VERSION", ArchivePath: "app/VERSION"},
+	}
+	if err := ZipSources(zipPath, sources, config.New()); err != nil {
+		t.Fatalf("从 fs.FS 打包失败: %v", err)
+	}
+
+	reader, err := zip.OpenReader(zipPath)
+	if err != nil {
+		t.Fatalf("打开ZIP失败: %v", err)
+	}
+	defer func() { _ = reader.Close() }()
+
+	got := make(map[string]bool)
+	for _, f := range reader.File {
+		got[f.Name] = true
+	}
+	for _, want := range []string{"app/static/", "app/static/index.html", "app/static/css/", "app/static/css/app.css", "app/VERSION"} {
+		if !got[want] {
+			t.Errorf("缺少条目: %s", want)
+		}
+	}
+}
@@ -0,0 +1,55 @@
+// Package cxzip 提供 ZIP 压缩包注释和条目注释的功能实现。
+//
+// ZIP 格式允许在中央目录末尾记录一段压缩包注释，并为每个条目记录单独的注释，
+// 构建系统常用来写入提交号和构建地址，解压工具会显示这些注释。
+//
+// 主要功能：
+//   - 按配置为条目设置注释
+//   - 写入压缩包注释
+package cxzip
+
+import (
+	"archive/zip"
+	"fmt"
+	"strings"
+
+	"gitee.com/MM-Q/comprx/internal/config"
+)
+
+// applyEntryComment 按配置设置条目注释
+//
+// 目录条目可以使用带或不带结尾 "/" 的名称配置注释。
+// 配置中没有该条目时保留文件头中已有的注释。
+//
+// 参数:
+//   - header: ZIP 文件头
+//   - cfg: 压缩配置
+func applyEntryComment(header *zip.FileHeader, cfg *config.Config) {
+	if len(cfg.EntryComments) == 0 {
+		return
+	}
+	if comment, ok := cfg.EntryComments[header.Name]; ok {
+		header.Comment = comment
+		return
+	}
+	if name := strings.TrimSuffix(header.Name, "/"); name != header.Name {
+		if comment, ok := cfg.EntryComments[name]; ok {
+			header.Comment = comment
+		}
+	}
+}
+
+// setArchiveComment 写入压缩包注释
+//
+// 参数:
+//   - zipWriter: ZIP 写入器，需在 Close 之前调用
+//   - comment: 压缩包注释
+//
+// 返回:
+//   - error: 注释超过 65535 字节时返回错误
+func setArchiveComment(zipWriter *zip.Writer, comment string) error {
+	if err := zipWriter.SetComment(comment); err != nil {
+		return fmt.Errorf("设置 ZIP 注释失败: %w", err)
+	}
+	return nil
+}
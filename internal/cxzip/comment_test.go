@@ -0,0 +1,110 @@
+package cxzip
+
+import (
+	"archive/zip"
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gitee.com/MM-Q/comprx/internal/config"
+	"gitee.com/MM-Q/comprx/types"
+)
+
+func TestZip_Comments(t *testing.T) {
+	tempDir := t.TempDir()
+	srcDir := filepath.Join(tempDir, "app")
+	if err := os.MkdirAll(filepath.Join(srcDir, "conf"), 0755); err != nil {
+		t.Fatalf("创建目录失败: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(srcDir, "conf", "app.yaml"), []byte("port: 80"), 0644); err != nil {
+		t.Fatalf("创建测试文件失败: %v", err)
+	}
+
+	cfg := config.New()
+	cfg.ArchiveComment = "commit 1a2b3c4\nhttps://ci.example.com/build/42"
+	cfg.EntryComments = map[string]string{
+		"app/conf/app.yaml": "生产环境配置",
+		"app/conf":          "配置目录",
+	}
+
+	zipFile := filepath.Join(tempDir, "app.zip")
+	if err := Zip(zipFile, srcDir, cfg); err != nil {
+		t.Fatalf("压缩失败: %v", err)
+	}
+
+	info, err := ListZip(zipFile)
+	if err != nil {
+		t.Fatalf("列出ZIP内容失败: %v", err)
+	}
+	if info.Comment != cfg.ArchiveComment {
+		t.Errorf("压缩包注释不正确: %q", info.Comment)
+	}
+	comments := map[string]string{}
+	for _, file := range info.Files {
+		comments[file.Name] = file.Comment
+	}
+	if comments["app/conf/app.yaml"] != "生产环境配置" || comments["app/conf/"] != "配置目录" || comments["app/"] != "" {
+		t.Errorf("条目注释不正确: %v", comments)
+	}
+
+	// 追加条目时保留原有注释
+	extra := filepath.Join(tempDir, "README")
+	if err := os.WriteFile(extra, []byte("readme"), 0644); err != nil {
+		t.Fatalf("创建测试文件失败: %v", err)
+	}
+	if err := Append(zipFile, extra, config.New()); err != nil {
+		t.Fatalf("追加条目失败: %v", err)
+	}
+	if info, err := ListZip(zipFile); err != nil || info.Comment != cfg.ArchiveComment {
+		t.Errorf("追加后压缩包注释应保留: %+v, %v", info, err)
+	}
+
+	// 删除条目时保留原有注释
+	if err := DeleteEntries(zipFile, []string{"README"}); err != nil {
+		t.Fatalf("删除条目失败: %v", err)
+	}
+	if info, err := ListZip(zipFile); err != nil || info.Comment != cfg.ArchiveComment {
+		t.Errorf("删除后压缩包注释应保留: %+v, %v", info, err)
+	}
+}
+
+func TestBuilder_Comments(t *testing.T) {
+	cfg := config.New()
+	cfg.ArchiveComment = "built by builder"
+
+	var buf bytes.Buffer
+	builder := NewBuilder(&buf, cfg)
+	if err := builder.AddFile("a.txt", strings.NewReader("a"), types.FileInfo{Comment: "第一个文件"}); err != nil {
+		t.Fatalf("添加文件失败: %v", err)
+	}
+	if err := builder.Close(); err != nil {
+		t.Fatalf("关闭构建器失败: %v", err)
+	}
+
+	reader, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatalf("读取ZIP失败: %v", err)
+	}
+	if reader.Comment != "built by builder" || reader.File[0].Comment != "第一个文件" {
+		t.Errorf("注释不正确: %q, %q", reader.Comment, reader.File[0].Comment)
+	}
+	if reader.File[0].Flags&zipFlagUTF8 == 0 {
+		t.Error("非 ASCII 注释应设置 UTF-8 标志位")
+	}
+}
+
+func TestZip_CommentTooLong(t *testing.T) {
+	tempDir := t.TempDir()
+	src := filepath.Join(tempDir, "a.txt")
+	if err := os.WriteFile(src, []byte("a"), 0644); err != nil {
+		t.Fatalf("创建测试文件失败: %v", err)
+	}
+
+	cfg := config.New()
+	cfg.ArchiveComment = strings.Repeat("x", 1<<16)
+	if err := Zip(filepath.Join(tempDir, "a.zip"), src, cfg); err == nil {
+		t.Error("注释超过 65535 字节时应返回错误")
+	}
+}
@@ -0,0 +1,131 @@
+// Package cxzip 提供将 ZIP 压缩包条目流式写入其他压缩包的功能实现。
+//
+// 该文件实现了格式转换时的 ZIP 读取端：逐个读取 ZIP 条目，
+// 连同名称、权限、修改时间和软链接目标一起写入任意条目写入器，全程不落盘。
+//
+// 主要功能：
+//   - 将 ZIP 条目流式写入条目写入器
+//   - 转换过程中应用过滤器
+//
+// 使用示例：
+//
+//	err := cxzip.WriteEntriesTo("vendor.zip", builder, cfg.Filter, cfg.Password, cfg.FilenameEncoding)
+package cxzip
+
+import (
+	"archive/zip"
+	"fmt"
+	"io"
+	"os"
+
+	"gitee.com/MM-Q/comprx/internal/utils"
+	"gitee.com/MM-Q/comprx/types"
+)
+
+// WriteEntriesTo 将 ZIP 压缩包中的条目写入条目写入器
+//
+// 参数:
+//   - archivePath: ZIP 压缩包路径
+//   - w: 目标条目写入器
+//   - filter: 过滤器，为 nil 时写入全部条目
+//   - password: 解密密码，条目未加密时忽略
+//   - encoding: 未设置 UTF-8 标志位的文件名编码，空值表示自动检测
+//
+// 返回值:
+//   - error: 读取或写入失败时返回错误
+func WriteEntriesTo(archivePath string, w utils.ArchiveEntryWriter, filter *types.FilterOptions, password string, encoding types.FilenameEncoding) error {
+	zipReader, err := zip.OpenReader(archivePath)
+	if err != nil {
+		return fmt.Errorf("打开 ZIP 文件失败: %w", err)
+	}
+	defer func() { _ = zipReader.Close() }()
+
+	// 将非 UTF-8 编码的文件名转换为 UTF-8
+	if err := decodeNames(zipReader.File, encoding); err != nil {
+		return err
+	}
+
+	for _, file := range zipReader.File {
+		mode := file.Mode()
+
+		// 应用过滤器检查
+		if filter != nil && filter.ShouldSkipByParams(file.Name, int64(file.UncompressedSize64), mode.IsDir()) {
+			continue
+		}
+
+		info := types.FileInfo{
+			Name:    file.Name,
+			Size:    int64(file.UncompressedSize64),
+			ModTime: file.Modified,
+			Mode:    mode,
+		}
+
+		switch {
+		case mode.IsDir(): // 处理目录
+			info.IsDir = true
+			if err := w.AddDir(file.Name, info); err != nil {
+				return err
+			}
+
+		case mode&os.ModeSymlink != 0: // 处理软链接
+			target, err := readLinkTarget(file, password)
+			if err != nil {
+				return err
+			}
+			info.IsSymlink = true
+			info.LinkTarget = target
+			if err := w.AddSymlink(file.Name, target, info); err != nil {
+				return err
+			}
+
+		default: // 处理普通文件
+			if err := writeFileEntry(file, w, info, password); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// writeFileEntry 将 ZIP 中的普通文件写入条目写入器
+//
+// 参数:
+//   - file: ZIP 条目
+//   - w: 目标条目写入器
+//   - info: 条目信息
+//   - password: 解密密码
+//
+// 返回值:
+//   - error: 读取或写入失败时返回错误
+func writeFileEntry(file *zip.File, w utils.ArchiveEntryWriter, info types.FileInfo, password string) error {
+	reader, err := openFile(file, password)
+	if err != nil {
+		return fmt.Errorf("打开 ZIP 条目 '%s' 失败: %w", file.Name, err)
+	}
+	defer func() { _ = reader.Close() }()
+
+	return w.AddFile(file.Name, reader, info)
+}
+
+// readLinkTarget 读取 ZIP 中软链接的完整目标路径
+//
+// 参数:
+//   - file: 软链接条目
+//   - password: 解密密码
+//
+// 返回值:
+//   - string: 软链接目标
+//   - error: 读取失败时返回错误
+func readLinkTarget(file *zip.File, password string) (string, error) {
+	reader, err := openFile(file, password)
+	if err != nil {
+		return "", fmt.Errorf("打开 ZIP 中的软链接 '%s' 失败: %w", file.Name, err)
+	}
+	defer func() { _ = reader.Close() }()
+
+	target, err := io.ReadAll(reader)
+	if err != nil {
+		return "", fmt.Errorf("读取软链接 '%s' 的目标失败: %w", file.Name, err)
+	}
+	return string(target), nil
+}
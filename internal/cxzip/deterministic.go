@@ -0,0 +1,68 @@
+// Package cxzip 提供 ZIP 格式的确定性打包支持。
+//
+// 该文件实现了 ZIP 文件头的规范化：钳制修改时间、规范化权限，
+// 并且不写入扩展时间戳额外字段，使相同内容的多次打包产生逐字节相同的结果。
+package cxzip
+
+import (
+	"archive/zip"
+	"io"
+	"time"
+
+	"gitee.com/MM-Q/comprx/internal/config"
+)
+
+// createHeader 规范化文件头后创建 ZIP 条目
+//
+// 参数:
+//   - zipWriter: ZIP 写入器
+//   - header: ZIP 文件头
+//   - cfg: 压缩配置
+//
+// 返回:
+//   - io.Writer: 条目内容写入器
+//   - error: 创建失败时返回错误
+func createHeader(zipWriter *zip.Writer, header *zip.FileHeader, cfg *config.Config) (io.Writer, error) {
+	normalizeHeader(header, cfg)
+	applyEntryComment(header, cfg)
+	markUTF8(header)
+	return zipWriter.CreateHeader(header)
+}
+
+// normalizeHeader 在确定性模式下规范化 ZIP 文件头
+//
+// 设置 Modified 字段时 archive/zip 会写入扩展时间戳额外字段(0x5455)，
+// 因此确定性模式下只写入 MS-DOS 格式的修改时间。
+//
+// 参数:
+//   - header: ZIP 文件头
+//   - cfg: 压缩配置
+func normalizeHeader(header *zip.FileHeader, cfg *config.Config) {
+	if !cfg.Deterministic {
+		return
+	}
+
+	modTime := cfg.ClampModTime(header.Modified)
+	header.Modified = time.Time{}
+	header.ModifiedDate, header.ModifiedTime = msDosTime(modTime.UTC())
+	header.Extra = nil
+	header.SetMode(cfg.NormalizeMode(header.Mode()))
+}
+
+// msDosTime 将时间转换为 MS-DOS 格式的日期和时间
+//
+// 参数:
+//   - t: 时间（按其所在时区转换，早于 1980 年时使用 1980-01-01）
+//
+// 返回:
+//   - uint16: MS-DOS 日期
+//   - uint16: MS-DOS 时间
+func msDosTime(t time.Time) (uint16, uint16) {
+	if t.Year() < 1980 {
+		t = time.Date(1980, 1, 1, 0, 0, 0, 0, time.UTC)
+	}
+
+	date := uint16(t.Day() + int(t.Month())<<5 + (t.Year()-1980)<<9)
+	clock := uint16(t.Second()/2 + t.Minute()<<5 + t.Hour()<<11)
+	return date, clock
+}
@@ -0,0 +1,187 @@
+// Package cxzip 提供删除和重命名 ZIP 压缩包条目的功能实现。
+//
+// 该文件实现了 ZIP 压缩包条目的删除和重命名。所有保留的条目都以原始(已压缩)形式
+// 复制到同目录的临时文件中并重写中央目录，不会解压或重新压缩，最后原子地替换原文件。
+//
+// 主要功能：
+//   - 按通配符模式删除条目
+//   - 重命名条目或目录
+//
+// 使用示例：
+//
+//	err := cxzip.DeleteEntries("backup.zip", []string{"config/secret.env"})
+//	err = cxzip.RenameEntries("backup.zip", map[string]string{"old/": "new/"})
+package cxzip
+
+import (
+	"archive/zip"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"os"
+	"unicode/utf8"
+
+	"gitee.com/MM-Q/comprx/internal/utils"
+)
+
+const (
+	// zipFlagUTF8 通用标志位中表示文件名使用 UTF-8 编码的位
+	zipFlagUTF8 = 0x800
+
+	// unicodePathExtraID Info-ZIP Unicode 路径扩展字段标识，记录的是原文件名
+	unicodePathExtraID = 0x7075
+)
+
+// DeleteEntries 删除 ZIP 压缩包中匹配模式的条目
+//
+// 参数:
+//   - archivePath: ZIP 压缩包路径
+//   - patterns: 删除模式，匹配到目录时同时删除其下所有条目
+//
+// 返回值:
+//   - error: 操作过程中遇到的错误
+func DeleteEntries(archivePath string, patterns []string) error {
+	return rewriteEntries(archivePath, func(entries utils.EntryKinds) (utils.EntryRewrite, error) {
+		return utils.PlanDelete(entries, patterns)
+	})
+}
+
+// RenameEntries 重命名 ZIP 压缩包中的条目
+//
+// 参数:
+//   - archivePath: ZIP 压缩包路径
+//   - renames: 原名称到新名称的映射，重命名目录时其下所有条目一并移动
+//
+// 返回值:
+//   - error: 操作过程中遇到的错误
+func RenameEntries(archivePath string, renames map[string]string) error {
+	return rewriteEntries(archivePath, func(entries utils.EntryKinds) (utils.EntryRewrite, error) {
+		return utils.PlanRename(entries, renames)
+	})
+}
+
+// rewriteEntries 按条目重写函数原子地重写 ZIP 压缩包
+//
+// 参数:
+//   - archivePath: ZIP 压缩包路径
+//   - plan: 根据压缩包中的条目生成条目重写函数
+//
+// 返回值:
+//   - error: 操作过程中遇到的错误
+func rewriteEntries(archivePath string, plan func(utils.EntryKinds) (utils.EntryRewrite, error)) error {
+	// 确保路径为绝对路径
+	var absErr error
+	if archivePath, absErr = utils.EnsureAbsPath(archivePath, "ZIP文件路径"); absErr != nil {
+		return absErr
+	}
+
+	reader, err := zip.OpenReader(archivePath)
+	if err != nil {
+		return fmt.Errorf("打开 ZIP 文件失败: %w", err)
+	}
+	defer func() { _ = reader.Close() }()
+
+	rewrite, err := plan(entryKinds(reader.File))
+	if err != nil {
+		return err
+	}
+
+	return utils.ReplaceFile(archivePath, func(f *os.File) error {
+		zipWriter := zip.NewWriter(f)
+		for _, file := range reader.File {
+			newName, keep := rewrite(file.Name)
+			if !keep {
+				continue
+			}
+
+			var copyErr error
+			if newName == file.Name {
+				copyErr = zipWriter.Copy(file)
+			} else {
+				copyErr = copyRenamedEntry(zipWriter, file, newName)
+			}
+			if copyErr != nil {
+				return fmt.Errorf("复制 ZIP 条目 '%s' 失败: %w", file.Name, copyErr)
+			}
+		}
+		if err := setArchiveComment(zipWriter, reader.Comment); err != nil {
+			return err
+		}
+		if err := zipWriter.Close(); err != nil {
+			return fmt.Errorf("关闭 ZIP 写入器失败: %w", err)
+		}
+		return nil
+	})
+}
+
+// copyRenamedEntry 以新名称原样复制 ZIP 条目的压缩数据
+//
+// 参数:
+//   - zipWriter: 目标 ZIP 写入器
+//   - file: 源 ZIP 条目
+//   - newName: 新条目名称
+//
+// 返回值:
+//   - error: 复制失败时返回错误
+func copyRenamedEntry(zipWriter *zip.Writer, file *zip.File, newName string) error {
+	header := file.FileHeader
+	header.Name = newName
+	header.Extra = removeExtraField(header.Extra, unicodePathExtraID)
+
+	// 非 ASCII 文件名标记为 UTF-8 编码
+	markUTF8(&header)
+
+	raw, err := file.OpenRaw()
+	if err != nil {
+		return err
+	}
+	w, err := zipWriter.CreateRaw(&header)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(w, raw)
+	return err
+}
+
+// removeExtraField 移除 ZIP 扩展字段中指定标识的字段
+//
+// 参数:
+//   - extra: 原扩展字段数据
+//   - id: 需要移除的字段标识
+//
+// 返回值:
+//   - []byte: 移除后的扩展字段数据，格式异常时原样返回
+func removeExtraField(extra []byte, id uint16) []byte {
+	var result []byte
+	for rest := extra; len(rest) > 0; {
+		if len(rest) < 4 {
+			return extra
+		}
+		fieldID := binary.LittleEndian.Uint16(rest[0:2])
+		size := int(binary.LittleEndian.Uint16(rest[2:4]))
+		if len(rest) < 4+size {
+			return extra
+		}
+		if fieldID != id {
+			result = append(result, rest[:4+size]...)
+		}
+		rest = rest[4+size:]
+	}
+	return result
+}
+
+// isASCII 检查字符串是否只包含 ASCII 字符
+//
+// 参数:
+//   - s: 待检查的字符串
+//
+// 返回值:
+//   - bool: 只包含 ASCII 字符返回 true
+func isASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= utf8.RuneSelf {
+			return false
+		}
+	}
+	return true
+}
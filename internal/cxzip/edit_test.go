@@ -0,0 +1,154 @@
+package cxzip
+
+import (
+	"archive/zip"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gitee.com/MM-Q/comprx/internal/config"
+)
+
+// createEditTestZip 创建包含多个条目的测试 ZIP 文件
+func createEditTestZip(t *testing.T) string {
+	t.Helper()
+
+	tempDir := t.TempDir()
+	srcDir := filepath.Join(tempDir, "backup")
+	files := map[string]string{
+		"main.go":           strings.Repeat("package main\n", 50),
+		"config/secret.env": "TOKEN=leaked",
+		"config/app.yaml":   "name: app",
+	}
+	for name, content := range files {
+		filePath := filepath.Join(srcDir, name)
+		if err := os.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
+			t.Fatalf("创建目录失败: %v", err)
+		}
+		if err := os.WriteFile(filePath, []byte(content), 0644); err != nil {
+			t.Fatalf("创建测试文件失败: %v", err)
+		}
+	}
+
+	zipFile := filepath.Join(tempDir, "backup.zip")
+	if err := Zip(zipFile, srcDir, config.New()); err != nil {
+		t.Fatalf("ZIP压缩失败: %v", err)
+	}
+	return zipFile
+}
+
+// zipHeaders 读取 ZIP 文件中所有条目的文件头
+func zipHeaders(t *testing.T, zipFile string) map[string]zip.FileHeader {
+	t.Helper()
+
+	reader, err := zip.OpenReader(zipFile)
+	if err != nil {
+		t.Fatalf("打开ZIP文件失败: %v", err)
+	}
+	defer func() { _ = reader.Close() }()
+
+	headers := make(map[string]zip.FileHeader)
+	for _, file := range reader.File {
+		headers[file.Name] = file.FileHeader
+	}
+	return headers
+}
+
+func TestDeleteEntries(t *testing.T) {
+	zipFile := createEditTestZip(t)
+	before := zipHeaders(t, zipFile)
+
+	if err := DeleteEntries(zipFile, []string{"*.env"}); err != nil {
+		t.Fatalf("删除条目失败: %v", err)
+	}
+
+	after := zipHeaders(t, zipFile)
+	if _, ok := after["backup/config/secret.env"]; ok {
+		t.Error("条目 backup/config/secret.env 未被删除")
+	}
+	if len(after) != len(before)-1 {
+		t.Errorf("条目数量不匹配: 期望 %d, 实际 %d", len(before)-1, len(after))
+	}
+
+	// 保留的条目原样复制
+	kept := after["backup/main.go"]
+	if kept.CRC32 != before["backup/main.go"].CRC32 || kept.CompressedSize64 != before["backup/main.go"].CompressedSize64 {
+		t.Error("保留的条目被重新压缩")
+	}
+
+	// 没有匹配的条目时返回错误
+	if err := DeleteEntries(zipFile, []string{"*.pem"}); err == nil {
+		t.Error("没有匹配的条目时应返回错误")
+	}
+}
+
+func TestRenameEntries(t *testing.T) {
+	zipFile := createEditTestZip(t)
+	before := zipHeaders(t, zipFile)
+
+	if err := RenameEntries(zipFile, map[string]string{"backup/config": "backup/设置"}); err != nil {
+		t.Fatalf("重命名条目失败: %v", err)
+	}
+
+	after := zipHeaders(t, zipFile)
+	for _, name := range []string{"backup/设置/", "backup/设置/secret.env", "backup/设置/app.yaml", "backup/main.go"} {
+		if _, ok := after[name]; !ok {
+			t.Errorf("缺少条目 %s", name)
+		}
+	}
+	if _, ok := after["backup/config/app.yaml"]; ok {
+		t.Error("原条目 backup/config/app.yaml 仍然存在")
+	}
+
+	renamed := after["backup/设置/app.yaml"]
+	if renamed.CRC32 != before["backup/config/app.yaml"].CRC32 {
+		t.Error("重命名后的条目数据不一致")
+	}
+	if renamed.Flags&zipFlagUTF8 == 0 {
+		t.Error("非 ASCII 文件名应设置 UTF-8 标志")
+	}
+
+	// 解压验证内容
+	extractDir := filepath.Join(t.TempDir(), "out")
+	if err := Unzip(zipFile, extractDir, config.New()); err != nil {
+		t.Fatalf("解压失败: %v", err)
+	}
+	data, err := os.ReadFile(filepath.Join(extractDir, "backup", "设置", "app.yaml"))
+	if err != nil || string(data) != "name: app" {
+		t.Errorf("重命名后的文件内容不匹配: %q, %v", string(data), err)
+	}
+}
+
+func TestRenameEntries_ImplicitDirectory(t *testing.T) {
+	// 构造没有目录条目的 ZIP 文件（许多工具的常见布局）
+	zipFile := filepath.Join(t.TempDir(), "nodirs.zip")
+	file, err := os.Create(zipFile)
+	if err != nil {
+		t.Fatalf("创建ZIP文件失败: %v", err)
+	}
+	writer := zip.NewWriter(file)
+	for _, name := range []string{"app/main.go", "app/config/app.yaml", "application.log"} {
+		w, err := writer.Create(name)
+		if err != nil {
+			t.Fatalf("创建条目失败: %v", err)
+		}
+		_, _ = w.Write([]byte(name))
+	}
+	_ = writer.Close()
+	_ = file.Close()
+
+	if err := RenameEntries(zipFile, map[string]string{"app": "service"}); err != nil {
+		t.Fatalf("重命名隐式目录失败: %v", err)
+	}
+
+	after := zipHeaders(t, zipFile)
+	for _, name := range []string{"service/main.go", "service/config/app.yaml", "application.log"} {
+		if _, ok := after[name]; !ok {
+			t.Errorf("缺少条目 %s", name)
+		}
+	}
+	if len(after) != 3 {
+		t.Errorf("条目数量不匹配: %v", after)
+	}
+}
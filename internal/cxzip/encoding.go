@@ -0,0 +1,287 @@
+// Package cxzip 提供 ZIP 文件名编码的转换功能实现。
+//
+// 中文和日文 Windows 上生成的 ZIP 通常使用 GBK/CP936 或 Shift-JIS 存储文件名，
+// 并且不设置 UTF-8 标志位。读取时按指定编码把这类文件名转换为 UTF-8，
+// 避免解压出乱码路径；打包时非 ASCII 文件名总是设置 UTF-8 标志位。
+//
+// 转换规则：
+//   - 设置了 UTF-8 标志位或只包含 ASCII 的文件名不转换
+//   - 存在 Info-ZIP Unicode 路径扩展字段且校验通过时优先使用其中的文件名
+//   - 自动检测时只转换不是有效 UTF-8 的文件名，并在 GB18030、Shift-JIS、CP437 中
+//     选择对整个压缩包解码错误最少的编码
+//
+// 主要功能：
+//   - 将条目名称、条目注释和压缩包注释转换为 UTF-8
+//   - 为非 ASCII 文件名设置 UTF-8 标志位
+package cxzip
+
+import (
+	"archive/zip"
+	"encoding/binary"
+	"fmt"
+	"hash/crc32"
+	"unicode/utf8"
+
+	"gitee.com/MM-Q/comprx/types"
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/charmap"
+	"golang.org/x/text/encoding/japanese"
+	"golang.org/x/text/encoding/simplifiedchinese"
+)
+
+// decodeNames 将未标记 UTF-8 的条目名称和注释转换为 UTF-8
+//
+// 参数:
+//   - files: ZIP 条目列表，转换结果直接写回 Name 和 Comment
+//   - enc: 文件名编码，空值表示自动检测
+//
+// 返回:
+//   - error: 编码不受支持或转换失败时返回错误
+func decodeNames(files []*zip.File, enc types.FilenameEncoding) error {
+	if enc == types.FilenameEncodingUTF8 {
+		return nil
+	}
+
+	// 筛选需要转换的条目
+	var pending []*zip.File
+	for _, file := range files {
+		if !file.NonUTF8 {
+			continue
+		}
+		if name, ok := unicodePathName(file); ok {
+			file.Name = name
+			continue
+		}
+		if isASCII(file.Name) && isASCII(file.Comment) {
+			continue
+		}
+		// 自动检测时有效的 UTF-8 文件名视为未设置标志位的 UTF-8
+		if (enc == "" || enc == types.FilenameEncodingAuto) && utf8.ValidString(file.Name) && utf8.ValidString(file.Comment) {
+			continue
+		}
+		pending = append(pending, file)
+	}
+	if len(pending) == 0 {
+		return nil
+	}
+
+	if enc == "" || enc == types.FilenameEncodingAuto {
+		texts := make([]string, 0, 2*len(pending))
+		for _, file := range pending {
+			texts = append(texts, file.Name, file.Comment)
+		}
+		enc = detectEncoding(texts)
+	}
+	charset, err := lookupEncoding(enc)
+	if err != nil {
+		return err
+	}
+
+	decoder := charset.NewDecoder()
+	for _, file := range pending {
+		name, err := decoder.String(file.Name)
+		if err != nil {
+			return fmt.Errorf("按 %s 转换文件名 %q 失败: %w", enc, file.Name, err)
+		}
+		comment, err := decoder.String(file.Comment)
+		if err != nil {
+			return fmt.Errorf("按 %s 转换条目 %s 的注释失败: %w", enc, name, err)
+		}
+		file.Name, file.Comment = name, comment
+		file.NonUTF8 = false
+	}
+	return nil
+}
+
+// decodeArchiveComment 将非 UTF-8 编码的压缩包注释转换为 UTF-8
+//
+// 压缩包注释没有编码标志位，只有不是有效 UTF-8 时才转换。
+//
+// 参数:
+//   - comment: 压缩包注释
+//   - enc: 文件名编码，空值表示自动检测
+//
+// 返回:
+//   - string: 转换后的注释，无法转换时返回原始注释
+func decodeArchiveComment(comment string, enc types.FilenameEncoding) string {
+	if enc == types.FilenameEncodingUTF8 || utf8.ValidString(comment) {
+		return comment
+	}
+	if enc == "" || enc == types.FilenameEncodingAuto {
+		enc = detectEncoding([]string{comment})
+	}
+	charset, err := lookupEncoding(enc)
+	if err != nil {
+		return comment
+	}
+	decoded, err := charset.NewDecoder().String(comment)
+	if err != nil {
+		return comment
+	}
+	return decoded
+}
+
+// lookupEncoding 返回文件名编码对应的字符集
+//
+// 参数:
+//   - enc: 文件名编码
+//
+// 返回:
+//   - encoding.Encoding: 字符集
+//   - error: 编码不受支持时返回错误
+func lookupEncoding(enc types.FilenameEncoding) (encoding.Encoding, error) {
+	switch enc {
+	case types.FilenameEncodingGBK:
+		return simplifiedchinese.GBK, nil
+	case types.FilenameEncodingGB18030:
+		return simplifiedchinese.GB18030, nil
+	case types.FilenameEncodingShiftJIS:
+		return japanese.ShiftJIS, nil
+	case types.FilenameEncodingCP437:
+		return charmap.CodePage437, nil
+	default:
+		return nil, fmt.Errorf("不支持的文件名编码: %s", enc)
+	}
+}
+
+// detectEncoding 检测条目名称最可能使用的编码
+//
+// 按字节规则统计每种编码的可疑程度：无法解码的字节记 10 分，
+// GBK 扩展区的罕用字、Shift-JIS 半角片假名和 CP437 的非 ASCII 字符记 1 分，
+// 选择得分最低的编码，得分相同时按 GB18030、Shift-JIS、CP437 的顺序选择。
+//
+// 参数:
+//   - texts: 需要转换的文件名和注释
+//
+// 返回:
+//   - types.FilenameEncoding: 检测到的编码
+func detectEncoding(texts []string) types.FilenameEncoding {
+	candidates := []struct {
+		enc     types.FilenameEncoding
+		penalty func(string) int
+	}{
+		{types.FilenameEncodingGB18030, gbkPenalty},
+		{types.FilenameEncodingShiftJIS, shiftJISPenalty},
+		{types.FilenameEncodingCP437, cp437Penalty},
+	}
+
+	best, bestScore := candidates[0].enc, -1
+	for _, c := range candidates {
+		score := 0
+		for _, text := range texts {
+			score += c.penalty(text)
+		}
+		if bestScore < 0 || score < bestScore {
+			best, bestScore = c.enc, score
+		}
+	}
+	return best
+}
+
+// gbkPenalty 计算按 GBK/GB18030 解码的可疑程度
+func gbkPenalty(s string) int {
+	penalty := 0
+	for i := 0; i < len(s); {
+		c := s[i]
+		switch {
+		case c < 0x80:
+			i++
+		case c == 0x80 || c == 0xff || i+1 >= len(s):
+			penalty += 10
+			i++
+		default:
+			t := s[i+1]
+			switch {
+			case c >= 0xa1 && c <= 0xf7 && t >= 0xa1 && t <= 0xfe: // GB2312 常用字
+				i += 2
+			case t >= 0x40 && t <= 0xfe && t != 0x7f: // GBK 扩展区
+				penalty++
+				i += 2
+			case t >= 0x30 && t <= 0x39 && i+3 < len(s): // GB18030 四字节
+				penalty++
+				i += 4
+			default:
+				penalty += 10
+				i++
+			}
+		}
+	}
+	return penalty
+}
+
+// shiftJISPenalty 计算按 Shift-JIS 解码的可疑程度
+func shiftJISPenalty(s string) int {
+	penalty := 0
+	for i := 0; i < len(s); {
+		c := s[i]
+		switch {
+		case c < 0x80:
+			i++
+		case c >= 0xa1 && c <= 0xdf: // 半角片假名，文件名中很少使用
+			penalty++
+			i++
+		case (c >= 0x81 && c <= 0x9f || c >= 0xe0 && c <= 0xfc) && i+1 < len(s) &&
+			(s[i+1] >= 0x40 && s[i+1] <= 0x7e || s[i+1] >= 0x80 && s[i+1] <= 0xfc):
+			i += 2
+		default:
+			penalty += 10
+			i++
+		}
+	}
+	return penalty
+}
+
+// cp437Penalty 计算按 CP437 解码的可疑程度，每个非 ASCII 字节记 1 分
+func cp437Penalty(s string) int {
+	penalty := 0
+	for i := 0; i < len(s); i++ {
+		if s[i] >= utf8.RuneSelf {
+			penalty++
+		}
+	}
+	return penalty
+}
+
+// unicodePathName 读取 Info-ZIP Unicode 路径扩展字段中的 UTF-8 文件名
+//
+// 参数:
+//   - file: ZIP 条目
+//
+// 返回:
+//   - string: UTF-8 文件名
+//   - bool: 扩展字段存在且原文件名的 CRC32 匹配时返回 true
+func unicodePathName(file *zip.File) (string, bool) {
+	for extra := file.Extra; len(extra) >= 4; {
+		id := binary.LittleEndian.Uint16(extra[0:])
+		size := int(binary.LittleEndian.Uint16(extra[2:]))
+		if len(extra) < 4+size {
+			break
+		}
+		data := extra[4 : 4+size]
+		extra = extra[4+size:]
+
+		// 版本(1) + 原文件名 CRC32(4) + UTF-8 文件名
+		if id != unicodePathExtraID || size < 5 || data[0] != 1 {
+			continue
+		}
+		if binary.LittleEndian.Uint32(data[1:]) != crc32.ChecksumIEEE([]byte(file.Name)) {
+			return "", false
+		}
+		name := string(data[5:])
+		return name, utf8.ValidString(name)
+	}
+	return "", false
+}
+
+// markUTF8 为非 ASCII 的文件名或注释设置 UTF-8 标志位
+//
+// 参数:
+//   - header: ZIP 文件头
+func markUTF8(header *zip.FileHeader) {
+	header.Flags &^= zipFlagUTF8
+	valid := utf8.ValidString(header.Name) && utf8.ValidString(header.Comment)
+	if valid && !(isASCII(header.Name) && isASCII(header.Comment)) {
+		header.Flags |= zipFlagUTF8
+		header.NonUTF8 = false
+	}
+}
@@ -0,0 +1,154 @@
+package cxzip
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/binary"
+	"hash/crc32"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"gitee.com/MM-Q/comprx/internal/config"
+	"gitee.com/MM-Q/comprx/types"
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/japanese"
+	"golang.org/x/text/encoding/simplifiedchinese"
+)
+
+// writeLegacyZip 按指定编码写入未设置 UTF-8 标志位的 ZIP
+func writeLegacyZip(t *testing.T, path string, charset encoding.Encoding, names ...string) {
+	t.Helper()
+
+	var buf bytes.Buffer
+	zipWriter := zip.NewWriter(&buf)
+	for _, name := range names {
+		raw, err := charset.NewEncoder().String(name)
+		if err != nil {
+			t.Fatalf("编码文件名 %s 失败: %v", name, err)
+		}
+		w, err := zipWriter.CreateHeader(&zip.FileHeader{Name: raw, Method: zip.Deflate, NonUTF8: true})
+		if err != nil {
+			t.Fatalf("创建条目失败: %v", err)
+		}
+		_, _ = w.Write([]byte("content of " + name))
+	}
+	if err := zipWriter.Close(); err != nil {
+		t.Fatalf("关闭ZIP写入器失败: %v", err)
+	}
+	if err := os.WriteFile(path, buf.Bytes(), 0644); err != nil {
+		t.Fatalf("写入ZIP文件失败: %v", err)
+	}
+}
+
+// listNames 返回 ListZip 列出的文件名
+func listNames(t *testing.T, path string) []string {
+	t.Helper()
+
+	info, err := ListZip(path)
+	if err != nil {
+		t.Fatalf("列出ZIP内容失败: %v", err)
+	}
+	var names []string
+	for _, file := range info.Files {
+		names = append(names, file.Name)
+	}
+	return names
+}
+
+func TestDecodeNames_Auto(t *testing.T) {
+	tempDir := t.TempDir()
+
+	cases := []struct {
+		name    string
+		charset encoding.Encoding
+		names   []string
+	}{
+		{"gbk.zip", simplifiedchinese.GBK, []string{"中文资料/季度报告.txt", "说明.md"}},
+		{"sjis.zip", japanese.ShiftJIS, []string{"テスト/日本語の資料.txt", "説明.md"}},
+	}
+	for _, c := range cases {
+		path := filepath.Join(tempDir, c.name)
+		writeLegacyZip(t, path, c.charset, c.names...)
+
+		got := listNames(t, path)
+		if len(got) != len(c.names) || got[0] != c.names[0] || got[1] != c.names[1] {
+			t.Errorf("%s 自动检测的文件名不正确: %q", c.name, got)
+		}
+	}
+}
+
+func TestDecodeNames_Explicit(t *testing.T) {
+	tempDir := t.TempDir()
+	zipFile := filepath.Join(tempDir, "gbk.zip")
+	writeLegacyZip(t, zipFile, simplifiedchinese.GBK, "资料/报告.txt")
+
+	// 指定编码解压
+	cfg := config.New()
+	cfg.FilenameEncoding = types.FilenameEncodingGB18030
+	outDir := filepath.Join(tempDir, "out")
+	if err := Unzip(zipFile, outDir, cfg); err != nil {
+		t.Fatalf("解压失败: %v", err)
+	}
+	if data, err := os.ReadFile(filepath.Join(outDir, "资料", "报告.txt")); err != nil || string(data) != "content of 资料/报告.txt" {
+		t.Errorf("解压后的文件不正确: %q, %v", data, err)
+	}
+
+	// utf-8 表示保留原始文件名
+	reader, err := zip.OpenReader(zipFile)
+	if err != nil {
+		t.Fatalf("打开ZIP文件失败: %v", err)
+	}
+	defer func() { _ = reader.Close() }()
+	raw := reader.File[0].Name
+	if err := decodeNames(reader.File, types.FilenameEncodingUTF8); err != nil || reader.File[0].Name != raw {
+		t.Errorf("utf-8 模式不应转换文件名: %q, %v", reader.File[0].Name, err)
+	}
+	if err := decodeNames(reader.File, "big5"); err == nil {
+		t.Error("不支持的编码应返回错误")
+	}
+}
+
+func TestDecodeNames_UnicodePathExtra(t *testing.T) {
+	raw, _ := simplifiedchinese.GBK.NewEncoder().String("旧名称.txt")
+	want := "新名称.txt"
+
+	extra := make([]byte, 9, 9+len(want))
+	binary.LittleEndian.PutUint16(extra[0:], unicodePathExtraID)
+	binary.LittleEndian.PutUint16(extra[2:], uint16(5+len(want)))
+	extra[4] = 1
+	binary.LittleEndian.PutUint32(extra[5:], crc32.ChecksumIEEE([]byte(raw)))
+	extra = append(extra, want...)
+
+	file := &zip.File{FileHeader: zip.FileHeader{Name: raw, Extra: extra, NonUTF8: true}}
+	if err := decodeNames([]*zip.File{file}, types.FilenameEncodingGBK); err != nil || file.Name != want {
+		t.Errorf("应优先使用 Unicode 路径扩展字段: %q, %v", file.Name, err)
+	}
+}
+
+func TestMarkUTF8(t *testing.T) {
+	tempDir := t.TempDir()
+	srcDir := filepath.Join(tempDir, "数据")
+	if err := os.MkdirAll(srcDir, 0755); err != nil {
+		t.Fatalf("创建目录失败: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(srcDir, "a.txt"), []byte("a"), 0644); err != nil {
+		t.Fatalf("创建测试文件失败: %v", err)
+	}
+
+	zipFile := filepath.Join(tempDir, "out.zip")
+	if err := Zip(zipFile, srcDir, config.New()); err != nil {
+		t.Fatalf("压缩失败: %v", err)
+	}
+
+	reader, err := zip.OpenReader(zipFile)
+	if err != nil {
+		t.Fatalf("打开ZIP文件失败: %v", err)
+	}
+	defer func() { _ = reader.Close() }()
+	for _, file := range reader.File {
+		if file.Flags&zipFlagUTF8 == 0 {
+			t.Errorf("非 ASCII 文件名 %s 应设置 UTF-8 标志位", file.Name)
+		}
+	}
+}
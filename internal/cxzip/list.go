@@ -7,6 +7,7 @@
 //   - ZIP 压缩包完整文件列表获取
 //   - 限制数量的文件列表获取
 //   - 模式匹配的文件列表过滤
+//   - 逐条遍历条目，不构建完整的条目列表
 //   - 多种文件类型支持（普通文件、目录、符号链接）
 //   - 完整的压缩信息统计
 //
@@ -21,9 +22,11 @@
 //   - 压缩率计算
 //   - 文件修改时间
 //   - 文件权限模式
+//   - CRC32、压缩方法和条目头偏移
+//   - 额外字段中记录的 UID/GID 和访问时间
 //
 // 性能优化：
-//   - 限制模式下的容量预分配
+//   - 遍历时不保留条目信息，中央目录索引由 archive/zip 持有
 //   - 符号链接目标的安全读取
 //   - 错误容忍的链接目标处理
 //
@@ -37,11 +40,19 @@
 //
 //	// 获取匹配 *.go 模式的文件
 //	info, err := cxzip.ListZipMatch("archive.zip", "*.go")
+//
+//	// 逐条遍历，返回 fs.SkipAll 提前结束
+//	err := cxzip.WalkZip("archive.zip", func(file types.FileInfo) error {
+//	    fmt.Println(file.Name)
+//	    return nil
+//	})
 package cxzip
 
 import (
 	"archive/zip"
+	"errors"
 	"fmt"
+	"io/fs"
 	"os"
 
 	"gitee.com/MM-Q/comprx/internal/utils"
@@ -50,119 +61,116 @@ import (
 
 // ListZip 获取ZIP压缩包的所有文件信息
 func ListZip(archivePath string) (*types.ArchiveInfo, error) {
+	return listZip(archivePath, 0, "")
+}
+
+// ListZipLimit 获取ZIP压缩包指定数量的文件信息
+func ListZipLimit(archivePath string, limit int) (*types.ArchiveInfo, error) {
+	return listZip(archivePath, limit, "")
+}
+
+// ListZipMatch 获取ZIP压缩包中匹配指定模式的文件信息
+func ListZipMatch(archivePath string, pattern string) (*types.ArchiveInfo, error) {
+	return listZip(archivePath, 0, pattern)
+}
+
+// listZip 遍历ZIP压缩包并收集文件信息
+//
+// 参数:
+//   - archivePath: ZIP文件路径
+//   - limit: 最多返回的文件数量，小于等于 0 表示不限制
+//   - pattern: 文件名匹配模式，为空时返回全部文件
+//
+// 返回:
+//   - *types.ArchiveInfo: 压缩包信息
+//   - error: 错误信息
+func listZip(archivePath string, limit int, pattern string) (*types.ArchiveInfo, error) {
 	// 确保路径为绝对路径
 	absPath, err := utils.EnsureAbsPath(archivePath, "ZIP文件路径")
 	if err != nil {
 		return nil, err
 	}
 
-	// 打开ZIP文件
-	reader, err := zip.OpenReader(absPath)
-	if err != nil {
-		return nil, fmt.Errorf("打开ZIP文件失败: %w", err)
-	}
-	defer func() { _ = reader.Close() }()
-
-	// 获取压缩包文件信息
-	stat, err := os.Stat(absPath)
-	if err != nil {
-		return nil, fmt.Errorf("获取ZIP文件信息失败: %w", err)
-	}
-
 	// 根据文件名检测压缩格式类型
 	compressType, err := types.DetectCompressFormat(absPath)
 	if err != nil {
 		return nil, fmt.Errorf("检测压缩格式失败: %w", err)
 	}
 
-	// 创建 ArchiveInfo 结构体
 	archiveInfo := &types.ArchiveInfo{
-		Type:           compressType,
-		TotalFiles:     len(reader.File),
-		CompressedSize: stat.Size(),
-		Files:          make([]types.FileInfo, 0, len(reader.File)),
+		Type:  compressType,
+		Files: make([]types.FileInfo, 0, utils.DefaultFileCapacity),
 	}
 
-	// 遍历ZIP文件中的每个条目
-	for _, file := range reader.File {
-		fileInfo := types.FileInfo{
-			Name:           file.Name,
-			Size:           int64(file.UncompressedSize64),
-			CompressedSize: int64(file.CompressedSize64),
-			ModTime:        file.Modified,
-			Mode:           file.Mode(),
-			IsDir:          file.Mode().IsDir(),
-			IsSymlink:      file.Mode()&os.ModeSymlink != 0,
-		}
-
-		// 如果是符号链接，读取链接目标
-		if fileInfo.IsSymlink {
-			if target, err := readSymlinkTarget(file); err == nil {
-				fileInfo.LinkTarget = target
-			}
-		}
-
-		archiveInfo.Files = append(archiveInfo.Files, fileInfo)
-		archiveInfo.TotalSize += fileInfo.Size
+	err = walkZip(absPath, func(reader *zip.Reader, size int64) {
+		archiveInfo.CompressedSize = size
+		archiveInfo.Comment = decodeArchiveComment(reader.Comment, types.FilenameEncodingAuto)
+	}, utils.CollectFiles(archiveInfo, limit, pattern))
+	if err != nil {
+		return nil, err
 	}
-
 	return archiveInfo, nil
 }
 
-// ListZipLimit 获取ZIP压缩包指定数量的文件信息
-func ListZipLimit(archivePath string, limit int) (*types.ArchiveInfo, error) {
+// WalkZip 逐条遍历ZIP压缩包中的条目
+//
+// 参数:
+//   - archivePath: ZIP文件路径
+//   - fn: 对每个条目调用的函数，返回 fs.SkipAll 时提前结束遍历，返回其他错误时中止并返回该错误
+//
+// 返回:
+//   - error: 错误信息
+func WalkZip(archivePath string, fn func(types.FileInfo) error) error {
 	// 确保路径为绝对路径
 	absPath, err := utils.EnsureAbsPath(archivePath, "ZIP文件路径")
 	if err != nil {
-		return nil, err
+		return err
 	}
+	return walkZip(absPath, nil, fn)
+}
 
+// walkZip 打开ZIP文件并逐条遍历条目
+//
+// 参数:
+//   - absPath: ZIP文件的绝对路径
+//   - onOpen: 读取中央目录后调用，用于获取压缩包注释和大小，可以为 nil
+//   - fn: 对每个条目调用的函数
+//
+// 返回:
+//   - error: 错误信息
+func walkZip(absPath string, onOpen func(reader *zip.Reader, size int64), fn func(types.FileInfo) error) error {
 	// 打开ZIP文件
-	reader, err := zip.OpenReader(absPath)
+	zipFile, err := os.Open(absPath)
 	if err != nil {
-		return nil, fmt.Errorf("打开ZIP文件失败: %w", err)
+		return fmt.Errorf("打开ZIP文件失败: %w", err)
 	}
-	defer func() { _ = reader.Close() }()
+	defer func() { _ = zipFile.Close() }()
 
 	// 获取压缩包文件信息
-	stat, err := os.Stat(absPath)
+	stat, err := zipFile.Stat()
 	if err != nil {
-		return nil, fmt.Errorf("获取ZIP文件信息失败: %w", err)
+		return fmt.Errorf("获取ZIP文件信息失败: %w", err)
 	}
 
-	// 计算实际需要处理的文件数量
-	totalFiles := len(reader.File)
-	maxFiles := totalFiles
-	if limit > 0 && limit < totalFiles {
-		maxFiles = limit
-	}
-
-	// 根据文件名检测压缩格式类型
-	compressType, err := types.DetectCompressFormat(absPath)
+	reader, err := zip.NewReader(zipFile, stat.Size())
 	if err != nil {
-		return nil, fmt.Errorf("检测压缩格式失败: %w", err)
+		return fmt.Errorf("打开ZIP文件失败: %w", err)
 	}
 
-	// 创建 ArchiveInfo 结构体
-	archiveInfo := &types.ArchiveInfo{
-		Type:           compressType,
-		TotalFiles:     maxFiles, // 注意：这里是实际返回的文件数
-		CompressedSize: stat.Size(),
-		Files:          make([]types.FileInfo, 0, maxFiles), // 优化容量分配
+	// 自动检测并转换非 UTF-8 编码的文件名
+	if err := decodeNames(reader.File, types.FilenameEncodingAuto); err != nil {
+		return err
+	}
+	if onOpen != nil {
+		onOpen(reader, stat.Size())
 	}
 
-	// 只遍历前 maxFiles 个文件
-	for i := 0; i < maxFiles; i++ {
-		file := reader.File[i]
-		fileInfo := types.FileInfo{
-			Name:           file.Name,
-			Size:           int64(file.UncompressedSize64),
-			CompressedSize: int64(file.CompressedSize64),
-			ModTime:        file.Modified,
-			Mode:           file.Mode(),
-			IsDir:          file.Mode().IsDir(),
-			IsSymlink:      file.Mode()&os.ModeSymlink != 0,
-		}
+	// 读取各条目本地文件头的偏移
+	offsets := headerOffsets(zipFile, stat.Size())
+
+	// 遍历ZIP文件中的每个条目
+	for i, file := range reader.File {
+		fileInfo := zipFileInfo(file, entryOffset(offsets, i))
 
 		// 如果是符号链接，读取链接目标
 		if fileInfo.IsSymlink {
@@ -171,31 +179,14 @@ func ListZipLimit(archivePath string, limit int) (*types.ArchiveInfo, error) {
 			}
 		}
 
-		archiveInfo.Files = append(archiveInfo.Files, fileInfo)
-		archiveInfo.TotalSize += fileInfo.Size
-	}
-
-	return archiveInfo, nil
-}
-
-// ListZipMatch 获取ZIP压缩包中匹配指定模式的文件信息
-func ListZipMatch(archivePath string, pattern string) (*types.ArchiveInfo, error) {
-	archiveInfo, err := ListZip(archivePath)
-	if err != nil {
-		return nil, err
-	}
-
-	archiveInfo.Files = utils.FilterFilesByPattern(archiveInfo.Files, pattern)
-	archiveInfo.TotalFiles = len(archiveInfo.Files)
-
-	// 重新计算总大小
-	var totalSize int64
-	for _, file := range archiveInfo.Files {
-		totalSize += file.Size
+		if err := fn(fileInfo); err != nil {
+			if errors.Is(err, fs.SkipAll) {
+				return nil
+			}
+			return err
+		}
 	}
-	archiveInfo.TotalSize = totalSize
-
-	return archiveInfo, nil
+	return nil
 }
 
 // readSymlinkTarget 读取ZIP文件中符号链接的目标
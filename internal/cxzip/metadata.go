@@ -0,0 +1,385 @@
+// Package cxzip 提供 ZIP 条目元数据解析的功能实现。
+//
+// 标准库只暴露了 ZIP 条目的基本信息，属主、访问时间和条目头偏移等元数据
+// 需要从额外字段和中央目录中自行解析，供内容列表使用。
+//
+// 主要功能：
+//   - 构建包含完整元数据的条目信息
+//   - 解析 Info-ZIP Unix、扩展时间戳和 NTFS 额外字段
+//   - 从中央目录读取各条目本地文件头的偏移
+package cxzip
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/binary"
+	"io"
+	"os"
+	"strconv"
+	"time"
+
+	"gitee.com/MM-Q/comprx/types"
+)
+
+const (
+	// unixOwnerExtraID Info-ZIP 新版 Unix 额外字段标识，记录 UID/GID
+	unixOwnerExtraID = 0x7875
+	// ntfsExtraID NTFS 额外字段标识，记录修改、访问和创建时间
+	ntfsExtraID = 0x000a
+	// zip64ExtraID ZIP64 扩展信息额外字段标识
+	zip64ExtraID = 0x0001
+
+	// ZIP 记录签名
+	directoryHeaderSignature  = 0x02014b50
+	directoryEndSignature     = 0x06054b50
+	directory64LocSignature   = 0x07064b50
+	directory64EndSignature   = 0x06064b50
+	directoryHeaderLen        = 46
+	directoryEndLen           = 22
+	directory64LocLen         = 20
+	directory64EndLen         = 56
+	maxArchiveCommentLen      = 1<<16 - 1
+	zip64PlaceholderOffset    = 0xffffffff
+	zip64PlaceholderDirectory = 0xffff
+)
+
+// zipMethodNames 压缩方法编号对应的名称
+var zipMethodNames = map[uint16]string{
+	zip.Store:   "store",
+	zip.Deflate: "deflate",
+	9:           "deflate64",
+	12:          "bzip2",
+	14:          "lzma",
+	93:          "zstd",
+	95:          "xz",
+	98:          "ppmd",
+}
+
+// zipFileInfo 根据 ZIP 条目构建条目信息
+//
+// 参数:
+//   - file: ZIP 条目
+//   - offset: 条目本地文件头的偏移
+//
+// 返回:
+//   - types.FileInfo: 条目信息，符号链接目标由调用方读取
+func zipFileInfo(file *zip.File, offset int64) types.FileInfo {
+	mode := file.Mode()
+	info := types.FileInfo{
+		Name:           file.Name,
+		Size:           int64(file.UncompressedSize64),
+		CompressedSize: int64(file.CompressedSize64),
+		ModTime:        file.Modified,
+		Mode:           mode,
+		IsDir:          mode.IsDir(),
+		IsSymlink:      mode&os.ModeSymlink != 0,
+		Encrypted:      file.Flags&zipFlagEncrypted != 0,
+		Comment:        file.Comment,
+		EntryType:      types.EntryTypeFromMode(mode),
+		CRC32:          file.CRC32,
+		Method:         zipMethodName(file),
+		HeaderOffset:   offset,
+	}
+
+	forEachExtraField(file.Extra, func(id uint16, data []byte) {
+		switch id {
+		case unixOwnerExtraID:
+			info.Uid, info.Gid = parseUnixOwner(data)
+		case extTimeExtraID:
+			if atime, ok := parseExtTimeAccess(data); ok {
+				info.AccessTime = atime
+			}
+		case ntfsExtraID:
+			if atime, ok := parseNTFSAccess(data); ok {
+				info.AccessTime = atime
+			}
+		}
+	})
+	return info
+}
+
+// zipMethodName 返回条目实际使用的压缩方法名称
+//
+// 参数:
+//   - file: ZIP 条目
+//
+// 返回:
+//   - string: 压缩方法名称，AES 加密条目返回额外字段中记录的实际方法，未知方法返回 "method-N"
+func zipMethodName(file *zip.File) string {
+	method := file.Method
+	if method == methodWinZipAES {
+		if _, _, actual, err := parseAESExtra(file.Extra); err == nil {
+			method = actual
+		}
+	}
+	if name, ok := zipMethodNames[method]; ok {
+		return name
+	}
+	return "method-" + strconv.Itoa(int(method))
+}
+
+// forEachExtraField 遍历 ZIP 额外字段
+//
+// 参数:
+//   - extra: 额外字段数据，格式异常时停止遍历
+//   - fn: 对每个字段调用的函数
+func forEachExtraField(extra []byte, fn func(id uint16, data []byte)) {
+	for len(extra) >= 4 {
+		id := binary.LittleEndian.Uint16(extra[0:])
+		size := int(binary.LittleEndian.Uint16(extra[2:]))
+		if len(extra) < 4+size {
+			return
+		}
+		fn(id, extra[4:4+size])
+		extra = extra[4+size:]
+	}
+}
+
+// parseUnixOwner 解析 Info-ZIP 新版 Unix 额外字段
+//
+// 参数:
+//   - data: 字段数据
+//
+// 返回:
+//   - int: UID，格式异常时为 0
+//   - int: GID，格式异常时为 0
+func parseUnixOwner(data []byte) (int, int) {
+	if len(data) < 2 || data[0] != 1 {
+		return 0, 0
+	}
+	uid, rest, ok := readVarLenUint(data[1:])
+	if !ok {
+		return 0, 0
+	}
+	gid, _, ok := readVarLenUint(rest)
+	if !ok {
+		return int(uid), 0
+	}
+	return int(uid), int(gid)
+}
+
+// readVarLenUint 读取一字节长度前缀的小端无符号整数
+//
+// 参数:
+//   - data: 以长度字节开头的数据
+//
+// 返回:
+//   - uint64: 读取的整数
+//   - []byte: 剩余数据
+//   - bool: 数据完整且长度不超过 8 字节时为 true
+func readVarLenUint(data []byte) (uint64, []byte, bool) {
+	if len(data) < 1 {
+		return 0, nil, false
+	}
+	size := int(data[0])
+	if size > 8 || len(data) < 1+size {
+		return 0, nil, false
+	}
+	var value uint64
+	for i := size; i > 0; i-- {
+		value = value<<8 | uint64(data[i])
+	}
+	return value, data[1+size:], true
+}
+
+// parseExtTimeAccess 从扩展时间戳额外字段中读取访问时间
+//
+// 中央目录中的扩展时间戳通常只保留修改时间，此时返回 false。
+//
+// 参数:
+//   - data: 字段数据
+//
+// 返回:
+//   - time.Time: 访问时间
+//   - bool: 字段中包含访问时间时为 true
+func parseExtTimeAccess(data []byte) (time.Time, bool) {
+	if len(data) < 1 {
+		return time.Time{}, false
+	}
+	flags := data[0]
+	pos := 1
+	if flags&0x1 != 0 {
+		pos += 4
+	}
+	if flags&0x2 == 0 || len(data) < pos+4 {
+		return time.Time{}, false
+	}
+	return time.Unix(int64(binary.LittleEndian.Uint32(data[pos:])), 0), true
+}
+
+// parseNTFSAccess 从 NTFS 额外字段中读取访问时间
+//
+// 参数:
+//   - data: 字段数据
+//
+// 返回:
+//   - time.Time: 访问时间
+//   - bool: 字段中包含访问时间时为 true
+func parseNTFSAccess(data []byte) (time.Time, bool) {
+	if len(data) < 4 {
+		return time.Time{}, false
+	}
+	// 跳过 4 字节保留字段，之后是若干 tag/size/data 属性
+	for attrs := data[4:]; len(attrs) >= 4; {
+		tag := binary.LittleEndian.Uint16(attrs[0:])
+		size := int(binary.LittleEndian.Uint16(attrs[2:]))
+		if len(attrs) < 4+size {
+			break
+		}
+		if tag == 1 && size >= 24 {
+			return filetimeToTime(binary.LittleEndian.Uint64(attrs[4+8:])), true
+		}
+		attrs = attrs[4+size:]
+	}
+	return time.Time{}, false
+}
+
+// filetimeToTime 将 Windows FILETIME(自 1601 年起的 100 纳秒数)转换为时间
+func filetimeToTime(ft uint64) time.Time {
+	const epochDiff = 116444736000000000 // 1601-01-01 到 1970-01-01 的 100 纳秒数
+	return time.Unix(0, (int64(ft)-epochDiff)*100)
+}
+
+// headerOffsets 从中央目录读取各条目本地文件头的偏移
+//
+// 偏移已按压缩包前的附加数据(如自解压程序)修正，与 zip.Reader 的条目顺序一致。
+// 中央目录无法解析时返回 nil，调用方应将偏移视为未知。
+//
+// 参数:
+//   - r: ZIP 文件读取器
+//   - size: ZIP 文件大小
+//
+// 返回:
+//   - []int64: 条目本地文件头的偏移
+func headerOffsets(r io.ReaderAt, size int64) []int64 {
+	// 读取文件末尾，查找中央目录结束记录
+	tailLen := int64(directoryEndLen + maxArchiveCommentLen)
+	if tailLen > size {
+		tailLen = size
+	}
+	tail := make([]byte, tailLen)
+	if _, err := r.ReadAt(tail, size-tailLen); err != nil && err != io.EOF {
+		return nil
+	}
+	sig := binary.LittleEndian.AppendUint32(nil, directoryEndSignature)
+	endPos := bytes.LastIndex(tail, sig)
+	if endPos < 0 || len(tail)-endPos < directoryEndLen {
+		return nil
+	}
+	end := tail[endPos:]
+	endOffset := size - tailLen + int64(endPos)
+
+	records := uint64(binary.LittleEndian.Uint16(end[10:]))
+	dirSize := uint64(binary.LittleEndian.Uint32(end[12:]))
+	dirOffset := uint64(binary.LittleEndian.Uint32(end[16:]))
+
+	// ZIP64 压缩包从 ZIP64 中央目录结束记录读取
+	if records == zip64PlaceholderDirectory || dirSize == zip64PlaceholderOffset || dirOffset == zip64PlaceholderOffset {
+		locator := make([]byte, directory64LocLen)
+		if endOffset < directory64LocLen {
+			return nil
+		}
+		if _, err := r.ReadAt(locator, endOffset-directory64LocLen); err != nil ||
+			binary.LittleEndian.Uint32(locator) != directory64LocSignature {
+			return nil
+		}
+		end64 := make([]byte, directory64EndLen)
+		end64Offset := int64(binary.LittleEndian.Uint64(locator[8:]))
+		if _, err := r.ReadAt(end64, end64Offset); err != nil ||
+			binary.LittleEndian.Uint32(end64) != directory64EndSignature {
+			return nil
+		}
+		records = binary.LittleEndian.Uint64(end64[32:])
+		dirSize = binary.LittleEndian.Uint64(end64[40:])
+		dirOffset = binary.LittleEndian.Uint64(end64[48:])
+		endOffset = end64Offset
+	}
+
+	if dirSize > uint64(size) || dirOffset > uint64(size) {
+		return nil
+	}
+
+	// 压缩包前存在附加数据时，记录中的偏移需要整体平移；
+	// 与 zip.Reader 一致，按偏移 0 能读到中央目录时忽略附加数据
+	base := endOffset - int64(dirSize) - int64(dirOffset)
+	if base < 0 {
+		return nil
+	}
+	if base > 0 {
+		sig := make([]byte, 4)
+		if _, err := r.ReadAt(sig, int64(dirOffset)); err == nil &&
+			binary.LittleEndian.Uint32(sig) == directoryHeaderSignature {
+			base = 0
+		}
+	}
+	dir := make([]byte, dirSize)
+	if _, err := r.ReadAt(dir, base+int64(dirOffset)); err != nil {
+		return nil
+	}
+
+	offsets := make([]int64, 0, min(records, uint64(len(dir)/directoryHeaderLen)))
+	for len(dir) >= directoryHeaderLen && binary.LittleEndian.Uint32(dir) == directoryHeaderSignature {
+		nameLen := int(binary.LittleEndian.Uint16(dir[28:]))
+		extraLen := int(binary.LittleEndian.Uint16(dir[30:]))
+		commentLen := int(binary.LittleEndian.Uint16(dir[32:]))
+		recordLen := directoryHeaderLen + nameLen + extraLen + commentLen
+		if len(dir) < recordLen {
+			return nil
+		}
+
+		offset := uint64(binary.LittleEndian.Uint32(dir[42:]))
+		if offset == zip64PlaceholderOffset {
+			extra := dir[directoryHeaderLen+nameLen : directoryHeaderLen+nameLen+extraLen]
+			offset = zip64HeaderOffset(extra,
+				binary.LittleEndian.Uint32(dir[24:]) == zip64PlaceholderOffset,
+				binary.LittleEndian.Uint32(dir[20:]) == zip64PlaceholderOffset)
+		}
+		offsets = append(offsets, base+int64(offset))
+		dir = dir[recordLen:]
+	}
+	return offsets
+}
+
+// entryOffset 返回第 i 个条目本地文件头的偏移
+//
+// 参数:
+//   - offsets: headerOffsets 读取的偏移列表
+//   - i: 条目序号
+//
+// 返回:
+//   - int64: 本地文件头偏移，未知时返回 -1
+func entryOffset(offsets []int64, i int) int64 {
+	if i < len(offsets) {
+		return offsets[i]
+	}
+	return -1
+}
+
+// zip64HeaderOffset 从 ZIP64 额外字段中读取本地文件头偏移
+//
+// 参数:
+//   - extra: 中央目录记录的额外字段
+//   - hasSize: 原始大小是否记录在 ZIP64 字段中
+//   - hasCompressed: 压缩大小是否记录在 ZIP64 字段中
+//
+// 返回:
+//   - uint64: 本地文件头偏移，找不到时返回 0
+func zip64HeaderOffset(extra []byte, hasSize, hasCompressed bool) uint64 {
+	var offset uint64
+	forEachExtraField(extra, func(id uint16, data []byte) {
+		if id != zip64ExtraID {
+			return
+		}
+		pos := 0
+		if hasSize {
+			pos += 8
+		}
+		if hasCompressed {
+			pos += 8
+		}
+		if len(data) >= pos+8 {
+			offset = binary.LittleEndian.Uint64(data[pos:])
+		}
+	})
+	return offset
+}
@@ -0,0 +1,132 @@
+package cxzip
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/binary"
+	"hash/crc32"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"gitee.com/MM-Q/comprx/internal/config"
+	"gitee.com/MM-Q/comprx/types"
+)
+
+// unixOwnerExtra 构造 Info-ZIP 新版 Unix 额外字段
+func unixOwnerExtra(uid, gid uint32) []byte {
+	extra := make([]byte, 4, 15)
+	binary.LittleEndian.PutUint16(extra[0:], unixOwnerExtraID)
+	binary.LittleEndian.PutUint16(extra[2:], 11)
+	extra = append(extra, 1, 4)
+	extra = binary.LittleEndian.AppendUint32(extra, uid)
+	extra = append(extra, 4)
+	return binary.LittleEndian.AppendUint32(extra, gid)
+}
+
+// ntfsTimesExtra 构造只包含时间属性的 NTFS 额外字段
+func ntfsTimesExtra(mtime, atime, ctime time.Time) []byte {
+	filetime := func(t time.Time) uint64 { return uint64(t.UnixNano()/100 + 116444736000000000) }
+	extra := make([]byte, 8, 40)
+	binary.LittleEndian.PutUint16(extra[0:], ntfsExtraID)
+	binary.LittleEndian.PutUint16(extra[2:], 32)
+	extra = binary.LittleEndian.AppendUint16(extra, 1)
+	extra = binary.LittleEndian.AppendUint16(extra, 24)
+	extra = binary.LittleEndian.AppendUint64(extra, filetime(mtime))
+	extra = binary.LittleEndian.AppendUint64(extra, filetime(atime))
+	return binary.LittleEndian.AppendUint64(extra, filetime(ctime))
+}
+
+func TestListZip_Metadata(t *testing.T) {
+	accessTime := time.Date(2024, 5, 6, 7, 8, 9, 0, time.UTC)
+
+	// 在 ZIP 数据前附加一段前缀，模拟自解压程序
+	prefix := bytes.Repeat([]byte{0x7f}, 100)
+	var buf bytes.Buffer
+	buf.Write(prefix)
+	zipWriter := zip.NewWriter(&buf)
+	zipWriter.SetOffset(int64(len(prefix)))
+
+	content := []byte("hello metadata")
+	entries := []struct {
+		header *zip.FileHeader
+		data   []byte
+	}{
+		{&zip.FileHeader{Name: "stored.txt", Method: zip.Store, Extra: unixOwnerExtra(1000, 50)}, content},
+		{&zip.FileHeader{Name: "deflated.txt", Method: zip.Deflate, Extra: ntfsTimesExtra(accessTime, accessTime, accessTime)}, content},
+		{&zip.FileHeader{Name: "docs/", Method: zip.Store}, nil},
+	}
+	for _, entry := range entries {
+		w, err := zipWriter.CreateHeader(entry.header)
+		if err != nil {
+			t.Fatalf("创建条目失败: %v", err)
+		}
+		_, _ = w.Write(entry.data)
+	}
+	if err := zipWriter.Close(); err != nil {
+		t.Fatalf("关闭ZIP写入器失败: %v", err)
+	}
+
+	zipFile := filepath.Join(t.TempDir(), "meta.zip")
+	if err := os.WriteFile(zipFile, buf.Bytes(), 0644); err != nil {
+		t.Fatalf("写入ZIP文件失败: %v", err)
+	}
+
+	info, err := ListZip(zipFile)
+	if err != nil {
+		t.Fatalf("列出ZIP内容失败: %v", err)
+	}
+	if len(info.Files) != len(entries) {
+		t.Fatalf("条目数量 = %d, want %d", len(info.Files), len(entries))
+	}
+
+	stored, deflated, dir := info.Files[0], info.Files[1], info.Files[2]
+	if stored.Method != "store" || deflated.Method != "deflate" {
+		t.Errorf("压缩方法不正确: %s, %s", stored.Method, deflated.Method)
+	}
+	if stored.CRC32 != crc32.ChecksumIEEE(content) || deflated.CRC32 != stored.CRC32 {
+		t.Errorf("CRC32 不正确: %08x, %08x", stored.CRC32, deflated.CRC32)
+	}
+	if stored.Uid != 1000 || stored.Gid != 50 {
+		t.Errorf("属主信息不正确: %d/%d", stored.Uid, stored.Gid)
+	}
+	if !deflated.AccessTime.Equal(accessTime) {
+		t.Errorf("访问时间不正确: %v", deflated.AccessTime)
+	}
+	if stored.EntryType != types.EntryTypeFile || dir.EntryType != types.EntryTypeDir {
+		t.Errorf("条目类型不正确: %s, %s", stored.EntryType, dir.EntryType)
+	}
+
+	// 每个条目的偏移处都应是本地文件头
+	data := buf.Bytes()
+	for _, file := range info.Files {
+		if file.HeaderOffset < int64(len(prefix)) || binary.LittleEndian.Uint32(data[file.HeaderOffset:]) != 0x04034b50 {
+			t.Errorf("%s 的条目头偏移不正确: %d", file.Name, file.HeaderOffset)
+		}
+	}
+}
+
+func TestListZip_AESMethod(t *testing.T) {
+	tempDir := t.TempDir()
+	src := filepath.Join(tempDir, "secret.txt")
+	if err := os.WriteFile(src, []byte("top secret"), 0644); err != nil {
+		t.Fatalf("创建测试文件失败: %v", err)
+	}
+
+	cfg := config.New()
+	cfg.Password = "p@ss"
+	cfg.Encryption = types.EncryptionAES256
+	zipFile := filepath.Join(tempDir, "secret.zip")
+	if err := Zip(zipFile, src, cfg); err != nil {
+		t.Fatalf("压缩失败: %v", err)
+	}
+
+	info, err := ListZip(zipFile)
+	if err != nil {
+		t.Fatalf("列出ZIP内容失败: %v", err)
+	}
+	if file := info.Files[0]; !file.Encrypted || file.Method != "deflate" {
+		t.Errorf("AES 条目应显示实际压缩方法: %+v", file)
+	}
+}
@@ -34,6 +34,7 @@ package cxzip
 
 import (
 	"archive/zip"
+	"errors"
 	"fmt"
 	"io"
 	"os"
@@ -61,6 +62,11 @@ func Unzip(zipFilePath string, targetDir string, cfg *config.Config) error {
 	}
 	defer func() { _ = zipReader.Close() }()
 
+	// 将非 UTF-8 编码的文件名转换为 UTF-8
+	if err := decodeNames(zipReader.File, cfg.FilenameEncoding); err != nil {
+		return err
+	}
+
 	// 在进度条模式下计算总大小
 	totalSize := calculateZipTotalSize(zipReader, cfg)
 
@@ -83,6 +89,7 @@ func Unzip(zipFilePath string, targetDir string, cfg *config.Config) error {
 		if cfg.Filter != nil {
 			// 使用通用的过滤方法，传入文件路径、大小和是否为目录
 			if cfg.Filter.ShouldSkipByParams(file.Name, int64(file.UncompressedSize64), file.Mode().IsDir()) {
+				cfg.Checksums.Skip(file.Name)
 				continue // 跳过此文件
 			}
 		}
@@ -108,7 +115,7 @@ func Unzip(zipFilePath string, targetDir string, cfg *config.Config) error {
 		// 处理软链接
 		case mode&os.ModeSymlink != 0:
 			cfg.Progress.Inflating(targetPath) // 更新进度
-			if err := extractSymlink(file, targetPath); err != nil {
+			if err := extractSymlink(file, targetPath, cfg.Password); err != nil {
 				return err
 			}
 
@@ -185,11 +192,12 @@ func extractDirectory(targetPath, fileName string) error {
 // 参数:
 //   - file: ZIP文件条目
 //   - targetPath: 目标路径
+//   - password: 解密密码，条目未加密时忽略
 //
 // 返回值:
 //   - error: 操作过程中遇到的错误
-func extractSymlink(file *zip.File, targetPath string) error {
-	zipFileReader, err := file.Open()
+func extractSymlink(file *zip.File, targetPath string, password string) error {
+	zipFileReader, err := openFile(file, password)
 	if err != nil {
 		return fmt.Errorf("处理软链接 '%s' 时出错 - 打开 ZIP 文件中的软链接失败: %w", file.Name, err)
 	}
@@ -252,9 +260,25 @@ func extractRegularFileWithWriter(file *zip.File, targetPath string, mode os.Fil
 			return fmt.Errorf("处理文件 '%s' 时出错 - 创建空文件失败: %w", file.Name, err)
 		}
 		defer func() { _ = emptyFile.Close() }()
+
+		// 空文件同样需要记录摘要
+		_, commit := cfg.Checksums.Writer(file.Name, emptyFile)
+		commit()
 		return nil
 	}
 
+	// 先打开 ZIP 文件中的文件，密码错误时不会截断已存在的目标文件
+	zipFileReader, err := openFile(file, cfg.Password)
+	if err != nil {
+		// 密码相关的错误是已包含条目名称的 EntryError，不再重复条目名称
+		var entryErr types.EntryError
+		if errors.As(err, &entryErr) {
+			return fmt.Errorf("打开 zip 文件中的文件失败: %w", err)
+		}
+		return fmt.Errorf("处理文件 '%s' 时出错 - 打开 zip 文件中的文件失败: %w", file.Name, err)
+	}
+	defer func() { _ = zipFileReader.Close() }()
+
 	// 创建文件
 	fileWriter, err := os.OpenFile(targetPath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, mode.Perm())
 	if err != nil {
@@ -262,13 +286,6 @@ func extractRegularFileWithWriter(file *zip.File, targetPath string, mode os.Fil
 	}
 	defer func() { _ = fileWriter.Close() }()
 
-	// 打开 ZIP 文件中的文件
-	zipFileReader, err := file.Open()
-	if err != nil {
-		return fmt.Errorf("处理文件 '%s' 时出错 - 打开 zip 文件中的文件失败: %w", file.Name, err)
-	}
-	defer func() { _ = zipFileReader.Close() }()
-
 	// 获取对应文件大小的缓冲区
 	bufferSize := utils.GetBufferSize(int64(fileSize))
 
@@ -276,10 +293,12 @@ func extractRegularFileWithWriter(file *zip.File, targetPath string, mode os.Fil
 	buffer := utils.GetBuffer(bufferSize)
 	defer utils.PutBuffer(buffer)
 
-	// 将文件内容写入目标文件
-	if _, err := cfg.Progress.CopyBuffer(fileWriter, zipFileReader, buffer); err != nil {
+	// 将文件内容写入目标文件，同时计算摘要
+	sumWriter, commit := cfg.Checksums.Writer(file.Name, fileWriter)
+	if _, err := cfg.Progress.CopyBuffer(sumWriter, zipFileReader, buffer); err != nil {
 		return fmt.Errorf("处理文件 '%s' 时出错 - 写入文件失败: %w", file.Name, err)
 	}
+	commit()
 
 	return nil
 }
@@ -0,0 +1,100 @@
+// Package cxzip 提供 ZIP 压缩包的完整性校验功能实现。
+//
+// 该文件实现了 ZIP 压缩包的完整性校验：解码每个条目的全部数据并丢弃，
+// 由 archive/zip 在读取结束时校验 CRC32 和原始大小，不会写入任何文件。
+//
+// 主要功能：
+//   - 校验每个条目的 CRC32 和大小
+//   - 按条目名称报告损坏或截断的条目
+//
+// 使用示例：
+//
+//	report, err := cxzip.Verify("backup.zip", nil, "", "")
+package cxzip
+
+import (
+	"archive/zip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"gitee.com/MM-Q/comprx/types"
+)
+
+// Verify 校验 ZIP 压缩包的完整性
+//
+// 参数:
+//   - archivePath: ZIP 压缩包路径
+//   - filter: 过滤器，为 nil 时校验全部条目
+//   - password: 解密密码，用于校验已加密的条目
+//   - encoding: 未设置 UTF-8 标志位的文件名编码，空值表示自动检测
+//
+// 返回值:
+//   - *types.VerifyReport: 校验报告，损坏的条目记录在 Failures 中
+//   - error: 无法打开文件时返回错误
+func Verify(archivePath string, filter *types.FilterOptions, password string, encoding types.FilenameEncoding) (*types.VerifyReport, error) {
+	file, err := os.Open(archivePath)
+	if err != nil {
+		return nil, fmt.Errorf("打开 ZIP 文件失败: %w", err)
+	}
+	defer func() { _ = file.Close() }()
+
+	stat, err := file.Stat()
+	if err != nil {
+		return nil, fmt.Errorf("获取 ZIP 文件信息失败: %w", err)
+	}
+
+	report := &types.VerifyReport{Type: types.CompressTypeZip}
+
+	// 中央目录损坏时无法定位任何条目
+	zipReader, err := zip.NewReader(file, stat.Size())
+	if err != nil {
+		report.AddFailure("", fmt.Errorf("读取 ZIP 中央目录失败: %w", err))
+		return report, nil
+	}
+
+	// 将非 UTF-8 编码的文件名转换为 UTF-8
+	if err := decodeNames(zipReader.File, encoding); err != nil {
+		return nil, err
+	}
+
+	for _, entry := range zipReader.File {
+		// 应用过滤器检查
+		if filter != nil && filter.ShouldSkipByParams(entry.Name, int64(entry.UncompressedSize64), entry.Mode().IsDir()) {
+			continue
+		}
+
+		report.TotalEntries++
+		size, err := verifyEntry(entry, password)
+		report.TotalSize += size
+		if err != nil {
+			// 密码错误等错误已带有条目名称，取出原因避免名称重复
+			var entryErr types.EntryError
+			if errors.As(err, &entryErr) {
+				err = entryErr.Err
+			}
+			report.AddFailure(entry.Name, err)
+		}
+	}
+	return report, nil
+}
+
+// verifyEntry 解码 ZIP 条目的全部数据并校验
+//
+// 参数:
+//   - entry: ZIP 条目
+//   - password: 解密密码
+//
+// 返回值:
+//   - int64: 解码的数据大小
+//   - error: 数据损坏、截断、校验和不匹配或密码错误时返回错误
+func verifyEntry(entry *zip.File, password string) (int64, error) {
+	reader, err := openFile(entry, password)
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = reader.Close() }()
+
+	return io.Copy(io.Discard, reader)
+}
@@ -11,6 +11,8 @@
 //   - 文件过滤功能
 //   - 进度显示支持
 //   - 可配置的压缩等级
+//   - 多源打包（任意路径映射与重复条目检测）
+//   - 确定性打包（可重现的压缩包）
 //
 // 支持的文件类型：
 //   - 普通文件：使用配置的压缩方法
@@ -51,13 +53,29 @@ import (
 // 返回值:
 //   - error: 操作过程中遇到的错误
 func Zip(dst string, src string, cfg *config.Config) error {
+	return ZipSources(dst, []types.Source{{Path: src}}, cfg)
+}
+
+// ZipSources 函数用于将多个源打包到同一个ZIP压缩文件
+//
+// 参数:
+//   - dst: 生成的ZIP文件路径
+//   - sources: 打包源列表，每个源可以映射到压缩包内的任意位置
+//   - cfg: 压缩配置指针
+//
+// 返回值:
+//   - error: 操作过程中遇到的错误（包括压缩包内条目重复）
+func ZipSources(dst string, sources []types.Source, cfg *config.Config) error {
 	// 确保路径为绝对路径
 	var absErr error
 	if dst, absErr = utils.EnsureAbsPath(dst, "ZIP文件路径"); absErr != nil {
 		return absErr
 	}
-	if src, absErr = utils.EnsureAbsPath(src, "源路径"); absErr != nil {
-		return absErr
+
+	// 解析并检查打包源列表
+	sources, err := utils.PrepareSources(sources)
+	if err != nil {
+		return err
 	}
 
 	// 检查目标文件是否已存在
@@ -74,7 +92,7 @@ func Zip(dst string, src string, cfg *config.Config) error {
 	}
 
 	// 在进度条模式下计算源文件总大小
-	totalSize := progress.CalculateSourceTotalSizeWithProgress(src, cfg.Progress, "正在分析内容...", cfg.Filter)
+	totalSize := progress.CalculateSourcesTotalSizeWithProgress(sources, cfg.Progress, "正在分析内容...", cfg.Filter)
 
 	// 开始进度显示
 	if err := cfg.Progress.Start(totalSize, dst, fmt.Sprintf("正在压缩 %s...", filepath.Base(dst))); err != nil {
@@ -95,33 +113,69 @@ func Zip(dst string, src string, cfg *config.Config) error {
 	zipWriter := zip.NewWriter(zipFile)
 	defer func() { _ = zipWriter.Close() }()
 
-	// 检查源路径是文件还是目录
-	srcInfo, err := os.Stat(src)
+	// 确定性模式下按压缩包内路径排序打包源
+	if cfg.Deterministic {
+		utils.SortSources(sources)
+	}
+
+	// 依次处理每个打包源，并检测重复条目
+	names := utils.NewEntryNameSet()
+	for _, source := range sources {
+		if err := addSource(zipWriter, source, cfg, names); err != nil {
+			return fmt.Errorf("打包目录到 ZIP 失败: %w", err)
+		}
+	}
+
+	// 写入校验清单
+	if err := cfg.Checksums.WriteManifests(newBuilder(zipWriter, cfg, names)); err != nil {
+		return err
+	}
+
+	// 写入压缩包注释
+	return setArchiveComment(zipWriter, cfg.ArchiveComment)
+}
+
+// addSource 将单个打包源写入ZIP包
+//
+// 参数:
+//   - zipWriter: ZIP写入器
+//   - source: 已解析的打包源
+//   - cfg: 压缩配置
+//   - names: 已写入的条目名称集合
+//
+// 返回值:
+//   - error: 操作过程中遇到的错误
+func addSource(zipWriter *zip.Writer, source types.Source, cfg *config.Config, names utils.EntryNameSet) error {
+	// fs.FS 源：通过构建器写入条目
+	if source.FS != nil {
+		return utils.WriteFSTree(source.FS, source.Path, source.ArchivePath, cfg.Filter, newBuilder(zipWriter, cfg, names))
+	}
+
+	srcInfo, err := os.Stat(source.Path)
 	if err != nil {
 		return fmt.Errorf("获取源路径信息失败: %w", err)
 	}
 
-	// 根据源路径类型处理
-	var zipErr error
+	// 目录源：遍历目录并添加文件到 ZIP 包
 	if srcInfo.IsDir() {
-		// 遍历目录并添加文件到 ZIP 包
-		zipErr = walkDirectoryForZip(src, zipWriter, cfg)
-	} else {
-		// 单文件处理逻辑 - 检查是否应该跳过
-		if cfg.Filter != nil && cfg.Filter.ShouldSkipByParams(src, srcInfo.Size(), srcInfo.IsDir()) {
-			// 文件被过滤器跳过，直接返回成功
-			return nil
-		}
-		cfg.Progress.Adding(src)
-		zipErr = processRegularFile(zipWriter, src, filepath.Base(src), srcInfo, cfg)
+		return walkDirectoryForZip(source.Path, source.ArchivePath, zipWriter, cfg, names)
 	}
 
-	// 检查是否有错误发生
-	if zipErr != nil {
-		return fmt.Errorf("打包目录到 ZIP 失败: %w", zipErr)
+	// 文件源必须映射到具体的文件名
+	if source.ArchivePath == "." {
+		return fmt.Errorf("文件源 '%s' 必须指定压缩包内的文件名", source.Path)
 	}
 
-	return nil
+	// 单文件处理逻辑 - 检查是否应该跳过
+	if cfg.Filter != nil && cfg.Filter.ShouldSkipByParams(source.Path, srcInfo.Size(), srcInfo.IsDir()) {
+		// 文件被过滤器跳过
+		return nil
+	}
+	if err := names.Add(source.ArchivePath); err != nil {
+		return err
+	}
+	cfg.Progress.Adding(source.Path)
+	return processRegularFile(zipWriter, source.Path, source.ArchivePath, srcInfo, cfg)
 }
 
 // processRegularFile 处理普通文件
@@ -144,8 +198,8 @@ func processRegularFile(zipWriter *zip.Writer, path, headerName string, info os.
 	header.Name = headerName                  // 设置文件名
 	header.Method = getCompressionMethod(cfg) // 使用配置的压缩方法
 
-	// 创建 ZIP 写入器
-	fileWriter, err := zipWriter.CreateHeader(header)
+	// 创建 ZIP 写入器(按配置加密)
+	fileWriter, err := createFileHeader(zipWriter, header, cfg)
 	if err != nil {
 		return fmt.Errorf("处理文件 '%s' 时出错 - 创建 ZIP 写入器失败: %w", path, err)
 	}
@@ -165,10 +219,15 @@ func processRegularFile(zipWriter *zip.Writer, path, headerName string, info os.
 	buffer := utils.GetBuffer(bufferSize)
 	defer utils.PutBuffer(buffer)
 
-	// 复制文件内容到ZIP写入器
-	if _, err := cfg.Progress.CopyBuffer(fileWriter, file, buffer); err != nil {
+	// 复制文件内容到ZIP写入器，同时计算摘要
+	sumWriter, commit := cfg.Checksums.Writer(headerName, fileWriter)
+	if _, err := cfg.Progress.CopyBuffer(sumWriter, file, buffer); err != nil {
 		return fmt.Errorf("处理文件 '%s' 时出错 - 写入 ZIP 文件失败: %w", path, err)
 	}
+	if err := fileWriter.Close(); err != nil {
+		return fmt.Errorf("处理文件 '%s' 时出错 - 完成 ZIP 条目失败: %w", path, err)
+	}
+	commit()
 
 	return nil
 }
@@ -179,10 +238,11 @@ func processRegularFile(zipWriter *zip.Writer, path, headerName string, info os.
 //   - zipWriter: *zip.Writer - ZIP 文件写入器
 //   - headerName: string - ZIP 文件中的目录名
 //   - info: os.FileInfo - 目录信息
+//   - cfg: 压缩配置
 //
 // 返回值:
 //   - error - 操作过程中遇到的错误
-func processDirectory(zipWriter *zip.Writer, headerName string, info os.FileInfo) error {
+func processDirectory(zipWriter *zip.Writer, headerName string, info os.FileInfo, cfg *config.Config) error {
 	// 创建目录文件头
 	header, err := zip.FileInfoHeader(info)
 	if err != nil {
@@ -193,7 +253,7 @@ func processDirectory(zipWriter *zip.Writer, headerName string, info os.FileInfo
 	header.Method = zip.Store      // 使用不压缩的方法
 
 	// 创建目录文件头
-	if _, err := zipWriter.CreateHeader(header); err != nil {
+	if _, err := createHeader(zipWriter, header, cfg); err != nil {
 		return fmt.Errorf("处理目录 '%s' 时出错 - 创建 ZIP 目录失败: %w", headerName, err)
 	}
 	return nil
@@ -206,10 +266,11 @@ func processDirectory(zipWriter *zip.Writer, headerName string, info os.FileInfo
 //   - path: string - 软链接路径
 //   - headerName: string - ZIP 文件中的软链接名
 //   - mode: fs.FileMode - 文件模式
+//   - cfg: 压缩配置
 //
 // 返回值:
 //   - error - 操作过程中遇到的错误
-func processSymlink(zipWriter *zip.Writer, path, headerName string, mode fs.FileMode) error {
+func processSymlink(zipWriter *zip.Writer, path, headerName string, mode fs.FileMode, cfg *config.Config) error {
 	// 读取软链接目标
 	target, err := os.Readlink(path)
 	if err != nil {
@@ -224,7 +285,7 @@ func processSymlink(zipWriter *zip.Writer, path, headerName string, mode fs.File
 	header.SetMode(mode)
 
 	// 创建软链接文件
-	writer, err := zipWriter.CreateHeader(header)
+	writer, err := createHeader(zipWriter, header, cfg)
 	if err != nil {
 		return fmt.Errorf("处理软链接 '%s' 时出错 - 创建 ZIP 软链接失败: %w", path, err)
 	}
@@ -240,10 +301,11 @@ func processSymlink(zipWriter *zip.Writer, path, headerName string, mode fs.File
 //   - zipWriter: *zip.Writer - ZIP 文件写入器
 //   - headerName: string - ZIP 文件中的特殊文件名
 //   - mode: fs.FileMode - 文件模式
+//   - cfg: 压缩配置
 //
 // 返回值:
 //   - error - 操作过程中遇到的错误
-func processSpecialFile(zipWriter *zip.Writer, headerName string, mode fs.FileMode) error {
+func processSpecialFile(zipWriter *zip.Writer, headerName string, mode fs.FileMode, cfg *config.Config) error {
 	// 创建 ZIP 文件头
 	header := &zip.FileHeader{
 		Name:   headerName,
@@ -252,7 +314,7 @@ func processSpecialFile(zipWriter *zip.Writer, headerName string, mode fs.FileMo
 	header.SetMode(mode)
 
 	// 创建 ZIP 文件写入器
-	writer, err := zipWriter.CreateHeader(header)
+	writer, err := createHeader(zipWriter, header, cfg)
 	if err != nil {
 		return fmt.Errorf("处理特殊文件 '%s' 时出错 - 创建 ZIP 特殊文件失败: %w", headerName, err)
 	}
@@ -275,12 +337,14 @@ func getCompressionMethod(cfg *config.Config) uint16 {
 //
 // 参数:
 //   - src: 源目录路径
+//   - archiveBase: 源目录在压缩包中的路径，"." 表示压缩包根目录
 //   - zipWriter: ZIP写入器
 //   - cfg: 压缩配置
+//   - names: 已写入的条目名称集合，用于检测重复条目
 //
 // 返回值:
 //   - error: 遍历过程中发生的错误
-func walkDirectoryForZip(src string, zipWriter *zip.Writer, cfg *config.Config) error {
+func walkDirectoryForZip(src, archiveBase string, zipWriter *zip.Writer, cfg *config.Config, names utils.EntryNameSet) error {
 	return filepath.WalkDir(src, func(path string, entry fs.DirEntry, err error) error {
 		if err != nil {
 			// 如果不存在则忽略
@@ -305,14 +369,22 @@ func walkDirectoryForZip(src string, zipWriter *zip.Writer, cfg *config.Config)
 			return nil // 跳过文件
 		}
 
-		// 获取相对路径，保留顶层目录
-		headerName, err := filepath.Rel(filepath.Dir(src), path)
+		// 获取相对于源目录的路径
+		relPath, err := filepath.Rel(src, path)
 		if err != nil {
 			return fmt.Errorf("处理路径 '%s' 时出错 - 获取相对路径失败: %w", path, err)
 		}
 
-		// 替换路径分隔符为正斜杠(ZIP 文件格式要求)
-		headerName = filepath.ToSlash(headerName)
+		// 映射到压缩包内的路径(ZIP 文件格式要求使用正斜杠)
+		headerName := utils.JoinArchivePath(archiveBase, relPath)
+		if headerName == "." {
+			return nil // 源目录映射到压缩包根目录时，不写入根目录本身
+		}
+
+		// 检测重复条目
+		if err := names.Add(headerName); err != nil {
+			return err
+		}
 
 		// 根据文件类型处理
 		switch {
@@ -322,15 +394,15 @@ func walkDirectoryForZip(src string, zipWriter *zip.Writer, cfg *config.Config)
 
 		case entry.IsDir(): // 处理目录
 			cfg.Progress.Storing(headerName) // 显示进度
-			return processDirectory(zipWriter, headerName, info)
+			return processDirectory(zipWriter, headerName, info, cfg)
 
 		case entry.Type()&fs.ModeSymlink != 0: // 处理符号链接
 			cfg.Progress.Adding(headerName) // 显示进度
-			return processSymlink(zipWriter, path, headerName, entry.Type())
+			return processSymlink(zipWriter, path, headerName, entry.Type(), cfg)
 
 		default: // 处理特殊文件
 			cfg.Progress.Adding(headerName) // 显示进度
-			return processSpecialFile(zipWriter, headerName, entry.Type())
+			return processSpecialFile(zipWriter, headerName, entry.Type(), cfg)
 		}
 	})
 }
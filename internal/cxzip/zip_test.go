@@ -2,11 +2,13 @@ package cxzip
 
 import (
 	"archive/zip"
+	"bytes"
 	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"gitee.com/MM-Q/comprx/internal/config"
 	"gitee.com/MM-Q/comprx/internal/utils"
@@ -348,3 +350,136 @@ func BenchmarkZip_LargeFile(b *testing.B) {
 		_ = Zip(zipFile, testFile, cfg)
 	}
 }
+
+func TestZipSources_PathMapping(t *testing.T) {
+	tempDir := t.TempDir()
+
+	// 创建发布包的各个组成部分
+	files := map[string]string{
+		"bin/app":         "binary",
+		"docs/README.md":  "readme",
+		"build/VERSION":   "1.2.0",
+		"docs/api/api.md": "api",
+	}
+	for name, content := range files {
+		filePath := filepath.Join(tempDir, name)
+		if err := os.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
+			t.Fatalf("创建目录失败: %v", err)
+		}
+		if err := os.WriteFile(filePath, []byte(content), 0644); err != nil {
+			t.Fatalf("创建文件失败: %v", err)
+		}
+	}
+
+	zipFile := filepath.Join(tempDir, "release.zip")
+	sources := []types.Source{
+		{Path: filepath.Join(tempDir, "bin"), ArchivePath: "app-1.2/bin"},
+		{Path: filepath.Join(tempDir, "docs"), ArchivePath: "app-1.2/docs"},
+		{Path: filepath.Join(tempDir, "build", "VERSION"), ArchivePath: "app-1.2/VERSION"},
+	}
+	if err := ZipSources(zipFile, sources, config.New()); err != nil {
+		t.Fatalf("ZipSources 失败: %v", err)
+	}
+
+	reader, err := zip.OpenReader(zipFile)
+	if err != nil {
+		t.Fatalf("打开ZIP文件失败: %v", err)
+	}
+	defer func() { _ = reader.Close() }()
+
+	got := make(map[string]bool)
+	for _, f := range reader.File {
+		got[f.Name] = true
+	}
+
+	expected := []string{
+		"app-1.2/bin/",
+		"app-1.2/bin/app",
+		"app-1.2/docs/",
+		"app-1.2/docs/README.md",
+		"app-1.2/docs/api/",
+		"app-1.2/docs/api/api.md",
+		"app-1.2/VERSION",
+	}
+	for _, name := range expected {
+		if !got[name] {
+			t.Errorf("ZIP 中缺少条目: %s", name)
+		}
+	}
+	if len(reader.File) != len(expected) {
+		t.Errorf("期望 %d 个条目, 实际 %d", len(expected), len(reader.File))
+	}
+}
+
+func TestZipSources_DuplicateEntry(t *testing.T) {
+	tempDir := t.TempDir()
+
+	fileA := filepath.Join(tempDir, "a.txt")
+	fileB := filepath.Join(tempDir, "b.txt")
+	for _, f := range []string{fileA, fileB} {
+		if err := os.WriteFile(f, []byte("content"), 0644); err != nil {
+			t.Fatalf("创建文件失败: %v", err)
+		}
+	}
+
+	zipFile := filepath.Join(tempDir, "dup.zip")
+	sources := []types.Source{
+		{Path: fileA, ArchivePath: "conf/app.conf"},
+		{Path: fileB, ArchivePath: "conf/app.conf"},
+	}
+	err := ZipSources(zipFile, sources, config.New())
+	if err == nil {
+		t.Fatal("重复条目应返回错误")
+	}
+	if !strings.Contains(err.Error(), "重复") {
+		t.Errorf("错误信息应包含重复条目提示, 实际: %v", err)
+	}
+}
+
+func TestZip_Deterministic(t *testing.T) {
+	tempDir := t.TempDir()
+	srcDir := filepath.Join(tempDir, "src")
+	createTestFiles(t, srcDir)
+
+	cfg := config.New()
+	cfg.Deterministic = true
+	cfg.SourceDateEpoch = time.Unix(1700000000, 0).UTC()
+
+	first := filepath.Join(tempDir, "first.zip")
+	if err := Zip(first, srcDir, cfg); err != nil {
+		t.Fatalf("第一次打包失败: %v", err)
+	}
+
+	// 修改时间戳和权限后再次打包
+	future := time.Now().Add(time.Hour)
+	if err := os.Chtimes(filepath.Join(srcDir, "file1.txt"), future, future); err != nil {
+		t.Fatalf("修改时间戳失败: %v", err)
+	}
+	if err := os.Chmod(filepath.Join(srcDir, "file2.txt"), 0600); err != nil {
+		t.Fatalf("修改权限失败: %v", err)
+	}
+	second := filepath.Join(tempDir, "second.zip")
+	if err := Zip(second, srcDir, cfg); err != nil {
+		t.Fatalf("第二次打包失败: %v", err)
+	}
+
+	firstData, _ := os.ReadFile(first)
+	secondData, _ := os.ReadFile(second)
+	if !bytes.Equal(firstData, secondData) {
+		t.Fatal("确定性模式下两次打包结果应逐字节相同")
+	}
+
+	reader, err := zip.OpenReader(first)
+	if err != nil {
+		t.Fatalf("打开ZIP失败: %v", err)
+	}
+	defer func() { _ = reader.Close() }()
+	for _, f := range reader.File {
+		if len(f.Extra) != 0 {
+			t.Errorf("条目 %s 不应包含额外字段", f.Name)
+		}
+		if !f.FileInfo().IsDir() && f.Mode().Perm() != 0644 {
+			t.Errorf("条目 %s 权限应为 0644, 实际 %o", f.Name, f.Mode().Perm())
+		}
+	}
+}
@@ -0,0 +1,132 @@
+// Package cxzip 提供传统 PKWARE 加密（ZipCrypto）ZIP 条目的解密实现。
+//
+// 该文件实现了 APPNOTE 6.1 中的传统加密算法：使用三个 32 位密钥组成的流密码，
+// 条目数据前有 12 字节的加密头，解密后最后一个字节用于快速校验密码。
+// ZipCrypto 的安全性很弱，这里只提供解密，用于读取旧工具生成的压缩包。
+//
+// 密码校验：
+//   - 未使用数据描述符时，校验字节为 CRC32 的最高字节
+//   - 使用数据描述符时，校验字节为 MS-DOS 修改时间的高字节
+//
+// 主要功能：
+//   - 打开 ZipCrypto 加密的条目并校验密码和 CRC32
+package cxzip
+
+import (
+	"archive/zip"
+	"compress/flate"
+	"fmt"
+	"hash/crc32"
+	"io"
+
+	"gitee.com/MM-Q/comprx/types"
+)
+
+// zipCryptoHeaderLen ZipCrypto 加密头长度
+const zipCryptoHeaderLen = 12
+
+// openZipCryptoFile 打开 ZipCrypto 加密条目
+//
+// 参数:
+//   - file: 使用传统 PKWARE 加密的 ZIP 条目
+//   - password: 解密密码
+//
+// 返回:
+//   - io.ReadCloser: 解压后的条目内容，读到末尾时校验 CRC32
+//   - error: 密码错误或条目格式错误时返回错误
+func openZipCryptoFile(file *zip.File, password string) (io.ReadCloser, error) {
+	dataLen := int64(file.CompressedSize64) - zipCryptoHeaderLen
+	if dataLen < 0 {
+		return nil, fmt.Errorf("条目 %s 的加密数据被截断", file.Name)
+	}
+
+	raw, err := file.OpenRaw()
+	if err != nil {
+		return nil, err
+	}
+
+	// 解密加密头并快速校验密码
+	keys := newZipCryptoKeys(password)
+	header := make([]byte, zipCryptoHeaderLen)
+	if _, err := io.ReadFull(raw, header); err != nil {
+		return nil, fmt.Errorf("读取条目 %s 的加密头失败: %w", file.Name, err)
+	}
+	keys.decrypt(header)
+
+	check := byte(file.CRC32 >> 24)
+	if file.Flags&zipFlagDataDescriptor != 0 {
+		check = byte(file.ModifiedTime >> 8)
+	}
+	if header[zipCryptoHeaderLen-1] != check {
+		return nil, types.EntryError{Name: file.Name, Err: types.ErrWrongPassword}
+	}
+
+	decrypted := &zipCryptoReader{r: io.LimitReader(raw, dataLen), keys: keys}
+
+	// 解压
+	var rc io.ReadCloser
+	switch file.Method {
+	case zip.Store:
+		rc = io.NopCloser(decrypted)
+	case zip.Deflate:
+		rc = flate.NewReader(decrypted)
+	default:
+		return nil, fmt.Errorf("条目 %s 使用了不支持的压缩方法: %d", file.Name, file.Method)
+	}
+
+	// 校验字节只有 8 位，密码错误仍有 1/256 的概率通过，由 CRC32 兜底
+	return &crcReader{ReadCloser: rc, name: file.Name, want: file.CRC32, hash: crc32.NewIEEE()}, nil
+}
+
+// zipCryptoKeys ZipCrypto 流密码的密钥状态
+type zipCryptoKeys [3]uint32
+
+// newZipCryptoKeys 使用密码初始化密钥
+//
+// 参数:
+//   - password: 密码
+//
+// 返回:
+//   - *zipCryptoKeys: 初始化后的密钥
+func newZipCryptoKeys(password string) *zipCryptoKeys {
+	keys := &zipCryptoKeys{0x12345678, 0x23456789, 0x34567890}
+	for i := 0; i < len(password); i++ {
+		keys.update(password[i])
+	}
+	return keys
+}
+
+// update 使用明文字节更新密钥
+func (k *zipCryptoKeys) update(b byte) {
+	k[0] = crc32Update(k[0], b)
+	k[1] = (k[1]+k[0]&0xff)*134775813 + 1
+	k[2] = crc32Update(k[2], byte(k[1]>>24))
+}
+
+// decrypt 原地解密数据
+func (k *zipCryptoKeys) decrypt(buf []byte) {
+	for i, c := range buf {
+		temp := uint16(k[2] | 2)
+		plain := c ^ byte((uint32(temp)*uint32(temp^1))>>8)
+		k.update(plain)
+		buf[i] = plain
+	}
+}
+
+// crc32Update 按传统加密规范使用单个字节更新 CRC32
+func crc32Update(crc uint32, b byte) uint32 {
+	return crc32.IEEETable[byte(crc)^b] ^ crc>>8
+}
+
+// zipCryptoReader 解密 ZipCrypto 条目数据
+type zipCryptoReader struct {
+	r    io.Reader
+	keys *zipCryptoKeys
+}
+
+// Read 读取并解密数据
+func (r *zipCryptoReader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	r.keys.decrypt(p[:n])
+	return n, err
+}
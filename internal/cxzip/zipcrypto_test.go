@@ -0,0 +1,145 @@
+package cxzip
+
+import (
+	"archive/zip"
+	"bytes"
+	"compress/flate"
+	"encoding/hex"
+	"errors"
+	"hash/crc32"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gitee.com/MM-Q/comprx/internal/config"
+	"gitee.com/MM-Q/comprx/types"
+)
+
+// infoZipCryptoZip 由 Info-ZIP 的 zip -0 -P oldpass 生成的 ZipCrypto 加密 ZIP，
+// 使用数据描述符，包含 msg.txt: "hello from libarchive\n"
+const infoZipCryptoZip = "504b03040a00090000005c91525d717d4c11220000001600000007001c00" +
+	"6d73672e7478745554090003b00bd56ab30bd56a75780b00010400000000" +
+	"0400000000787b24fc6bc919ec8d852f5aa3c0140241a3da0436fc754c16" +
+	"9c31d8397be213cf6a504b0708717d4c112200000016000000504b01021e" +
+	"030a00090000005c91525d717d4c11220000001600000007001800000000" +
+	"0000000000a481000000006d73672e7478745554050003b00bd56a75780b" +
+	"000104000000000400000000504b050600000000010001004d0000007300" +
+	"00000000"
+
+// encrypt 使用 ZipCrypto 原地加密数据（仅用于测试）
+func (k *zipCryptoKeys) encrypt(buf []byte) {
+	for i, plain := range buf {
+		temp := uint16(k[2] | 2)
+		buf[i] = plain ^ byte((uint32(temp)*uint32(temp^1))>>8)
+		k.update(plain)
+	}
+}
+
+// writeZipCryptoEntry 写入不使用数据描述符的 ZipCrypto 加密 DEFLATE 条目
+func writeZipCryptoEntry(t *testing.T, zipWriter *zip.Writer, name, content, password string) {
+	t.Helper()
+
+	var compressed bytes.Buffer
+	fw, _ := flate.NewWriter(&compressed, flate.DefaultCompression)
+	_, _ = fw.Write([]byte(content))
+	_ = fw.Close()
+
+	crc := crc32.ChecksumIEEE([]byte(content))
+	header := make([]byte, zipCryptoHeaderLen)
+	header[zipCryptoHeaderLen-1] = byte(crc >> 24)
+	data := append(header, compressed.Bytes()...)
+	newZipCryptoKeys(password).encrypt(data)
+
+	w, err := zipWriter.CreateRaw(&zip.FileHeader{
+		Name:               name,
+		Method:             zip.Deflate,
+		Flags:              zipFlagEncrypted,
+		CRC32:              crc,
+		CompressedSize64:   uint64(len(data)),
+		UncompressedSize64: uint64(len(content)),
+	})
+	if err != nil {
+		t.Fatalf("创建加密条目失败: %v", err)
+	}
+	if _, err := w.Write(data); err != nil {
+		t.Fatalf("写入加密条目失败: %v", err)
+	}
+}
+
+func TestUnzip_ZipCrypto(t *testing.T) {
+	tempDir := t.TempDir()
+	zipFile := filepath.Join(tempDir, "legacy.zip")
+	content := strings.Repeat("legacy archive ", 200)
+
+	f, err := os.Create(zipFile)
+	if err != nil {
+		t.Fatalf("创建ZIP文件失败: %v", err)
+	}
+	zipWriter := zip.NewWriter(f)
+	writeZipCryptoEntry(t, zipWriter, "old/report.txt", content, "1234")
+	if err := zipWriter.Close(); err != nil {
+		t.Fatalf("关闭ZIP写入器失败: %v", err)
+	}
+	_ = f.Close()
+
+	cfg := config.New()
+	cfg.Progress.Enabled = false
+	cfg.Password = "1234"
+	outDir := filepath.Join(tempDir, "out")
+	if err := Unzip(zipFile, outDir, cfg); err != nil {
+		t.Fatalf("解压 ZipCrypto 条目失败: %v", err)
+	}
+	got, err := os.ReadFile(filepath.Join(outDir, "old", "report.txt"))
+	if err != nil || string(got) != content {
+		t.Errorf("解密内容不正确: %v", err)
+	}
+
+	// 列表和完整性校验
+	info, err := ListZip(zipFile)
+	if err != nil || len(info.Files) != 1 || !info.Files[0].Encrypted {
+		t.Errorf("列表信息不正确: %+v, %v", info, err)
+	}
+	if report, err := Verify(zipFile, nil, "1234", ""); err != nil || !report.OK() {
+		t.Errorf("校验失败: %+v, %v", report, err)
+	}
+
+	// 校验字节快速拒绝错误的密码，且不截断已解压的文件
+	cfg.Password = "4321"
+	cfg.OverwriteExisting = true
+	if err := Unzip(zipFile, outDir, cfg); !errors.Is(err, types.ErrWrongPassword) {
+		t.Errorf("密码错误时应返回 ErrWrongPassword: %v", err)
+	}
+	if got, _ := os.ReadFile(filepath.Join(outDir, "old", "report.txt")); string(got) != content {
+		t.Error("密码错误时不应修改已存在的文件")
+	}
+}
+
+func TestUnzip_ZipCryptoFromInfoZip(t *testing.T) {
+	data, err := hex.DecodeString(infoZipCryptoZip)
+	if err != nil {
+		t.Fatalf("解码测试数据失败: %v", err)
+	}
+	zipFile := filepath.Join(t.TempDir(), "infozip.zip")
+	if err := os.WriteFile(zipFile, data, 0644); err != nil {
+		t.Fatalf("写入ZIP文件失败: %v", err)
+	}
+
+	cfg := config.New()
+	cfg.Progress.Enabled = false
+	cfg.Password = "oldpass"
+	outDir := t.TempDir()
+	if err := Unzip(zipFile, outDir, cfg); err != nil {
+		t.Fatalf("解压 Info-ZIP 生成的加密ZIP失败: %v", err)
+	}
+	got, err := os.ReadFile(filepath.Join(outDir, "msg.txt"))
+	if err != nil || string(got) != "hello from libarchive\n" {
+		t.Errorf("解密内容不正确: %q, %v", got, err)
+	}
+
+	// 使用数据描述符时以修改时间的高字节作为校验字节
+	cfg.Password = "wrong"
+	if err := Unzip(zipFile, t.TempDir(), cfg); !errors.Is(err, types.ErrWrongPassword) {
+		t.Errorf("密码错误时应返回 ErrWrongPassword: %v", err)
+	}
+}
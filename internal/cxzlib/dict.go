@@ -0,0 +1,206 @@
+// Package cxzlib 提供 DEFLATE 预设字典的训练功能实现。
+//
+// 大量短小且结构相似的消息(如 JSON)单独压缩时几乎没有可引用的历史数据，
+// 使用包含公共子串的预设字典可以显著减小压缩结果。该文件从样本中统计
+// 在多个样本中出现的子串，按出现频率和长度选取片段组成字典。
+//
+// 主要功能：
+//   - 统计样本间的公共子串
+//   - 按未覆盖子串的得分贪心选取片段，避免字典中出现大量相近的片段
+//   - 得分最高的片段放在字典末尾(距离最近)
+//
+// 使用示例：
+//
+//	dict, err := cxzlib.TrainDictionary(samples, 4096)
+//	compressed, err := cxzlib.CompressBytesWithDict(message, types.CompressionLevelDefault, dict)
+package cxzlib
+
+import (
+	"container/heap"
+	"fmt"
+)
+
+const (
+	// MaxDictionarySize DEFLATE 能够引用的字典上限，即 32 KiB 的滑动窗口
+	MaxDictionarySize = 32 * 1024
+
+	// dictGramSize 统计公共子串时使用的最小子串长度
+	dictGramSize = 6
+)
+
+// gramStat 子串的统计信息
+type gramStat struct {
+	samples int // 包含该子串的样本数量
+	last    int // 最后一次计数的样本序号加一，用于同一样本只计一次
+}
+
+// TrainDictionary 从样本中训练预设字典
+//
+// 统计每个长度为 6 的子串出现在多少个样本中，将样本中连续由公共子串覆盖的片段作为候选，
+// 片段得分为其中各子串出现的样本数之和。按得分从高到低贪心选取片段，已被选中片段覆盖的子串
+// 不再计分，直到达到指定大小。得分最高的片段放在字典末尾，使匹配距离最短。
+//
+// 参数:
+//   - samples: 样本数据，应与实际要压缩的数据相似
+//   - size: 字典的最大字节数，超过 MaxDictionarySize 时按 MaxDictionarySize 处理
+//
+// 返回:
+//   - []byte: 预设字典
+//   - error: 参数无效或样本中没有公共子串时返回错误
+func TrainDictionary(samples [][]byte, size int) ([]byte, error) {
+	if len(samples) == 0 {
+		return nil, fmt.Errorf("训练字典需要至少一个样本")
+	}
+	if size <= 0 {
+		return nil, fmt.Errorf("字典大小必须大于 0: %d", size)
+	}
+	size = min(size, MaxDictionarySize)
+
+	// 统计每个子串出现在多少个样本中
+	stats := make(map[string]*gramStat)
+	for i, sample := range samples {
+		for j := 0; j+dictGramSize <= len(sample); j++ {
+			stat := stats[string(sample[j:j+dictGramSize])]
+			if stat == nil {
+				stat = &gramStat{}
+				stats[string(sample[j:j+dictGramSize])] = stat
+			}
+			if stat.last != i+1 {
+				stat.samples++
+				stat.last = i + 1
+			}
+		}
+	}
+
+	// 只有一个样本时无法比较样本之间的公共子串，使用样本中的所有子串
+	threshold := 2
+	if len(samples) == 1 {
+		threshold = 1
+	}
+
+	// 样本中连续由公共子串覆盖的区间作为候选片段
+	candidates := make(map[string]int)
+	for _, sample := range samples {
+		start, score := -1, 0
+		for j := 0; j+dictGramSize <= len(sample)+1; j++ {
+			common := j+dictGramSize <= len(sample)
+			var count int
+			if common {
+				count = stats[string(sample[j:j+dictGramSize])].samples
+				common = count >= threshold
+			}
+			if common {
+				if start < 0 {
+					start, score = j, 0
+				}
+				score += count
+				continue
+			}
+			if start >= 0 {
+				segment := string(sample[start : j-1+dictGramSize])
+				candidates[segment] = max(candidates[segment], score)
+				start = -1
+			}
+		}
+	}
+	if len(candidates) == 0 {
+		return nil, fmt.Errorf("样本中没有公共子串，无法训练字典")
+	}
+
+	// 按得分贪心选取片段: 已被选中片段覆盖的子串不再计分，分数变化的片段重新排队
+	queue := make(segmentQueue, 0, len(candidates))
+	for segment, score := range candidates {
+		queue = append(queue, scoredSegment{segment: segment, score: score})
+	}
+	heap.Init(&queue)
+
+	covered := make(map[string]bool)
+	var chosen []string
+	total := 0
+	for queue.Len() > 0 && total < size {
+		top := heap.Pop(&queue).(scoredSegment)
+		segment, score := trimCovered(top.segment, stats, covered)
+		if score == 0 {
+			continue
+		}
+		if score < top.score {
+			heap.Push(&queue, scoredSegment{segment: segment, score: score})
+			continue
+		}
+
+		// 放不下的片段截取末尾剩余空间的长度
+		if total+len(segment) > size {
+			segment = segment[len(segment)-(size-total):]
+		}
+		for j := 0; j+dictGramSize <= len(segment); j++ {
+			covered[segment[j:j+dictGramSize]] = true
+		}
+		chosen = append(chosen, segment)
+		total += len(segment)
+	}
+
+	// 得分最高的片段放在末尾
+	result := make([]byte, 0, total)
+	for i := len(chosen) - 1; i >= 0; i-- {
+		result = append(result, chosen[i]...)
+	}
+	return result, nil
+}
+
+// trimCovered 去除片段首尾已被覆盖的子串并重新计算得分
+//
+// 参数:
+//   - segment: 候选片段
+//   - stats: 子串统计信息
+//   - covered: 已被选中片段覆盖的子串
+//
+// 返回:
+//   - string: 去除首尾已覆盖部分后的片段
+//   - int: 未覆盖子串出现的样本数之和
+func trimCovered(segment string, stats map[string]*gramStat, covered map[string]bool) (string, int) {
+	first, last, score := -1, -1, 0
+	for j := 0; j+dictGramSize <= len(segment); j++ {
+		gram := segment[j : j+dictGramSize]
+		if covered[gram] {
+			continue
+		}
+		if first < 0 {
+			first = j
+		}
+		last = j
+		score += stats[gram].samples
+	}
+	if first < 0 {
+		return "", 0
+	}
+	return segment[first : last+dictGramSize], score
+}
+
+// scoredSegment 带得分的候选片段
+type scoredSegment struct {
+	segment string
+	score   int
+}
+
+// segmentQueue 按得分从高到低出队的优先队列，得分相同时按内容排序以保证结果稳定
+type segmentQueue []scoredSegment
+
+func (q segmentQueue) Len() int { return len(q) }
+
+func (q segmentQueue) Less(i, j int) bool {
+	if q[i].score != q[j].score {
+		return q[i].score > q[j].score
+	}
+	return q[i].segment < q[j].segment
+}
+
+func (q segmentQueue) Swap(i, j int) { q[i], q[j] = q[j], q[i] }
+
+func (q *segmentQueue) Push(x any) { *q = append(*q, x.(scoredSegment)) }
+
+func (q *segmentQueue) Pop() any {
+	old := *q
+	item := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return item
+}
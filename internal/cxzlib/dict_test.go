@@ -0,0 +1,104 @@
+package cxzlib
+
+import (
+	"bytes"
+	"compress/zlib"
+	"errors"
+	"fmt"
+	"testing"
+
+	"gitee.com/MM-Q/comprx/types"
+)
+
+// jsonMessage 生成结构相同、内容不同的 JSON 消息
+func jsonMessage(i int) []byte {
+	return []byte(fmt.Sprintf(`{"event":"order.created","version":2,"order_id":"ord_%08d","customer":{"id":%d,"tier":"gold"},"currency":"CNY","amount":%d,"status":"pending"}`, i*7919, i%97, i*31%1000))
+}
+
+// TestTrainDictionary 测试训练的字典能减小相似小消息的压缩结果
+func TestTrainDictionary(t *testing.T) {
+	var samples [][]byte
+	for i := 0; i < 200; i++ {
+		samples = append(samples, jsonMessage(i))
+	}
+	dict, err := TrainDictionary(samples, 1024)
+	if err != nil {
+		t.Fatalf("训练字典失败: %v", err)
+	}
+	if len(dict) == 0 || len(dict) > 1024 {
+		t.Fatalf("字典大小不正确: %d", len(dict))
+	}
+	if !bytes.Contains(dict, []byte(`"currency":"CNY","amount":`)) {
+		t.Errorf("字典应包含公共子串: %q", dict)
+	}
+
+	// 训练结果是确定的
+	again, _ := TrainDictionary(samples, 1024)
+	if !bytes.Equal(dict, again) {
+		t.Error("相同样本训练的字典应一致")
+	}
+
+	var plainSize, dictSize int
+	for i := 1000; i < 1100; i++ {
+		message := jsonMessage(i)
+		plain, err := CompressBytes(message, types.CompressionLevelDefault)
+		if err != nil {
+			t.Fatal(err)
+		}
+		compressed, err := CompressBytesWithDict(message, types.CompressionLevelDefault, dict)
+		if err != nil {
+			t.Fatalf("使用字典压缩失败: %v", err)
+		}
+		got, err := DecompressBytesWithDict(compressed, dict)
+		if err != nil || !bytes.Equal(got, message) {
+			t.Fatalf("使用字典解压结果不一致: %v", err)
+		}
+		plainSize += len(plain)
+		dictSize += len(compressed)
+	}
+	if dictSize*2 > plainSize {
+		t.Errorf("使用字典后应至少减小一半: 无字典 %d 字节, 有字典 %d 字节", plainSize, dictSize)
+	}
+}
+
+// TestDictMismatch 测试字典不一致时返回错误
+func TestDictMismatch(t *testing.T) {
+	dict := []byte(`{"event":"order.created","status":"pending"}`)
+	compressed, err := CompressBytesWithDict(jsonMessage(1), types.CompressionLevelBest, dict)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := DecompressBytes(compressed); !errors.Is(err, zlib.ErrDictionary) {
+		t.Errorf("未提供字典时应返回 zlib.ErrDictionary, 实际 %v", err)
+	}
+	if _, err := DecompressBytesWithDict(compressed, []byte("other")); !errors.Is(err, zlib.ErrDictionary) {
+		t.Errorf("字典不一致时应返回 zlib.ErrDictionary, 实际 %v", err)
+	}
+
+	// 流式接口
+	var buf, out bytes.Buffer
+	if err := CompressStreamWithDict(&buf, bytes.NewReader(jsonMessage(2)), types.CompressionLevelDefault, dict); err != nil {
+		t.Fatalf("流式压缩失败: %v", err)
+	}
+	if err := DecompressStreamWithDict(&out, &buf, dict); err != nil || !bytes.Equal(out.Bytes(), jsonMessage(2)) {
+		t.Errorf("流式解压结果不一致: %v", err)
+	}
+}
+
+// TestTrainDictionary_Invalid 测试无效参数
+func TestTrainDictionary_Invalid(t *testing.T) {
+	if _, err := TrainDictionary(nil, 100); err == nil {
+		t.Error("没有样本时应返回错误")
+	}
+	if _, err := TrainDictionary([][]byte{[]byte("abcdefgh")}, 0); err == nil {
+		t.Error("字典大小为 0 时应返回错误")
+	}
+	if _, err := TrainDictionary([][]byte{[]byte("abcdefgh"), []byte("12345678")}, 100); err == nil {
+		t.Error("没有公共子串时应返回错误")
+	}
+	dict, err := TrainDictionary([][]byte{bytes.Repeat([]byte("x"), 100000)}, 1<<20)
+	if err != nil || len(dict) != MaxDictionarySize {
+		t.Errorf("字典应截断到 %d 字节, 实际 %d: %v", MaxDictionarySize, len(dict), err)
+	}
+}
@@ -26,7 +26,6 @@
 // 性能优化：
 //   - 使用缓冲区池减少内存分配
 //   - 高效的文件大小计算方法
-//   - 错误容忍的大小估算机制
 //
 // 使用示例：
 //
@@ -103,8 +102,7 @@ func ListZlib(archivePath string) (*types.ArchiveInfo, error) {
 
 	originalSize, err := io.CopyBuffer(io.Discard, zlibReader, buffer)
 	if err != nil {
-		// 如果读取失败，使用压缩文件大小作为估算
-		originalSize = stat.Size()
+		return nil, fmt.Errorf("解压ZLIB数据失败: %w", err)
 	}
 
 	// 创建FileInfo
@@ -116,6 +114,9 @@ func ListZlib(archivePath string) (*types.ArchiveInfo, error) {
 		Mode:           utils.DefaultFileMode, // ZLIB不保存文件权限，使用默认权限
 		IsDir:          false,
 		IsSymlink:      false,
+		EntryType:      types.EntryTypeFile,
+		Method:         "deflate",
+		HeaderOffset:   -1, // ZLIB 没有条目头
 	}
 
 	// 创建ArchiveInfo
@@ -112,6 +112,9 @@ func TestListZlibLimit(t *testing.T) {
 	if len(archiveInfo.Files) != 1 {
 		t.Fatalf("文件列表长度不匹配，期望: 1, 实际: %d", len(archiveInfo.Files))
 	}
+	if archiveInfo.Files[0].HeaderOffset != -1 {
+		t.Errorf("ZLIB 没有条目头，偏移应为 -1: %d", archiveInfo.Files[0].HeaderOffset)
+	}
 
 	t.Logf("限制列表功能测试成功")
 }
@@ -201,6 +204,31 @@ func TestListZlibInvalidFile(t *testing.T) {
 	}
 }
 
+func TestListZlib_Truncated(t *testing.T) {
+	tempDir := t.TempDir()
+
+	testFile := filepath.Join(tempDir, "test.txt")
+	if err := os.WriteFile(testFile, []byte(strings.Repeat("truncated zlib data\n", 500)), 0644); err != nil {
+		t.Fatalf("创建测试文件失败: %v", err)
+	}
+	zlibFile := filepath.Join(tempDir, "test.zlib")
+	if err := Zlib(zlibFile, testFile, config.New()); err != nil {
+		t.Fatalf("ZLIB压缩失败: %v", err)
+	}
+	data, err := os.ReadFile(zlibFile)
+	if err != nil {
+		t.Fatalf("读取ZLIB文件失败: %v", err)
+	}
+	if err := os.WriteFile(zlibFile, data[:len(data)/2], 0644); err != nil {
+		t.Fatalf("截断ZLIB文件失败: %v", err)
+	}
+
+	// 解压失败时返回错误，不再用压缩大小估算
+	if info, err := ListZlib(zlibFile); err == nil || info != nil {
+		t.Errorf("解压失败时应返回错误, 实际 %+v, %v", info, err)
+	}
+}
+
 func TestListZlibDifferentExtensions(t *testing.T) {
 	// 创建临时目录
 	tempDir := t.TempDir()
@@ -7,6 +7,7 @@
 //   - ZLIB 内存压缩：字节数组和字符串的压缩解压
 //   - ZLIB 流式压缩：支持 io.Reader 和 io.Writer 接口
 //   - 支持自定义压缩等级
+//   - 支持预设字典(FDICT)
 //   - 优化的内存分配策略
 //   - 完善的错误处理和资源管理
 //
@@ -58,7 +59,23 @@ import (
 // 返回:
 //   - []byte: 压缩后的数据
 //   - error: 错误信息
-func CompressBytes(data []byte, level types.CompressionLevel) (result []byte, err error) {
+func CompressBytes(data []byte, level types.CompressionLevel) ([]byte, error) {
+	return CompressBytesWithDict(data, level, nil)
+}
+
+// CompressBytesWithDict 使用预设字典压缩字节数据到内存
+//
+// 解压时必须提供相同的字典。DEFLATE 的窗口为 32 KiB，字典超出部分只使用末尾的 32 KiB。
+//
+// 参数:
+//   - data: 要压缩的字节数据
+//   - level: 压缩级别
+//   - dict: 预设字典，为空时不使用字典
+//
+// 返回:
+//   - []byte: 压缩后的数据
+//   - error: 错误信息
+func CompressBytesWithDict(data []byte, level types.CompressionLevel, dict []byte) (result []byte, err error) {
 	// 参数验证 - 更精确的nil检查
 	if data == nil {
 		return nil, fmt.Errorf("输入数据不能为nil")
@@ -76,7 +93,7 @@ func CompressBytes(data []byte, level types.CompressionLevel) (result []byte, er
 	buf := bytes.NewBuffer(make([]byte, 0, estimatedSize))
 
 	// 创建zlib写入器
-	writer, err := zlib.NewWriterLevel(buf, config.GetCompressionLevel(level))
+	writer, err := zlib.NewWriterLevelDict(buf, config.GetCompressionLevel(level), dict)
 	if err != nil {
 		return nil, fmt.Errorf("创建zlib写入器失败: %w", err)
 	}
@@ -103,7 +120,20 @@ func CompressBytes(data []byte, level types.CompressionLevel) (result []byte, er
 // 返回:
 //   - []byte: 解压后的数据
 //   - error: 错误信息
-func DecompressBytes(compressedData []byte) (result []byte, err error) {
+func DecompressBytes(compressedData []byte) ([]byte, error) {
+	return DecompressBytesWithDict(compressedData, nil)
+}
+
+// DecompressBytesWithDict 使用预设字典从内存解压字节数据
+//
+// 参数:
+//   - compressedData: 压缩的字节数据
+//   - dict: 压缩时使用的预设字典
+//
+// 返回:
+//   - []byte: 解压后的数据
+//   - error: 字典与压缩时不一致时返回 zlib.ErrDictionary
+func DecompressBytesWithDict(compressedData []byte, dict []byte) (result []byte, err error) {
 	// 参数验证 - 更精确的nil检查
 	if compressedData == nil {
 		return nil, fmt.Errorf("压缩数据不能为nil")
@@ -116,7 +146,7 @@ func DecompressBytes(compressedData []byte) (result []byte, err error) {
 	reader := bytes.NewReader(compressedData)
 
 	// 创建zlib读取器
-	zlibReader, err := zlib.NewReader(reader)
+	zlibReader, err := zlib.NewReaderDict(reader, dict)
 	if err != nil {
 		return nil, fmt.Errorf("创建zlib读取器失败: %w", err)
 	}
@@ -199,7 +229,21 @@ func DecompressString(compressedData []byte) (string, error) {
 //
 // 返回:
 //   - error: 错误信息
-func CompressStream(dst io.Writer, src io.Reader, level types.CompressionLevel) (err error) {
+func CompressStream(dst io.Writer, src io.Reader, level types.CompressionLevel) error {
+	return CompressStreamWithDict(dst, src, level, nil)
+}
+
+// CompressStreamWithDict 使用预设字典流式压缩数据
+//
+// 参数:
+//   - dst: 目标写入器
+//   - src: 源读取器
+//   - level: 压缩级别
+//   - dict: 预设字典，为空时不使用字典
+//
+// 返回:
+//   - error: 错误信息
+func CompressStreamWithDict(dst io.Writer, src io.Reader, level types.CompressionLevel, dict []byte) (err error) {
 	// 1. 参数验证
 	if dst == nil {
 		err = fmt.Errorf("目标写入器不能为nil")
@@ -211,7 +255,7 @@ func CompressStream(dst io.Writer, src io.Reader, level types.CompressionLevel)
 	}
 
 	// 2. 创建zlib写入器
-	writer, createErr := zlib.NewWriterLevel(dst, config.GetCompressionLevel(level))
+	writer, createErr := zlib.NewWriterLevelDict(dst, config.GetCompressionLevel(level), dict)
 	if createErr != nil {
 		err = fmt.Errorf("创建zlib写入器失败: %w", createErr)
 		return
@@ -246,7 +290,20 @@ func CompressStream(dst io.Writer, src io.Reader, level types.CompressionLevel)
 //
 // 返回:
 //   - error: 错误信息
-func DecompressStream(dst io.Writer, src io.Reader) (err error) {
+func DecompressStream(dst io.Writer, src io.Reader) error {
+	return DecompressStreamWithDict(dst, src, nil)
+}
+
+// DecompressStreamWithDict 使用预设字典流式解压数据
+//
+// 参数:
+//   - dst: 目标写入器
+//   - src: 源读取器（压缩数据）
+//   - dict: 压缩时使用的预设字典
+//
+// 返回:
+//   - error: 字典与压缩时不一致时返回 zlib.ErrDictionary
+func DecompressStreamWithDict(dst io.Writer, src io.Reader, dict []byte) (err error) {
 	// 1. 参数验证
 	if dst == nil {
 		err = fmt.Errorf("目标写入器不能为nil")
@@ -258,7 +315,7 @@ func DecompressStream(dst io.Writer, src io.Reader) (err error) {
 	}
 
 	// 2. 创建zlib读取器
-	reader, createErr := zlib.NewReader(src)
+	reader, createErr := zlib.NewReaderDict(src, dict)
 	if createErr != nil {
 		err = fmt.Errorf("创建zlib读取器失败: %w", createErr)
 		return
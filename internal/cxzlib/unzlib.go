@@ -48,6 +48,7 @@ import (
 	"strings"
 
 	"gitee.com/MM-Q/comprx/internal/config"
+	"gitee.com/MM-Q/comprx/internal/envelope"
 	"gitee.com/MM-Q/comprx/internal/utils"
 	"gitee.com/MM-Q/comprx/types"
 )
@@ -57,10 +58,11 @@ import (
 // 参数:
 //   - zlibFilePath: ZLIB文件路径
 //   - cfg: 解压配置
+//   - opener: 加密信封打开器，与解压共用以复用派生出的密钥
 //
 // 返回值:
 //   - int64: 解压后的文件大小（字节）
-func calculateZlibTotalSize(zlibFilePath string, cfg *config.Config) int64 {
+func calculateZlibTotalSize(zlibFilePath string, cfg *config.Config, opener *envelope.Opener) int64 {
 	// 只在进度条模式下计算总大小
 	if !cfg.Progress.Enabled || cfg.Progress.BarStyle == types.ProgressStyleText {
 		return 0
@@ -73,7 +75,7 @@ func calculateZlibTotalSize(zlibFilePath string, cfg *config.Config) int64 {
 	}()
 
 	// 打开ZLIB文件进行扫描
-	zlibFile, err := os.Open(zlibFilePath)
+	zlibFile, err := opener.Open(zlibFilePath)
 	if err != nil {
 		return 0
 	}
@@ -109,8 +111,11 @@ func calculateZlibTotalSize(zlibFilePath string, cfg *config.Config) int64 {
 // 返回值:
 //   - error: 解压缩过程中发生的错误
 func Unzlib(zlibFilePath string, targetPath string, config *config.Config) error {
+	// 扫描总大小和解压共用打开器，加密信封的密钥只派生一次
+	opener := envelope.NewOpener(config.EncryptWith.Passphrase)
+
 	// 在进度条模式下计算总大小
-	totalSize := calculateZlibTotalSize(zlibFilePath, config)
+	totalSize := calculateZlibTotalSize(zlibFilePath, config, opener)
 
 	// 开始进度显示
 	if err := config.Progress.Start(totalSize, zlibFilePath, fmt.Sprintf("正在解压 %s...", filepath.Base(zlibFilePath))); err != nil {
@@ -121,14 +126,14 @@ func Unzlib(zlibFilePath string, targetPath string, config *config.Config) error
 	}()
 
 	// 打开 ZLIB 文件（同时检查文件是否存在）
-	zlibFile, err := os.Open(zlibFilePath)
+	zlibFile, err := opener.Open(zlibFilePath)
 	if err != nil {
 		return fmt.Errorf("打开 ZLIB 文件失败: %w", err)
 	}
 	defer func() { _ = zlibFile.Close() }()
 
 	// 获取ZLIB文件信息用于预验证
-	zlibInfo, err := zlibFile.Stat()
+	zlibInfo, err := os.Stat(zlibFilePath)
 	if err != nil {
 		return fmt.Errorf("获取ZLIB文件信息失败: %w", err)
 	}
@@ -0,0 +1,58 @@
+// Package cxzlib 提供 ZLIB 文件的完整性校验功能实现。
+//
+// 该文件实现了 ZLIB 文件的完整性校验：解压全部数据并丢弃，
+// 由 compress/zlib 校验尾部的 Adler-32 校验和，不会写入任何文件。
+//
+// 主要功能：
+//   - 校验 Adler-32 校验和
+//   - 发现截断的压缩数据
+//
+// 使用示例：
+//
+//	report, err := cxzlib.Verify("data.zlib", "")
+package cxzlib
+
+import (
+	"compress/zlib"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"gitee.com/MM-Q/comprx/internal/envelope"
+	"gitee.com/MM-Q/comprx/types"
+)
+
+// Verify 校验 ZLIB 文件的完整性
+//
+// 参数:
+//   - archivePath: ZLIB 文件路径
+//   - passphrase: 加密信封的口令，文件不是加密信封时忽略
+//
+// 返回值:
+//   - *types.VerifyReport: 校验报告，损坏时记录在 Failures 中
+//   - error: 无法打开文件、加密信封未提供口令或口令错误时返回错误
+func Verify(archivePath string, passphrase string) (*types.VerifyReport, error) {
+	file, err := envelope.Open(archivePath, passphrase)
+	if err != nil {
+		return nil, fmt.Errorf("打开ZLIB文件失败: %w", err)
+	}
+	defer func() { _ = file.Close() }()
+
+	report := &types.VerifyReport{Type: types.CompressTypeZlib, TotalEntries: 1}
+	name := strings.TrimSuffix(filepath.Base(archivePath), filepath.Ext(archivePath))
+
+	zlibReader, err := zlib.NewReader(file)
+	if err != nil {
+		report.AddFailure(name, fmt.Errorf("读取ZLIB文件头失败: %w", err))
+		return report, nil
+	}
+	defer func() { _ = zlibReader.Close() }()
+
+	size, err := io.Copy(io.Discard, zlibReader)
+	report.TotalSize = size
+	if err != nil {
+		report.AddFailure(name, fmt.Errorf("ZLIB数据校验失败: %w", err))
+	}
+	return report, nil
+}
@@ -39,6 +39,7 @@ import (
 	"path/filepath"
 
 	"gitee.com/MM-Q/comprx/internal/config"
+	"gitee.com/MM-Q/comprx/internal/envelope"
 	"gitee.com/MM-Q/comprx/internal/utils"
 )
 
@@ -97,7 +98,7 @@ func Zlib(dst string, src string, cfg *config.Config) error {
 	}()
 
 	// 创建 ZLIB 文件
-	zlibFile, err := os.Create(dst)
+	zlibFile, err := envelope.Create(dst, cfg.EncryptWith)
 	if err != nil {
 		return fmt.Errorf("创建 ZLIB 文件失败: %w", err)
 	}
@@ -130,5 +131,12 @@ func Zlib(dst string, src string, cfg *config.Config) error {
 		return fmt.Errorf("压缩文件失败: %w", err)
 	}
 
+	// 显式关闭以返回写入 ZLIB 尾部和加密末块时的错误
+	if err := zlibWriter.Close(); err != nil {
+		return fmt.Errorf("关闭 ZLIB 写入器失败: %w", err)
+	}
+	if err := zlibFile.Close(); err != nil {
+		return fmt.Errorf("关闭 ZLIB 文件失败: %w", err)
+	}
 	return nil
 }
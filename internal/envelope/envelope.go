@@ -0,0 +1,473 @@
+// Package envelope 提供口令加密信封的读写功能。
+//
+// 加密信封把整个压缩输出流包裹在认证加密容器中，用于 tar/tgz/gz/zlib 等
+// 本身不支持加密的格式。口令先经过 scrypt 或 Argon2id 派生出密钥，
+// 数据再按固定大小分块，使用 AES-256-GCM 或 ChaCha20-Poly1305 逐块加密，
+// 因此可以边压缩边加密，也可以边解密边解压，不需要额外的完整读写。
+//
+// 信封格式(版本 1，整数均为大端序)：
+//   - 5 字节魔数 "CXENV"，1 字节版本号
+//   - 1 字节派生算法，1 字节加密算法
+//   - 3 个 uint32 派生参数(scrypt: log2(N), r, p；Argon2id: time, memory(KiB), threads)
+//   - uint32 数据块大小，16 字节盐，8 字节口令校验值
+//   - 若干加密数据块，每块为密文加 16 字节认证标签
+//
+// 整个文件头作为每个数据块的附加认证数据，篡改参数会导致解密失败。
+// 每个数据块的 nonce 由块序号和末块标志组成，调换、删除或截断数据块都会被发现。
+//
+// 主要功能：
+//   - 流式加密写入和解密读取
+//   - 多次打开同一信封时复用派生出的密钥
+//   - 识别文件是否为加密信封
+//
+// 使用示例：
+//
+//	w, err := envelope.Create("dump.tgz", types.EnvelopeOptions{Passphrase: "secret"})
+//	r, err := envelope.Open("dump.tgz", "secret")
+package envelope
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+
+	"gitee.com/MM-Q/comprx/types"
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/chacha20poly1305"
+	"golang.org/x/crypto/scrypt"
+)
+
+// Magic 信封文件的魔数
+const Magic = "CXENV"
+
+const (
+	version      = 1                // 当前格式版本
+	headerLen    = 48               // 文件头长度
+	saltLen      = 16               // 盐长度
+	checkLen     = 8                // 口令校验值长度
+	keyLen       = 32               // 加密密钥长度
+	chunkSize    = 64 * 1024        // 默认数据块大小
+	maxChunkSize = 16 * 1024 * 1024 // 允许读取的最大数据块
+)
+
+// 文件头中的算法编号
+const (
+	kdfScrypt   byte = 1
+	kdfArgon2id byte = 2
+
+	cipherAES256GCM        byte = 1
+	cipherChaCha20Poly1305 byte = 2
+)
+
+// 默认派生参数
+const (
+	scryptLogN = 15 // N = 32768
+	scryptR    = 8
+	scryptP    = 1
+
+	argon2Time    = 3
+	argon2Memory  = 64 * 1024 // 64 MiB
+	argon2Threads = 4
+)
+
+// errTruncated 数据在末块之前结束
+var errTruncated = errors.New("加密数据被截断")
+
+// header 信封文件头
+type header struct {
+	kdf       byte
+	cipher    byte
+	params    [3]uint32
+	chunkSize uint32
+	salt      [saltLen]byte
+	check     [checkLen]byte
+}
+
+// marshal 编码文件头
+func (h *header) marshal() []byte {
+	buf := make([]byte, headerLen)
+	copy(buf, Magic)
+	buf[5] = version
+	buf[6] = h.kdf
+	buf[7] = h.cipher
+	for i, p := range h.params {
+		binary.BigEndian.PutUint32(buf[8+4*i:], p)
+	}
+	binary.BigEndian.PutUint32(buf[20:], h.chunkSize)
+	copy(buf[24:], h.salt[:])
+	copy(buf[40:], h.check[:])
+	return buf
+}
+
+// parseHeader 解析并检查文件头
+//
+// 参数:
+//   - buf: 文件头数据
+//
+// 返回:
+//   - *header: 解析后的文件头
+//   - error: 不是信封、版本不受支持或参数超出范围时返回错误
+func parseHeader(buf []byte) (*header, error) {
+	if len(buf) < headerLen || string(buf[:len(Magic)]) != Magic {
+		return nil, fmt.Errorf("不是加密信封文件")
+	}
+	if buf[5] != version {
+		return nil, fmt.Errorf("不支持的加密信封版本: %d", buf[5])
+	}
+
+	h := &header{kdf: buf[6], cipher: buf[7]}
+	for i := range h.params {
+		h.params[i] = binary.BigEndian.Uint32(buf[8+4*i:])
+	}
+	h.chunkSize = binary.BigEndian.Uint32(buf[20:])
+	copy(h.salt[:], buf[24:])
+	copy(h.check[:], buf[40:])
+
+	// 限制参数范围，避免恶意文件头耗尽内存
+	p := h.params
+	switch h.kdf {
+	case kdfScrypt:
+		if p[0] < 1 || p[0] > 20 || p[1] < 1 || p[1] > 32 || p[2] < 1 || p[2] > 16 || uint64(128)*uint64(p[1])<<p[0] > 1<<30 {
+			return nil, fmt.Errorf("scrypt 参数超出范围: N=2^%d r=%d p=%d", p[0], p[1], p[2])
+		}
+	case kdfArgon2id:
+		if p[0] < 1 || p[0] > 16 || p[1] < 8 || p[1] > 1<<20 || p[2] < 1 || p[2] > 255 {
+			return nil, fmt.Errorf("argon2id 参数超出范围: time=%d memory=%d threads=%d", p[0], p[1], p[2])
+		}
+	default:
+		return nil, fmt.Errorf("不支持的密钥派生算法编号: %d", h.kdf)
+	}
+	if h.cipher != cipherAES256GCM && h.cipher != cipherChaCha20Poly1305 {
+		return nil, fmt.Errorf("不支持的加密算法编号: %d", h.cipher)
+	}
+	if h.chunkSize < 1024 || h.chunkSize > maxChunkSize {
+		return nil, fmt.Errorf("数据块大小超出范围: %d", h.chunkSize)
+	}
+	return h, nil
+}
+
+// newAEAD 从口令派生密钥并创建 AEAD
+//
+// 参数:
+//   - passphrase: 口令
+//   - h: 文件头
+//
+// 返回:
+//   - cipher.AEAD: 加密算法实例
+//   - []byte: 口令校验值
+//   - error: 派生或创建失败时返回错误
+func newAEAD(passphrase string, h *header) (cipher.AEAD, []byte, error) {
+	var derived []byte
+	switch h.kdf {
+	case kdfScrypt:
+		var err error
+		derived, err = scrypt.Key([]byte(passphrase), h.salt[:], 1<<h.params[0], int(h.params[1]), int(h.params[2]), keyLen+checkLen)
+		if err != nil {
+			return nil, nil, fmt.Errorf("派生密钥失败: %w", err)
+		}
+	case kdfArgon2id:
+		derived = argon2.IDKey([]byte(passphrase), h.salt[:], h.params[0], h.params[1], uint8(h.params[2]), keyLen+checkLen)
+	}
+
+	key := derived[:keyLen]
+	var aead cipher.AEAD
+	switch h.cipher {
+	case cipherAES256GCM:
+		block, err := aes.NewCipher(key)
+		if err != nil {
+			return nil, nil, fmt.Errorf("创建 AES 加密器失败: %w", err)
+		}
+		if aead, err = cipher.NewGCM(block); err != nil {
+			return nil, nil, fmt.Errorf("创建 GCM 加密器失败: %w", err)
+		}
+	case cipherChaCha20Poly1305:
+		var err error
+		if aead, err = chacha20poly1305.New(key); err != nil {
+			return nil, nil, fmt.Errorf("创建 ChaCha20-Poly1305 加密器失败: %w", err)
+		}
+	}
+	return aead, derived[keyLen:], nil
+}
+
+// chunkNonce 生成数据块的 nonce：前 8 字节为块序号，最后 1 字节为末块标志
+func chunkNonce(nonce []byte, counter uint64, last bool) []byte {
+	clear(nonce)
+	binary.BigEndian.PutUint64(nonce, counter)
+	if last {
+		nonce[len(nonce)-1] = 1
+	}
+	return nonce
+}
+
+// Writer 加密信封写入器
+//
+// 写入的数据按块加密后写入底层写入器，Close 时写出末块。
+// Close 不会关闭底层写入器。
+type Writer struct {
+	w       io.Writer
+	aead    cipher.AEAD
+	ad      []byte // 附加认证数据(文件头)
+	buf     []byte // 待加密的明文
+	out     []byte // 密文缓冲区
+	nonce   []byte
+	counter uint64
+	closed  bool
+}
+
+// NewWriter 创建加密信封写入器并写入文件头
+//
+// 参数:
+//   - w: 底层写入器
+//   - opts: 加密信封选项
+//
+// 返回:
+//   - *Writer: 加密写入器
+//   - error: 选项无效或写入文件头失败时返回错误
+func NewWriter(w io.Writer, opts types.EnvelopeOptions) (*Writer, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+	if !opts.Enabled() {
+		return nil, fmt.Errorf("启用加密信封时必须设置口令")
+	}
+
+	h := &header{chunkSize: chunkSize}
+	switch opts.KDF {
+	case "", types.EnvelopeKDFScrypt:
+		h.kdf, h.params = kdfScrypt, [3]uint32{scryptLogN, scryptR, scryptP}
+	case types.EnvelopeKDFArgon2id:
+		h.kdf, h.params = kdfArgon2id, [3]uint32{argon2Time, argon2Memory, argon2Threads}
+	}
+	switch opts.Cipher {
+	case "", types.EnvelopeCipherAES256GCM:
+		h.cipher = cipherAES256GCM
+	case types.EnvelopeCipherChaCha20Poly1305:
+		h.cipher = cipherChaCha20Poly1305
+	}
+	if _, err := rand.Read(h.salt[:]); err != nil {
+		return nil, fmt.Errorf("生成盐失败: %w", err)
+	}
+
+	aead, check, err := newAEAD(opts.Passphrase, h)
+	if err != nil {
+		return nil, err
+	}
+	copy(h.check[:], check)
+
+	ad := h.marshal()
+	if _, err := w.Write(ad); err != nil {
+		return nil, fmt.Errorf("写入加密信封文件头失败: %w", err)
+	}
+
+	return &Writer{
+		w:     w,
+		aead:  aead,
+		ad:    ad,
+		buf:   make([]byte, 0, h.chunkSize),
+		out:   make([]byte, 0, int(h.chunkSize)+aead.Overhead()),
+		nonce: make([]byte, aead.NonceSize()),
+	}, nil
+}
+
+// Write 写入明文数据
+//
+// 参数:
+//   - p: 明文数据
+//
+// 返回:
+//   - int: 写入的字节数
+//   - error: 写入失败时返回错误
+func (w *Writer) Write(p []byte) (int, error) {
+	if w.closed {
+		return 0, fmt.Errorf("加密信封写入器已关闭")
+	}
+
+	written := 0
+	for len(p) > 0 {
+		// 缓冲区已满且还有数据时，缓冲区中的块不是末块
+		if len(w.buf) == cap(w.buf) {
+			if err := w.flush(false); err != nil {
+				return written, err
+			}
+		}
+		n := copy(w.buf[len(w.buf):cap(w.buf)], p)
+		w.buf = w.buf[:len(w.buf)+n]
+		p = p[n:]
+		written += n
+	}
+	return written, nil
+}
+
+// Close 加密并写出末块，重复调用时直接返回
+//
+// 返回:
+//   - error: 写入失败时返回错误
+func (w *Writer) Close() error {
+	if w.closed {
+		return nil
+	}
+	w.closed = true
+	return w.flush(true)
+}
+
+// flush 加密并写出缓冲区中的数据块
+func (w *Writer) flush(last bool) error {
+	w.out = w.aead.Seal(w.out[:0], chunkNonce(w.nonce, w.counter, last), w.buf, w.ad)
+	if _, err := w.w.Write(w.out); err != nil {
+		return fmt.Errorf("写入加密数据失败: %w", err)
+	}
+	w.counter++
+	w.buf = w.buf[:0]
+	return nil
+}
+
+// Reader 加密信封读取器
+type Reader struct {
+	r       *bufio.Reader
+	aead    cipher.AEAD
+	ad      []byte
+	in      []byte // 密文缓冲区
+	plain   []byte // 尚未读取的明文
+	nonce   []byte
+	counter uint64
+	done    bool // 已读取末块
+}
+
+// NewReader 读取文件头并创建加密信封读取器
+//
+// 参数:
+//   - r: 底层读取器，需位于文件头起始处
+//   - passphrase: 口令
+//
+// 返回:
+//   - *Reader: 解密读取器
+//   - error: 不是信封、未提供口令(types.ErrPasswordRequired)或口令错误(types.ErrWrongPassword)时返回错误
+func NewReader(r io.Reader, passphrase string) (*Reader, error) {
+	return NewOpener(passphrase).NewReader(r)
+}
+
+// Opener 加密信封打开器，缓存最近一次从口令派生出的密钥
+//
+// 口令派生刻意消耗大量时间和内存，同一次解压中多次打开同一个信封
+// (如先扫描总大小再解压)时使用同一个打开器，只派生一次密钥。
+// Opener 不能并发使用。
+type Opener struct {
+	passphrase string
+	ad         []byte      // 派生密钥时的文件头，文件头相同时复用密钥
+	aead       cipher.AEAD // 缓存的加密算法实例
+}
+
+// NewOpener 创建加密信封打开器
+//
+// 参数:
+//   - passphrase: 口令
+//
+// 返回:
+//   - *Opener: 加密信封打开器
+func NewOpener(passphrase string) *Opener {
+	return &Opener{passphrase: passphrase}
+}
+
+// NewReader 读取文件头并创建加密信封读取器，文件头与上次相同时复用已派生的密钥
+//
+// 参数:
+//   - r: 底层读取器，需位于文件头起始处
+//
+// 返回:
+//   - *Reader: 解密读取器
+//   - error: 不是信封、未提供口令(types.ErrPasswordRequired)或口令错误(types.ErrWrongPassword)时返回错误
+func (o *Opener) NewReader(r io.Reader) (*Reader, error) {
+	ad := make([]byte, headerLen)
+	if _, err := io.ReadFull(r, ad); err != nil {
+		return nil, fmt.Errorf("读取加密信封文件头失败: %w", err)
+	}
+	h, err := parseHeader(ad)
+	if err != nil {
+		return nil, err
+	}
+	if o.passphrase == "" {
+		return nil, fmt.Errorf("加密信封: %w", types.ErrPasswordRequired)
+	}
+
+	// 文件头包含盐和派生参数，相同时派生出的密钥也相同
+	aead := o.aead
+	if aead == nil || !bytes.Equal(o.ad, ad) {
+		var check []byte
+		if aead, check, err = newAEAD(o.passphrase, h); err != nil {
+			return nil, err
+		}
+		if subtle.ConstantTimeCompare(check, h.check[:]) != 1 {
+			return nil, fmt.Errorf("加密信封: %w", types.ErrWrongPassword)
+		}
+		o.ad, o.aead = ad, aead
+	}
+
+	return &Reader{
+		r:     bufio.NewReaderSize(r, int(h.chunkSize)+aead.Overhead()),
+		aead:  aead,
+		ad:    ad,
+		in:    make([]byte, int(h.chunkSize)+aead.Overhead()),
+		nonce: make([]byte, aead.NonceSize()),
+	}, nil
+}
+
+// Read 读取解密后的数据
+//
+// 参数:
+//   - p: 读取缓冲区
+//
+// 返回:
+//   - int: 读取的字节数
+//   - error: 数据被篡改或截断时返回错误，读完末块后返回 io.EOF
+func (r *Reader) Read(p []byte) (int, error) {
+	for len(r.plain) == 0 {
+		if r.done {
+			return 0, io.EOF
+		}
+		if err := r.next(); err != nil {
+			return 0, err
+		}
+	}
+	n := copy(p, r.plain)
+	r.plain = r.plain[n:]
+	return n, nil
+}
+
+// next 读取并解密下一个数据块
+func (r *Reader) next() error {
+	n, err := io.ReadFull(r.r, r.in)
+	last := false
+	switch {
+	case err == io.EOF || err == io.ErrUnexpectedEOF:
+		last = true // 不足一整块，只能是末块
+	case err != nil:
+		return fmt.Errorf("读取加密数据失败: %w", err)
+	default:
+		// 恰好一整块时，后面没有数据才是末块
+		if _, peekErr := r.r.Peek(1); peekErr == io.EOF {
+			last = true
+		}
+	}
+	if n < r.aead.Overhead() {
+		return errTruncated
+	}
+
+	plain, err := r.aead.Open(r.in[:0], chunkNonce(r.nonce, r.counter, last), r.in[:n], r.ad)
+	if err != nil {
+		if last {
+			return fmt.Errorf("第 %d 个数据块认证失败，数据被篡改或截断", r.counter+1)
+		}
+		return fmt.Errorf("第 %d 个数据块认证失败，数据被篡改", r.counter+1)
+	}
+	r.plain = plain
+	r.counter++
+	r.done = last
+	return nil
+}
@@ -0,0 +1,205 @@
+package envelope
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gitee.com/MM-Q/comprx/types"
+)
+
+// seal 使用指定选项加密数据
+func seal(t *testing.T, data []byte, opts types.EnvelopeOptions) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	w, err := NewWriter(&buf, opts)
+	if err != nil {
+		t.Fatalf("创建加密写入器失败: %v", err)
+	}
+	if _, err := w.Write(data); err != nil {
+		t.Fatalf("写入数据失败: %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("关闭加密写入器失败: %v", err)
+	}
+	return buf.Bytes()
+}
+
+// open 解密全部数据
+func open(data []byte, passphrase string) ([]byte, error) {
+	r, err := NewReader(bytes.NewReader(data), passphrase)
+	if err != nil {
+		return nil, err
+	}
+	return io.ReadAll(r)
+}
+
+func TestEnvelope_RoundTrip(t *testing.T) {
+	sizes := []int{0, 1, chunkSize - 1, chunkSize, 2 * chunkSize, 2*chunkSize + 7}
+	options := []types.EnvelopeOptions{
+		{Passphrase: "secret"},
+		{Passphrase: "secret", KDF: types.EnvelopeKDFArgon2id, Cipher: types.EnvelopeCipherChaCha20Poly1305},
+	}
+
+	for _, opts := range options {
+		for _, size := range sizes {
+			data := bytes.Repeat([]byte("0123456789"), size/10+1)[:size]
+			sealed := seal(t, data, opts)
+			if !bytes.HasPrefix(sealed, []byte(Magic)) {
+				t.Fatal("加密数据应以魔数开头")
+			}
+
+			got, err := open(sealed, "secret")
+			if err != nil {
+				t.Fatalf("%+v 大小 %d 解密失败: %v", opts, size, err)
+			}
+			if !bytes.Equal(got, data) {
+				t.Errorf("%+v 大小 %d 解密结果不一致", opts, size)
+			}
+		}
+	}
+}
+
+func TestEnvelope_Passphrase(t *testing.T) {
+	sealed := seal(t, []byte("hello"), types.EnvelopeOptions{Passphrase: "secret"})
+
+	if _, err := open(sealed, "wrong"); !errors.Is(err, types.ErrWrongPassword) {
+		t.Errorf("口令错误时应返回 ErrWrongPassword: %v", err)
+	}
+	if _, err := open(sealed, ""); !errors.Is(err, types.ErrPasswordRequired) {
+		t.Errorf("未提供口令时应返回 ErrPasswordRequired: %v", err)
+	}
+}
+
+func TestEnvelope_Tampered(t *testing.T) {
+	data := []byte(strings.Repeat("x", 2*chunkSize+100))
+	sealed := seal(t, data, types.EnvelopeOptions{Passphrase: "secret"})
+
+	cases := map[string][]byte{
+		"篡改数据":   append([]byte(nil), sealed...),
+		"篡改文件头":  append([]byte(nil), sealed...),
+		"截断末块":   sealed[:len(sealed)-200],
+		"删除末块":   sealed[:headerLen+2*(chunkSize+16)],
+		"只有文件头":  sealed[:headerLen],
+		"截断一整块后": append(append([]byte(nil), sealed[:headerLen+chunkSize+16]...), sealed[headerLen+2*(chunkSize+16):]...),
+	}
+	cases["篡改数据"][headerLen+100] ^= 0xff
+	cases["篡改文件头"][20] ^= 0x01 // 数据块大小
+
+	for name, tampered := range cases {
+		if _, err := open(tampered, "secret"); err == nil {
+			t.Errorf("%s 时应解密失败", name)
+		}
+	}
+}
+
+func TestEnvelope_Options(t *testing.T) {
+	if _, err := NewWriter(io.Discard, types.EnvelopeOptions{}); err == nil {
+		t.Error("未设置口令时应返回错误")
+	}
+	if _, err := NewWriter(io.Discard, types.EnvelopeOptions{Passphrase: "x", KDF: "pbkdf2"}); err == nil {
+		t.Error("不支持的派生算法应返回错误")
+	}
+	if _, err := NewWriter(io.Discard, types.EnvelopeOptions{Passphrase: "x", Cipher: "aes-128-cbc"}); err == nil {
+		t.Error("不支持的加密算法应返回错误")
+	}
+}
+
+func TestCreateOpen(t *testing.T) {
+	tempDir := t.TempDir()
+	plainPath := filepath.Join(tempDir, "plain.gz")
+	sealedPath := filepath.Join(tempDir, "sealed.gz")
+
+	for path, opts := range map[string]types.EnvelopeOptions{
+		plainPath:  {},
+		sealedPath: {Passphrase: "secret"},
+	} {
+		w, err := Create(path, opts)
+		if err != nil {
+			t.Fatalf("创建文件失败: %v", err)
+		}
+		if _, err := w.Write([]byte("payload")); err != nil {
+			t.Fatalf("写入文件失败: %v", err)
+		}
+		if err := w.Close(); err != nil {
+			t.Fatalf("关闭文件失败: %v", err)
+		}
+		if err := w.Close(); err != nil && path == sealedPath {
+			t.Errorf("重复关闭应直接返回: %v", err)
+		}
+	}
+
+	// 普通文件原样读取，不需要口令
+	if encrypted, err := IsEncrypted(plainPath); err != nil || encrypted {
+		t.Errorf("普通文件不应识别为加密信封: %v, %v", encrypted, err)
+	}
+	if data, _ := os.ReadFile(plainPath); string(data) != "payload" {
+		t.Errorf("普通文件内容不正确: %q", data)
+	}
+
+	// 加密信封自动解密
+	if encrypted, err := IsEncrypted(sealedPath); err != nil || !encrypted {
+		t.Errorf("应识别为加密信封: %v, %v", encrypted, err)
+	}
+	r, err := Open(sealedPath, "secret")
+	if err != nil {
+		t.Fatalf("打开加密信封失败: %v", err)
+	}
+	defer func() { _ = r.Close() }()
+	if data, err := io.ReadAll(r); err != nil || string(data) != "payload" {
+		t.Errorf("解密结果不正确: %q, %v", data, err)
+	}
+
+	if _, err := Open(sealedPath, "wrong"); !errors.Is(err, types.ErrWrongPassword) {
+		t.Errorf("口令错误时应返回 ErrWrongPassword: %v", err)
+	}
+}
+
+func TestOpener_ReusesKey(t *testing.T) {
+	first := seal(t, []byte("first"), types.EnvelopeOptions{Passphrase: "secret"})
+	second := seal(t, []byte("second"), types.EnvelopeOptions{Passphrase: "secret"})
+
+	opener := NewOpener("secret")
+	read := func(data []byte) string {
+		t.Helper()
+		r, err := opener.NewReader(bytes.NewReader(data))
+		if err != nil {
+			t.Fatalf("打开加密信封失败: %v", err)
+		}
+		plain, err := io.ReadAll(r)
+		if err != nil {
+			t.Fatalf("解密失败: %v", err)
+		}
+		return string(plain)
+	}
+
+	// 再次打开同一信封时复用已派生的密钥
+	if got := read(first); got != "first" {
+		t.Errorf("解密结果不正确: %q", got)
+	}
+	aead := opener.aead
+	if got := read(first); got != "first" || opener.aead != aead {
+		t.Errorf("再次打开同一信封时应复用密钥: %q", got)
+	}
+
+	// 盐不同的信封重新派生密钥
+	if got := read(second); got != "second" || opener.aead == aead {
+		t.Errorf("打开不同的信封时应重新派生密钥: %q", got)
+	}
+
+	// 口令错误时不缓存密钥
+	wrong := NewOpener("wrong")
+	for i := 0; i < 2; i++ {
+		if _, err := wrong.NewReader(bytes.NewReader(first)); !errors.Is(err, types.ErrWrongPassword) {
+			t.Errorf("口令错误时应返回 ErrWrongPassword: %v", err)
+		}
+	}
+	if wrong.aead != nil {
+		t.Error("口令错误时不应缓存密钥")
+	}
+}
@@ -0,0 +1,146 @@
+// Package envelope 提供按路径创建和打开加密信封文件的功能。
+//
+// 格式实现调用 Create 代替 os.Create、调用 Open 代替 os.Open，
+// 即可在未启用加密时保持原有行为，启用时透明地加密和解密。
+//
+// 主要功能：
+//   - 按选项创建普通文件或加密信封文件
+//   - 打开文件时自动识别并解密加密信封
+//   - 使用 Opener 多次打开同一文件时只派生一次密钥
+//   - 检查文件是否为加密信封
+package envelope
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"gitee.com/MM-Q/comprx/types"
+)
+
+// fileWriter 写入加密信封的文件
+type fileWriter struct {
+	*Writer
+	file *os.File
+}
+
+// Close 写出末块并关闭文件，重复调用时直接返回
+func (f *fileWriter) Close() error {
+	if f.Writer.closed {
+		return nil
+	}
+	if err := f.Writer.Close(); err != nil {
+		_ = f.file.Close()
+		return err
+	}
+	return f.file.Close()
+}
+
+// fileReader 读取加密信封的文件
+type fileReader struct {
+	*Reader
+	file *os.File
+}
+
+// Close 关闭文件
+func (f *fileReader) Close() error {
+	return f.file.Close()
+}
+
+// Create 创建输出文件
+//
+// 参数:
+//   - path: 文件路径
+//   - opts: 加密信封选项，未设置口令时创建普通文件
+//
+// 返回:
+//   - io.WriteCloser: 文件写入器，调用方必须检查 Close 的错误，末块在 Close 时写出
+//   - error: 创建文件或写入文件头失败时返回错误
+func Create(path string, opts types.EnvelopeOptions) (io.WriteCloser, error) {
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+	if !opts.Enabled() {
+		return file, nil
+	}
+
+	w, err := NewWriter(file, opts)
+	if err != nil {
+		_ = file.Close()
+		return nil, err
+	}
+	return &fileWriter{Writer: w, file: file}, nil
+}
+
+// Open 打开输入文件，是加密信封时返回解密读取器
+//
+// 参数:
+//   - path: 文件路径
+//   - passphrase: 口令，文件不是加密信封时忽略
+//
+// 返回:
+//   - io.ReadCloser: 文件读取器
+//   - error: 打开文件失败、未提供口令或口令错误时返回错误
+func Open(path string, passphrase string) (io.ReadCloser, error) {
+	return NewOpener(passphrase).Open(path)
+}
+
+// Open 打开输入文件，是加密信封时返回解密读取器，文件头与上次相同时复用已派生的密钥
+//
+// 参数:
+//   - path: 文件路径
+//
+// 返回:
+//   - io.ReadCloser: 文件读取器
+//   - error: 打开文件失败、未提供口令或口令错误时返回错误
+func (o *Opener) Open(path string) (io.ReadCloser, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	encrypted, err := isEnvelope(file)
+	if err != nil {
+		_ = file.Close()
+		return nil, err
+	}
+	if !encrypted {
+		return file, nil
+	}
+
+	r, err := o.NewReader(file)
+	if err != nil {
+		_ = file.Close()
+		return nil, err
+	}
+	return &fileReader{Reader: r, file: file}, nil
+}
+
+// IsEncrypted 检查文件是否为加密信封
+//
+// 参数:
+//   - path: 文件路径
+//
+// 返回:
+//   - bool: 以信封魔数开头时返回 true
+//   - error: 打开或读取文件失败时返回错误
+func IsEncrypted(path string) (bool, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return false, err
+	}
+	defer func() { _ = file.Close() }()
+
+	return isEnvelope(file)
+}
+
+// isEnvelope 读取文件开头的魔数，不移动读取位置
+func isEnvelope(file *os.File) (bool, error) {
+	magic := make([]byte, len(Magic)+1)
+	n, err := file.ReadAt(magic, 0)
+	if err != nil && err != io.EOF {
+		return false, fmt.Errorf("读取文件头失败: %w", err)
+	}
+	return n == len(magic) && string(magic[:len(Magic)]) == Magic, nil
+}
@@ -33,6 +33,7 @@ import (
 	"path/filepath"
 
 	"gitee.com/MM-Q/comprx/types"
+	"github.com/schollz/progressbar/v3"
 )
 
 // CalculateSourceTotalSizeWithProgress 计算源路径中所有普通文件的总大小并显示进度
@@ -64,6 +65,52 @@ func CalculateSourceTotalSizeWithProgress(srcPath string, progress *Progress, sc
 		_ = progress.CloseBar(bar)
 	}()
 
+	return sumSourceSize(srcPath, bar, filter)
+}
+
+// CalculateSourcesTotalSizeWithProgress 计算多个打包源中所有普通文件的总大小并显示进度
+//
+// 参数:
+//   - sources: 已解析的打包源列表（磁盘源或 fs.FS 源）
+//   - progress: 进度显示对象
+//   - scanMessage: 扫描时显示的消息，如 "正在分析内容..."
+//   - filter: 文件过滤器，用于跳过不需要的文件
+//
+// 返回值:
+//   - int64: 所有打包源中普通文件的总大小（字节）
+func CalculateSourcesTotalSizeWithProgress(sources []types.Source, progress *Progress, scanMessage string, filter *types.FilterOptions) int64 {
+	// 只在进度条模式下计算总大小
+	if !progress.Enabled || progress.BarStyle == types.ProgressStyleText {
+		return 0
+	}
+
+	// 开始扫描进度显示(多个源共用同一个扫描进度条)
+	bar := progress.StartScan(scanMessage)
+	defer func() {
+		_ = progress.CloseBar(bar)
+	}()
+
+	var totalSize int64
+	for _, source := range sources {
+		if source.FS != nil {
+			totalSize += sumFSSourceSize(source.FS, source.Path, bar, filter)
+			continue
+		}
+		totalSize += sumSourceSize(source.Path, bar, filter)
+	}
+	return totalSize
+}
+
+// sumSourceSize 计算单个源路径中所有普通文件的总大小
+//
+// 参数:
+//   - srcPath: 源路径（文件或目录）
+//   - bar: 扫描进度条
+//   - filter: 文件过滤器
+//
+// 返回值:
+//   - int64: 普通文件的总大小（字节）
+func sumSourceSize(srcPath string, bar *progressbar.ProgressBar, filter *types.FilterOptions) int64 {
 	var totalSize int64
 
 	// 检查是文件还是目录
@@ -120,3 +167,46 @@ func CalculateSourceTotalSizeWithProgress(srcPath string, progress *Progress, sc
 	// 其他类型文件（符号链接、设备文件等）不计算大小
 	return 0
 }
+
+// sumFSSourceSize 计算 fs.FS 源中所有普通文件的总大小
+//
+// 参数:
+//   - fsys: 源文件系统
+//   - root: FS 内的源路径
+//   - bar: 扫描进度条
+//   - filter: 文件过滤器
+//
+// 返回值:
+//   - int64: 普通文件的总大小（字节）
+func sumFSSourceSize(fsys fs.FS, root string, bar *progressbar.ProgressBar, filter *types.FilterOptions) int64 {
+	var totalSize int64
+
+	_ = fs.WalkDir(fsys, root, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return nil // 忽略错误，继续遍历
+		}
+
+		// 获取文件信息用于过滤检查
+		fileInfo, err := entry.Info()
+		if err != nil {
+			return nil // 忽略错误，继续遍历
+		}
+
+		// 应用过滤器检查
+		if filter != nil && filter.ShouldSkipByParams(path, fileInfo.Size(), fileInfo.IsDir()) {
+			if fileInfo.IsDir() {
+				return fs.SkipDir // 跳过整个目录
+			}
+			return nil // 跳过文件
+		}
+
+		// 只计算普通文件的大小
+		if entry.Type().IsRegular() {
+			totalSize += fileInfo.Size()
+			_ = bar.Add64(fileInfo.Size())
+		}
+		return nil
+	})
+
+	return totalSize
+}
@@ -0,0 +1,172 @@
+// Package signature 提供压缩包的 Ed25519 分离签名功能。
+//
+// 该包为压缩包生成与之分离的 .sig 签名文件，并在解压前验证压缩包的来源和完整性。
+// 签名使用标准库 crypto/ed25519 的 Ed25519ph 模式：先对压缩包做 SHA-512 摘要再签名，
+// 因此可以流式处理任意大小的压缩包，不需要把整个文件读入内存。
+//
+// 主要功能：
+//   - 为压缩包生成签名文件
+//   - 使用公钥验证压缩包签名
+//   - 验证签名的同时复制压缩包内容，确保解压的数据就是验证过的数据
+//
+// 签名文件格式：
+//   - 64 字节的原始 Ed25519 签名，默认路径为 <压缩包路径>.sig
+//
+// 使用示例：
+//
+//	// 发布端签名
+//	err := signature.Sign("plugin.tgz", privateKey)
+//
+//	// 接收端验证
+//	err := signature.Verify("plugin.tgz", "", publicKey)
+package signature
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/sha512"
+	"fmt"
+	"io"
+	"os"
+)
+
+// Extension 签名文件的扩展名
+const Extension = ".sig"
+
+// signatureContext 签名上下文，用于区分其他用途的 Ed25519ph 签名
+const signatureContext = "comprx archive signature v1"
+
+// Path 返回压缩包默认的签名文件路径
+//
+// 参数:
+//   - archivePath: 压缩包路径
+//
+// 返回:
+//   - string: 签名文件路径，如 plugin.tgz.sig
+func Path(archivePath string) string {
+	return archivePath + Extension
+}
+
+// Sign 为压缩包生成签名文件
+//
+// 参数:
+//   - archivePath: 压缩包路径
+//   - privateKey: Ed25519 私钥
+//
+// 返回:
+//   - error: 私钥无效、读取压缩包或写入签名文件失败时返回错误
+//
+// 注意:
+//   - 签名文件写入 <压缩包路径>.sig，已存在时会被覆盖
+func Sign(archivePath string, privateKey ed25519.PrivateKey) error {
+	if len(privateKey) != ed25519.PrivateKeySize {
+		return fmt.Errorf("无效的 Ed25519 私钥长度: %d，应为 %d 字节", len(privateKey), ed25519.PrivateKeySize)
+	}
+
+	digest, err := digestFile(archivePath, io.Discard)
+	if err != nil {
+		return err
+	}
+
+	sig, err := privateKey.Sign(nil, digest, signOptions())
+	if err != nil {
+		return fmt.Errorf("签名压缩包失败: %w", err)
+	}
+
+	sigPath := Path(archivePath)
+	if err := os.WriteFile(sigPath, sig, 0644); err != nil {
+		return fmt.Errorf("写入签名文件 %s 失败: %w", sigPath, err)
+	}
+	return nil
+}
+
+// Verify 使用公钥验证压缩包签名
+//
+// 参数:
+//   - archivePath: 压缩包路径
+//   - sigPath: 签名文件路径，为空时使用 <压缩包路径>.sig
+//   - publicKey: Ed25519 公钥
+//
+// 返回:
+//   - error: 公钥无效、签名文件缺失或签名与压缩包内容不匹配时返回错误
+func Verify(archivePath string, sigPath string, publicKey ed25519.PublicKey) error {
+	return VerifyTo(archivePath, sigPath, publicKey, io.Discard)
+}
+
+// VerifyTo 使用公钥验证压缩包签名，同时将读取到的压缩包内容写入 w
+//
+// 压缩包只打开和读取一次，写入 w 的内容就是参与验证的内容。
+// 先验证再按路径重新打开压缩包解压时，压缩包可能在两次打开之间被替换，
+// 解压前应验证写入私有临时文件的副本，再从副本解压。
+//
+// 参数:
+//   - archivePath: 压缩包路径
+//   - sigPath: 签名文件路径，为空时使用 <压缩包路径>.sig
+//   - publicKey: Ed25519 公钥
+//   - w: 压缩包内容的写入目标
+//
+// 返回:
+//   - error: 公钥无效、签名文件缺失、复制失败或签名与压缩包内容不匹配时返回错误
+//
+// 注意:
+//   - 返回错误时 w 中可能已写入部分或全部内容，调用方不应使用
+func VerifyTo(archivePath string, sigPath string, publicKey ed25519.PublicKey, w io.Writer) error {
+	if len(publicKey) != ed25519.PublicKeySize {
+		return fmt.Errorf("无效的 Ed25519 公钥长度: %d，应为 %d 字节", len(publicKey), ed25519.PublicKeySize)
+	}
+	if sigPath == "" {
+		sigPath = Path(archivePath)
+	}
+
+	sig, err := os.ReadFile(sigPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return fmt.Errorf("压缩包 %s 未签名: 找不到签名文件 %s", archivePath, sigPath)
+		}
+		return fmt.Errorf("读取签名文件 %s 失败: %w", sigPath, err)
+	}
+	if len(sig) != ed25519.SignatureSize {
+		return fmt.Errorf("签名文件 %s 格式错误: 长度 %d，应为 %d 字节", sigPath, len(sig), ed25519.SignatureSize)
+	}
+
+	digest, err := digestFile(archivePath, w)
+	if err != nil {
+		return err
+	}
+
+	if err := ed25519.VerifyWithOptions(publicKey, digest, sig, signOptions()); err != nil {
+		return fmt.Errorf("压缩包 %s 签名验证失败，文件可能被篡改或不是由该公钥对应的私钥签名", archivePath)
+	}
+	return nil
+}
+
+// digestFile 计算文件的 SHA-512 摘要
+//
+// 参数:
+//   - path: 文件路径
+//   - w: 同时写入文件内容的目标
+//
+// 返回:
+//   - []byte: SHA-512 摘要
+//   - error: 读取或写入失败时返回错误
+func digestFile(path string, w io.Writer) ([]byte, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("打开压缩包失败: %w", err)
+	}
+	defer func() { _ = file.Close() }()
+
+	h := sha512.New()
+	if _, err := io.Copy(io.MultiWriter(h, w), file); err != nil {
+		return nil, fmt.Errorf("读取压缩包失败: %w", err)
+	}
+	return h.Sum(nil), nil
+}
+
+// signOptions 返回 Ed25519ph 签名选项
+//
+// 返回:
+//   - *ed25519.Options: 使用 SHA-512 预哈希和固定上下文的签名选项
+func signOptions() *ed25519.Options {
+	return &ed25519.Options{Hash: crypto.SHA512, Context: signatureContext}
+}
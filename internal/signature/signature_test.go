@@ -0,0 +1,115 @@
+package signature
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeArchive 创建待签名的测试文件
+func writeArchive(t *testing.T, content string) string {
+	t.Helper()
+	archivePath := filepath.Join(t.TempDir(), "plugin.tgz")
+	if err := os.WriteFile(archivePath, []byte(content), 0644); err != nil {
+		t.Fatalf("创建测试文件失败: %v", err)
+	}
+	return archivePath
+}
+
+func TestSignAndVerify(t *testing.T) {
+	publicKey, privateKey, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatalf("生成密钥失败: %v", err)
+	}
+	archivePath := writeArchive(t, "plugin bundle")
+
+	if err := Sign(archivePath, privateKey); err != nil {
+		t.Fatalf("签名失败: %v", err)
+	}
+	sig, err := os.ReadFile(Path(archivePath))
+	if err != nil || len(sig) != ed25519.SignatureSize {
+		t.Fatalf("签名文件不正确: %d 字节, %v", len(sig), err)
+	}
+
+	if err := Verify(archivePath, "", publicKey); err != nil {
+		t.Errorf("验证签名失败: %v", err)
+	}
+
+	// 指定签名文件路径
+	otherSig := filepath.Join(t.TempDir(), "other.sig")
+	if err := os.WriteFile(otherSig, sig, 0644); err != nil {
+		t.Fatalf("复制签名文件失败: %v", err)
+	}
+	if err := Verify(archivePath, otherSig, publicKey); err != nil {
+		t.Errorf("使用指定签名文件验证失败: %v", err)
+	}
+}
+
+func TestVerify_Rejects(t *testing.T) {
+	publicKey, privateKey, _ := ed25519.GenerateKey(nil)
+	otherPublicKey, _, _ := ed25519.GenerateKey(nil)
+
+	// 未签名
+	unsigned := writeArchive(t, "unsigned")
+	if err := Verify(unsigned, "", publicKey); err == nil || !strings.Contains(err.Error(), "未签名") {
+		t.Errorf("未签名的压缩包应验证失败: %v", err)
+	}
+
+	// 被篡改
+	tampered := writeArchive(t, "original")
+	if err := Sign(tampered, privateKey); err != nil {
+		t.Fatalf("签名失败: %v", err)
+	}
+	if err := os.WriteFile(tampered, []byte("modified"), 0644); err != nil {
+		t.Fatalf("篡改文件失败: %v", err)
+	}
+	if err := Verify(tampered, "", publicKey); err == nil {
+		t.Error("被篡改的压缩包应验证失败")
+	}
+
+	// 公钥不匹配
+	signed := writeArchive(t, "signed")
+	if err := Sign(signed, privateKey); err != nil {
+		t.Fatalf("签名失败: %v", err)
+	}
+	if err := Verify(signed, "", otherPublicKey); err == nil {
+		t.Error("使用其他公钥应验证失败")
+	}
+
+	// 无效的密钥
+	if err := Verify(signed, "", ed25519.PublicKey{1, 2, 3}); err == nil {
+		t.Error("无效的公钥应返回错误")
+	}
+	if err := Sign(signed, ed25519.PrivateKey{1, 2, 3}); err == nil {
+		t.Error("无效的私钥应返回错误")
+	}
+}
+
+func TestVerifyTo(t *testing.T) {
+	publicKey, privateKey, _ := ed25519.GenerateKey(nil)
+	archivePath := writeArchive(t, "plugin bundle")
+	if err := Sign(archivePath, privateKey); err != nil {
+		t.Fatalf("签名失败: %v", err)
+	}
+
+	// 写入的内容就是参与验证的内容
+	var copied bytes.Buffer
+	if err := VerifyTo(archivePath, "", publicKey, &copied); err != nil {
+		t.Fatalf("验证签名失败: %v", err)
+	}
+	if copied.String() != "plugin bundle" {
+		t.Errorf("复制的内容不正确: %q", copied.String())
+	}
+
+	// 签名不匹配时返回错误
+	if err := os.WriteFile(archivePath, []byte("replaced"), 0644); err != nil {
+		t.Fatalf("替换测试文件失败: %v", err)
+	}
+	copied.Reset()
+	if err := VerifyTo(archivePath, "", publicKey, &copied); err == nil {
+		t.Error("被替换的压缩包应验证失败")
+	}
+}
@@ -0,0 +1,102 @@
+// Package utils 提供向已有压缩包追加条目时的冲突处理工具。
+//
+// 该文件根据覆盖策略计算追加条目与已有条目之间的冲突处理计划：
+// 同名目录直接合并，其他同名条目在允许覆盖时替换已有条目，否则返回错误。
+//
+// 主要类型：
+//   - AppendPlan: 追加条目的冲突处理计划
+//
+// 使用示例：
+//
+//	plan, err := utils.PlanAppend(existing, added, cfg.OverwriteExisting)
+//	if plan.Replaces("logs/app.log") {
+//	    // 跳过已有压缩包中的该条目
+//	}
+package utils
+
+import (
+	"fmt"
+	"strings"
+)
+
+// EntryKinds 条目名称到是否为目录的映射，名称不含末尾斜杠
+type EntryKinds map[string]bool
+
+// Add 记录条目
+//
+// 参数:
+//   - name: 条目名称（目录可带末尾斜杠）
+//   - isDir: 是否为目录
+func (k EntryKinds) Add(name string, isDir bool) {
+	k[strings.TrimSuffix(name, "/")] = isDir
+}
+
+// AppendPlan 追加条目的冲突处理计划
+type AppendPlan struct {
+	replace map[string]struct{} // 被新条目覆盖、需要从已有压缩包中移除的条目
+	skip    map[string]struct{} // 已存在同名目录、不需要重复写入的新目录条目
+}
+
+// PlanAppend 计算追加条目的冲突处理计划
+//
+// 参数:
+//   - existing: 已有压缩包中的条目
+//   - added: 待追加的条目
+//   - overwrite: 是否允许覆盖已有条目
+//
+// 返回:
+//   - *AppendPlan: 冲突处理计划
+//   - error: 存在冲突且不允许覆盖时返回错误
+func PlanAppend(existing, added EntryKinds, overwrite bool) (*AppendPlan, error) {
+	plan := &AppendPlan{
+		replace: make(map[string]struct{}),
+		skip:    make(map[string]struct{}),
+	}
+
+	for name, isDir := range added {
+		existingIsDir, exists := existing[name]
+		switch {
+		case !exists:
+			continue
+		case isDir && existingIsDir: // 同名目录直接合并
+			plan.skip[name] = struct{}{}
+		case overwrite: // 允许覆盖时替换已有条目
+			plan.replace[name] = struct{}{}
+		default:
+			return nil, fmt.Errorf("压缩包中已存在条目 %s，如需覆盖请设置 OverwriteExisting 为 true", name)
+		}
+	}
+	return plan, nil
+}
+
+// HasReplacements 检查是否有需要替换的已有条目
+//
+// 返回:
+//   - bool: 存在需要替换的条目返回 true
+func (p *AppendPlan) HasReplacements() bool {
+	return len(p.replace) > 0
+}
+
+// Replaces 检查已有条目是否被新条目覆盖
+//
+// 参数:
+//   - name: 已有条目名称
+//
+// 返回:
+//   - bool: 需要从已有压缩包中移除返回 true
+func (p *AppendPlan) Replaces(name string) bool {
+	_, ok := p.replace[strings.TrimSuffix(name, "/")]
+	return ok
+}
+
+// Skips 检查新条目是否需要跳过
+//
+// 参数:
+//   - name: 新条目名称
+//
+// 返回:
+//   - bool: 已存在同名目录、不需要写入返回 true
+func (p *AppendPlan) Skips(name string) bool {
+	_, ok := p.skip[strings.TrimSuffix(name, "/")]
+	return ok
+}
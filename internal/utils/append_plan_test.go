@@ -0,0 +1,40 @@
+package utils
+
+import "testing"
+
+func TestPlanAppend(t *testing.T) {
+	existing := EntryKinds{}
+	existing.Add("logs/", true)
+	existing.Add("logs/app.log", false)
+
+	added := EntryKinds{}
+	added.Add("logs/", true)
+	added.Add("logs/app.log", false)
+	added.Add("logs/new.log", false)
+
+	// 不允许覆盖时同名文件返回错误
+	if _, err := PlanAppend(existing, added, false); err == nil {
+		t.Error("期望同名文件冲突返回错误")
+	}
+
+	plan, err := PlanAppend(existing, added, true)
+	if err != nil {
+		t.Fatalf("计算追加计划失败: %v", err)
+	}
+	if !plan.HasReplacements() || !plan.Replaces("logs/app.log") {
+		t.Error("同名文件应被替换")
+	}
+	if !plan.Skips("logs/") || plan.Replaces("logs/") {
+		t.Error("同名目录应直接合并")
+	}
+	if plan.Skips("logs/new.log") || plan.Replaces("logs/new.log") {
+		t.Error("新条目不应被跳过或替换")
+	}
+
+	// 文件与目录同名时按覆盖策略处理
+	added = EntryKinds{}
+	added.Add("logs/app.log/", true)
+	if _, err := PlanAppend(existing, added, false); err == nil {
+		t.Error("期望文件与目录同名返回错误")
+	}
+}
@@ -0,0 +1,231 @@
+// Package utils 提供压缩包内条目路径处理的工具函数。
+//
+// 该文件实现了压缩包内路径的标准化和重复条目检测功能，
+// 用于多源打包时将源路径映射到压缩包内的任意位置。
+//
+// 主要功能：
+//   - 压缩包内路径标准化（统一正斜杠、去除前导斜杠）
+//   - 拒绝包含上级目录引用的不安全路径
+//   - 重复条目检测
+//   - 打包源列表解析与排序
+//
+// 使用示例：
+//
+//	// 标准化压缩包内路径
+//	name, err := utils.NormalizeArchivePath("app-1.2\\bin")
+//
+//	// 检测重复条目
+//	names := utils.NewEntryNameSet()
+//	err := names.Add("app-1.2/bin/app")
+package utils
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gitee.com/MM-Q/comprx/types"
+)
+
+// NormalizeArchivePath 标准化压缩包内的路径
+//
+// 参数:
+//   - archivePath: 压缩包内的路径
+//
+// 返回:
+//   - string: 标准化后的路径（使用正斜杠，不含前导和末尾斜杠），压缩包根目录返回 "."
+//   - error: 路径为空或包含上级目录引用时返回错误
+func NormalizeArchivePath(archivePath string) (string, error) {
+	if strings.TrimSpace(archivePath) == "" {
+		return "", fmt.Errorf("压缩包内路径不能为空")
+	}
+
+	// 统一路径分隔符并清理路径
+	slashPath := strings.ReplaceAll(archivePath, "\\", "/")
+	cleanPath := path.Clean("/" + slashPath)
+	cleanPath = strings.TrimPrefix(cleanPath, "/")
+
+	// 检查上级目录引用
+	for _, part := range strings.Split(slashPath, "/") {
+		if part == ".." {
+			return "", fmt.Errorf("压缩包内路径不能包含上级目录引用: %s", archivePath)
+		}
+	}
+
+	if cleanPath == "" {
+		return ".", nil
+	}
+	return cleanPath, nil
+}
+
+// JoinArchivePath 拼接压缩包内的路径
+//
+// 参数:
+//   - base: 已标准化的基础路径，"." 表示压缩包根目录
+//   - rel: 相对于基础路径的系统路径
+//
+// 返回:
+//   - string: 拼接后使用正斜杠的路径
+func JoinArchivePath(base, rel string) string {
+	rel = filepath.ToSlash(rel)
+	if base == "." || base == "" {
+		return rel
+	}
+	if rel == "." || rel == "" {
+		return base
+	}
+	return base + "/" + rel
+}
+
+// EntryNameSet 压缩包条目名称集合，用于检测重复条目
+type EntryNameSet map[string]struct{}
+
+// NewEntryNameSet 创建条目名称集合
+//
+// 返回:
+//   - EntryNameSet: 空的条目名称集合
+func NewEntryNameSet() EntryNameSet {
+	return make(EntryNameSet)
+}
+
+// Add 添加条目名称，名称已存在时返回错误
+//
+// 目录名称末尾的斜杠会被忽略，因此 "dir" 和 "dir/" 视为同一条目。
+//
+// 参数:
+//   - name: 条目名称
+//
+// 返回:
+//   - error: 条目重复时返回错误
+func (s EntryNameSet) Add(name string) error {
+	key := strings.TrimSuffix(name, "/")
+	if _, exists := s[key]; exists {
+		return fmt.Errorf("压缩包中存在重复的条目: %s", key)
+	}
+	s[key] = struct{}{}
+	return nil
+}
+
+// Contains 检查条目名称是否已存在
+//
+// 参数:
+//   - name: 条目名称
+//
+// 返回:
+//   - bool: 已存在返回 true
+func (s EntryNameSet) Contains(name string) bool {
+	_, exists := s[strings.TrimSuffix(name, "/")]
+	return exists
+}
+
+// ResolveSources 解析打包源列表
+//
+// 将每个磁盘源路径转换为绝对路径，并标准化压缩包内的目标路径。
+// 未指定压缩包内路径时，使用源路径的基本名称。
+//
+// 参数:
+//   - sources: 打包源列表
+//
+// 返回:
+//   - []types.Source: 解析后的打包源列表
+//   - error: 源路径为空或压缩包内路径不合法时返回错误
+func ResolveSources(sources []types.Source) ([]types.Source, error) {
+	if len(sources) == 0 {
+		return nil, fmt.Errorf("打包源列表不能为空")
+	}
+
+	resolved := make([]types.Source, 0, len(sources))
+	for _, source := range sources {
+		var srcPath, defaultName string
+
+		if source.FS != nil {
+			// fs.FS 源：路径必须是合法的 FS 路径
+			srcPath = source.Path
+			if srcPath == "" {
+				srcPath = "."
+			}
+			if !fs.ValidPath(srcPath) {
+				return nil, fmt.Errorf("无效的文件系统路径: %s", source.Path)
+			}
+			defaultName = path.Base(srcPath)
+		} else {
+			if source.Path == "" {
+				return nil, fmt.Errorf("源路径不能为空")
+			}
+
+			// 确保源路径为绝对路径
+			absPath, err := EnsureAbsPath(source.Path, "源路径")
+			if err != nil {
+				return nil, err
+			}
+			srcPath = absPath
+			defaultName = filepath.Base(absPath)
+		}
+
+		// 未指定压缩包内路径时，使用源路径的基本名称
+		archivePath := source.ArchivePath
+		if archivePath == "" {
+			archivePath = defaultName
+		}
+		archivePath, err := NormalizeArchivePath(archivePath)
+		if err != nil {
+			return nil, err
+		}
+
+		resolved = append(resolved, types.Source{Path: srcPath, ArchivePath: archivePath, FS: source.FS})
+	}
+	return resolved, nil
+}
+
+// SortSources 按压缩包内路径对打包源进行稳定排序
+//
+// 用于确定性打包，使条目顺序不依赖于调用方传入源的顺序。
+//
+// 参数:
+//   - sources: 已解析的打包源列表（原地排序）
+func SortSources(sources []types.Source) {
+	sort.SliceStable(sources, func(i, j int) bool {
+		return sources[i].ArchivePath < sources[j].ArchivePath
+	})
+}
+
+// PrepareSources 解析打包源列表并检查源路径是否存在
+//
+// 参数:
+//   - sources: 打包源列表
+//
+// 返回值:
+//   - []types.Source: 已解析的打包源列表
+//   - error: 解析或检查失败时返回错误
+func PrepareSources(sources []types.Source) ([]types.Source, error) {
+	resolved, err := ResolveSources(sources)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, source := range resolved {
+		if _, err := StatSource(source); err != nil {
+			return nil, fmt.Errorf("获取源路径信息失败: %w", err)
+		}
+	}
+	return resolved, nil
+}
+
+// StatSource 获取打包源的文件信息
+//
+// 参数:
+//   - source: 已解析的打包源
+//
+// 返回值:
+//   - fs.FileInfo: 文件信息（磁盘源会跟随符号链接）
+//   - error: 获取失败时返回错误
+func StatSource(source types.Source) (fs.FileInfo, error) {
+	if source.FS != nil {
+		return fs.Stat(source.FS, source.Path)
+	}
+	return os.Stat(source.Path)
+}
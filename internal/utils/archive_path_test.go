@@ -0,0 +1,109 @@
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"gitee.com/MM-Q/comprx/types"
+)
+
+func TestNormalizeArchivePath(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{"普通路径", "app-1.2/bin", "app-1.2/bin", false},
+		{"反斜杠路径", "app-1.2\\docs", "app-1.2/docs", false},
+		{"前导斜杠", "/app/VERSION", "app/VERSION", false},
+		{"末尾斜杠", "app/", "app", false},
+		{"当前目录前缀", "./app/bin", "app/bin", false},
+		{"根目录", ".", ".", false},
+		{"空路径", "", "", true},
+		{"上级目录引用", "../etc/passwd", "", true},
+		{"中间上级目录引用", "app/../../etc", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NormalizeArchivePath(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NormalizeArchivePath(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("NormalizeArchivePath(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestJoinArchivePath(t *testing.T) {
+	tests := []struct {
+		base, rel, want string
+	}{
+		{"app", ".", "app"},
+		{"app", filepath.Join("bin", "tool"), "app/bin/tool"},
+		{".", "bin", "bin"},
+		{".", ".", "."},
+	}
+
+	for _, tt := range tests {
+		if got := JoinArchivePath(tt.base, tt.rel); got != tt.want {
+			t.Errorf("JoinArchivePath(%q, %q) = %q, want %q", tt.base, tt.rel, got, tt.want)
+		}
+	}
+}
+
+func TestEntryNameSet_Add(t *testing.T) {
+	names := NewEntryNameSet()
+
+	if err := names.Add("app/bin"); err != nil {
+		t.Fatalf("首次添加条目失败: %v", err)
+	}
+	if err := names.Add("app/bin/"); err == nil {
+		t.Error("目录条目与同名条目应视为重复")
+	}
+	if err := names.Add("app/docs"); err != nil {
+		t.Errorf("添加不同条目失败: %v", err)
+	}
+	if !names.Contains("app/docs/") {
+		t.Error("Contains 应忽略末尾斜杠")
+	}
+}
+
+func TestPrepareSources(t *testing.T) {
+	tempDir := t.TempDir()
+	binDir := filepath.Join(tempDir, "bin")
+	if err := os.MkdirAll(binDir, 0755); err != nil {
+		t.Fatalf("创建目录失败: %v", err)
+	}
+
+	sources, err := PrepareSources([]types.Source{
+		{Path: binDir},
+		{Path: binDir, ArchivePath: "app-1.2\\bin"},
+	})
+	if err != nil {
+		t.Fatalf("PrepareSources 失败: %v", err)
+	}
+	if len(sources) != 2 {
+		t.Fatalf("期望 2 个打包源, 实际 %d", len(sources))
+	}
+	if sources[0].ArchivePath != "bin" {
+		t.Errorf("默认压缩包内路径应为基本名称, 实际 %q", sources[0].ArchivePath)
+	}
+	if sources[1].ArchivePath != "app-1.2/bin" {
+		t.Errorf("压缩包内路径未标准化, 实际 %q", sources[1].ArchivePath)
+	}
+
+	// 不存在的源路径
+	if _, err := PrepareSources([]types.Source{{Path: filepath.Join(tempDir, "missing")}}); err == nil {
+		t.Error("不存在的源路径应返回错误")
+	}
+
+	// 空源列表
+	if _, err := PrepareSources(nil); err == nil {
+		t.Error("空源列表应返回错误")
+	}
+}
@@ -0,0 +1,212 @@
+// Package utils 提供删除和重命名压缩包条目时的规划工具。
+//
+// 该文件根据删除模式或重命名映射计算每个条目的处理方式，
+// 供各格式在重写压缩包时逐条目判断是否保留以及写入的新名称。
+//
+// 主要类型：
+//   - EntryRewrite: 条目重写函数
+//
+// 主要功能：
+//   - 根据通配符模式计算需要删除的条目
+//   - 根据重命名映射计算条目的新名称（目录重命名时连同其下所有条目）
+//
+// 使用示例：
+//
+//	rewrite, err := utils.PlanDelete(entries, []string{"config/secret.env"})
+//	if newName, keep := rewrite(header.Name); keep {
+//	    header.Name = newName
+//	}
+package utils
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+)
+
+// EntryRewrite 条目重写函数
+//
+// 参数:
+//   - name: 原条目名称（目录可带末尾斜杠）
+//
+// 返回:
+//   - string: 写入的新名称，保留原名称的末尾斜杠
+//   - bool: 是否保留该条目
+type EntryRewrite func(name string) (string, bool)
+
+// PlanDelete 根据通配符模式计算需要删除的条目
+//
+// 参数:
+//   - entries: 压缩包中的条目
+//   - patterns: 删除模式，语法同 path.Match；不含斜杠的模式同时匹配条目的基本名称
+//
+// 返回:
+//   - EntryRewrite: 条目重写函数，被删除的条目返回 false
+//   - error: 模式为空、无效或没有匹配任何条目时返回错误
+//
+// 注意:
+//   - 匹配到目录时同时删除该目录下的所有条目
+func PlanDelete(entries EntryKinds, patterns []string) (EntryRewrite, error) {
+	if len(patterns) == 0 {
+		return nil, fmt.Errorf("删除模式不能为空")
+	}
+
+	// 规范化并校验模式
+	cleaned := make([]string, 0, len(patterns))
+	for _, pattern := range patterns {
+		pattern = strings.Trim(strings.ReplaceAll(pattern, "\\", "/"), "/")
+		if pattern == "" {
+			return nil, fmt.Errorf("删除模式不能为空")
+		}
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("无效的删除模式 %s: %w", pattern, err)
+		}
+		cleaned = append(cleaned, pattern)
+	}
+
+	// 收集匹配的条目
+	deleted := make(map[string]struct{})
+	for name := range entries {
+		for _, pattern := range cleaned {
+			if matchEntryPattern(name, pattern) {
+				deleted[name] = struct{}{}
+				break
+			}
+		}
+	}
+	if len(deleted) == 0 {
+		return nil, fmt.Errorf("压缩包中没有匹配 %s 的条目", strings.Join(patterns, ", "))
+	}
+
+	return func(name string) (string, bool) {
+		key := strings.TrimSuffix(name, "/")
+		for candidate := key; candidate != "." && candidate != "/"; candidate = path.Dir(candidate) {
+			if _, ok := deleted[candidate]; ok {
+				return "", false
+			}
+		}
+		return name, true
+	}, nil
+}
+
+// PlanRename 根据重命名映射计算条目的新名称
+//
+// 参数:
+//   - entries: 压缩包中的条目
+//   - renames: 原名称到新名称的映射
+//
+// 返回:
+//   - EntryRewrite: 条目重写函数，所有条目均保留
+//   - error: 映射为空、原条目不存在或新名称与其他条目冲突时返回错误
+//
+// 注意:
+//   - 重命名目录时，该目录下的所有条目一并移动
+//   - 压缩包中没有显式目录条目时，按 "目录/" 前缀匹配其下的条目（许多 ZIP 文件不含目录条目）
+func PlanRename(entries EntryKinds, renames map[string]string) (EntryRewrite, error) {
+	if len(renames) == 0 {
+		return nil, fmt.Errorf("重命名映射不能为空")
+	}
+
+	// 规范化并校验映射
+	mapping := make(map[string]string, len(renames))
+	dirs := make(map[string]bool, len(renames))
+	for oldName, newName := range renames {
+		oldKey := strings.Trim(strings.ReplaceAll(oldName, "\\", "/"), "/")
+		isDir, ok := entries[oldKey]
+		if !isDir && hasEntryUnder(entries, oldKey) {
+			// 没有显式目录条目的隐式目录
+			isDir, ok = true, true
+		}
+		if !ok {
+			return nil, fmt.Errorf("压缩包中不存在条目 %s", oldName)
+		}
+		newKey, err := NormalizeArchivePath(newName)
+		if err != nil {
+			return nil, err
+		}
+		if newKey == "." {
+			return nil, fmt.Errorf("条目 %s 的新名称不能为根目录", oldName)
+		}
+		mapping[oldKey] = newKey
+		dirs[oldKey] = isDir
+	}
+
+	rename := func(key string) string {
+		// 从自身开始逐级向上查找最近的重命名目标
+		for candidate := key; candidate != "." && candidate != "/"; candidate = path.Dir(candidate) {
+			newKey, ok := mapping[candidate]
+			if !ok {
+				continue
+			}
+			if candidate == key {
+				return newKey
+			}
+			if dirs[candidate] {
+				return newKey + strings.TrimPrefix(key, candidate)
+			}
+		}
+		return key
+	}
+
+	// 检查重命名后的名称冲突
+	keys := make([]string, 0, len(entries))
+	for key := range entries {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	renamed := make(map[string]string, len(keys))
+	for _, key := range keys {
+		newKey := rename(key)
+		if other, ok := renamed[newKey]; ok {
+			return nil, fmt.Errorf("重命名后条目 %s 与 %s 冲突", other, key)
+		}
+		renamed[newKey] = key
+	}
+
+	return func(name string) (string, bool) {
+		key := strings.TrimSuffix(name, "/")
+		newKey := rename(key)
+		if strings.HasSuffix(name, "/") {
+			newKey += "/"
+		}
+		return newKey, true
+	}, nil
+}
+
+// hasEntryUnder 检查是否存在以 dir/ 为前缀的条目
+//
+// 参数:
+//   - entries: 压缩包中的条目
+//   - dir: 目录名称（不含末尾斜杠）
+//
+// 返回:
+//   - bool: 存在该目录下的条目时返回 true
+func hasEntryUnder(entries EntryKinds, dir string) bool {
+	prefix := dir + "/"
+	for name := range entries {
+		if strings.HasPrefix(name, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchEntryPattern 检查条目名称是否匹配删除模式
+//
+// 参数:
+//   - name: 条目名称（不含末尾斜杠）
+//   - pattern: 删除模式（已校验）
+//
+// 返回:
+//   - bool: 匹配返回 true
+func matchEntryPattern(name, pattern string) bool {
+	if matched, _ := path.Match(pattern, name); matched {
+		return true
+	}
+	if !strings.Contains(pattern, "/") {
+		matched, _ := path.Match(pattern, path.Base(name))
+		return matched
+	}
+	return false
+}
@@ -0,0 +1,108 @@
+package utils
+
+import "testing"
+
+// newTestEntries 创建测试用的条目集合
+func newTestEntries() EntryKinds {
+	entries := EntryKinds{}
+	entries.Add("app/", true)
+	entries.Add("app/main.go", false)
+	entries.Add("app/config/", true)
+	entries.Add("app/config/secret.env", false)
+	entries.Add("README.md", false)
+	return entries
+}
+
+func TestPlanDelete(t *testing.T) {
+	tests := []struct {
+		name     string
+		patterns []string
+		deleted  []string
+		kept     []string
+	}{
+		{"精确路径", []string{"app/config/secret.env"}, []string{"app/config/secret.env"}, []string{"app/config/", "app/main.go"}},
+		{"基本名称通配", []string{"*.env"}, []string{"app/config/secret.env"}, []string{"README.md"}},
+		{"目录连同子条目", []string{"app/config/"}, []string{"app/config/", "app/config/secret.env"}, []string{"app/main.go"}},
+		{"不匹配中间片段", []string{"app/*.go"}, []string{"app/main.go"}, []string{"app/config/secret.env"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rewrite, err := PlanDelete(newTestEntries(), tt.patterns)
+			if err != nil {
+				t.Fatalf("计算删除计划失败: %v", err)
+			}
+			for _, name := range tt.deleted {
+				if _, keep := rewrite(name); keep {
+					t.Errorf("条目 %s 应被删除", name)
+				}
+			}
+			for _, name := range tt.kept {
+				if newName, keep := rewrite(name); !keep || newName != name {
+					t.Errorf("条目 %s 应保留", name)
+				}
+			}
+		})
+	}
+
+	if _, err := PlanDelete(newTestEntries(), []string{"missing.txt"}); err == nil {
+		t.Error("没有匹配的条目时应返回错误")
+	}
+	if _, err := PlanDelete(newTestEntries(), []string{"[invalid"}); err == nil {
+		t.Error("无效的模式应返回错误")
+	}
+	if _, err := PlanDelete(newTestEntries(), nil); err == nil {
+		t.Error("空模式应返回错误")
+	}
+}
+
+func TestPlanRename(t *testing.T) {
+	rewrite, err := PlanRename(newTestEntries(), map[string]string{
+		"app/":      "service",
+		"README.md": "docs/README.md",
+	})
+	if err != nil {
+		t.Fatalf("计算重命名计划失败: %v", err)
+	}
+
+	expected := map[string]string{
+		"app/":                  "service/",
+		"app/main.go":           "service/main.go",
+		"app/config/secret.env": "service/config/secret.env",
+		"README.md":             "docs/README.md",
+	}
+	for oldName, want := range expected {
+		if got, keep := rewrite(oldName); !keep || got != want {
+			t.Errorf("重命名 %s: 期望 %s, 实际 %s", oldName, want, got)
+		}
+	}
+
+	// 没有显式目录条目时按前缀重命名目录
+	implicit := EntryKinds{}
+	implicit.Add("app/main.go", false)
+	implicit.Add("app/config/secret.env", false)
+	implicit.Add("application.log", false)
+	rewrite, err = PlanRename(implicit, map[string]string{"app": "service"})
+	if err != nil {
+		t.Fatalf("重命名隐式目录失败: %v", err)
+	}
+	for oldName, want := range map[string]string{
+		"app/main.go":           "service/main.go",
+		"app/config/secret.env": "service/config/secret.env",
+		"application.log":       "application.log",
+	} {
+		if got, _ := rewrite(oldName); got != want {
+			t.Errorf("重命名 %s: 期望 %s, 实际 %s", oldName, want, got)
+		}
+	}
+
+	if _, err := PlanRename(newTestEntries(), map[string]string{"missing": "x"}); err == nil {
+		t.Error("原条目不存在时应返回错误")
+	}
+	if _, err := PlanRename(newTestEntries(), map[string]string{"README.md": "app/main.go"}); err == nil {
+		t.Error("新名称与已有条目冲突时应返回错误")
+	}
+	if _, err := PlanRename(newTestEntries(), map[string]string{"README.md": "../README.md"}); err == nil {
+		t.Error("新名称包含上级目录引用时应返回错误")
+	}
+}
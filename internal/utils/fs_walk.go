@@ -0,0 +1,164 @@
+// Package utils 提供从 fs.FS 遍历并写入压缩包条目的工具函数。
+//
+// 该文件定义了压缩包条目写入接口，以及遍历 fs.FS（如 embed.FS、fstest.MapFS）
+// 并将其中的文件、目录和符号链接写入压缩包的通用实现，供各归档格式复用。
+//
+// 主要类型：
+//   - ArchiveEntryWriter: 压缩包条目写入接口
+//   - ReadLinkFS: 支持读取符号链接目标的文件系统接口
+//
+// 主要功能：
+//   - 遍历 fs.FS 并按映射路径写入压缩包
+//   - 应用文件过滤器
+//   - 将 fs.FileInfo 转换为 types.FileInfo
+//
+// 使用示例：
+//
+//	err := utils.WriteFSTree(fsys, "static", "app/static", cfg.Filter, builder)
+package utils
+
+import (
+	"fmt"
+	"io"
+	"io/fs"
+	"strings"
+
+	"gitee.com/MM-Q/comprx/types"
+)
+
+// ArchiveEntryWriter 压缩包条目写入接口
+//
+// 由各归档格式的构建器实现，条目名称使用正斜杠分隔的压缩包内路径。
+type ArchiveEntryWriter interface {
+	// AddFile 添加普通文件，内容从 r 读取
+	AddFile(name string, r io.Reader, info types.FileInfo) error
+	// AddDir 添加目录
+	AddDir(name string, info types.FileInfo) error
+	// AddSymlink 添加符号链接
+	AddSymlink(name, target string, info types.FileInfo) error
+}
+
+// ReadLinkFS 支持读取符号链接目标的文件系统
+//
+// 方法签名与 Go 1.25 引入的 fs.ReadLinkFS 保持一致。
+type ReadLinkFS interface {
+	fs.FS
+	// ReadLink 返回符号链接的目标
+	ReadLink(name string) (string, error)
+}
+
+// FileInfoFromFS 将 fs.FileInfo 转换为压缩包条目信息
+//
+// 参数:
+//   - name: 压缩包内的条目名称
+//   - info: 文件信息
+//
+// 返回:
+//   - types.FileInfo: 压缩包条目信息
+func FileInfoFromFS(name string, info fs.FileInfo) types.FileInfo {
+	return types.FileInfo{
+		Name:      name,
+		Size:      info.Size(),
+		ModTime:   info.ModTime(),
+		Mode:      info.Mode(),
+		IsDir:     info.IsDir(),
+		IsSymlink: info.Mode()&fs.ModeSymlink != 0,
+	}
+}
+
+// WriteFSTree 遍历 fs.FS 并将其中的条目写入压缩包
+//
+// 参数:
+//   - fsys: 源文件系统
+//   - root: FS 内的源路径，可以是文件或目录
+//   - archiveBase: 源路径在压缩包中的路径，"." 表示压缩包根目录
+//   - filter: 文件过滤器，为 nil 时不过滤
+//   - w: 压缩包条目写入器
+//
+// 返回:
+//   - error: 遍历或写入过程中遇到的错误
+//
+// 注意:
+//   - 符号链接仅在 fsys 实现 ReadLinkFS 时保留，否则返回错误
+//   - 设备文件、命名管道等特殊文件会被跳过
+func WriteFSTree(fsys fs.FS, root, archiveBase string, filter *types.FilterOptions, w ArchiveEntryWriter) error {
+	return fs.WalkDir(fsys, root, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return fmt.Errorf("遍历路径 '%s' 时出错: %w", path, err)
+		}
+
+		// 获取文件信息用于过滤检查
+		info, err := entry.Info()
+		if err != nil {
+			return fmt.Errorf("处理路径 '%s' 时出错 - 获取文件信息失败: %w", path, err)
+		}
+
+		// 应用过滤器检查
+		if filter != nil && filter.ShouldSkipByParams(path, info.Size(), info.IsDir()) {
+			if info.IsDir() {
+				return fs.SkipDir // 跳过整个目录
+			}
+			return nil // 跳过文件
+		}
+
+		// 映射到压缩包内的路径
+		relPath := "."
+		if path != root {
+			relPath = strings.TrimPrefix(path, root+"/")
+			if root == "." {
+				relPath = path
+			}
+		}
+		name := JoinArchivePath(archiveBase, relPath)
+		if name == "." {
+			if !info.IsDir() {
+				return fmt.Errorf("文件源 '%s' 必须指定压缩包内的文件名", path)
+			}
+			return nil // 源目录映射到压缩包根目录时，不写入根目录本身
+		}
+
+		// 根据文件类型处理
+		switch {
+		case info.Mode().IsRegular(): // 处理普通文件
+			return writeFSFile(fsys, path, name, info, w)
+
+		case info.IsDir(): // 处理目录
+			return w.AddDir(name, FileInfoFromFS(name, info))
+
+		case info.Mode()&fs.ModeSymlink != 0: // 处理符号链接
+			linkFS, ok := fsys.(ReadLinkFS)
+			if !ok {
+				return fmt.Errorf("处理软链接 '%s' 时出错 - 文件系统不支持读取符号链接", path)
+			}
+			target, err := linkFS.ReadLink(path)
+			if err != nil {
+				return fmt.Errorf("处理软链接 '%s' 时出错 - 读取软链接目标失败: %w", path, err)
+			}
+			return w.AddSymlink(name, target, FileInfoFromFS(name, info))
+
+		default: // 特殊文件在 fs.FS 中没有意义，直接跳过
+			return nil
+		}
+	})
+}
+
+// writeFSFile 从 fs.FS 打开普通文件并写入压缩包
+//
+// 参数:
+//   - fsys: 源文件系统
+//   - path: FS 内的文件路径
+//   - name: 压缩包内的条目名称
+//   - info: 文件信息
+//   - w: 压缩包条目写入器
+//
+// 返回:
+//   - error: 打开或写入失败时返回错误
+func writeFSFile(fsys fs.FS, path, name string, info fs.FileInfo, w ArchiveEntryWriter) error {
+	file, err := fsys.Open(path)
+	if err != nil {
+		return fmt.Errorf("处理文件 '%s' 时出错 - 打开文件失败: %w", path, err)
+	}
+	defer func() { _ = file.Close() }()
+
+	return w.AddFile(name, file, FileInfoFromFS(name, info))
+}
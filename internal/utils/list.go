@@ -10,12 +10,15 @@
 //   - 压缩包摘要信息打印
 //   - 文件列表格式化打印
 //   - 文件列表过滤和限制
+//   - 遍历条目时按数量和模式收集文件
 //
 // 显示格式：
 //   - 简洁模式：仅显示文件名
 //   - 详细模式：显示权限、大小、时间等完整信息
-//   - 支持符号链接目标显示
+//   - 支持符号链接和硬链接目标显示
+//   - 显示条目类型、属主、压缩方法、CRC32、偏移和扩展属性等元数据
 //   - 自动计算压缩率
+//   - 显示 ZIP 压缩包注释和条目注释
 //
 // 使用示例：
 //
@@ -34,8 +37,11 @@ package utils
 
 import (
 	"fmt"
+	"io/fs"
 	"os"
 	"path/filepath"
+	"sort"
+	"strconv"
 	"strings"
 
 	"gitee.com/MM-Q/comprx/types"
@@ -151,10 +157,21 @@ func PrintFileInfo(info types.FileInfo, showDetails bool) {
 		sizeStr := FormatFileSize(info.Size)
 		timeStr := info.ModTime.Format("2006-01-02 15:04:05")
 
+		line := fmt.Sprintf("%s %8s %s %s", modeStr, sizeStr, timeStr, info.Name)
 		if info.IsSymlink {
-			fmt.Printf("%s %8s %s %s -> %s\n", modeStr, sizeStr, timeStr, info.Name, info.LinkTarget)
-		} else {
-			fmt.Printf("%s %8s %s %s\n", modeStr, sizeStr, timeStr, info.Name)
+			line += " -> " + info.LinkTarget
+		}
+		if info.HardlinkTarget != "" {
+			line += " link to " + info.HardlinkTarget
+		}
+		if info.Comment != "" {
+			line += "  # " + info.Comment
+		}
+		fmt.Println(line)
+
+		// 条目元数据单独缩进一行显示
+		if meta := FormatEntryMetadata(info); meta != "" {
+			fmt.Println("    " + meta)
 		}
 	} else {
 		// 简单模式：只显示文件名
@@ -166,6 +183,68 @@ func PrintFileInfo(info types.FileInfo, showDetails bool) {
 	}
 }
 
+// FormatEntryMetadata 格式化条目的扩展元数据
+//
+// 只输出压缩包中实际记录的字段，格式为空格分隔的 key=value，扩展属性按名称排序。
+//
+// 参数:
+//   - info: 文件信息
+//
+// 返回:
+//   - string: 格式化后的元数据，没有可显示的字段时返回空字符串
+func FormatEntryMetadata(info types.FileInfo) string {
+	var fields []string
+	if info.EntryType != "" {
+		fields = append(fields, "type="+info.EntryType.String())
+	}
+	if info.Uname != "" || info.Gname != "" || info.Uid != 0 || info.Gid != 0 {
+		fields = append(fields, fmt.Sprintf("owner=%s/%s", ownerName(info.Uname, info.Uid), ownerName(info.Gname, info.Gid)))
+	}
+	if info.Method != "" {
+		fields = append(fields, "method="+info.Method)
+	}
+	if info.CRC32 != 0 {
+		fields = append(fields, fmt.Sprintf("crc32=%08x", info.CRC32))
+	}
+	if info.Encrypted {
+		fields = append(fields, "encrypted")
+	}
+	if info.HeaderOffset >= 0 {
+		fields = append(fields, fmt.Sprintf("offset=%d", info.HeaderOffset))
+	}
+	if !info.AccessTime.IsZero() {
+		fields = append(fields, "atime="+info.AccessTime.Format("2006-01-02 15:04:05"))
+	}
+	if !info.ChangeTime.IsZero() {
+		fields = append(fields, "ctime="+info.ChangeTime.Format("2006-01-02 15:04:05"))
+	}
+
+	names := make([]string, 0, len(info.Xattrs))
+	for name := range info.Xattrs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fields = append(fields, fmt.Sprintf("xattr.%s=%q", name, info.Xattrs[name]))
+	}
+	return strings.Join(fields, " ")
+}
+
+// ownerName 返回属主的显示名称
+//
+// 参数:
+//   - name: 用户名或组名
+//   - id: 用户 ID 或组 ID
+//
+// 返回:
+//   - string: 名称存在时返回名称，否则返回数字 ID
+func ownerName(name string, id int) string {
+	if name != "" {
+		return name
+	}
+	return strconv.Itoa(id)
+}
+
 // PrintArchiveSummary 打印压缩包摘要信息
 //
 // 参数:
@@ -190,6 +269,16 @@ func PrintArchiveSummary(archiveInfo *types.ArchiveInfo) {
 			fmt.Printf("压缩率: %.1f%%\n", ratio)
 		}
 	}
+
+	// ZIP 压缩包注释
+	if archiveInfo.Comment != "" {
+		fmt.Printf("注释: %s\n", archiveInfo.Comment)
+	}
+
+	// 列表结果可能不准确的说明
+	for _, warning := range archiveInfo.Warnings {
+		fmt.Printf("警告: %s\n", warning)
+	}
 	fmt.Println(strings.Repeat("-", 50)) // 分隔线
 }
 
@@ -227,6 +316,33 @@ func FilterFilesByPattern(files []types.FileInfo, pattern string) []types.FileIn
 	return filtered
 }
 
+// CollectFiles 返回把遍历到的条目收集到压缩包信息中的回调函数
+//
+// 回调只收集匹配模式的条目并同步更新文件总数和总大小，
+// 收集数量达到限制后返回 fs.SkipAll 提前结束遍历。
+//
+// 参数:
+//   - archiveInfo: 用于收集条目的压缩包信息
+//   - limit: 最多收集的条目数量，小于等于 0 表示不限制
+//   - pattern: 文件名匹配模式，为空时收集全部条目
+//
+// 返回:
+//   - func(types.FileInfo) error: 传给 Walk 系列函数的回调
+func CollectFiles(archiveInfo *types.ArchiveInfo, limit int, pattern string) func(types.FileInfo) error {
+	return func(info types.FileInfo) error {
+		if !MatchPattern(info.Name, pattern) {
+			return nil
+		}
+		archiveInfo.Files = append(archiveInfo.Files, info)
+		archiveInfo.TotalFiles++
+		archiveInfo.TotalSize += info.Size
+		if limit > 0 && archiveInfo.TotalFiles >= limit {
+			return fs.SkipAll
+		}
+		return nil
+	}
+}
+
 // LimitFiles 限制文件列表数量
 //
 // 参数:
@@ -168,6 +168,20 @@ func TestPrintFileInfo(t *testing.T) {
 			showDetails: true,
 			contains:    []string{"link.txt", "->", "target.txt", "0 B", "2023-12-25 15:30:45"},
 		},
+		{
+			name: "详细模式硬链接",
+			fileInfo: types.FileInfo{
+				Name:           "hard.txt",
+				Mode:           0644,
+				ModTime:        testTime,
+				EntryType:      types.EntryTypeHardlink,
+				HardlinkTarget: "test.txt",
+				Uname:          "root",
+				Gname:          "wheel",
+			},
+			showDetails: true,
+			contains:    []string{"hard.txt link to test.txt", "type=hardlink", "owner=root/wheel"},
+		},
 	}
 
 	for _, tt := range tests {
@@ -526,3 +540,32 @@ func TestPrintArchiveSummary_CompressionRatio(t *testing.T) {
 		})
 	}
 }
+
+func TestFormatEntryMetadata(t *testing.T) {
+	atime := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	info := types.FileInfo{
+		Name:         "bin/app",
+		EntryType:    types.EntryTypeFile,
+		Uid:          1000,
+		Gname:        "users",
+		Method:       "deflate",
+		CRC32:        0x1a2b3c,
+		Encrypted:    true,
+		HeaderOffset: 512,
+		AccessTime:   atime,
+		Xattrs:       map[string]string{"user.b": "2", "user.a": "1"},
+	}
+
+	want := `type=file owner=1000/users method=deflate crc32=001a2b3c encrypted offset=512 atime=2024-01-02 03:04:05 xattr.user.a="1" xattr.user.b="2"`
+	if got := FormatEntryMetadata(info); got != want {
+		t.Errorf("FormatEntryMetadata() = %q, want %q", got, want)
+	}
+	if got := FormatEntryMetadata(types.FileInfo{Name: "a.txt", HeaderOffset: -1}); got != "" {
+		t.Errorf("没有元数据时应返回空字符串: %q", got)
+	}
+
+	// 偏移为 0 的条目(如 TAR 的第一个条目)同样显示偏移
+	if got := FormatEntryMetadata(types.FileInfo{Name: "a.txt"}); got != "offset=0" {
+		t.Errorf("偏移为 0 时应显示偏移: %q", got)
+	}
+}
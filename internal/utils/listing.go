@@ -0,0 +1,455 @@
+// Package utils 提供压缩包内容列表的结构化输出功能。
+//
+// 该文件把 ArchiveInfo 按指定格式写入任意 io.Writer，
+// 输出使用稳定的英文字段名，便于接入审计、比对等工具。
+//
+// 主要功能：
+//   - JSON 和 NDJSON 输出
+//   - 带表头的 CSV 输出
+//   - 类似 tree 命令的层级视图，目录显示其下所有文件的总大小
+//   - 与 tar -tv 兼容的输出
+//
+// 使用示例：
+//
+//	info, _ := core.List("app.tar.gz")
+//	err := utils.WriteListing(os.Stdout, info, types.ListFormatNDJSON)
+package utils
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gitee.com/MM-Q/comprx/types"
+)
+
+// listingTimeFormat 结构化输出中的时间格式
+const listingTimeFormat = time.RFC3339
+
+// listingEntry 结构化输出中的条目记录
+type listingEntry struct {
+	Name           string            `json:"name"`
+	Type           types.EntryType   `json:"type"`
+	Size           int64             `json:"size"`
+	CompressedSize int64             `json:"compressed_size"`
+	Mode           string            `json:"mode"`
+	ModTime        string            `json:"mod_time,omitempty"`
+	LinkTarget     string            `json:"link_target,omitempty"`
+	HardlinkTarget string            `json:"hardlink_target,omitempty"`
+	Uid            int               `json:"uid"`
+	Gid            int               `json:"gid"`
+	Uname          string            `json:"uname,omitempty"`
+	Gname          string            `json:"gname,omitempty"`
+	AccessTime     string            `json:"access_time,omitempty"`
+	ChangeTime     string            `json:"change_time,omitempty"`
+	Method         string            `json:"method,omitempty"`
+	CRC32          string            `json:"crc32,omitempty"`
+	Encrypted      bool              `json:"encrypted,omitempty"`
+	HeaderOffset   int64             `json:"header_offset"`
+	Comment        string            `json:"comment,omitempty"`
+	Xattrs         map[string]string `json:"xattrs,omitempty"`
+}
+
+// listingDocument JSON 输出的完整文档
+type listingDocument struct {
+	Type           types.CompressType `json:"type"`
+	TotalFiles     int                `json:"total_files"`
+	TotalSize      int64              `json:"total_size"`
+	CompressedSize int64              `json:"compressed_size"`
+	Comment        string             `json:"comment,omitempty"`
+	Warnings       []string           `json:"warnings,omitempty"`
+	Files          []listingEntry     `json:"files"`
+}
+
+// listingCSVHeader CSV 输出的表头
+var listingCSVHeader = []string{
+	"name", "type", "size", "compressed_size", "mode", "mod_time",
+	"link_target", "hardlink_target", "uid", "gid", "uname", "gname",
+	"method", "crc32", "encrypted", "header_offset", "comment", "xattrs",
+}
+
+// WriteListing 按指定格式输出压缩包内容列表
+//
+// 参数:
+//   - w: 输出目标
+//   - info: 压缩包信息
+//   - format: 输出格式
+//
+// 返回:
+//   - error: 格式不受支持或写入失败时返回错误
+func WriteListing(w io.Writer, info *types.ArchiveInfo, format types.ListFormat) error {
+	if info == nil {
+		return fmt.Errorf("压缩包信息不能为空")
+	}
+
+	bw := bufio.NewWriter(w)
+	var err error
+	switch format {
+	case types.ListFormatJSON:
+		err = writeListingJSON(bw, info)
+	case types.ListFormatNDJSON:
+		err = writeListingNDJSON(bw, info)
+	case types.ListFormatCSV:
+		err = writeListingCSV(bw, info)
+	case types.ListFormatTree:
+		err = writeListingTree(bw, info)
+	case types.ListFormatTarTV:
+		err = writeListingTarTV(bw, info)
+	default:
+		return fmt.Errorf("不支持的列表输出格式: %s", format)
+	}
+	if err != nil {
+		return fmt.Errorf("输出内容列表失败: %w", err)
+	}
+	if err := bw.Flush(); err != nil {
+		return fmt.Errorf("输出内容列表失败: %w", err)
+	}
+	return nil
+}
+
+// newListingEntry 把条目信息转换为结构化输出记录
+//
+// 参数:
+//   - file: 条目信息
+//
+// 返回:
+//   - listingEntry: 输出记录
+func newListingEntry(file types.FileInfo) listingEntry {
+	entry := listingEntry{
+		Name:           file.Name,
+		Type:           entryType(file),
+		Size:           file.Size,
+		CompressedSize: file.CompressedSize,
+		Mode:           listingModeString(file),
+		ModTime:        formatListingTime(file.ModTime),
+		LinkTarget:     file.LinkTarget,
+		HardlinkTarget: file.HardlinkTarget,
+		Uid:            file.Uid,
+		Gid:            file.Gid,
+		Uname:          file.Uname,
+		Gname:          file.Gname,
+		AccessTime:     formatListingTime(file.AccessTime),
+		ChangeTime:     formatListingTime(file.ChangeTime),
+		Method:         file.Method,
+		Encrypted:      file.Encrypted,
+		HeaderOffset:   file.HeaderOffset,
+		Comment:        file.Comment,
+		Xattrs:         file.Xattrs,
+	}
+	if file.CRC32 != 0 {
+		entry.CRC32 = fmt.Sprintf("%08x", file.CRC32)
+	}
+	return entry
+}
+
+// entryType 返回条目类型，未记录时根据文件模式推断
+func entryType(file types.FileInfo) types.EntryType {
+	if file.EntryType != "" {
+		return file.EntryType
+	}
+	if file.IsDir {
+		return types.EntryTypeDir
+	}
+	return types.EntryTypeFromMode(file.Mode)
+}
+
+// formatListingTime 格式化结构化输出中的时间，零值返回空字符串
+func formatListingTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(listingTimeFormat)
+}
+
+// writeListingJSON 输出包含汇总信息和条目列表的 JSON 文档
+func writeListingJSON(w io.Writer, info *types.ArchiveInfo) error {
+	doc := listingDocument{
+		Type:           info.Type,
+		TotalFiles:     info.TotalFiles,
+		TotalSize:      info.TotalSize,
+		CompressedSize: info.CompressedSize,
+		Comment:        info.Comment,
+		Warnings:       info.Warnings,
+		Files:          make([]listingEntry, 0, len(info.Files)),
+	}
+	for _, file := range info.Files {
+		doc.Files = append(doc.Files, newListingEntry(file))
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(doc)
+}
+
+// writeListingNDJSON 输出每行一个条目的 JSON
+func writeListingNDJSON(w io.Writer, info *types.ArchiveInfo) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetEscapeHTML(false)
+	for _, file := range info.Files {
+		if err := encoder.Encode(newListingEntry(file)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeListingCSV 输出带表头的 CSV，扩展属性按名称排序后以 "name=value;" 拼接
+func writeListingCSV(w io.Writer, info *types.ArchiveInfo) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(listingCSVHeader); err != nil {
+		return err
+	}
+	for _, file := range info.Files {
+		entry := newListingEntry(file)
+		record := []string{
+			entry.Name,
+			string(entry.Type),
+			strconv.FormatInt(entry.Size, 10),
+			strconv.FormatInt(entry.CompressedSize, 10),
+			entry.Mode,
+			entry.ModTime,
+			entry.LinkTarget,
+			entry.HardlinkTarget,
+			strconv.Itoa(entry.Uid),
+			strconv.Itoa(entry.Gid),
+			entry.Uname,
+			entry.Gname,
+			entry.Method,
+			entry.CRC32,
+			strconv.FormatBool(entry.Encrypted),
+			strconv.FormatInt(entry.HeaderOffset, 10),
+			entry.Comment,
+			joinXattrs(entry.Xattrs),
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// joinXattrs 把扩展属性按名称排序后拼接为 "name=value;name=value"
+func joinXattrs(xattrs map[string]string) string {
+	names := make([]string, 0, len(xattrs))
+	for name := range xattrs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	pairs := make([]string, 0, len(names))
+	for _, name := range names {
+		pairs = append(pairs, name+"="+xattrs[name])
+	}
+	return strings.Join(pairs, ";")
+}
+
+// treeNode 层级视图中的节点
+type treeNode struct {
+	name     string
+	file     *types.FileInfo      // 压缩包中对应的条目，隐含的父目录为 nil
+	isDir    bool                 // 是否为目录
+	size     int64                // 文件大小，目录为其下所有文件的总大小
+	children map[string]*treeNode // 子节点
+}
+
+// child 返回指定名称的子节点，不存在时创建
+func (n *treeNode) child(name string) *treeNode {
+	if n.children == nil {
+		n.children = make(map[string]*treeNode)
+	}
+	node, ok := n.children[name]
+	if !ok {
+		node = &treeNode{name: name}
+		n.children[name] = node
+	}
+	return node
+}
+
+// sortedChildren 返回按名称排序的子节点
+func (n *treeNode) sortedChildren() []*treeNode {
+	children := make([]*treeNode, 0, len(n.children))
+	for _, child := range n.children {
+		children = append(children, child)
+	}
+	sort.Slice(children, func(i, j int) bool { return children[i].name < children[j].name })
+	return children
+}
+
+// sumSize 汇总目录下所有文件的大小
+//
+// 返回:
+//   - int64: 总大小
+//   - int: 子孙目录数
+//   - int: 子孙文件数
+func (n *treeNode) sumSize() (int64, int, int) {
+	if !n.isDir {
+		return n.size, 0, 1
+	}
+	var total int64
+	dirs, files := 0, 0
+	for _, child := range n.children {
+		size, d, f := child.sumSize()
+		total += size
+		dirs += d
+		files += f
+		if child.isDir {
+			dirs++
+		}
+	}
+	n.size = total
+	return total, dirs, files
+}
+
+// writeListingTree 输出类似 tree 命令的层级视图
+func writeListingTree(w io.Writer, info *types.ArchiveInfo) error {
+	root := &treeNode{name: ".", isDir: true}
+	for i := range info.Files {
+		file := &info.Files[i]
+		name := strings.TrimPrefix(path.Clean("/"+strings.ReplaceAll(file.Name, "\\", "/")), "/")
+		if name == "" {
+			continue
+		}
+
+		node := root
+		parts := strings.Split(name, "/")
+		for _, part := range parts[:len(parts)-1] {
+			node = node.child(part)
+			node.isDir = true
+		}
+		node = node.child(parts[len(parts)-1])
+		node.file = file
+		if entryType(*file) == types.EntryTypeDir {
+			node.isDir = true
+		} else {
+			node.size = file.Size
+		}
+	}
+
+	totalSize, dirs, files := root.sumSize()
+	if _, err := fmt.Fprintf(w, ". (%s)\n", FormatFileSize(totalSize)); err != nil {
+		return err
+	}
+	if err := writeTreeChildren(w, root, ""); err != nil {
+		return err
+	}
+	_, err := fmt.Fprintf(w, "\n%d directories, %d files\n", dirs, files)
+	return err
+}
+
+// writeTreeChildren 递归输出目录的子节点
+func writeTreeChildren(w io.Writer, node *treeNode, prefix string) error {
+	children := node.sortedChildren()
+	for i, child := range children {
+		branch, indent := "├── ", "│   "
+		if i == len(children)-1 {
+			branch, indent = "└── ", "    "
+		}
+
+		line := prefix + branch + child.name
+		if child.isDir {
+			line += "/"
+		}
+		if child.file != nil && child.file.IsSymlink {
+			line += " -> " + child.file.LinkTarget
+		}
+		line += " (" + FormatFileSize(child.size) + ")"
+		if _, err := fmt.Fprintln(w, line); err != nil {
+			return err
+		}
+
+		if child.isDir {
+			if err := writeTreeChildren(w, child, prefix+indent); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// tarTVMinWidth GNU tar 中属主和大小两列的初始总宽度
+const tarTVMinWidth = 19
+
+// writeListingTarTV 输出与 GNU tar -tv 相同的格式
+//
+// 属主和大小两列的对齐宽度与 GNU tar 一样随已输出的最宽条目增长。
+func writeListingTarTV(w io.Writer, info *types.ArchiveInfo) error {
+	width := tarTVMinWidth
+	for _, file := range info.Files {
+		owner := ownerName(file.Uname, file.Uid) + "/" + ownerName(file.Gname, file.Gid)
+		size := strconv.FormatInt(file.Size, 10)
+		if n := len(owner) + 1 + len(size); n > width {
+			width = n
+		}
+
+		line := fmt.Sprintf("%s %s %*s %s %s",
+			listingModeString(file), owner, width-len(owner)-1, size,
+			file.ModTime.Format("2006-01-02 15:04"), file.Name)
+		switch {
+		case file.IsSymlink:
+			line += " -> " + file.LinkTarget
+		case file.HardlinkTarget != "":
+			line += " link to " + file.HardlinkTarget
+		}
+		if _, err := fmt.Fprintln(w, line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// listingModeString 返回 ls -l 和 tar -tv 风格的权限字符串
+//
+// 参数:
+//   - file: 条目信息
+//
+// 返回:
+//   - string: 10 个字符的权限字符串，如 "-rw-r--r--"、"drwxr-xr-x"、"hrw-r--r--"
+func listingModeString(file types.FileInfo) string {
+	var typeChar byte
+	switch entryType(file) {
+	case types.EntryTypeDir:
+		typeChar = 'd'
+	case types.EntryTypeSymlink:
+		typeChar = 'l'
+	case types.EntryTypeHardlink:
+		typeChar = 'h'
+	case types.EntryTypeFifo:
+		typeChar = 'p'
+	case types.EntryTypeCharDevice:
+		typeChar = 'c'
+	case types.EntryTypeBlockDevice:
+		typeChar = 'b'
+	case types.EntryTypeSocket:
+		typeChar = 's'
+	default:
+		typeChar = '-'
+	}
+
+	mode := file.Mode
+	perm := []byte(mode.Perm().String())
+	perm[0] = typeChar
+	setSpecial := func(pos int, set bool, exec, noExec byte) {
+		if !set {
+			return
+		}
+		if perm[pos] == 'x' {
+			perm[pos] = exec
+		} else {
+			perm[pos] = noExec
+		}
+	}
+	setSpecial(3, mode&os.ModeSetuid != 0, 's', 'S')
+	setSpecial(6, mode&os.ModeSetgid != 0, 's', 'S')
+	setSpecial(9, mode&os.ModeSticky != 0, 't', 'T')
+	return string(perm)
+}
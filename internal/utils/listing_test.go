@@ -0,0 +1,139 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"gitee.com/MM-Q/comprx/types"
+)
+
+// listingFixture 返回用于测试输出格式的压缩包信息
+func listingFixture() *types.ArchiveInfo {
+	modTime := time.Date(2024, 6, 1, 12, 30, 0, 0, time.UTC)
+	return &types.ArchiveInfo{
+		Type:           types.CompressTypeTar,
+		TotalFiles:     5,
+		TotalSize:      1536,
+		CompressedSize: 10240,
+		Files: []types.FileInfo{
+			{Name: "app/", IsDir: true, Mode: os.ModeDir | 0755, ModTime: modTime, EntryType: types.EntryTypeDir, Uname: "root", Gname: "root"},
+			{Name: "app/bin/run", Size: 1024, Mode: os.ModeSetuid | 0755, ModTime: modTime, EntryType: types.EntryTypeFile, Uname: "root", Gname: "wheel", HeaderOffset: 512},
+			{Name: "app/conf/a.yaml", Size: 512, Mode: 0644, ModTime: modTime, EntryType: types.EntryTypeFile, Uid: 1000, Gid: 1000, CRC32: 0xabcdef, Xattrs: map[string]string{"user.b": "2", "user.a": "1"}},
+			{Name: "app/current", Mode: os.ModeSymlink | 0777, ModTime: modTime, EntryType: types.EntryTypeSymlink, IsSymlink: true, LinkTarget: "bin/run", Uname: "root", Gname: "root"},
+			{Name: "app/run-hard", Mode: 0755, ModTime: modTime, EntryType: types.EntryTypeHardlink, HardlinkTarget: "app/bin/run", Uname: "root", Gname: "root"},
+		},
+	}
+}
+
+func TestWriteListing_JSON(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteListing(&buf, listingFixture(), types.ListFormatJSON); err != nil {
+		t.Fatalf("输出 JSON 失败: %v", err)
+	}
+
+	var doc listingDocument
+	if err := json.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatalf("解析 JSON 失败: %v\n%s", err, buf.String())
+	}
+	if doc.Type != types.CompressTypeTar || doc.TotalFiles != 5 || len(doc.Files) != 5 {
+		t.Errorf("汇总信息不正确: %+v", doc)
+	}
+	conf := doc.Files[2]
+	if conf.CRC32 != "00abcdef" || conf.Xattrs["user.a"] != "1" || conf.ModTime != "2024-06-01T12:30:00Z" {
+		t.Errorf("条目信息不正确: %+v", conf)
+	}
+	if doc.Files[1].Mode != "-rwsr-xr-x" || doc.Files[3].Mode != "lrwxrwxrwx" {
+		t.Errorf("权限字符串不正确: %s, %s", doc.Files[1].Mode, doc.Files[3].Mode)
+	}
+}
+
+func TestWriteListing_NDJSON(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteListing(&buf, listingFixture(), types.ListFormatNDJSON); err != nil {
+		t.Fatalf("输出 NDJSON 失败: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if len(lines) != 5 {
+		t.Fatalf("应输出 5 行: %q", buf.String())
+	}
+	var entry listingEntry
+	if err := json.Unmarshal([]byte(lines[4]), &entry); err != nil {
+		t.Fatalf("解析 NDJSON 行失败: %v", err)
+	}
+	if entry.Type != types.EntryTypeHardlink || entry.HardlinkTarget != "app/bin/run" {
+		t.Errorf("硬链接条目不正确: %+v", entry)
+	}
+}
+
+func TestWriteListing_CSV(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteListing(&buf, listingFixture(), types.ListFormatCSV); err != nil {
+		t.Fatalf("输出 CSV 失败: %v", err)
+	}
+
+	records, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("解析 CSV 失败: %v", err)
+	}
+	if len(records) != 6 || strings.Join(records[0], ",") != strings.Join(listingCSVHeader, ",") {
+		t.Fatalf("CSV 表头或行数不正确: %v", records)
+	}
+	conf := records[3]
+	if conf[0] != "app/conf/a.yaml" || conf[2] != "512" || conf[8] != "1000" || conf[17] != "user.a=1;user.b=2" {
+		t.Errorf("CSV 记录不正确: %v", conf)
+	}
+}
+
+func TestWriteListing_Tree(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteListing(&buf, listingFixture(), types.ListFormatTree); err != nil {
+		t.Fatalf("输出树形视图失败: %v", err)
+	}
+
+	want := `. (1.5 KB)
+└── app/ (1.5 KB)
+    ├── bin/ (1.0 KB)
+    │   └── run (1.0 KB)
+    ├── conf/ (512 B)
+    │   └── a.yaml (512 B)
+    ├── current -> bin/run (0 B)
+    └── run-hard (0 B)
+
+3 directories, 4 files
+`
+	if buf.String() != want {
+		t.Errorf("树形视图不正确:\n%s\nwant:\n%s", buf.String(), want)
+	}
+}
+
+func TestWriteListing_TarTV(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteListing(&buf, listingFixture(), types.ListFormatTarTV); err != nil {
+		t.Fatalf("输出 tar -tv 格式失败: %v", err)
+	}
+
+	want := `drwxr-xr-x root/root         0 2024-06-01 12:30 app/
+-rwsr-xr-x root/wheel     1024 2024-06-01 12:30 app/bin/run
+-rw-r--r-- 1000/1000       512 2024-06-01 12:30 app/conf/a.yaml
+lrwxrwxrwx root/root         0 2024-06-01 12:30 app/current -> bin/run
+hrwxr-xr-x root/root         0 2024-06-01 12:30 app/run-hard link to app/bin/run
+`
+	if buf.String() != want {
+		t.Errorf("tar -tv 输出不正确:\n%s\nwant:\n%s", buf.String(), want)
+	}
+}
+
+func TestWriteListing_Invalid(t *testing.T) {
+	if err := WriteListing(&bytes.Buffer{}, listingFixture(), "xml"); err == nil {
+		t.Error("不支持的格式应返回错误")
+	}
+	if err := WriteListing(&bytes.Buffer{}, nil, types.ListFormatJSON); err == nil {
+		t.Error("压缩包信息为空时应返回错误")
+	}
+}
@@ -0,0 +1,92 @@
+// Package utils 提供文件原子替换和临时文件相关的工具函数。
+//
+// 该文件实现了通过"写入同目录临时文件 + 重命名"的方式原子地重写文件，
+// 保证重写过程中出错或中断时原文件保持不变，供追加、删除、重命名条目等修改压缩包的操作复用。
+//
+// 主要功能：
+//   - 原子替换文件内容
+//   - 创建临时工作路径
+//
+// 使用示例：
+//
+//	err := utils.ReplaceFile("archive.zip", func(f *os.File) error {
+//	    return writeNewArchive(f)
+//	})
+package utils
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// ReplaceFile 以原子方式重写文件
+//
+// 在目标文件所在目录创建临时文件，由 write 写入新内容后重命名覆盖目标文件。
+// write 返回错误时删除临时文件，目标文件保持不变。
+//
+// 参数:
+//   - dst: 目标文件路径
+//   - write: 写入新内容的函数
+//
+// 返回:
+//   - error: 写入或替换失败时返回错误
+//
+// 注意:
+//   - 目标文件存在时，新文件沿用其权限；否则权限为 0644
+func ReplaceFile(dst string, write func(f *os.File) error) (err error) {
+	tmpFile, err := os.CreateTemp(filepath.Dir(dst), "."+filepath.Base(dst)+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("创建临时文件失败: %w", err)
+	}
+	tmpPath := tmpFile.Name()
+
+	// 出错时清理临时文件
+	defer func() {
+		if err != nil {
+			_ = tmpFile.Close()
+			_ = os.Remove(tmpPath)
+		}
+	}()
+
+	if err = write(tmpFile); err != nil {
+		return err
+	}
+
+	// 沿用原文件权限，目标文件不存在时使用默认权限
+	perm := os.FileMode(0644)
+	if info, statErr := os.Stat(dst); statErr == nil {
+		perm = info.Mode().Perm()
+	}
+	if err = tmpFile.Chmod(perm); err != nil {
+		return fmt.Errorf("设置临时文件权限失败: %w", err)
+	}
+
+	if err = tmpFile.Sync(); err != nil {
+		return fmt.Errorf("同步临时文件失败: %w", err)
+	}
+	if err = tmpFile.Close(); err != nil {
+		return fmt.Errorf("关闭临时文件失败: %w", err)
+	}
+	if err = os.Rename(tmpPath, dst); err != nil {
+		return fmt.Errorf("替换文件 '%s' 失败: %w", dst, err)
+	}
+	return nil
+}
+
+// NewScratchPath 在系统临时目录中创建工作目录并返回其中的文件路径
+//
+// 参数:
+//   - name: 文件名
+//
+// 返回:
+//   - string: 工作文件路径（文件本身尚未创建）
+//   - func(): 删除工作目录的清理函数
+//   - error: 创建工作目录失败时返回错误
+func NewScratchPath(name string) (string, func(), error) {
+	dir, err := os.MkdirTemp("", "comprx-*")
+	if err != nil {
+		return "", nil, fmt.Errorf("创建临时工作目录失败: %w", err)
+	}
+	return filepath.Join(dir, name), func() { _ = os.RemoveAll(dir) }, nil
+}
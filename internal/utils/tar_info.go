@@ -0,0 +1,190 @@
+// Package utils 提供根据 TAR 文件头构建条目信息的实用工具函数。
+//
+// 该文件供 TAR 和 TGZ 的内容列表共用，负责把 TAR 文件头和 PAX 记录中的
+// 属主、时间、扩展属性和链接信息转换为 types.FileInfo，并记录条目头偏移。
+//
+// 主要功能：
+//   - TAR 文件头到条目信息的转换
+//   - PAX 扩展属性(SCHILY.xattr.*)提取
+//   - 遍历条目时记录条目头在 TAR 流中的偏移
+//
+// 使用示例：
+//
+//	entries := utils.NewTarEntryReader(file)
+//	for {
+//	    header, offset, err := entries.Next()
+//	    if err == io.EOF {
+//	        break
+//	    }
+//	    info := utils.TarFileInfo(header, offset)
+//	}
+package utils
+
+import (
+	"archive/tar"
+	"io"
+	"strings"
+
+	"gitee.com/MM-Q/comprx/types"
+)
+
+const (
+	// tarBlockSize TAR 记录块大小
+	tarBlockSize = 512
+	// paxXattrPrefix PAX 记录中扩展属性的前缀
+	paxXattrPrefix = "SCHILY.xattr."
+)
+
+// TarEntryReader 遍历 TAR 条目并记录条目头偏移的读取器
+type TarEntryReader struct {
+	*tar.Reader
+	counter *countingReader // 统计底层已读取的字节数
+	next    int64           // 下一个条目头的偏移
+}
+
+// countingReader 统计已读取字节数的读取器
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+// Read 读取数据并累计字节数
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
+}
+
+// NewTarEntryReader 创建记录条目头偏移的 TAR 读取器
+//
+// 参数:
+//   - r: TAR 数据流，偏移相对于该数据流的起始位置
+//
+// 返回:
+//   - *TarEntryReader: TAR 条目读取器
+func NewTarEntryReader(r io.Reader) *TarEntryReader {
+	counter := &countingReader{r: r}
+	return &TarEntryReader{Reader: tar.NewReader(counter), counter: counter}
+}
+
+// Next 读取下一个条目头
+//
+// 返回的偏移是条目第一个头记录的位置，PAX 扩展头和 GNU 长文件名头都计入该条目。
+//
+// 返回:
+//   - *tar.Header: 条目头
+//   - int64: 条目头在 TAR 流中的偏移
+//   - error: 没有更多条目时返回 io.EOF
+func (r *TarEntryReader) Next() (*tar.Header, int64, error) {
+	offset := r.next
+	header, err := r.Reader.Next()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// tar.Reader 按块读取文件头，此时计数恰好位于条目数据的起始位置
+	r.next = r.counter.n + TarPaddedDataSize(header)
+	return header, offset, nil
+}
+
+// TarPaddedDataSize 返回条目数据在 TAR 流中占用的字节数（按块大小向上对齐）
+//
+// 参数:
+//   - header: 条目头
+//
+// 返回:
+//   - int64: 对齐后的数据大小，只有文件头的类型(链接、目录、设备等)为 0
+func TarPaddedDataSize(header *tar.Header) int64 {
+	switch header.Typeflag {
+	case tar.TypeLink, tar.TypeSymlink, tar.TypeChar, tar.TypeBlock, tar.TypeDir, tar.TypeFifo:
+		return 0
+	}
+	return (header.Size + tarBlockSize - 1) / tarBlockSize * tarBlockSize
+}
+
+// TarEntryType 返回 TAR 条目类型
+//
+// 参数:
+//   - header: 条目头
+//
+// 返回:
+//   - types.EntryType: 条目类型，未知类型按普通文件处理
+func TarEntryType(header *tar.Header) types.EntryType {
+	switch header.Typeflag {
+	case tar.TypeDir:
+		return types.EntryTypeDir
+	case tar.TypeSymlink:
+		return types.EntryTypeSymlink
+	case tar.TypeLink:
+		return types.EntryTypeHardlink
+	case tar.TypeFifo:
+		return types.EntryTypeFifo
+	case tar.TypeChar:
+		return types.EntryTypeCharDevice
+	case tar.TypeBlock:
+		return types.EntryTypeBlockDevice
+	default:
+		return types.EntryTypeFile
+	}
+}
+
+// TarFileInfo 根据 TAR 文件头构建条目信息
+//
+// 参数:
+//   - header: 条目头
+//   - offset: 条目头在 TAR 流中的偏移
+//
+// 返回:
+//   - types.FileInfo: 条目信息，压缩大小由调用方按格式设置
+func TarFileInfo(header *tar.Header, offset int64) types.FileInfo {
+	mode := header.FileInfo().Mode()
+	info := types.FileInfo{
+		Name:         header.Name,
+		Size:         header.Size,
+		ModTime:      header.ModTime,
+		Mode:         mode,
+		IsDir:        mode.IsDir(),
+		IsSymlink:    header.Typeflag == tar.TypeSymlink,
+		EntryType:    TarEntryType(header),
+		Uid:          header.Uid,
+		Gid:          header.Gid,
+		Uname:        header.Uname,
+		Gname:        header.Gname,
+		AccessTime:   header.AccessTime,
+		ChangeTime:   header.ChangeTime,
+		Xattrs:       tarXattrs(header),
+		HeaderOffset: offset,
+	}
+
+	// 硬链接同时保留 LinkTarget，兼容只读取 LinkTarget 的调用方
+	switch header.Typeflag {
+	case tar.TypeSymlink:
+		info.LinkTarget = header.Linkname
+	case tar.TypeLink:
+		info.LinkTarget = header.Linkname
+		info.HardlinkTarget = header.Linkname
+	}
+	return info
+}
+
+// tarXattrs 从 PAX 记录中提取扩展属性
+//
+// 参数:
+//   - header: 条目头
+//
+// 返回:
+//   - map[string]string: 扩展属性，没有时返回 nil
+func tarXattrs(header *tar.Header) map[string]string {
+	var xattrs map[string]string
+	for key, value := range header.PAXRecords {
+		name, ok := strings.CutPrefix(key, paxXattrPrefix)
+		if !ok {
+			continue
+		}
+		if xattrs == nil {
+			xattrs = make(map[string]string)
+		}
+		xattrs[name] = value
+	}
+	return xattrs
+}
@@ -0,0 +1,26 @@
+package utils
+
+import (
+	"archive/tar"
+	"testing"
+)
+
+func TestTarPaddedDataSize(t *testing.T) {
+	tests := []struct {
+		header *tar.Header
+		want   int64
+	}{
+		{&tar.Header{Typeflag: tar.TypeReg, Size: 0}, 0},
+		{&tar.Header{Typeflag: tar.TypeReg, Size: 1}, 512},
+		{&tar.Header{Typeflag: tar.TypeReg, Size: 512}, 512},
+		{&tar.Header{Typeflag: tar.TypeReg, Size: 513}, 1024},
+		{&tar.Header{Typeflag: tar.TypeDir, Size: 100}, 0},
+		{&tar.Header{Typeflag: tar.TypeLink, Size: 100}, 0},
+		{&tar.Header{Typeflag: tar.TypeSymlink, Size: 100}, 0},
+	}
+	for _, tt := range tests {
+		if got := TarPaddedDataSize(tt.header); got != tt.want {
+			t.Errorf("类型 %c 大小 %d 的对齐数据大小 = %d, want %d", tt.header.Typeflag, tt.header.Size, got, tt.want)
+		}
+	}
+}
@@ -7,8 +7,12 @@
 //   - 列出压缩包内的文件信息
 //   - 打印压缩包基本信息
 //   - 支持文件名模式匹配
+//   - 按与解压、压缩相同的过滤选项列出文件
 //   - 支持限制显示文件数量
 //   - 提供简洁和详细两种显示样式
+//   - 流式遍历超大压缩包的条目
+//   - 以 JSON、NDJSON、CSV、树形和 tar -tv 格式输出内容列表
+//   - 排序、按目录分组和内容统计
 //
 // 使用示例：
 //
@@ -20,9 +24,22 @@
 //
 //	// 打印匹配模式的文件（详细样式）
 //	err := comprx.PrintLlMatch("archive.zip", "*.go")
+//
+//	// 流式遍历条目
+//	for file, err := range comprx.Entries("huge.tar.gz") {
+//	    if err != nil {
+//	        return err
+//	    }
+//	    fmt.Println(file.Name)
+//	}
 package comprx
 
 import (
+	"fmt"
+	"io"
+	"io/fs"
+	"iter"
+
 	"gitee.com/MM-Q/comprx/internal/core"
 	"gitee.com/MM-Q/comprx/internal/utils"
 	"gitee.com/MM-Q/comprx/types"
@@ -34,14 +51,30 @@ import (
 
 // List 列出压缩包的所有文件信息
 //
+// GZIP 文件默认只读取尾部的 ISIZE 获取原始大小，传入 Accurate 为 true 的列表选项时完整解压；
+// 传入的排序、分组和数量选项与 ListSorted 相同。
+//
 // 参数:
 //   - archivePath: 压缩包文件路径
+//   - opts: 可选的列表选项，最多一个，见 types.ListOptions
 //
 // 返回:
 //   - *types.ArchiveInfo: 压缩包信息
 //   - error: 错误信息
-func List(archivePath string) (*types.ArchiveInfo, error) {
-	return core.List(archivePath)
+//
+// 使用示例:
+//
+//	// 完整解压获取 GZIP 文件准确的原始大小
+//	info, err := comprx.List("app.log.gz", types.ListOptions{Accurate: true})
+func List(archivePath string, opts ...types.ListOptions) (*types.ArchiveInfo, error) {
+	switch len(opts) {
+	case 0:
+		return core.List(archivePath)
+	case 1:
+		return ListSorted(archivePath, opts[0])
+	default:
+		return nil, fmt.Errorf("列表选项最多只能传入一个，实际 %d 个", len(opts))
+	}
 }
 
 // ListLimit 列出指定数量的文件信息
@@ -70,6 +103,163 @@ func ListMatch(archivePath string, pattern string) (*types.ArchiveInfo, error) {
 	return core.ListMatch(archivePath, pattern)
 }
 
+// ListOptions 列出通过过滤选项的文件信息
+//
+// 与 ListMatch 不同，ListOptions 使用与 PackOptions、UnpackOptions 相同的过滤逻辑，
+// 支持包含、排除和大小过滤。使用同一过滤选项时，列出的条目与 UnpackOptions 解压的条目完全一致，
+// 可以先预览再解压。
+//
+// 参数:
+//   - archivePath: 压缩包文件路径
+//   - opts: 过滤选项，见 types.FilterOptions
+//
+// 返回:
+//   - *types.ArchiveInfo: 压缩包信息
+//   - error: 错误信息
+//
+// 使用示例:
+//
+//	filter := types.FilterOptions{Include: []string{"*.go"}, Exclude: []string{"*_test.go"}}
+//	info, err := comprx.ListOptions("src.tar.gz", filter)
+//	// 解压的正是上面列出的文件
+//	err = comprx.UnpackOptions("src.tar.gz", "out", comprx.Options{Filter: filter})
+func ListOptions(archivePath string, opts types.FilterOptions) (*types.ArchiveInfo, error) {
+	return core.ListFilter(archivePath, &opts)
+}
+
+// ListSorted 按排序、分组和数量选项列出文件信息
+//
+// GZIP 文件默认只读取尾部的 ISIZE 获取原始大小，设置 opts.Accurate 时完整解压。
+//
+// 参数:
+//   - archivePath: 压缩包文件路径
+//   - opts: 列表选项，见 types.ListOptions
+//
+// 返回:
+//   - *types.ArchiveInfo: 排序后的压缩包信息
+//   - error: 错误信息
+//
+// 使用示例:
+//
+//	// 最大的 20 个文件
+//	info, err := comprx.ListSorted("release.zip", types.ListOptions{
+//	    SortBy:  types.ListSortBySize,
+//	    Reverse: true,
+//	    TopN:    20,
+//	})
+func ListSorted(archivePath string, opts types.ListOptions) (*types.ArchiveInfo, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+
+	list := core.List
+	if opts.Accurate {
+		list = core.ListAccurate
+	}
+	archiveInfo, err := list(archivePath)
+	if err != nil {
+		return nil, err
+	}
+	return archiveInfo.Apply(opts), nil
+}
+
+// Stats 统计压缩包内容
+//
+// 返回按扩展名汇总的数量和字节数、最大的文件、层级最深的路径、
+// 内容可能重复的文件(大小和 CRC32 相同)以及各目录的压缩比。
+//
+// 参数:
+//   - archivePath: 压缩包文件路径
+//
+// 返回:
+//   - *types.ArchiveStats: 压缩包内容统计，最大文件和最深路径各保留 types.DefaultStatsTopN 个
+//   - error: 错误信息
+func Stats(archivePath string) (*types.ArchiveStats, error) {
+	archiveInfo, err := core.List(archivePath)
+	if err != nil {
+		return nil, err
+	}
+	return archiveInfo.Stats(types.DefaultStatsTopN), nil
+}
+
+// Walk 逐条遍历压缩包中的条目
+//
+// 与 List 不同，Walk 不构建完整的文件列表，TAR、TGZ 和 ZIP 的内存占用与条目数量无关，
+// 适合包含海量条目的压缩包。
+//
+// 参数:
+//   - archivePath: 压缩包文件路径
+//   - fn: 对每个条目调用的函数，返回 fs.SkipAll 时提前结束遍历，返回其他错误时中止并返回该错误
+//
+// 返回:
+//   - error: 错误信息
+//
+// 使用示例:
+//
+//	err := comprx.Walk("huge.tar", func(file types.FileInfo) error {
+//	    if file.Name == "target.txt" {
+//	        return fs.SkipAll // 找到后停止遍历
+//	    }
+//	    return nil
+//	})
+func Walk(archivePath string, fn func(types.FileInfo) error) error {
+	return core.Walk(archivePath, fn)
+}
+
+// Entries 返回逐条产出压缩包条目的迭代器
+//
+// 迭代器基于 Walk 实现，可以在 range 循环中 break 提前结束。
+// 遍历失败时产出一个零值条目和对应的错误，随后结束迭代。
+//
+// 参数:
+//   - archivePath: 压缩包文件路径
+//
+// 返回:
+//   - iter.Seq2[types.FileInfo, error]: 条目迭代器
+//
+// 使用示例:
+//
+//	for file, err := range comprx.Entries("huge.tar.gz") {
+//	    if err != nil {
+//	        log.Fatal(err)
+//	    }
+//	    fmt.Println(file.Name)
+//	}
+func Entries(archivePath string) iter.Seq2[types.FileInfo, error] {
+	return func(yield func(types.FileInfo, error) bool) {
+		err := core.Walk(archivePath, func(file types.FileInfo) error {
+			if !yield(file, nil) {
+				return fs.SkipAll
+			}
+			return nil
+		})
+		if err != nil {
+			yield(types.FileInfo{}, err)
+		}
+	}
+}
+
+// WriteListing 按指定格式输出压缩包内容列表
+//
+// 参数:
+//   - w: 输出目标
+//   - info: 压缩包信息，通常由 List 系列函数获取
+//   - format: 输出格式，见 types.ListFormat
+//
+// 返回:
+//   - error: 格式不受支持或写入失败时返回错误
+//
+// 使用示例:
+//
+//	info, err := comprx.List("app.tar.gz")
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	err = comprx.WriteListing(os.Stdout, info, types.ListFormatNDJSON)
+func WriteListing(w io.Writer, info *types.ArchiveInfo, format types.ListFormat) error {
+	return utils.WriteListing(w, info, format)
+}
+
 // ==============================================
 // 打印压缩包本身信息
 // ==============================================
@@ -1,13 +1,16 @@
 package comprx
 
 import (
+	"errors"
 	"fmt"
+	"io/fs"
 	"os"
 	"path/filepath"
 	"strings"
 	"testing"
 
 	"gitee.com/MM-Q/comprx/internal/core"
+	"gitee.com/MM-Q/comprx/types"
 )
 
 // TestListEmptyPaths 测试空路径参数
@@ -292,6 +295,36 @@ func TestListGzipFile(t *testing.T) {
 	}
 }
 
+// TestListWithOptions 测试 List 接受列表选项
+func TestListWithOptions(t *testing.T) {
+	tempDir := t.TempDir()
+
+	srcFile := filepath.Join(tempDir, "app.log")
+	testContent := strings.Repeat("list options\n", 100)
+	if err := os.WriteFile(srcFile, []byte(testContent), 0644); err != nil {
+		t.Fatal(err)
+	}
+	gzFile := filepath.Join(tempDir, "app.log.gz")
+	if err := core.New().Pack(gzFile, srcFile); err != nil {
+		t.Fatalf("创建GZIP文件失败: %v", err)
+	}
+
+	info, err := List(gzFile, types.ListOptions{Accurate: true})
+	if err != nil {
+		t.Fatalf("完整解压列出GZIP文件失败: %v", err)
+	}
+	if info.TotalSize != int64(len(testContent)) {
+		t.Errorf("原始大小不正确: 期望 %d, 实际 %d", len(testContent), info.TotalSize)
+	}
+
+	if _, err := List(gzFile, types.ListOptions{TopN: -1}); err == nil {
+		t.Error("无效的列表选项应返回错误")
+	}
+	if _, err := List(gzFile, types.ListOptions{}, types.ListOptions{}); err == nil {
+		t.Error("传入多个列表选项应返回错误")
+	}
+}
+
 // TestListBz2File 测试列出BZ2文件内容
 func TestListBz2File(t *testing.T) {
 	tempDir := t.TempDir()
@@ -1088,3 +1121,242 @@ func BenchmarkPrintFunctions(b *testing.B) {
 		}
 	})
 }
+
+// TestWalkAndEntries 测试流式遍历压缩包条目
+func TestWalkAndEntries(t *testing.T) {
+	tempDir := t.TempDir()
+	srcDir := filepath.Join(tempDir, "src")
+	if err := os.MkdirAll(srcDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 5; i++ {
+		if err := os.WriteFile(filepath.Join(srcDir, fmt.Sprintf("file%d.txt", i)), []byte("data"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, name := range []string{"src.zip", "src.tar", "src.tgz"} {
+		t.Run(name, func(t *testing.T) {
+			archive := filepath.Join(tempDir, name)
+			if err := Pack(archive, srcDir); err != nil {
+				t.Fatalf("打包失败: %v", err)
+			}
+
+			// 完整遍历的条目数与 List 一致
+			info, err := List(archive)
+			if err != nil {
+				t.Fatalf("列出内容失败: %v", err)
+			}
+			count := 0
+			if err := Walk(archive, func(types.FileInfo) error { count++; return nil }); err != nil {
+				t.Fatalf("遍历失败: %v", err)
+			}
+			if count != len(info.Files) {
+				t.Errorf("遍历条目数 = %d, want %d", count, len(info.Files))
+			}
+
+			// 返回 fs.SkipAll 提前结束，不视为错误
+			count = 0
+			err = Walk(archive, func(types.FileInfo) error {
+				count++
+				if count == 2 {
+					return fs.SkipAll
+				}
+				return nil
+			})
+			if err != nil || count != 2 {
+				t.Errorf("SkipAll 应在第 2 个条目后结束遍历: count=%d, err=%v", count, err)
+			}
+
+			// 回调返回的其他错误原样返回
+			stop := errors.New("stop")
+			if err := Walk(archive, func(types.FileInfo) error { return stop }); !errors.Is(err, stop) {
+				t.Errorf("应返回回调的错误: %v", err)
+			}
+
+			// range 循环中 break 提前结束
+			var names []string
+			for file, err := range Entries(archive) {
+				if err != nil {
+					t.Fatalf("迭代失败: %v", err)
+				}
+				names = append(names, file.Name)
+				if len(names) == 3 {
+					break
+				}
+			}
+			if len(names) != 3 || names[0] != info.Files[0].Name {
+				t.Errorf("迭代结果不正确: %v", names)
+			}
+		})
+	}
+
+	// 遍历失败时产出错误
+	var gotErr error
+	for _, err := range Entries(filepath.Join(tempDir, "missing.tar")) {
+		gotErr = err
+	}
+	if gotErr == nil {
+		t.Error("压缩包不存在时应产出错误")
+	}
+}
+
+// TestListSortedAndStats 测试排序列表和内容统计
+func TestListSortedAndStats(t *testing.T) {
+	tempDir := t.TempDir()
+	srcDir := filepath.Join(tempDir, "src")
+	if err := os.MkdirAll(filepath.Join(srcDir, "sub"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	files := map[string]string{
+		"small.txt":   "a",
+		"big.txt":     strings.Repeat("b", 4096),
+		"sub/big.txt": strings.Repeat("b", 4096),
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(srcDir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	archive := filepath.Join(tempDir, "src.zip")
+	if err := Pack(archive, srcDir); err != nil {
+		t.Fatalf("打包失败: %v", err)
+	}
+
+	info, err := ListSorted(archive, types.ListOptions{SortBy: types.ListSortBySize, Reverse: true, TopN: 2})
+	if err != nil {
+		t.Fatalf("排序列表失败: %v", err)
+	}
+	if len(info.Files) != 2 || info.Files[0].Size != 4096 || info.Files[1].Size != 4096 {
+		t.Errorf("最大的两个文件不正确: %+v", info.Files)
+	}
+	if _, err := ListSorted(archive, types.ListOptions{SortBy: "owner"}); err == nil {
+		t.Error("不支持的排序字段应返回错误")
+	}
+
+	stats, err := Stats(archive)
+	if err != nil {
+		t.Fatalf("统计失败: %v", err)
+	}
+	if stats.TotalFiles != 3 || len(stats.Duplicates) != 1 || len(stats.Duplicates[0].Files) != 2 {
+		t.Errorf("统计结果不正确: %+v", stats)
+	}
+}
+
+// TestListOptionsMatchesUnpack 测试按过滤选项列出的文件与解压的文件一致
+func TestListOptionsMatchesUnpack(t *testing.T) {
+	tempDir := t.TempDir()
+	srcDir := filepath.Join(tempDir, "src")
+	if err := os.MkdirAll(filepath.Join(srcDir, "pkg", "internal"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	files := map[string]string{
+		"main.go":                "package main",
+		"main_test.go":           "package main",
+		"README.md":              "readme",
+		"pkg/util.go":            strings.Repeat("u", 2048),
+		"pkg/internal/helper.go": "package internal",
+		"pkg/data.GO":            "upper",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(srcDir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	filters := map[string]types.FilterOptions{
+		"包含":   {Include: []string{"*.go"}},
+		"包含排除": {Include: []string{"*.go"}, Exclude: []string{"*_test.go"}},
+		"目录排除": {Exclude: []string{"pkg/internal"}},
+		"大小":   {MinSize: 1024},
+		"无通配符": {Include: []string{"util"}},
+	}
+
+	for _, ext := range []string{".zip", ".tar", ".tgz"} {
+		archive := filepath.Join(tempDir, "src"+ext)
+		if err := Pack(archive, srcDir); err != nil {
+			t.Fatalf("打包失败: %v", err)
+		}
+
+		for name, filter := range filters {
+			t.Run(ext+"/"+name, func(t *testing.T) {
+				info, err := ListOptions(archive, filter)
+				if err != nil {
+					t.Fatalf("按过滤选项列出失败: %v", err)
+				}
+				listed := make(map[string]bool)
+				for _, file := range info.Files {
+					if !file.IsDir {
+						listed[file.Name] = true
+					}
+				}
+
+				outDir := filepath.Join(t.TempDir(), "out")
+				unpackOpts := DefaultOptions()
+				unpackOpts.Filter = filter
+				if err := UnpackOptions(archive, outDir, unpackOpts); err != nil {
+					t.Fatalf("解压失败: %v", err)
+				}
+				extracted := make(map[string]bool)
+				err = filepath.WalkDir(outDir, func(path string, d fs.DirEntry, err error) error {
+					if err != nil || d.IsDir() {
+						return err
+					}
+					rel, err := filepath.Rel(outDir, path)
+					if err != nil {
+						return err
+					}
+					extracted[filepath.ToSlash(rel)] = true
+					return nil
+				})
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				if len(listed) == 0 {
+					t.Fatal("过滤后应至少列出一个文件")
+				}
+				if len(listed) != len(extracted) {
+					t.Fatalf("列出 %v 与解压 %v 不一致", listed, extracted)
+				}
+				for name := range extracted {
+					if !listed[name] {
+						t.Errorf("解压了未列出的文件 %s，列出: %v", name, listed)
+					}
+				}
+			})
+		}
+	}
+
+	if _, err := ListOptions(filepath.Join(tempDir, "src.zip"), types.FilterOptions{MinSize: -1}); err == nil {
+		t.Error("无效的过滤选项应返回错误")
+	}
+}
+
+// TestListGzipHeader 测试打包时写入的 GZIP 文件头在列表中可读取
+func TestListGzipHeader(t *testing.T) {
+	tempDir := t.TempDir()
+	src := filepath.Join(tempDir, "data.csv")
+	if err := os.WriteFile(src, []byte("a,b\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	archive := filepath.Join(tempDir, "data.csv.gz")
+	opts := DefaultOptions().WithGzipHeader(types.GzipHeader{Name: "data.csv", Extra: []byte("PV\x01\x00x"), OS: types.GzipOSUnix})
+	if err := PackOptions(archive, src, opts); err != nil {
+		t.Fatalf("打包失败: %v", err)
+	}
+
+	info, err := List(archive)
+	if err != nil {
+		t.Fatalf("列出失败: %v", err)
+	}
+	if header := info.Files[0].Gzip; header == nil || string(header.Extra) != "PV\x01\x00x" || header.OS != types.GzipOSUnix {
+		t.Errorf("文件头不正确: %+v", header)
+	}
+
+	opts = DefaultOptions().WithGzipHeader(types.GzipHeader{Name: "数据.csv"})
+	if err := PackOptions(filepath.Join(tempDir, "bad.gz"), src, opts); err == nil {
+		t.Error("无法以 Latin-1 存储的文件名应返回错误")
+	}
+}
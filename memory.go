@@ -1,13 +1,20 @@
 // Package comprx 提供内存中的压缩和解压缩功能。
 //
-// 该文件提供了 GZIP 和 ZLIB 格式的内存压缩和流式压缩功能。
+// 该文件提供了 GZIP、ZLIB、原始 DEFLATE、LZ4 和 Snappy 格式的内存压缩和流式压缩功能。
 // 支持字节数组、字符串和流式数据的压缩与解压缩操作。
 //
 // 主要功能：
 //   - GZIP 内存压缩：字节数组和字符串的压缩解压
 //   - GZIP 流式压缩：支持 io.Reader 和 io.Writer 接口
+//   - GZIP 文件头：写入和读取原始文件名、注释、额外字段和操作系统标识
+//   - BGZF 内存压缩和流式压缩：分块 GZIP，兼容 htslib
 //   - ZLIB 内存压缩：字节数组和字符串的压缩解压
 //   - ZLIB 流式压缩：支持 io.Reader 和 io.Writer 接口
+//   - ZLIB 预设字典：从样本训练字典，提高相似小消息的压缩率
+//   - 原始 DEFLATE 内存压缩和流式压缩：没有文件头和校验和
+//   - 原始 DEFLATE 预设字典：解压时自行提供相同的字典
+//   - LZ4 内存压缩和流式压缩：LZ4 帧格式，兼容 lz4 命令行工具
+//   - Snappy 内存压缩和流式压缩：Snappy 分帧格式
 //   - 支持自定义压缩等级
 //
 // 使用示例：
@@ -22,7 +29,11 @@ package comprx
 import (
 	"io"
 
+	"gitee.com/MM-Q/comprx/internal/cxbgzf"
+	"gitee.com/MM-Q/comprx/internal/cxflate"
 	"gitee.com/MM-Q/comprx/internal/cxgzip"
+	"gitee.com/MM-Q/comprx/internal/cxlz4"
+	"gitee.com/MM-Q/comprx/internal/cxsnappy"
 	"gitee.com/MM-Q/comprx/internal/cxzlib"
 	"gitee.com/MM-Q/comprx/types"
 )
@@ -62,6 +73,23 @@ func GzipBytesWithLevel(data []byte, level types.CompressionLevel) ([]byte, erro
 	return cxgzip.CompressBytes(data, level)
 }
 
+// GzipBytesWithHeader 压缩字节数据并写入指定的文件头（使用默认压缩等级）
+//
+// 参数:
+//   - data: 要压缩的字节数据
+//   - header: GZIP 文件头，可记录原始文件名、注释、FEXTRA 额外字段、修改时间和操作系统标识
+//
+// 返回:
+//   - []byte: 压缩后的数据
+//   - error: 错误信息
+//
+// 使用示例:
+//
+//	compressed, err := GzipBytesWithHeader(data, types.GzipHeader{Name: "data.csv", Extra: provenance})
+func GzipBytesWithHeader(data []byte, header types.GzipHeader) ([]byte, error) {
+	return cxgzip.CompressBytesWithHeader(data, types.CompressionLevelDefault, &header)
+}
+
 // UngzipBytes 解压字节数据
 //
 // 参数:
@@ -172,6 +200,24 @@ func GzipStreamWithLevel(dst io.Writer, src io.Reader, level types.CompressionLe
 	return cxgzip.CompressStream(dst, src, level)
 }
 
+// GzipStreamWithHeader 流式压缩数据并写入指定的文件头（使用默认压缩等级）
+//
+// 参数:
+//   - dst: 目标写入器
+//   - src: 源读取器
+//   - header: GZIP 文件头
+//
+// 返回:
+//   - error: 错误信息
+//
+// 使用示例:
+//
+//	header := types.GzipHeader{Name: "data.csv", ModTime: time.Now(), OS: types.GzipOSUnix}
+//	err := GzipStreamWithHeader(output, file, header)
+func GzipStreamWithHeader(dst io.Writer, src io.Reader, header types.GzipHeader) error {
+	return cxgzip.CompressStreamWithHeader(dst, src, types.CompressionLevelDefault, &header)
+}
+
 // UngzipStream 流式解压数据
 //
 // 参数:
@@ -194,6 +240,131 @@ func UngzipStream(dst io.Writer, src io.Reader) error {
 	return cxgzip.DecompressStream(dst, src)
 }
 
+// UngzipStreamWithHeader 流式解压数据并返回文件头
+//
+// 参数:
+//   - dst: 目标写入器
+//   - src: 源读取器（压缩数据）
+//
+// 返回:
+//   - *types.GzipHeader: 第一个成员的文件头
+//   - error: 错误信息
+//
+// 使用示例:
+//
+//	header, err := UngzipStreamWithHeader(output, compressedFile)
+//	fmt.Println(header.Name, header.Comment, len(header.Extra))
+func UngzipStreamWithHeader(dst io.Writer, src io.Reader) (*types.GzipHeader, error) {
+	return cxgzip.DecompressStreamWithHeader(dst, src)
+}
+
+// ==================== BGZF 内存压缩API ====================
+
+// BgzfBytes 压缩字节数据为BGZF（使用默认压缩等级）
+//
+// 结果是合法的多成员 GZIP，也可以用 UngzipBytes 解压。
+//
+// 参数:
+//   - data: 要压缩的字节数据
+//
+// 返回:
+//   - []byte: 压缩后的数据
+//   - error: 错误信息
+//
+// 使用示例:
+//
+//	compressed, err := BgzfBytes(vcfData)
+func BgzfBytes(data []byte) ([]byte, error) {
+	return cxbgzf.CompressBytes(data, types.CompressionLevelDefault)
+}
+
+// BgzfBytesWithLevel 压缩字节数据为BGZF（指定压缩等级）
+//
+// 参数:
+//   - data: 要压缩的字节数据
+//   - level: 压缩级别
+//
+// 返回:
+//   - []byte: 压缩后的数据
+//   - error: 错误信息
+//
+// 使用示例:
+//
+//	compressed, err := BgzfBytesWithLevel(vcfData, types.CompressionLevelBest)
+func BgzfBytesWithLevel(data []byte, level types.CompressionLevel) ([]byte, error) {
+	return cxbgzf.CompressBytes(data, level)
+}
+
+// UnbgzfBytes 解压BGZF字节数据
+//
+// 与 UngzipBytes 不同，会校验每个数据块的 BGZF 结构。
+//
+// 参数:
+//   - compressedData: 压缩的字节数据
+//
+// 返回:
+//   - []byte: 解压后的数据
+//   - error: 错误信息
+//
+// 使用示例:
+//
+//	data, err := UnbgzfBytes(compressed)
+func UnbgzfBytes(compressedData []byte) ([]byte, error) {
+	return cxbgzf.DecompressBytes(compressedData)
+}
+
+// BgzfStream 流式压缩数据为BGZF（使用默认压缩等级）
+//
+// 参数:
+//   - dst: 目标写入器
+//   - src: 源读取器
+//
+// 返回:
+//   - error: 错误信息
+//
+// 使用示例:
+//
+//	output, _ := os.Create("variants.vcf.gz")
+//	defer output.Close()
+//
+//	err := BgzfStream(output, file)
+func BgzfStream(dst io.Writer, src io.Reader) error {
+	return cxbgzf.CompressStream(dst, src, types.CompressionLevelDefault)
+}
+
+// BgzfStreamWithLevel 流式压缩数据为BGZF（指定压缩等级）
+//
+// 参数:
+//   - dst: 目标写入器
+//   - src: 源读取器
+//   - level: 压缩级别
+//
+// 返回:
+//   - error: 错误信息
+//
+// 使用示例:
+//
+//	err := BgzfStreamWithLevel(output, file, types.CompressionLevelFast)
+func BgzfStreamWithLevel(dst io.Writer, src io.Reader, level types.CompressionLevel) error {
+	return cxbgzf.CompressStream(dst, src, level)
+}
+
+// UnbgzfStream 流式解压BGZF数据
+//
+// 参数:
+//   - dst: 目标写入器
+//   - src: 源读取器（压缩数据）
+//
+// 返回:
+//   - error: 错误信息
+//
+// 使用示例:
+//
+//	err := UnbgzfStream(output, compressedFile)
+func UnbgzfStream(dst io.Writer, src io.Reader) error {
+	return cxbgzf.DecompressStream(dst, src)
+}
+
 // ==================== ZLIB 内存压缩API ====================
 
 // ZlibBytes 压缩字节数据（使用默认压缩等级）
@@ -360,3 +531,446 @@ func ZlibStreamWithLevel(dst io.Writer, src io.Reader, level types.CompressionLe
 func UnzlibStream(dst io.Writer, src io.Reader) error {
 	return cxzlib.DecompressStream(dst, src)
 }
+
+// ZlibBytesWithDict 使用预设字典压缩字节数据（使用默认压缩等级）
+//
+// 适合大量短小且结构相似的消息，解压时必须提供相同的字典。
+//
+// 参数:
+//   - data: 要压缩的字节数据
+//   - dict: 预设字典，通常由 TrainDictionary 生成，只使用末尾的 32 KiB
+//
+// 返回:
+//   - []byte: 压缩后的数据
+//   - error: 错误信息
+//
+// 使用示例:
+//
+//	compressed, err := ZlibBytesWithDict(message, dict)
+func ZlibBytesWithDict(data []byte, dict []byte) ([]byte, error) {
+	return cxzlib.CompressBytesWithDict(data, types.CompressionLevelDefault, dict)
+}
+
+// UnzlibBytesWithDict 使用预设字典解压字节数据
+//
+// 参数:
+//   - compressedData: 压缩的字节数据
+//   - dict: 压缩时使用的预设字典
+//
+// 返回:
+//   - []byte: 解压后的数据
+//   - error: 字典与压缩时不一致时返回包装了 zlib.ErrDictionary 的错误
+//
+// 使用示例:
+//
+//	message, err := UnzlibBytesWithDict(compressed, dict)
+func UnzlibBytesWithDict(compressedData []byte, dict []byte) ([]byte, error) {
+	return cxzlib.DecompressBytesWithDict(compressedData, dict)
+}
+
+// ZlibStreamWithDict 使用预设字典流式压缩数据（使用默认压缩等级）
+//
+// 参数:
+//   - dst: 目标写入器
+//   - src: 源读取器
+//   - dict: 预设字典
+//
+// 返回:
+//   - error: 错误信息
+//
+// 使用示例:
+//
+//	err := ZlibStreamWithDict(conn, bytes.NewReader(message), dict)
+func ZlibStreamWithDict(dst io.Writer, src io.Reader, dict []byte) error {
+	return cxzlib.CompressStreamWithDict(dst, src, types.CompressionLevelDefault, dict)
+}
+
+// UnzlibStreamWithDict 使用预设字典流式解压数据
+//
+// 参数:
+//   - dst: 目标写入器
+//   - src: 源读取器（压缩数据）
+//   - dict: 压缩时使用的预设字典
+//
+// 返回:
+//   - error: 错误信息
+//
+// 使用示例:
+//
+//	err := UnzlibStreamWithDict(&buf, conn, dict)
+func UnzlibStreamWithDict(dst io.Writer, src io.Reader, dict []byte) error {
+	return cxzlib.DecompressStreamWithDict(dst, src, dict)
+}
+
+// TrainDictionary 从样本中训练 DEFLATE 预设字典
+//
+// 选取在多个样本中出现的公共子串组成字典，出现越频繁的子串越靠近字典末尾。
+// DEFLATE 最多引用 32 KiB 的历史数据，size 超过 32 KiB 时按 32 KiB 处理。
+//
+// 参数:
+//   - samples: 样本数据，应与实际要压缩的消息相似
+//   - size: 字典的最大字节数
+//
+// 返回:
+//   - []byte: 预设字典
+//   - error: 参数无效或样本中没有公共子串时返回错误
+//
+// 使用示例:
+//
+//	dict, err := TrainDictionary(samples, 4096)
+//	compressed, err := ZlibBytesWithDict(message, dict)
+func TrainDictionary(samples [][]byte, size int) ([]byte, error) {
+	return cxzlib.TrainDictionary(samples, size)
+}
+
+// ==================== 原始 DEFLATE 内存压缩API ====================
+
+// FlateBytes 压缩字节数据为原始DEFLATE（使用默认压缩等级）
+//
+// 原始 DEFLATE 没有文件头和校验和，用于 HTTP deflate 内容编码、PDF 的 FlateDecode 流
+// 和 ZIP 条目的内部数据。
+//
+// 参数:
+//   - data: 要压缩的字节数据
+//
+// 返回:
+//   - []byte: 压缩后的数据
+//   - error: 错误信息
+//
+// 使用示例:
+//
+//	compressed, err := FlateBytes([]byte("hello world"))
+func FlateBytes(data []byte) ([]byte, error) {
+	return cxflate.CompressBytes(data, types.CompressionLevelDefault)
+}
+
+// FlateBytesWithLevel 压缩字节数据为原始DEFLATE（指定压缩等级）
+//
+// 参数:
+//   - data: 要压缩的字节数据
+//   - level: 压缩级别
+//
+// 返回:
+//   - []byte: 压缩后的数据
+//   - error: 错误信息
+//
+// 使用示例:
+//
+//	compressed, err := FlateBytesWithLevel(data, types.CompressionLevelBest)
+func FlateBytesWithLevel(data []byte, level types.CompressionLevel) ([]byte, error) {
+	return cxflate.CompressBytes(data, level)
+}
+
+// UnflateBytes 解压原始DEFLATE字节数据
+//
+// 参数:
+//   - compressedData: 压缩的字节数据
+//
+// 返回:
+//   - []byte: 解压后的数据
+//   - error: 错误信息
+//
+// 使用示例:
+//
+//	data, err := UnflateBytes(compressed)
+func UnflateBytes(compressedData []byte) ([]byte, error) {
+	return cxflate.DecompressBytes(compressedData)
+}
+
+// FlateBytesWithDict 使用预设字典压缩字节数据为原始DEFLATE（使用默认压缩等级）
+//
+// 原始 DEFLATE 不记录字典的校验值，解压时必须自行提供相同的字典。
+//
+// 参数:
+//   - data: 要压缩的字节数据
+//   - dict: 预设字典，通常由 TrainDictionary 生成，只使用末尾的 32 KiB
+//
+// 返回:
+//   - []byte: 压缩后的数据
+//   - error: 错误信息
+//
+// 使用示例:
+//
+//	compressed, err := FlateBytesWithDict(message, dict)
+func FlateBytesWithDict(data []byte, dict []byte) ([]byte, error) {
+	return cxflate.CompressBytesWithDict(data, types.CompressionLevelDefault, dict)
+}
+
+// UnflateBytesWithDict 使用预设字典解压原始DEFLATE字节数据
+//
+// 参数:
+//   - compressedData: 压缩的字节数据
+//   - dict: 压缩时使用的预设字典
+//
+// 返回:
+//   - []byte: 解压后的数据
+//   - error: 错误信息，字典不一致时通常得到错误的数据而不是错误信息
+//
+// 使用示例:
+//
+//	message, err := UnflateBytesWithDict(compressed, dict)
+func UnflateBytesWithDict(compressedData []byte, dict []byte) ([]byte, error) {
+	return cxflate.DecompressBytesWithDict(compressedData, dict)
+}
+
+// FlateStream 流式压缩数据为原始DEFLATE（使用默认压缩等级）
+//
+// 参数:
+//   - dst: 目标写入器
+//   - src: 源读取器
+//
+// 返回:
+//   - error: 错误信息
+//
+// 使用示例:
+//
+//	w.Header().Set("Content-Encoding", "deflate")
+//	err := FlateStream(w, file)
+func FlateStream(dst io.Writer, src io.Reader) error {
+	return cxflate.CompressStream(dst, src, types.CompressionLevelDefault)
+}
+
+// FlateStreamWithLevel 流式压缩数据为原始DEFLATE（指定压缩等级）
+//
+// 参数:
+//   - dst: 目标写入器
+//   - src: 源读取器
+//   - level: 压缩级别
+//
+// 返回:
+//   - error: 错误信息
+//
+// 使用示例:
+//
+//	err := FlateStreamWithLevel(output, file, types.CompressionLevelFast)
+func FlateStreamWithLevel(dst io.Writer, src io.Reader, level types.CompressionLevel) error {
+	return cxflate.CompressStream(dst, src, level)
+}
+
+// UnflateStream 流式解压原始DEFLATE数据
+//
+// 参数:
+//   - dst: 目标写入器
+//   - src: 源读取器（压缩数据）
+//
+// 返回:
+//   - error: 错误信息
+//
+// 使用示例:
+//
+//	err := UnflateStream(output, resp.Body)
+func UnflateStream(dst io.Writer, src io.Reader) error {
+	return cxflate.DecompressStream(dst, src)
+}
+
+// FlateStreamWithDict 使用预设字典流式压缩数据为原始DEFLATE（使用默认压缩等级）
+//
+// 参数:
+//   - dst: 目标写入器
+//   - src: 源读取器
+//   - dict: 预设字典
+//
+// 返回:
+//   - error: 错误信息
+//
+// 使用示例:
+//
+//	err := FlateStreamWithDict(conn, bytes.NewReader(message), dict)
+func FlateStreamWithDict(dst io.Writer, src io.Reader, dict []byte) error {
+	return cxflate.CompressStreamWithDict(dst, src, types.CompressionLevelDefault, dict)
+}
+
+// UnflateStreamWithDict 使用预设字典流式解压原始DEFLATE数据
+//
+// 参数:
+//   - dst: 目标写入器
+//   - src: 源读取器（压缩数据）
+//   - dict: 压缩时使用的预设字典
+//
+// 返回:
+//   - error: 错误信息
+//
+// 使用示例:
+//
+//	err := UnflateStreamWithDict(&buf, conn, dict)
+func UnflateStreamWithDict(dst io.Writer, src io.Reader, dict []byte) error {
+	return cxflate.DecompressStreamWithDict(dst, src, dict)
+}
+
+// ==================== LZ4 内存压缩API ====================
+
+// Lz4Bytes 压缩字节数据为LZ4帧（使用默认压缩等级）
+//
+// 结果与 lz4 命令行工具生成的 .lz4 文件兼容。
+//
+// 参数:
+//   - data: 要压缩的字节数据
+//
+// 返回:
+//   - []byte: 压缩后的数据
+//   - error: 错误信息
+//
+// 使用示例:
+//
+//	compressed, err := Lz4Bytes([]byte("hello world"))
+func Lz4Bytes(data []byte) ([]byte, error) {
+	return cxlz4.CompressBytes(data, types.CompressionLevelDefault)
+}
+
+// Lz4BytesWithLevel 压缩字节数据为LZ4帧（指定压缩等级）
+//
+// 参数:
+//   - data: 要压缩的字节数据
+//   - level: 压缩级别，CompressionLevelFast 最快，CompressionLevelBest 压缩率最高
+//
+// 返回:
+//   - []byte: 压缩后的数据
+//   - error: 错误信息
+//
+// 使用示例:
+//
+//	compressed, err := Lz4BytesWithLevel(data, types.CompressionLevelFast)
+func Lz4BytesWithLevel(data []byte, level types.CompressionLevel) ([]byte, error) {
+	return cxlz4.CompressBytes(data, level)
+}
+
+// Unlz4Bytes 解压LZ4帧字节数据
+//
+// 支持拼接的多个帧和可跳过的帧。
+//
+// 参数:
+//   - compressedData: 压缩的字节数据
+//
+// 返回:
+//   - []byte: 解压后的数据
+//   - error: 错误信息
+//
+// 使用示例:
+//
+//	data, err := Unlz4Bytes(compressed)
+func Unlz4Bytes(compressedData []byte) ([]byte, error) {
+	return cxlz4.DecompressBytes(compressedData)
+}
+
+// Lz4Stream 流式压缩数据为LZ4帧（使用默认压缩等级）
+//
+// 参数:
+//   - dst: 目标写入器
+//   - src: 源读取器
+//
+// 返回:
+//   - error: 错误信息
+//
+// 使用示例:
+//
+//	output, _ := os.Create("data.lz4")
+//	defer output.Close()
+//
+//	err := Lz4Stream(output, file)
+func Lz4Stream(dst io.Writer, src io.Reader) error {
+	return cxlz4.CompressStream(dst, src, types.CompressionLevelDefault)
+}
+
+// Lz4StreamWithLevel 流式压缩数据为LZ4帧（指定压缩等级）
+//
+// 参数:
+//   - dst: 目标写入器
+//   - src: 源读取器
+//   - level: 压缩级别
+//
+// 返回:
+//   - error: 错误信息
+//
+// 使用示例:
+//
+//	err := Lz4StreamWithLevel(output, file, types.CompressionLevelBest)
+func Lz4StreamWithLevel(dst io.Writer, src io.Reader, level types.CompressionLevel) error {
+	return cxlz4.CompressStream(dst, src, level)
+}
+
+// Unlz4Stream 流式解压LZ4帧数据
+//
+// 参数:
+//   - dst: 目标写入器
+//   - src: 源读取器（压缩数据）
+//
+// 返回:
+//   - error: 错误信息
+//
+// 使用示例:
+//
+//	err := Unlz4Stream(output, compressedFile)
+func Unlz4Stream(dst io.Writer, src io.Reader) error {
+	return cxlz4.DecompressStream(dst, src)
+}
+
+// ==================== Snappy 内存压缩API ====================
+
+// SnappyBytes 压缩字节数据为Snappy分帧格式
+//
+// Snappy 没有压缩等级之分，需要以未压缩的分块写入时使用 NewSnappyCodec(types.CompressionLevelNone)。
+//
+// 参数:
+//   - data: 要压缩的字节数据
+//
+// 返回:
+//   - []byte: 压缩后的数据
+//   - error: 错误信息
+//
+// 使用示例:
+//
+//	compressed, err := SnappyBytes([]byte("hello world"))
+func SnappyBytes(data []byte) ([]byte, error) {
+	return cxsnappy.CompressBytes(data, types.CompressionLevelDefault)
+}
+
+// UnsnappyBytes 解压Snappy分帧格式的字节数据
+//
+// 参数:
+//   - compressedData: 压缩的字节数据
+//
+// 返回:
+//   - []byte: 解压后的数据
+//   - error: 错误信息
+//
+// 使用示例:
+//
+//	data, err := UnsnappyBytes(compressed)
+func UnsnappyBytes(compressedData []byte) ([]byte, error) {
+	return cxsnappy.DecompressBytes(compressedData)
+}
+
+// SnappyStream 流式压缩数据为Snappy分帧格式
+//
+// 参数:
+//   - dst: 目标写入器
+//   - src: 源读取器
+//
+// 返回:
+//   - error: 错误信息
+//
+// 使用示例:
+//
+//	output, _ := os.Create("data.sz")
+//	defer output.Close()
+//
+//	err := SnappyStream(output, file)
+func SnappyStream(dst io.Writer, src io.Reader) error {
+	return cxsnappy.CompressStream(dst, src, types.CompressionLevelDefault)
+}
+
+// UnsnappyStream 流式解压Snappy分帧格式的数据
+//
+// 参数:
+//   - dst: 目标写入器
+//   - src: 源读取器（压缩数据）
+//
+// 返回:
+//   - error: 错误信息
+//
+// 使用示例:
+//
+//	err := UnsnappyStream(output, compressedFile)
+func UnsnappyStream(dst io.Writer, src io.Reader) error {
+	return cxsnappy.DecompressStream(dst, src)
+}
@@ -0,0 +1,87 @@
+// Package comprx 提供修改已有压缩包的功能。
+//
+// 该文件提供了在不重新打包的情况下修改已有压缩包的方法，
+// 支持 ZIP、TAR、TGZ 格式。
+//
+// 主要功能：
+//   - 向已有压缩包追加文件或目录
+//   - 删除和重命名压缩包中的条目
+//
+// 使用示例：
+//
+//	// 每小时向当天的日志压缩包追加一个文件
+//	err := comprx.Append("logs-2024-05-01.tgz", "app-10.log", comprx.DefaultOptions())
+//
+//	// 从备份中删除误打包的密钥文件
+//	err = comprx.DeleteEntries("backup.zip", []string{"config/secret.env"})
+package comprx
+
+import "gitee.com/MM-Q/comprx/internal/core"
+
+// Append 向已有压缩包追加文件或目录 - 线程安全
+//
+// 参数:
+//   - archivePath: 已有的压缩包路径（支持 .zip、.tar、.tgz、.tar.gz）
+//   - src: 需要追加的源路径，以其基本名称放在压缩包根目录下
+//   - opts: 配置选项，OverwriteExisting 决定与已有条目同名时的处理方式
+//
+// 返回:
+//   - error: 错误信息
+//
+// 注意:
+//   - ZIP 以原始形式复制已有条目并重写中央目录，不会重新压缩
+//   - TAR 和追加过的 TGZ 在没有需要覆盖的条目时原地追加，其他情况原子地重写整个压缩包
+//   - TGZ 首次追加时重写为最后一个 GZIP 成员只包含归档结束标记的布局，Pack 生成的 TGZ 不受影响
+//   - 同名目录直接合并；同名文件在 OverwriteExisting 为 true 时替换，否则返回错误
+//
+// 使用示例:
+//
+//	err := Append("logs.zip", "app-10.log", DefaultOptions())
+func Append(archivePath string, src string, opts Options) error {
+	comprx, err := newPackComprx(opts)
+	if err != nil {
+		return err
+	}
+
+	return comprx.Append(archivePath, src)
+}
+
+// DeleteEntries 删除压缩包中匹配模式的条目 - 线程安全
+//
+// 参数:
+//   - archivePath: 压缩包路径（支持 .zip、.tar、.tgz、.tar.gz）
+//   - patterns: 删除模式，语法同 path.Match；不含斜杠的模式同时匹配条目的基本名称，匹配到目录时同时删除其下所有条目
+//
+// 返回:
+//   - error: 错误信息，没有匹配任何条目时也会返回错误
+//
+// 注意:
+//   - 通过同目录临时文件重写后原子替换，出错时原压缩包保持不变
+//   - ZIP 和 TAR 的保留条目原样复制，不会重新压缩；TGZ 需要重新压缩
+//
+// 使用示例:
+//
+//	err := DeleteEntries("backup.zip", []string{"config/secret.env", "*.pem"})
+func DeleteEntries(archivePath string, patterns []string) error {
+	return core.New().DeleteEntries(archivePath, patterns)
+}
+
+// RenameEntries 重命名压缩包中的条目 - 线程安全
+//
+// 参数:
+//   - archivePath: 压缩包路径（支持 .zip、.tar、.tgz、.tar.gz）
+//   - renames: 原名称到新名称的映射，重命名目录时其下所有条目一并移动
+//
+// 返回:
+//   - error: 错误信息，原条目不存在或新名称与其他条目冲突时返回错误
+//
+// 注意:
+//   - 通过同目录临时文件重写后原子替换，出错时原压缩包保持不变
+//   - ZIP 和 TAR 的条目数据原样复制，不会重新压缩；TGZ 需要重新压缩
+//
+// 使用示例:
+//
+//	err := RenameEntries("release.zip", map[string]string{"app-1.2/": "app-1.3/"})
+func RenameEntries(archivePath string, renames map[string]string) error {
+	return core.New().RenameEntries(archivePath, renames)
+}
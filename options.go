@@ -10,20 +10,49 @@
 //   - 提供默认配置选项
 //   - 支持链式配置方法
 //   - 提供各种预设配置选项
+//   - 确定性(可重现)打包配置
+//   - 校验清单配置
+//   - 解压前签名验证配置
+//   - ZIP 加密配置
+//   - 加密信封配置
+//   - ZIP 文件名编码配置
+//   - ZIP 注释配置
+//   - 多成员 GZIP 解压方式配置
+//   - GZIP 文件头配置
+//   - BGZF 分块压缩配置
 package comprx
 
 import (
+	"crypto/ed25519"
+	"time"
+
 	"gitee.com/MM-Q/comprx/types"
 )
 
 // Options 压缩/解压配置选项
 type Options struct {
-	CompressionLevel      types.CompressionLevel // 压缩等级
-	OverwriteExisting     bool                   // 是否覆盖已存在的文件
-	ProgressEnabled       bool                   // 是否启用进度显示
-	ProgressStyle         types.ProgressStyle    // 进度条样式
-	DisablePathValidation bool                   // 是否禁用路径验证
-	Filter                types.FilterOptions    // 过滤选项
+	CompressionLevel      types.CompressionLevel    // 压缩等级
+	OverwriteExisting     bool                      // 是否覆盖已存在的文件
+	ProgressEnabled       bool                      // 是否启用进度显示
+	ProgressStyle         types.ProgressStyle       // 进度条样式
+	DisablePathValidation bool                      // 是否禁用路径验证
+	Filter                types.FilterOptions       // 过滤选项
+	Deterministic         bool                      // 是否生成可重现(逐字节相同)的压缩包
+	SourceDateEpoch       time.Time                 // 确定性模式下修改时间的上限，零值时读取 $SOURCE_DATE_EPOCH
+	Checksums             []types.ChecksumAlgorithm // 打包时生成、解压时校验的摘要算法，为空时不启用
+	ChecksumsBeside       bool                      // 校验清单写在压缩包旁边(<压缩包>.SHA256SUMS)而不是压缩包内
+	RequireSignature      bool                      // 解压前必须使用 SignaturePublicKey 验证 <压缩包>.sig 签名
+	SignaturePublicKey    ed25519.PublicKey         // 验证签名使用的 Ed25519 公钥
+	Password              string                    // ZIP 加密和解密使用的密码
+	Encryption            types.EncryptionMethod    // 打包 ZIP 时的加密方式，需要同时设置 Password
+	EncryptWith           types.EnvelopeOptions     // tar/tgz/gz/zlib 的加密信封，解压时使用其中的口令(为空时使用 Password)
+	FilenameEncoding      types.FilenameEncoding    // 读取 ZIP 时未设置 UTF-8 标志位的文件名编码，为空时自动检测
+	ArchiveComment        string                    // 打包 ZIP 时写入的压缩包注释
+	EntryComments         map[string]string         // 打包 ZIP 时写入的条目注释，键为压缩包内的条目名称
+	GzipMembers           types.GzipMemberMode      // 解压多成员 GZIP 时拼接成一个文件(默认)还是按成员拆分为单独的文件
+	GzipHeader            *types.GzipHeader         // 压缩 GZIP 时写入的文件头，为 nil 时使用源文件名和修改时间
+	Bgzf                  bool                      // 压缩 GZIP 时是否写为 BGZF 分块格式(htslib 兼容，可随机访问)
+	BgzfIndex             bool                      // 写为 BGZF 时是否同时在压缩文件旁生成 .gzi 索引
 }
 
 // DefaultOptions 返回默认配置选项
@@ -37,6 +66,7 @@ type Options struct {
 //   - ProgressEnabled: false (不显示进度)
 //   - ProgressStyle: 文本样式
 //   - DisablePathValidation: false (启用路径验证)
+//   - Deterministic: false (保留原始元数据)
 func DefaultOptions() Options {
 	return Options{
 		CompressionLevel:      types.CompressionLevelDefault,
@@ -187,6 +217,23 @@ func NoCompressionProgressOptions(style types.ProgressStyle) Options {
 	return opts
 }
 
+// DeterministicOptions 返回生成可重现压缩包的配置选项
+//
+// 返回:
+//   - Options: 确定性打包配置选项
+//
+// 配置特点:
+//   - Deterministic: true (条目按字典序排列，时间戳钳制到 $SOURCE_DATE_EPOCH，清零属主信息，权限规范化为 0644/0755)
+//
+// 使用示例:
+//
+//	err := PackOptions("release.tar.gz", "dist", DeterministicOptions())
+func DeterministicOptions() Options {
+	opts := DefaultOptions()
+	opts.Deterministic = true
+	return opts
+}
+
 // ==============================================
 // Options Set 方法（直接设置，不返回对象）
 // ==============================================
@@ -355,6 +402,229 @@ func (o *Options) SetMinSize(minSize int64) {
 	o.Filter.MinSize = minSize
 }
 
+// SetDeterministic 设置是否生成可重现的压缩包
+//
+// 参数:
+//   - enabled: 是否启用确定性打包
+//
+// 使用示例:
+//
+//	opts := DefaultOptions()
+//	opts.SetDeterministic(true)
+func (o *Options) SetDeterministic(enabled bool) {
+	o.Deterministic = enabled
+}
+
+// SetSourceDateEpoch 设置确定性模式下修改时间的上限
+//
+// 参数:
+//   - epoch: 时间上限，零值时读取 $SOURCE_DATE_EPOCH 环境变量
+//
+// 使用示例:
+//
+//	opts := DefaultOptions()
+//	opts.SetSourceDateEpoch(time.Unix(1700000000, 0))
+func (o *Options) SetSourceDateEpoch(epoch time.Time) {
+	o.SourceDateEpoch = epoch
+}
+
+// SetChecksums 设置打包时生成、解压时校验的摘要算法
+//
+// 参数:
+//   - algorithms: 摘要算法列表，为空时不启用校验清单
+//
+// 使用示例:
+//
+//	opts := DefaultOptions()
+//	opts.SetChecksums(types.ChecksumSHA256, types.ChecksumBLAKE2b)
+func (o *Options) SetChecksums(algorithms ...types.ChecksumAlgorithm) {
+	o.Checksums = algorithms
+}
+
+// SetChecksumsBeside 设置校验清单是否写在压缩包旁边
+//
+// 参数:
+//   - beside: 为 true 时清单写为 <压缩包路径>.<清单文件名>，否则写入压缩包根目录
+//
+// 使用示例:
+//
+//	opts := DefaultOptions()
+//	opts.SetChecksumsBeside(true)
+func (o *Options) SetChecksumsBeside(beside bool) {
+	o.ChecksumsBeside = beside
+}
+
+// SetRequireSignature 设置解压前必须通过签名验证
+//
+// 参数:
+//   - publicKey: 验证签名使用的 Ed25519 公钥，为 nil 时关闭签名验证
+//
+// 使用示例:
+//
+//	opts := DefaultOptions()
+//	opts.SetRequireSignature(publicKey)
+func (o *Options) SetRequireSignature(publicKey ed25519.PublicKey) {
+	o.RequireSignature = publicKey != nil
+	o.SignaturePublicKey = publicKey
+}
+
+// SetPassword 设置 ZIP 加密和解密使用的密码
+//
+// 参数:
+//   - password: 密码
+//
+// 使用示例:
+//
+//	opts := DefaultOptions()
+//	opts.SetPassword("secret")
+func (o *Options) SetPassword(password string) {
+	o.Password = password
+}
+
+// SetEncryption 设置打包 ZIP 时的加密方式
+//
+// 参数:
+//   - method: 加密方式，需要同时设置密码
+//
+// 使用示例:
+//
+//	opts := DefaultOptions()
+//	opts.SetEncryption(types.EncryptionAES256)
+func (o *Options) SetEncryption(method types.EncryptionMethod) {
+	o.Encryption = method
+}
+
+// SetEncryptWith 设置加密信封选项
+//
+// 打包 tar/tgz/gz/zlib 时使用口令加密整个输出流，解压时自动识别并解密。
+//
+// 参数:
+//   - envelope: 加密信封选项，Passphrase 为空时不启用
+//
+// 使用示例:
+//
+//	opts := DefaultOptions()
+//	opts.SetEncryptWith(types.EnvelopeOptions{Passphrase: "secret", KDF: types.EnvelopeKDFArgon2id})
+func (o *Options) SetEncryptWith(envelope types.EnvelopeOptions) {
+	o.EncryptWith = envelope
+}
+
+// SetFilenameEncoding 设置读取 ZIP 时的文件名编码
+//
+// 只作用于未设置 UTF-8 标志位的条目，如中文或日文 Windows 上生成的 ZIP。
+//
+// 参数:
+//   - encoding: 文件名编码，空值或 auto 表示自动检测
+//
+// 使用示例:
+//
+//	opts := DefaultOptions()
+//	opts.SetFilenameEncoding(types.FilenameEncodingGBK)
+func (o *Options) SetFilenameEncoding(encoding types.FilenameEncoding) {
+	o.FilenameEncoding = encoding
+}
+
+// SetArchiveComment 设置打包 ZIP 时写入的压缩包注释
+//
+// 参数:
+//   - comment: 压缩包注释，最长 65535 字节
+//
+// 使用示例:
+//
+//	opts := DefaultOptions()
+//	opts.SetArchiveComment("commit 1a2b3c4\nhttps://ci.example.com/build/42")
+func (o *Options) SetArchiveComment(comment string) {
+	o.ArchiveComment = comment
+}
+
+// SetEntryComment 设置打包 ZIP 时单个条目的注释
+//
+// 参数:
+//   - name: 压缩包内的条目名称，如 "app/config.yaml"
+//   - comment: 条目注释，最长 65535 字节
+//
+// 使用示例:
+//
+//	opts := DefaultOptions()
+//	opts.SetEntryComment("app/config.yaml", "生产环境配置")
+func (o *Options) SetEntryComment(name, comment string) {
+	// 复制后再修改，避免影响通过值传递共享同一个 map 的其他 Options
+	comments := make(map[string]string, len(o.EntryComments)+1)
+	for k, v := range o.EntryComments {
+		comments[k] = v
+	}
+	comments[name] = comment
+	o.EntryComments = comments
+}
+
+// SetGzipMembers 设置解压多成员 GZIP 的方式
+//
+// 多成员 GZIP 由 cat a.gz b.gz 或日志轮转生成。拆分时目标路径作为目录，
+// 每个成员使用成员头中的原始文件名写入单独的文件。
+//
+// 参数:
+//   - mode: 解压方式，空值或 concat 表示拼接
+//
+// 使用示例:
+//
+//	opts := DefaultOptions()
+//	opts.SetGzipMembers(types.GzipMembersSplit)
+func (o *Options) SetGzipMembers(mode types.GzipMemberMode) {
+	o.GzipMembers = mode
+}
+
+// SetGzipHeader 设置压缩 GZIP 时写入的文件头
+//
+// Name、ModTime 和 OS 为零值时保持默认值：源文件名、源文件修改时间和未知的操作系统(255)。
+// 确定性模式下修改时间和操作系统标识仍会被固定。
+//
+// 参数:
+//   - header: GZIP 文件头
+//
+// 使用示例:
+//
+//	opts := DefaultOptions()
+//	opts.SetGzipHeader(types.GzipHeader{Name: "data.csv", Extra: provenance, OS: types.GzipOSUnix})
+func (o *Options) SetGzipHeader(header types.GzipHeader) {
+	o.GzipHeader = &header
+}
+
+// SetBgzf 设置压缩 GZIP 时是否写为 BGZF 分块格式
+//
+// BGZF 由多个不超过 64 KiB 的 GZIP 成员组成，与 htslib 的 bgzip 兼容，
+// 仍可被任何 GZIP 工具解压，同时支持按虚拟文件偏移随机访问。
+// 不能与自定义文件头或加密信封同时使用。
+//
+// 参数:
+//   - enabled: 是否写为 BGZF
+//
+// 使用示例:
+//
+//	opts := DefaultOptions()
+//	opts.SetBgzf(true)
+func (o *Options) SetBgzf(enabled bool) {
+	o.Bgzf = enabled
+}
+
+// SetBgzfIndex 设置写为 BGZF 时是否同时生成 .gzi 索引
+//
+// 索引写入压缩文件路径加 .gzi 后缀的文件，格式与 bgzip -i 一致。
+// 启用索引时同时启用 BGZF。
+//
+// 参数:
+//   - enabled: 是否生成 .gzi 索引
+//
+// 使用示例:
+//
+//	opts := DefaultOptions()
+//	opts.SetBgzfIndex(true)
+func (o *Options) SetBgzfIndex(enabled bool) {
+	o.BgzfIndex = enabled
+	if enabled {
+		o.Bgzf = true
+	}
+}
+
 // ==============================================
 // Options 链式配置方法（通过 Set 方法实现）
 // ==============================================
@@ -556,3 +826,244 @@ func (o Options) WithMinSize(minSize int64) Options {
 	o.SetMinSize(minSize)
 	return o
 }
+
+// WithDeterministic 设置是否生成可重现的压缩包
+//
+// 参数:
+//   - enabled: 是否启用确定性打包
+//
+// 返回:
+//   - Options: 配置选项（支持链式调用）
+//
+// 使用示例:
+//
+//	opts := DefaultOptions().WithDeterministic(true)
+func (o Options) WithDeterministic(enabled bool) Options {
+	o.SetDeterministic(enabled)
+	return o
+}
+
+// WithSourceDateEpoch 设置确定性模式下修改时间的上限
+//
+// 参数:
+//   - epoch: 时间上限，零值时读取 $SOURCE_DATE_EPOCH 环境变量
+//
+// 返回:
+//   - Options: 配置选项（支持链式调用）
+//
+// 使用示例:
+//
+//	opts := DeterministicOptions().WithSourceDateEpoch(time.Unix(1700000000, 0))
+func (o Options) WithSourceDateEpoch(epoch time.Time) Options {
+	o.SetSourceDateEpoch(epoch)
+	return o
+}
+
+// WithChecksums 设置打包时生成、解压时校验的摘要算法
+//
+// 参数:
+//   - algorithms: 摘要算法列表，为空时不启用校验清单
+//
+// 返回:
+//   - Options: 配置选项（支持链式调用）
+//
+// 使用示例:
+//
+//	opts := DefaultOptions().WithChecksums(types.ChecksumSHA256)
+func (o Options) WithChecksums(algorithms ...types.ChecksumAlgorithm) Options {
+	o.SetChecksums(algorithms...)
+	return o
+}
+
+// WithChecksumsBeside 设置校验清单是否写在压缩包旁边
+//
+// 参数:
+//   - beside: 为 true 时清单写为 <压缩包路径>.<清单文件名>，否则写入压缩包根目录
+//
+// 返回:
+//   - Options: 配置选项（支持链式调用）
+//
+// 使用示例:
+//
+//	opts := DefaultOptions().WithChecksums(types.ChecksumSHA512).WithChecksumsBeside(true)
+func (o Options) WithChecksumsBeside(beside bool) Options {
+	o.SetChecksumsBeside(beside)
+	return o
+}
+
+// WithRequireSignature 设置解压前必须通过签名验证
+//
+// 参数:
+//   - publicKey: 验证签名使用的 Ed25519 公钥，为 nil 时关闭签名验证
+//
+// 返回:
+//   - Options: 配置选项（支持链式调用）
+//
+// 使用示例:
+//
+//	opts := DefaultOptions().WithRequireSignature(publicKey)
+func (o Options) WithRequireSignature(publicKey ed25519.PublicKey) Options {
+	o.SetRequireSignature(publicKey)
+	return o
+}
+
+// WithPassword 设置 ZIP 加密和解密使用的密码
+//
+// 参数:
+//   - password: 密码
+//
+// 返回:
+//   - Options: 配置选项（支持链式调用）
+//
+// 使用示例:
+//
+//	opts := DefaultOptions().WithPassword("secret")
+func (o Options) WithPassword(password string) Options {
+	o.SetPassword(password)
+	return o
+}
+
+// WithEncryption 设置打包 ZIP 时的加密方式
+//
+// 参数:
+//   - method: 加密方式，需要同时设置密码
+//
+// 返回:
+//   - Options: 配置选项（支持链式调用）
+//
+// 使用示例:
+//
+//	opts := DefaultOptions().WithPassword("secret").WithEncryption(types.EncryptionAES256)
+func (o Options) WithEncryption(method types.EncryptionMethod) Options {
+	o.SetEncryption(method)
+	return o
+}
+
+// WithEncryptWith 设置加密信封选项
+//
+// 参数:
+//   - envelope: 加密信封选项，Passphrase 为空时不启用
+//
+// 返回:
+//   - Options: 配置选项（支持链式调用）
+//
+// 使用示例:
+//
+//	opts := DefaultOptions().WithEncryptWith(types.EnvelopeOptions{Passphrase: "secret"})
+func (o Options) WithEncryptWith(envelope types.EnvelopeOptions) Options {
+	o.SetEncryptWith(envelope)
+	return o
+}
+
+// WithFilenameEncoding 设置读取 ZIP 时的文件名编码
+//
+// 参数:
+//   - encoding: 文件名编码，空值或 auto 表示自动检测
+//
+// 返回:
+//   - Options: 配置选项（支持链式调用）
+//
+// 使用示例:
+//
+//	opts := DefaultOptions().WithFilenameEncoding(types.FilenameEncodingShiftJIS)
+func (o Options) WithFilenameEncoding(encoding types.FilenameEncoding) Options {
+	o.SetFilenameEncoding(encoding)
+	return o
+}
+
+// WithArchiveComment 设置打包 ZIP 时写入的压缩包注释
+//
+// 参数:
+//   - comment: 压缩包注释，最长 65535 字节
+//
+// 返回:
+//   - Options: 配置选项（支持链式调用）
+//
+// 使用示例:
+//
+//	opts := DefaultOptions().WithArchiveComment("commit 1a2b3c4")
+func (o Options) WithArchiveComment(comment string) Options {
+	o.SetArchiveComment(comment)
+	return o
+}
+
+// WithEntryComment 设置打包 ZIP 时单个条目的注释
+//
+// 参数:
+//   - name: 压缩包内的条目名称
+//   - comment: 条目注释，最长 65535 字节
+//
+// 返回:
+//   - Options: 配置选项（支持链式调用）
+//
+// 使用示例:
+//
+//	opts := DefaultOptions().WithEntryComment("app/config.yaml", "生产环境配置")
+func (o Options) WithEntryComment(name, comment string) Options {
+	o.SetEntryComment(name, comment)
+	return o
+}
+
+// WithGzipMembers 设置解压多成员 GZIP 的方式
+//
+// 参数:
+//   - mode: 解压方式，空值或 concat 表示拼接
+//
+// 返回:
+//   - Options: 配置选项（支持链式调用）
+//
+// 使用示例:
+//
+//	opts := DefaultOptions().WithGzipMembers(types.GzipMembersSplit)
+func (o Options) WithGzipMembers(mode types.GzipMemberMode) Options {
+	o.SetGzipMembers(mode)
+	return o
+}
+
+// WithGzipHeader 设置压缩 GZIP 时写入的文件头
+//
+// 参数:
+//   - header: GZIP 文件头
+//
+// 返回:
+//   - Options: 配置选项（支持链式调用）
+//
+// 使用示例:
+//
+//	opts := DefaultOptions().WithGzipHeader(types.GzipHeader{Name: "data.csv", Comment: "nightly export"})
+func (o Options) WithGzipHeader(header types.GzipHeader) Options {
+	o.SetGzipHeader(header)
+	return o
+}
+
+// WithBgzf 设置压缩 GZIP 时是否写为 BGZF 分块格式
+//
+// 参数:
+//   - enabled: 是否写为 BGZF
+//
+// 返回:
+//   - Options: 配置选项（支持链式调用）
+//
+// 使用示例:
+//
+//	opts := DefaultOptions().WithBgzf(true)
+func (o Options) WithBgzf(enabled bool) Options {
+	o.SetBgzf(enabled)
+	return o
+}
+
+// WithBgzfIndex 设置写为 BGZF 时是否同时生成 .gzi 索引
+//
+// 参数:
+//   - enabled: 是否生成 .gzi 索引
+//
+// 返回:
+//   - Options: 配置选项（支持链式调用）
+//
+// 使用示例:
+//
+//	opts := DefaultOptions().WithBgzfIndex(true)
+func (o Options) WithBgzfIndex(enabled bool) Options {
+	o.SetBgzfIndex(enabled)
+	return o
+}
@@ -0,0 +1,66 @@
+// Package comprx 提供压缩包的分离签名功能。
+//
+// 该文件提供了基于 Ed25519 的签名和验证方法：发布端为压缩包生成 .sig 签名文件，
+// 接收端在解压前使用公钥验证压缩包的来源，拒绝未签名或被篡改的压缩包。
+//
+// 主要功能：
+//   - 为压缩包生成分离签名
+//   - 使用公钥验证压缩包签名
+//   - 配合 Options.RequireSignature 在解压前强制验证
+//
+// 使用示例：
+//
+//	publicKey, privateKey, _ := ed25519.GenerateKey(nil)
+//
+//	// 发布端: 生成 plugin.tgz.sig
+//	err := comprx.Sign("plugin.tgz", privateKey)
+//
+//	// 接收端: 验证通过后才解压
+//	opts := comprx.DefaultOptions().WithRequireSignature(publicKey)
+//	err = comprx.UnpackOptions("plugin.tgz", "plugins", opts)
+package comprx
+
+import (
+	"crypto/ed25519"
+
+	"gitee.com/MM-Q/comprx/internal/signature"
+)
+
+// Sign 为压缩包生成 Ed25519 签名文件 - 线程安全
+//
+// 参数:
+//   - archivePath: 压缩包路径（任意格式）
+//   - privateKey: Ed25519 私钥
+//
+// 返回:
+//   - error: 私钥无效、读取压缩包或写入签名文件失败时返回错误
+//
+// 注意:
+//   - 签名文件写入 <压缩包路径>.sig，内容为 64 字节的原始签名
+//   - 签名使用 Ed25519ph（SHA-512 预哈希），可以流式处理任意大小的压缩包
+//
+// 使用示例:
+//
+//	err := Sign("plugin.tgz", privateKey) // 生成 plugin.tgz.sig
+func Sign(archivePath string, privateKey ed25519.PrivateKey) error {
+	return signature.Sign(archivePath, privateKey)
+}
+
+// Verify 使用公钥验证压缩包签名 - 线程安全
+//
+// 参数:
+//   - archivePath: 压缩包路径
+//   - sig: 签名文件路径，为空时使用 <压缩包路径>.sig
+//   - publicKey: Ed25519 公钥
+//
+// 返回:
+//   - error: 公钥无效、签名文件缺失或签名与压缩包内容不匹配时返回错误
+//
+// 使用示例:
+//
+//	if err := Verify("plugin.tgz", "", publicKey); err != nil {
+//	    // 压缩包未签名或已被篡改
+//	}
+func Verify(archivePath string, sig string, publicKey ed25519.PublicKey) error {
+	return signature.Verify(archivePath, sig, publicKey)
+}
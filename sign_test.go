@@ -0,0 +1,71 @@
+package comprx
+
+import (
+	"crypto/ed25519"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestUnpackOptions_RequireSignature(t *testing.T) {
+	tempDir := t.TempDir()
+	publicKey, privateKey, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatalf("生成密钥失败: %v", err)
+	}
+
+	srcDir := filepath.Join(tempDir, "plugin")
+	if err := os.MkdirAll(srcDir, 0755); err != nil {
+		t.Fatalf("创建目录失败: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(srcDir, "main.lua"), []byte("print('hi')"), 0644); err != nil {
+		t.Fatalf("创建测试文件失败: %v", err)
+	}
+	archivePath := filepath.Join(tempDir, "plugin.tgz")
+	if err := Pack(archivePath, srcDir); err != nil {
+		t.Fatalf("压缩失败: %v", err)
+	}
+
+	opts := DefaultOptions().WithRequireSignature(publicKey)
+
+	// 未签名时拒绝解压，且不写入任何文件
+	outDir := filepath.Join(tempDir, "out")
+	if err := UnpackOptions(archivePath, outDir, opts); err == nil {
+		t.Fatal("未签名的压缩包应拒绝解压")
+	}
+	if _, err := os.Stat(filepath.Join(outDir, "plugin", "main.lua")); !os.IsNotExist(err) {
+		t.Errorf("拒绝解压时不应写入文件: %v", err)
+	}
+
+	// 签名后正常解压
+	if err := Sign(archivePath, privateKey); err != nil {
+		t.Fatalf("签名失败: %v", err)
+	}
+	if err := Verify(archivePath, "", publicKey); err != nil {
+		t.Fatalf("验证签名失败: %v", err)
+	}
+	if err := UnpackOptions(archivePath, outDir, opts); err != nil {
+		t.Fatalf("已签名的压缩包解压失败: %v", err)
+	}
+
+	// 被篡改后拒绝解压
+	data, err := os.ReadFile(archivePath)
+	if err != nil {
+		t.Fatalf("读取压缩包失败: %v", err)
+	}
+	data[len(data)/2] ^= 0xff
+	if err := os.WriteFile(archivePath, data, 0644); err != nil {
+		t.Fatalf("篡改压缩包失败: %v", err)
+	}
+	opts.OverwriteExisting = true
+	if err := UnpackOptions(archivePath, outDir, opts); err == nil {
+		t.Error("被篡改的压缩包应拒绝解压")
+	}
+
+	// 启用签名验证但未提供公钥
+	noKey := DefaultOptions()
+	noKey.RequireSignature = true
+	if err := UnpackOptions(archivePath, outDir, noKey); err == nil {
+		t.Error("未提供公钥时应返回错误")
+	}
+}
@@ -0,0 +1,60 @@
+// Package types 定义了 BGZF (分块 GZIP) 相关的类型。
+//
+// BGZF 是 htslib 使用的 GZIP 变体，由多个不超过 64 KiB 的 GZIP 成员组成，
+// 每个成员的 FEXTRA 字段中带有记录块大小的 BC 子字段，因此可以按块随机访问。
+// BgzfOffset 是 htslib 的虚拟文件偏移，BgzfIndex 对应 .gzi 索引文件。
+//
+// 主要类型：
+//   - BgzfOffset: 虚拟文件偏移
+//   - BgzfIndexEntry: 索引条目
+//   - BgzfIndex: .gzi 索引
+//
+// 使用示例：
+//
+//	offset := types.NewBgzfOffset(blockStart, 100)
+//	err := reader.Seek(offset)
+package types
+
+// BgzfOffset BGZF 虚拟文件偏移
+//
+// 高 48 位为数据块在压缩文件中的偏移，低 16 位为数据在解压后的块内的偏移。
+type BgzfOffset uint64
+
+// NewBgzfOffset 创建虚拟文件偏移
+//
+// 参数:
+//   - compressed: 数据块在压缩文件中的偏移
+//   - uncompressed: 数据在解压后的块内的偏移
+//
+// 返回:
+//   - BgzfOffset: 虚拟文件偏移
+func NewBgzfOffset(compressed uint64, uncompressed uint16) BgzfOffset {
+	return BgzfOffset(compressed<<16 | uint64(uncompressed))
+}
+
+// Compressed 返回数据块在压缩文件中的偏移
+//
+// 返回:
+//   - uint64: 压缩文件中的偏移
+func (o BgzfOffset) Compressed() uint64 {
+	return uint64(o) >> 16
+}
+
+// Uncompressed 返回数据在解压后的块内的偏移
+//
+// 返回:
+//   - uint16: 块内偏移
+func (o BgzfOffset) Uncompressed() uint16 {
+	return uint16(o & 0xffff)
+}
+
+// BgzfIndexEntry BGZF 索引条目，记录一个数据块的起始位置
+type BgzfIndexEntry struct {
+	CompressedOffset   uint64 // 数据块在压缩文件中的偏移
+	UncompressedOffset uint64 // 数据块第一个字节在解压后数据中的偏移
+}
+
+// BgzfIndex BGZF 索引
+//
+// 与 htslib 的 .gzi 文件一致，按偏移升序记录除第一个数据块(偏移均为 0)以外的每个数据块。
+type BgzfIndex []BgzfIndexEntry
@@ -0,0 +1,65 @@
+// Package types 定义了校验清单使用的摘要算法类型。
+//
+// 该文件提供了 ChecksumAlgorithm 类型及其常量，用于在打包时生成
+// SHA256SUMS 风格的校验清单，并在解压时校验每个文件。
+//
+// 主要类型：
+//   - ChecksumAlgorithm: 摘要算法
+//
+// 支持的算法：
+//   - ChecksumSHA256: SHA-256，清单文件名 SHA256SUMS
+//   - ChecksumSHA512: SHA-512，清单文件名 SHA512SUMS
+//   - ChecksumBLAKE2b: BLAKE2b-512，清单文件名 B2SUMS（与 b2sum 兼容）
+//
+// 使用示例：
+//
+//	opts := comprx.DefaultOptions()
+//	opts.Checksums = []types.ChecksumAlgorithm{types.ChecksumSHA256}
+package types
+
+// ChecksumAlgorithm 摘要算法
+type ChecksumAlgorithm string
+
+const (
+	ChecksumSHA256  ChecksumAlgorithm = "sha256"  // SHA-256
+	ChecksumSHA512  ChecksumAlgorithm = "sha512"  // SHA-512
+	ChecksumBLAKE2b ChecksumAlgorithm = "blake2b" // BLAKE2b-512
+)
+
+// String 返回摘要算法的字符串表示
+//
+// 返回:
+//   - string: 算法名称
+func (a ChecksumAlgorithm) String() string {
+	return string(a)
+}
+
+// IsValid 检查摘要算法是否受支持
+//
+// 返回:
+//   - bool: 受支持返回 true
+func (a ChecksumAlgorithm) IsValid() bool {
+	switch a {
+	case ChecksumSHA256, ChecksumSHA512, ChecksumBLAKE2b:
+		return true
+	default:
+		return false
+	}
+}
+
+// ManifestName 返回摘要算法对应的清单文件名
+//
+// 返回:
+//   - string: 清单文件名，如 SHA256SUMS；不支持的算法返回空字符串
+func (a ChecksumAlgorithm) ManifestName() string {
+	switch a {
+	case ChecksumSHA256:
+		return "SHA256SUMS"
+	case ChecksumSHA512:
+		return "SHA512SUMS"
+	case ChecksumBLAKE2b:
+		return "B2SUMS"
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,53 @@
+// Package types 定义了 ZIP 文件名编码相关的类型。
+//
+// 中文和日文 Windows 上生成的 ZIP 通常使用 GBK/CP936 或 Shift-JIS 存储文件名，
+// 并且不设置 UTF-8 标志位。FilenameEncoding 指定读取这类文件名时使用的编码。
+//
+// 主要类型：
+//   - FilenameEncoding: ZIP 文件名编码
+//
+// 使用示例：
+//
+//	opts := comprx.DefaultOptions()
+//	opts.FilenameEncoding = types.FilenameEncodingGBK
+//	err := comprx.UnpackOptions("资料.zip", "out", opts)
+package types
+
+// FilenameEncoding ZIP 文件名编码
+//
+// 只作用于未设置 UTF-8 标志位的条目。
+type FilenameEncoding string
+
+const (
+	FilenameEncodingAuto     FilenameEncoding = "auto"      // 自动检测(默认)，只转换不是有效 UTF-8 的文件名
+	FilenameEncodingUTF8     FilenameEncoding = "utf-8"     // 不转换，保留原始文件名
+	FilenameEncodingGBK      FilenameEncoding = "gbk"       // GBK/CP936 (简体中文 Windows)
+	FilenameEncodingGB18030  FilenameEncoding = "gb18030"   // GB18030
+	FilenameEncodingShiftJIS FilenameEncoding = "shift_jis" // Shift-JIS (日文 Windows)
+	FilenameEncodingCP437    FilenameEncoding = "cp437"     // CP437 (ZIP 规范的默认编码)
+)
+
+// String 返回文件名编码的字符串表示
+//
+// 返回:
+//   - string: 编码名称，空值返回 "auto"
+func (e FilenameEncoding) String() string {
+	if e == "" {
+		return string(FilenameEncodingAuto)
+	}
+	return string(e)
+}
+
+// IsValid 检查文件名编码是否受支持，空值表示自动检测
+//
+// 返回:
+//   - bool: 受支持返回 true
+func (e FilenameEncoding) IsValid() bool {
+	switch e {
+	case "", FilenameEncodingAuto, FilenameEncodingUTF8, FilenameEncodingGBK,
+		FilenameEncodingGB18030, FilenameEncodingShiftJIS, FilenameEncodingCP437:
+		return true
+	default:
+		return false
+	}
+}
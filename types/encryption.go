@@ -0,0 +1,65 @@
+// Package types 定义了 ZIP 加密相关的类型和错误。
+//
+// 该文件提供了 EncryptionMethod 类型及其常量，用于选择打包 ZIP 时的加密方式，
+// 以及解密时密码缺失或错误的哨兵错误。
+//
+// 主要类型：
+//   - EncryptionMethod: ZIP 条目加密方式
+//
+// 错误：
+//   - ErrPasswordRequired: 条目已加密但未提供密码
+//   - ErrWrongPassword: 密码错误
+//
+// 使用示例：
+//
+//	opts := comprx.DefaultOptions()
+//	opts.Password = "secret"
+//	opts.Encryption = types.EncryptionAES256
+//
+//	err := comprx.UnpackOptions("secret.zip", "out", opts)
+//	if errors.Is(err, types.ErrWrongPassword) {
+//	    // 提示重新输入密码
+//	}
+package types
+
+import "errors"
+
+// EncryptionMethod ZIP 条目加密方式
+type EncryptionMethod string
+
+const (
+	EncryptionNone   EncryptionMethod = ""       // 不加密
+	EncryptionAES256 EncryptionMethod = "aes256" // WinZip AES-256 (AE-2)
+)
+
+var (
+	// ErrPasswordRequired 条目已加密但未提供密码
+	ErrPasswordRequired = errors.New("条目已加密，需要提供密码")
+
+	// ErrWrongPassword 密码错误
+	ErrWrongPassword = errors.New("密码错误")
+)
+
+// String 返回加密方式的字符串表示
+//
+// 返回:
+//   - string: 加密方式名称，不加密时返回 "none"
+func (m EncryptionMethod) String() string {
+	if m == EncryptionNone {
+		return "none"
+	}
+	return string(m)
+}
+
+// IsValid 检查加密方式是否受支持
+//
+// 返回:
+//   - bool: 受支持返回 true
+func (m EncryptionMethod) IsValid() bool {
+	switch m {
+	case EncryptionNone, EncryptionAES256:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,99 @@
+// Package types 定义了加密信封相关的类型。
+//
+// 加密信封把 tar/tgz/gz/zlib 的整个输出流包裹在认证加密容器中：
+// 先用口令派生密钥，再按块使用 AEAD 加密，文件扩展名保持不变。
+// 解压时会自动识别信封并解密。
+//
+// 主要类型：
+//   - EnvelopeKDF: 口令派生密钥的算法
+//   - EnvelopeCipher: 数据块的加密算法
+//   - EnvelopeOptions: 加密信封选项
+//
+// 使用示例：
+//
+//	opts := comprx.DefaultOptions()
+//	opts.EncryptWith = types.EnvelopeOptions{
+//	    Passphrase: "secret",
+//	    KDF:        types.EnvelopeKDFArgon2id,
+//	    Cipher:     types.EnvelopeCipherChaCha20Poly1305,
+//	}
+//	err := comprx.PackOptions("dump.tgz", "dump/", opts)
+package types
+
+import "fmt"
+
+// EnvelopeKDF 口令派生密钥的算法
+type EnvelopeKDF string
+
+const (
+	EnvelopeKDFScrypt   EnvelopeKDF = "scrypt"   // scrypt (默认)
+	EnvelopeKDFArgon2id EnvelopeKDF = "argon2id" // Argon2id
+)
+
+// IsValid 检查派生算法是否受支持，空值表示使用默认算法
+//
+// 返回:
+//   - bool: 受支持返回 true
+func (k EnvelopeKDF) IsValid() bool {
+	switch k {
+	case "", EnvelopeKDFScrypt, EnvelopeKDFArgon2id:
+		return true
+	default:
+		return false
+	}
+}
+
+// EnvelopeCipher 数据块的加密算法
+type EnvelopeCipher string
+
+const (
+	EnvelopeCipherAES256GCM        EnvelopeCipher = "aes-256-gcm"       // AES-256-GCM (默认)
+	EnvelopeCipherChaCha20Poly1305 EnvelopeCipher = "chacha20-poly1305" // ChaCha20-Poly1305
+)
+
+// IsValid 检查加密算法是否受支持，空值表示使用默认算法
+//
+// 返回:
+//   - bool: 受支持返回 true
+func (c EnvelopeCipher) IsValid() bool {
+	switch c {
+	case "", EnvelopeCipherAES256GCM, EnvelopeCipherChaCha20Poly1305:
+		return true
+	default:
+		return false
+	}
+}
+
+// EnvelopeOptions 加密信封选项
+//
+// 打包时 Passphrase 非空即启用加密信封；解压时 Passphrase 用于解密信封。
+type EnvelopeOptions struct {
+	Passphrase string         // 口令，为空时不启用
+	KDF        EnvelopeKDF    // 派生算法，为空时使用 scrypt
+	Cipher     EnvelopeCipher // 加密算法，为空时使用 AES-256-GCM
+}
+
+// Enabled 检查是否启用了加密信封
+//
+// 返回:
+//   - bool: 设置了口令时返回 true
+func (o EnvelopeOptions) Enabled() bool {
+	return o.Passphrase != ""
+}
+
+// Validate 验证加密信封选项
+//
+// 返回:
+//   - error: 算法不受支持或只设置了算法未设置口令时返回错误
+func (o EnvelopeOptions) Validate() error {
+	if !o.KDF.IsValid() {
+		return fmt.Errorf("不支持的密钥派生算法: %s，有效值: scrypt, argon2id", o.KDF)
+	}
+	if !o.Cipher.IsValid() {
+		return fmt.Errorf("不支持的信封加密算法: %s，有效值: aes-256-gcm, chacha20-poly1305", o.Cipher)
+	}
+	if !o.Enabled() && (o.KDF != "" || o.Cipher != "") {
+		return fmt.Errorf("启用加密信封时必须设置口令")
+	}
+	return nil
+}
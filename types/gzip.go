@@ -0,0 +1,138 @@
+// Package types 定义了 GZIP 文件头和多成员处理相关的类型。
+//
+// GzipHeader 对应 RFC 1952 中成员头的可选字段，用于写入和读取原始文件名、注释、
+// FEXTRA 额外字段、修改时间和操作系统标识。
+// 一个 GZIP 文件可以由多个成员首尾相接组成，如 cat a.gz b.gz 或日志轮转生成的文件。
+// GzipMemberMode 指定解压这类文件时如何处理各个成员。
+//
+// 主要类型：
+//   - GzipHeader: GZIP 成员头
+//   - GzipMemberMode: 多成员 GZIP 的解压方式
+//
+// 使用示例：
+//
+//	header := types.GzipHeader{Name: "data.csv", Extra: provenance, OS: types.GzipOSUnix}
+//	compressed, err := comprx.GzipBytesWithHeader(data, header)
+//
+//	opts := comprx.DefaultOptions()
+//	opts.GzipMembers = types.GzipMembersSplit
+//	err := comprx.UnpackOptions("rotated.log.gz", "out", opts)
+package types
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// GzipOS GZIP 文件头中的操作系统标识
+//
+// 零值 GzipOSUnset 表示不指定，写入时保持默认值 255(未知)；
+// 其他值由 GzipOSByte 构造，低 8 位为写入文件头的标识。
+type GzipOS uint16
+
+// gzipOSSet 标记操作系统标识已指定，用于区分未指定和标识 0(FAT)
+const gzipOSSet GzipOS = 0x100
+
+// GZIP 文件头中常用的操作系统标识
+const (
+	GzipOSUnset   GzipOS = 0                // 不指定，写入时保持默认值(未知)
+	GzipOSFAT     GzipOS = gzipOSSet | 0    // FAT 文件系统(MS-DOS、Windows)
+	GzipOSUnix    GzipOS = gzipOSSet | 3    // Unix
+	GzipOSNTFS    GzipOS = gzipOSSet | 11   // NTFS 文件系统(Windows)
+	GzipOSUnknown GzipOS = gzipOSSet | 0xff // 未知(compress/gzip 的默认值)
+)
+
+// GzipOSByte 根据文件头中的标识字节构造操作系统标识
+//
+// 参数:
+//   - b: RFC 1952 定义的操作系统标识
+//
+// 返回:
+//   - GzipOS: 已指定的操作系统标识
+func GzipOSByte(b byte) GzipOS {
+	return gzipOSSet | GzipOS(b)
+}
+
+// Byte 返回写入文件头的标识字节
+//
+// 返回:
+//   - byte: 操作系统标识字节
+//   - bool: 未指定时返回 false
+func (o GzipOS) Byte() (byte, bool) {
+	if o&gzipOSSet == 0 {
+		return 0, false
+	}
+	return byte(o), true
+}
+
+// GzipHeader GZIP 成员头
+//
+// Name 和 Comment 按 RFC 1952 以 ISO 8859-1 (Latin-1) 存储，不能包含 NUL 字符和 U+00FF 以上的字符。
+// 压缩文件时 Name、ModTime 和 OS 为零值的字段保持默认值：源文件名、源文件修改时间和未知的操作系统。
+type GzipHeader struct {
+	Name    string    // 原始文件名，为空时使用默认值(压缩文件时为源文件名，否则不记录)
+	Comment string    // 注释，为空时不记录
+	Extra   []byte    // FEXTRA 额外字段，最长 65535 字节，为 nil 时不记录
+	ModTime time.Time // 修改时间，零值时使用默认值(压缩文件时为源文件修改时间，否则为未知)
+	OS      GzipOS    // 操作系统标识，见 GzipOS* 常量，零值 GzipOSUnset 保持未知(255)
+}
+
+// Validate 验证文件头能否写入 GZIP 文件
+//
+// 返回:
+//   - error: 文件头无效时返回错误
+func (h *GzipHeader) Validate() error {
+	if h.OS > gzipOSSet|0xff || (h.OS != GzipOSUnset && h.OS&gzipOSSet == 0) {
+		return fmt.Errorf("无效的 GZIP 操作系统标识: %d，请使用 GzipOSByte 构造", h.OS)
+	}
+	if len(h.Extra) > math.MaxUint16 {
+		return fmt.Errorf("GZIP 额外字段不能超过 %d 字节: %d", math.MaxUint16, len(h.Extra))
+	}
+	if err := validateLatin1("原始文件名", h.Name); err != nil {
+		return err
+	}
+	return validateLatin1("注释", h.Comment)
+}
+
+// validateLatin1 检查字符串能否以 NUL 结尾的 Latin-1 编码存储
+func validateLatin1(field, value string) error {
+	for _, r := range value {
+		if r == 0 || r > 0xff {
+			return fmt.Errorf("GZIP %s包含无法以 Latin-1 存储的字符 %q", field, r)
+		}
+	}
+	return nil
+}
+
+// GzipMemberMode 多成员 GZIP 的解压方式
+type GzipMemberMode string
+
+const (
+	GzipMembersConcat GzipMemberMode = "concat" // 按顺序拼接所有成员写入一个文件(默认)
+	GzipMembersSplit  GzipMemberMode = "split"  // 每个成员写入单独的文件，使用成员头中的原始文件名
+)
+
+// String 返回解压方式的字符串表示
+//
+// 返回:
+//   - string: 解压方式名称，空值返回 "concat"
+func (m GzipMemberMode) String() string {
+	if m == "" {
+		return string(GzipMembersConcat)
+	}
+	return string(m)
+}
+
+// IsValid 检查解压方式是否受支持，空值表示拼接
+//
+// 返回:
+//   - bool: 受支持返回 true
+func (m GzipMemberMode) IsValid() bool {
+	switch m {
+	case "", GzipMembersConcat, GzipMembersSplit:
+		return true
+	default:
+		return false
+	}
+}
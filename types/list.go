@@ -5,12 +5,14 @@
 //
 // 主要类型：
 //   - FileInfo: 压缩包内单个文件的详细信息
+//   - EntryType: 条目类型（文件、目录、链接、设备等）
 //   - ArchiveInfo: 压缩包的整体信息和文件列表
 //
 // 主要功能：
 //   - 存储文件的基本属性（名称、大小、时间等）
 //   - 记录压缩相关信息（原始大小、压缩后大小）
-//   - 支持符号链接信息
+//   - 支持符号链接和硬链接信息
+//   - 记录属主、扩展属性、CRC32 等条目元数据
 //   - 提供压缩包统计信息
 //
 // 使用示例：
@@ -36,16 +38,80 @@ import (
 	"time"
 )
 
+// EntryType 压缩包条目类型
+type EntryType string
+
+// 支持的条目类型
+const (
+	EntryTypeFile        EntryType = "file"     // 普通文件
+	EntryTypeDir         EntryType = "dir"      // 目录
+	EntryTypeSymlink     EntryType = "symlink"  // 符号链接
+	EntryTypeHardlink    EntryType = "hardlink" // 硬链接
+	EntryTypeFifo        EntryType = "fifo"     // 命名管道
+	EntryTypeCharDevice  EntryType = "char"     // 字符设备
+	EntryTypeBlockDevice EntryType = "block"    // 块设备
+	EntryTypeSocket      EntryType = "socket"   // 套接字
+)
+
+// String 返回条目类型的字符串表示
+//
+// 返回:
+//   - string: 条目类型名称
+func (t EntryType) String() string {
+	return string(t)
+}
+
+// EntryTypeFromMode 根据文件模式推断条目类型
+//
+// 参数:
+//   - mode: 文件模式
+//
+// 返回:
+//   - EntryType: 条目类型，硬链接无法从文件模式区分，按普通文件处理
+func EntryTypeFromMode(mode os.FileMode) EntryType {
+	switch {
+	case mode.IsDir():
+		return EntryTypeDir
+	case mode&os.ModeSymlink != 0:
+		return EntryTypeSymlink
+	case mode&os.ModeNamedPipe != 0:
+		return EntryTypeFifo
+	case mode&os.ModeSocket != 0:
+		return EntryTypeSocket
+	case mode&os.ModeCharDevice != 0:
+		return EntryTypeCharDevice
+	case mode&os.ModeDevice != 0:
+		return EntryTypeBlockDevice
+	default:
+		return EntryTypeFile
+	}
+}
+
 // FileInfo 压缩包内文件信息
 type FileInfo struct {
-	Name           string      // 文件名/路径
-	Size           int64       // 原始大小
-	CompressedSize int64       // 压缩后大小
-	ModTime        time.Time   // 修改时间
-	Mode           os.FileMode // 文件权限
-	IsDir          bool        // 是否为目录
-	IsSymlink      bool        // 是否为符号链接
-	LinkTarget     string      // 符号链接目标(如果是符号链接)
+	Name           string            // 文件名/路径
+	Size           int64             // 原始大小
+	CompressedSize int64             // 压缩后大小
+	ModTime        time.Time         // 修改时间
+	Mode           os.FileMode       // 文件权限
+	IsDir          bool              // 是否为目录
+	IsSymlink      bool              // 是否为符号链接
+	LinkTarget     string            // 链接目标(符号链接或 TAR/TGZ 硬链接)
+	Encrypted      bool              // 是否已加密(仅 ZIP)
+	Comment        string            // 条目注释(仅 ZIP/GZIP)
+	EntryType      EntryType         // 条目类型
+	HardlinkTarget string            // 硬链接目标(仅 TAR/TGZ)
+	CRC32          uint32            // 原始数据的 CRC32(仅 ZIP/GZIP)
+	Method         string            // 压缩方法(store、deflate 等)
+	Uid            int               // 属主用户 ID
+	Gid            int               // 属主组 ID
+	Uname          string            // 属主用户名(仅 TAR/TGZ)
+	Gname          string            // 属主组名(仅 TAR/TGZ)
+	AccessTime     time.Time         // 访问时间(未记录时为零值)
+	ChangeTime     time.Time         // 状态变更时间(未记录时为零值)
+	Xattrs         map[string]string // 扩展属性(仅 TAR/TGZ 的 PAX 记录)
+	HeaderOffset   int64             // 条目头在压缩包中的偏移(TGZ 为解压后 TAR 流中的偏移，未知时为 -1)
+	Gzip           *GzipHeader       // GZIP 成员头(仅 GZIP)
 }
 
 // ArchiveInfo 压缩包整体信息
@@ -55,4 +121,6 @@ type ArchiveInfo struct {
 	TotalSize      int64        // 总原始大小
 	CompressedSize int64        // 总压缩大小
 	Files          []FileInfo   // 文件列表
+	Comment        string       // 压缩包注释(仅 ZIP)
+	Warnings       []string     // 列表结果可能不准确时的说明(如 GZIP 原始大小不可信)
 }
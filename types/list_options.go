@@ -0,0 +1,164 @@
+// Package types 定义了压缩包内容列表的排序和分组选项。
+//
+// ListOptions 作用于已获取的 ArchiveInfo，对文件列表排序、按目录分组并截取前 N 个条目，
+// 不重新读取压缩包。Accurate 只影响 List 和 ListSorted 读取压缩包的方式。
+//
+// 主要类型：
+//   - ListSortBy: 排序字段
+//   - ListOptions: 排序、分组和数量限制选项
+//
+// 使用示例：
+//
+//	info, _ := comprx.List("release.zip")
+//	largest := info.Apply(types.ListOptions{SortBy: types.ListSortBySize, Reverse: true, TopN: 20})
+package types
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+)
+
+// ListSortBy 内容列表的排序字段
+type ListSortBy string
+
+const (
+	ListSortByName    ListSortBy = "name"  // 按名称排序
+	ListSortBySize    ListSortBy = "size"  // 按原始大小排序
+	ListSortByModTime ListSortBy = "mtime" // 按修改时间排序
+	ListSortByRatio   ListSortBy = "ratio" // 按压缩比(压缩大小/原始大小)排序，压缩效果越差越靠后
+)
+
+// IsValid 检查排序字段是否受支持，空值表示保持压缩包中的顺序
+//
+// 返回:
+//   - bool: 受支持返回 true
+func (s ListSortBy) IsValid() bool {
+	switch s {
+	case "", ListSortByName, ListSortBySize, ListSortByModTime, ListSortByRatio:
+		return true
+	default:
+		return false
+	}
+}
+
+// ListOptions 内容列表的排序、分组和数量限制选项
+type ListOptions struct {
+	SortBy     ListSortBy // 排序字段，为空时保持压缩包中的顺序
+	Reverse    bool       // 是否倒序，分组时只影响组内顺序
+	GroupByDir bool       // 是否先按所在目录分组，组内再按 SortBy 排序
+	TopN       int        // 只保留排序后的前 N 个条目，小于等于 0 表示不限制
+	Accurate   bool       // 是否完整解压 GZIP 获取准确的原始大小，默认只读取尾部的 ISIZE
+}
+
+// Validate 验证列表选项
+//
+// 返回:
+//   - error: 选项无效时返回错误
+func (o ListOptions) Validate() error {
+	if !o.SortBy.IsValid() {
+		return fmt.Errorf("不支持的排序字段: %s", o.SortBy)
+	}
+	if o.TopN < 0 {
+		return fmt.Errorf("TopN 不能为负数: %d", o.TopN)
+	}
+	return nil
+}
+
+// Apply 按列表选项返回排序后的压缩包信息
+//
+// 原 ArchiveInfo 不会被修改。返回值的 TotalFiles 和 TotalSize 按保留的条目重新计算，
+// 与 ListLimit 的语义一致。
+//
+// 参数:
+//   - opts: 列表选项，无效的排序字段按空值处理
+//
+// 返回:
+//   - *ArchiveInfo: 排序后的压缩包信息
+func (a *ArchiveInfo) Apply(opts ListOptions) *ArchiveInfo {
+	result := *a
+	result.Files = make([]FileInfo, len(a.Files))
+	copy(result.Files, a.Files)
+
+	// 未指定排序字段时倒序即反转压缩包中的顺序
+	if opts.SortBy == "" && opts.Reverse {
+		for i, j := 0, len(result.Files)-1; i < j; i, j = i+1, j-1 {
+			result.Files[i], result.Files[j] = result.Files[j], result.Files[i]
+		}
+	}
+
+	less := fileLess(opts.SortBy)
+	sort.SliceStable(result.Files, func(i, j int) bool {
+		fi, fj := result.Files[i], result.Files[j]
+		if opts.GroupByDir {
+			if di, dj := fileDir(fi.Name), fileDir(fj.Name); di != dj {
+				return di < dj
+			}
+		}
+		if opts.Reverse {
+			fi, fj = fj, fi
+		}
+		return less(fi, fj)
+	})
+
+	if opts.TopN > 0 && opts.TopN < len(result.Files) {
+		result.Files = result.Files[:opts.TopN]
+	}
+
+	result.TotalFiles = len(result.Files)
+	result.TotalSize = 0
+	for _, file := range result.Files {
+		result.TotalSize += file.Size
+	}
+	return &result
+}
+
+// fileLess 返回排序字段对应的比较函数，相同时按名称排序保证结果稳定
+func fileLess(sortBy ListSortBy) func(a, b FileInfo) bool {
+	switch sortBy {
+	case ListSortByName:
+		return func(a, b FileInfo) bool { return a.Name < b.Name }
+	case ListSortBySize:
+		return func(a, b FileInfo) bool {
+			if a.Size != b.Size {
+				return a.Size < b.Size
+			}
+			return a.Name < b.Name
+		}
+	case ListSortByModTime:
+		return func(a, b FileInfo) bool {
+			if !a.ModTime.Equal(b.ModTime) {
+				return a.ModTime.Before(b.ModTime)
+			}
+			return a.Name < b.Name
+		}
+	case ListSortByRatio:
+		return func(a, b FileInfo) bool {
+			if ra, rb := a.Ratio(), b.Ratio(); ra != rb {
+				return ra < rb
+			}
+			return a.Name < b.Name
+		}
+	default:
+		// 未指定排序字段时保持原顺序
+		return func(a, b FileInfo) bool { return false }
+	}
+}
+
+// Ratio 返回条目的压缩比
+//
+// 返回:
+//   - float64: 压缩大小与原始大小之比，原始大小为 0 时返回 0
+func (f FileInfo) Ratio() float64 {
+	if f.Size <= 0 {
+		return 0
+	}
+	return float64(f.CompressedSize) / float64(f.Size)
+}
+
+// fileDir 返回条目所在的目录，目录条目返回其父目录
+func fileDir(name string) string {
+	name = strings.TrimSuffix(strings.ReplaceAll(name, "\\", "/"), "/")
+	return path.Dir(name)
+}
@@ -0,0 +1,45 @@
+// Package types 定义了压缩包内容列表输出格式相关的类型。
+//
+// ListFormat 指定 WriteListing 输出压缩包内容列表时使用的格式，
+// 结构化格式便于接入审计和比对工具。
+//
+// 主要类型：
+//   - ListFormat: 内容列表输出格式
+//
+// 使用示例：
+//
+//	info, _ := comprx.List("app.tar.gz")
+//	err := comprx.WriteListing(os.Stdout, info, types.ListFormatJSON)
+package types
+
+// ListFormat 内容列表输出格式
+type ListFormat string
+
+const (
+	ListFormatJSON   ListFormat = "json"   // 包含汇总信息和条目列表的 JSON 文档
+	ListFormatNDJSON ListFormat = "ndjson" // 每行一个条目的 JSON
+	ListFormatCSV    ListFormat = "csv"    // 带表头的 CSV
+	ListFormatTree   ListFormat = "tree"   // 类似 tree 命令的层级视图，目录显示总大小
+	ListFormatTarTV  ListFormat = "tartv"  // 与 tar -tv 相同的格式
+)
+
+// String 返回输出格式的字符串表示
+//
+// 返回:
+//   - string: 格式名称
+func (f ListFormat) String() string {
+	return string(f)
+}
+
+// IsValid 检查输出格式是否受支持
+//
+// 返回:
+//   - bool: 受支持返回 true
+func (f ListFormat) IsValid() bool {
+	switch f {
+	case ListFormatJSON, ListFormatNDJSON, ListFormatCSV, ListFormatTree, ListFormatTarTV:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,32 @@
+// Package types 定义了多源打包使用的源映射类型。
+//
+// 该文件提供了 Source 结构体，用于描述将磁盘上或 fs.FS 中的文件或目录
+// 映射到压缩包内任意位置的规则，支持将多个源合并打包到同一个压缩包中。
+//
+// 主要类型：
+//   - Source: 打包源映射
+//
+// 使用示例：
+//
+//	sources := []types.Source{
+//	    {Path: "bin", ArchivePath: "app-1.2/bin"},
+//	    {Path: "docs", ArchivePath: "app-1.2/docs"},
+//	    {Path: "build/VERSION", ArchivePath: "app-1.2/VERSION"},
+//	    {FS: assets, Path: "static", ArchivePath: "app-1.2/static"},
+//	}
+package types
+
+import "io/fs"
+
+// Source 打包源映射
+//
+// 字段说明:
+//   - Path: 源文件或目录路径；FS 不为 nil 时为 FS 内的路径（使用正斜杠，"." 表示 FS 根目录）
+//   - ArchivePath: 在压缩包中的路径，为空时使用源路径的基本名称；
+//     对目录源设置为 "." 时，目录内容直接放在压缩包根目录下
+//   - FS: 源文件系统（如 embed.FS、fstest.MapFS），为 nil 时从磁盘读取
+type Source struct {
+	Path        string // 源文件或目录路径
+	ArchivePath string // 压缩包内的目标路径
+	FS          fs.FS  // 源文件系统，为 nil 时使用磁盘文件系统
+}
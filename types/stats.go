@@ -0,0 +1,204 @@
+// Package types 定义了压缩包内容统计的数据结构。
+//
+// ArchiveStats 基于 ArchiveInfo 汇总压缩包内容，用于分析压缩包体积的构成，
+// 例如排查发布包为何突然变大。
+//
+// 主要类型：
+//   - ArchiveStats: 压缩包内容统计
+//   - ExtensionStats: 按扩展名汇总的数量和字节数
+//   - DirectoryStats: 按目录汇总的大小和压缩比
+//   - DuplicateGroup: 内容可能重复的文件组
+//
+// 使用示例：
+//
+//	stats, _ := comprx.Stats("release.zip")
+//	for _, ext := range stats.ByExtension {
+//	    fmt.Printf("%s: %d 个文件, %d 字节\n", ext.Extension, ext.Files, ext.Size)
+//	}
+package types
+
+import (
+	"path"
+	"sort"
+	"strings"
+)
+
+// DefaultStatsTopN 统计中最大文件和最深路径默认保留的数量
+const DefaultStatsTopN = 10
+
+// ArchiveStats 压缩包内容统计
+type ArchiveStats struct {
+	Type           CompressType     // 压缩包类型
+	TotalFiles     int              // 文件数(不含目录)
+	TotalDirs      int              // 目录条目数
+	TotalSize      int64            // 文件总原始大小
+	CompressedSize int64            // 压缩包大小
+	ByExtension    []ExtensionStats // 按扩展名汇总，按原始大小从大到小排序
+	LargestFiles   []FileInfo       // 最大的 N 个文件，按原始大小从大到小排序
+	DeepestPaths   []FileInfo       // 层级最深的 N 个条目，按层级从深到浅排序
+	Duplicates     []DuplicateGroup // 内容可能重复的文件组，按可节省的字节数从大到小排序
+	Directories    []DirectoryStats // 按文件所在目录汇总，按原始大小从大到小排序
+}
+
+// ExtensionStats 按扩展名汇总的统计信息
+type ExtensionStats struct {
+	Extension      string // 小写扩展名(含 "."), 没有扩展名时为空
+	Files          int    // 文件数
+	Size           int64  // 原始大小
+	CompressedSize int64  // 压缩后大小
+}
+
+// DirectoryStats 按目录汇总的统计信息
+//
+// 只统计直接位于该目录下的文件，不包含子目录。
+type DirectoryStats struct {
+	Dir            string  // 目录路径，根目录为 "."
+	Files          int     // 文件数
+	Size           int64   // 原始大小
+	CompressedSize int64   // 压缩后大小
+	Ratio          float64 // 压缩比(压缩大小/原始大小)，原始大小为 0 时为 0
+}
+
+// DuplicateGroup 内容可能重复的文件组
+//
+// 大小和 CRC32 都相同的文件视为重复候选。TAR/TGZ 不记录 CRC32，只按大小分组，
+// 结果需要进一步比对内容确认。
+type DuplicateGroup struct {
+	Size  int64    // 单个文件的原始大小
+	CRC32 uint32   // CRC32，格式不记录时为 0
+	Files []string // 文件名列表
+}
+
+// Wasted 返回删除重复文件后可节省的字节数
+//
+// 返回:
+//   - int64: 除保留的一份外其余文件的总大小
+func (g DuplicateGroup) Wasted() int64 {
+	return g.Size * int64(len(g.Files)-1)
+}
+
+// Stats 计算压缩包内容统计
+//
+// 参数:
+//   - topN: 最大文件和最深路径保留的数量，小于等于 0 时使用 DefaultStatsTopN
+//
+// 返回:
+//   - *ArchiveStats: 压缩包内容统计
+func (a *ArchiveInfo) Stats(topN int) *ArchiveStats {
+	if topN <= 0 {
+		topN = DefaultStatsTopN
+	}
+
+	stats := &ArchiveStats{
+		Type:           a.Type,
+		CompressedSize: a.CompressedSize,
+	}
+
+	byExt := make(map[string]*ExtensionStats)
+	byDir := make(map[string]*DirectoryStats)
+	type dupKey struct {
+		size int64
+		crc  uint32
+	}
+	dups := make(map[dupKey][]string)
+	var files []FileInfo
+
+	for _, file := range a.Files {
+		if file.IsDir || file.EntryType == EntryTypeDir {
+			stats.TotalDirs++
+			continue
+		}
+		stats.TotalFiles++
+		stats.TotalSize += file.Size
+		files = append(files, file)
+
+		ext := strings.ToLower(path.Ext(file.Name))
+		e, ok := byExt[ext]
+		if !ok {
+			e = &ExtensionStats{Extension: ext}
+			byExt[ext] = e
+		}
+		e.Files++
+		e.Size += file.Size
+		e.CompressedSize += file.CompressedSize
+
+		dir := fileDir(file.Name)
+		d, ok := byDir[dir]
+		if !ok {
+			d = &DirectoryStats{Dir: dir}
+			byDir[dir] = d
+		}
+		d.Files++
+		d.Size += file.Size
+		d.CompressedSize += file.CompressedSize
+
+		// 只有普通文件参与重复检测，空文件没有意义
+		if file.Size > 0 && (file.EntryType == "" || file.EntryType == EntryTypeFile) {
+			key := dupKey{size: file.Size, crc: file.CRC32}
+			dups[key] = append(dups[key], file.Name)
+		}
+	}
+
+	for _, e := range byExt {
+		stats.ByExtension = append(stats.ByExtension, *e)
+	}
+	sort.Slice(stats.ByExtension, func(i, j int) bool {
+		if stats.ByExtension[i].Size != stats.ByExtension[j].Size {
+			return stats.ByExtension[i].Size > stats.ByExtension[j].Size
+		}
+		return stats.ByExtension[i].Extension < stats.ByExtension[j].Extension
+	})
+
+	for _, d := range byDir {
+		if d.Size > 0 {
+			d.Ratio = float64(d.CompressedSize) / float64(d.Size)
+		}
+		stats.Directories = append(stats.Directories, *d)
+	}
+	sort.Slice(stats.Directories, func(i, j int) bool {
+		if stats.Directories[i].Size != stats.Directories[j].Size {
+			return stats.Directories[i].Size > stats.Directories[j].Size
+		}
+		return stats.Directories[i].Dir < stats.Directories[j].Dir
+	})
+
+	for key, names := range dups {
+		if len(names) > 1 {
+			sort.Strings(names)
+			stats.Duplicates = append(stats.Duplicates, DuplicateGroup{Size: key.size, CRC32: key.crc, Files: names})
+		}
+	}
+	sort.Slice(stats.Duplicates, func(i, j int) bool {
+		if wi, wj := stats.Duplicates[i].Wasted(), stats.Duplicates[j].Wasted(); wi != wj {
+			return wi > wj
+		}
+		return stats.Duplicates[i].Files[0] < stats.Duplicates[j].Files[0]
+	})
+
+	largest := (&ArchiveInfo{Files: files}).Apply(ListOptions{SortBy: ListSortBySize, Reverse: true, TopN: topN})
+	stats.LargestFiles = largest.Files
+
+	deepest := make([]FileInfo, len(a.Files))
+	copy(deepest, a.Files)
+	sort.SliceStable(deepest, func(i, j int) bool {
+		if di, dj := pathDepth(deepest[i].Name), pathDepth(deepest[j].Name); di != dj {
+			return di > dj
+		}
+		return deepest[i].Name < deepest[j].Name
+	})
+	if len(deepest) > topN {
+		deepest = deepest[:topN]
+	}
+	stats.DeepestPaths = deepest
+
+	return stats
+}
+
+// pathDepth 返回条目路径的层级数，如 "a/b/c.txt" 为 3
+func pathDepth(name string) int {
+	name = strings.Trim(strings.ReplaceAll(name, "\\", "/"), "/")
+	if name == "" {
+		return 0
+	}
+	return strings.Count(name, "/") + 1
+}
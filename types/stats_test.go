@@ -0,0 +1,118 @@
+package types
+
+import (
+	"testing"
+	"time"
+)
+
+// statsFixture 返回用于排序和统计测试的压缩包信息
+func statsFixture() *ArchiveInfo {
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	return &ArchiveInfo{
+		Type:           CompressTypeZip,
+		CompressedSize: 5000,
+		Files: []FileInfo{
+			{Name: "app/", IsDir: true, EntryType: EntryTypeDir},
+			{Name: "app/main.go", Size: 1000, CompressedSize: 300, ModTime: base.Add(3 * time.Hour), CRC32: 1},
+			{Name: "app/assets/logo.PNG", Size: 4000, CompressedSize: 3900, ModTime: base.Add(1 * time.Hour), CRC32: 2},
+			{Name: "app/assets/copy/logo.png", Size: 4000, CompressedSize: 3900, ModTime: base.Add(2 * time.Hour), CRC32: 2},
+			{Name: "app/util.go", Size: 500, CompressedSize: 100, ModTime: base, CRC32: 3},
+			{Name: "README", Size: 200, CompressedSize: 150, ModTime: base.Add(4 * time.Hour), CRC32: 4},
+		},
+	}
+}
+
+// fileNames 返回文件列表中的名称
+func fileNames(files []FileInfo) []string {
+	names := make([]string, 0, len(files))
+	for _, file := range files {
+		names = append(names, file.Name)
+	}
+	return names
+}
+
+func TestArchiveInfo_Apply(t *testing.T) {
+	info := statsFixture()
+
+	tests := []struct {
+		name string
+		opts ListOptions
+		want []string
+	}{
+		{"按大小倒序取前2", ListOptions{SortBy: ListSortBySize, Reverse: true, TopN: 2},
+			[]string{"app/assets/logo.PNG", "app/assets/copy/logo.png"}},
+		{"按修改时间", ListOptions{SortBy: ListSortByModTime, TopN: 3},
+			[]string{"app/", "app/util.go", "app/assets/logo.PNG"}},
+		{"按压缩比", ListOptions{SortBy: ListSortByRatio, TopN: 3},
+			[]string{"app/", "app/util.go", "app/main.go"}},
+		{"保持原顺序倒序", ListOptions{Reverse: true, TopN: 2},
+			[]string{"README", "app/util.go"}},
+		{"按目录分组再按名称", ListOptions{SortBy: ListSortByName, GroupByDir: true},
+			[]string{"README", "app/", "app/main.go", "app/util.go", "app/assets/logo.PNG", "app/assets/copy/logo.png"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := fileNames(info.Apply(tt.opts).Files)
+			if len(got) != len(tt.want) {
+				t.Fatalf("Apply() = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Fatalf("Apply() = %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+
+	// 原列表不受影响，结果的汇总按保留的条目重新计算
+	result := info.Apply(ListOptions{SortBy: ListSortBySize, Reverse: true, TopN: 1})
+	if info.Files[1].Name != "app/main.go" || result.TotalFiles != 1 || result.TotalSize != 4000 {
+		t.Errorf("Apply() 汇总不正确或修改了原列表: %+v", result)
+	}
+
+	if err := (ListOptions{SortBy: "owner"}).Validate(); err == nil {
+		t.Error("不支持的排序字段应返回错误")
+	}
+	if err := (ListOptions{TopN: -1}).Validate(); err == nil {
+		t.Error("TopN 为负数应返回错误")
+	}
+}
+
+func TestArchiveInfo_Stats(t *testing.T) {
+	stats := statsFixture().Stats(2)
+
+	if stats.TotalFiles != 5 || stats.TotalDirs != 1 || stats.TotalSize != 9700 {
+		t.Errorf("汇总不正确: files=%d dirs=%d size=%d", stats.TotalFiles, stats.TotalDirs, stats.TotalSize)
+	}
+
+	// 扩展名统一为小写并按大小排序
+	if ext := stats.ByExtension[0]; ext.Extension != ".png" || ext.Files != 2 || ext.Size != 8000 {
+		t.Errorf("扩展名统计不正确: %+v", stats.ByExtension)
+	}
+	if ext := stats.ByExtension[len(stats.ByExtension)-1]; ext.Extension != "" || ext.Files != 1 {
+		t.Errorf("无扩展名的文件应单独统计: %+v", stats.ByExtension)
+	}
+
+	if len(stats.LargestFiles) != 2 || stats.LargestFiles[0].Size != 4000 {
+		t.Errorf("最大文件不正确: %v", fileNames(stats.LargestFiles))
+	}
+	if len(stats.DeepestPaths) != 2 || stats.DeepestPaths[0].Name != "app/assets/copy/logo.png" {
+		t.Errorf("最深路径不正确: %v", fileNames(stats.DeepestPaths))
+	}
+
+	if len(stats.Duplicates) != 1 || stats.Duplicates[0].Wasted() != 4000 ||
+		stats.Duplicates[0].Files[0] != "app/assets/copy/logo.png" {
+		t.Errorf("重复文件不正确: %+v", stats.Duplicates)
+	}
+
+	dirs := map[string]DirectoryStats{}
+	for _, d := range stats.Directories {
+		dirs[d.Dir] = d
+	}
+	if d := dirs["app"]; d.Files != 2 || d.Size != 1500 || d.Ratio != 400.0/1500.0 {
+		t.Errorf("目录统计不正确: %+v", d)
+	}
+	if d := dirs["."]; d.Files != 1 || d.Size != 200 {
+		t.Errorf("根目录统计不正确: %+v", d)
+	}
+}
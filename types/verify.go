@@ -0,0 +1,83 @@
+// Package types 定义了压缩包完整性校验的结果类型。
+//
+// 该文件提供了 VerifyReport 和 EntryError 结构体，用于汇总完整性校验中
+// 检查过的条目数量、解码的数据量以及损坏或截断条目的名称和原因。
+//
+// 主要类型：
+//   - VerifyReport: 完整性校验报告
+//   - EntryError: 单个条目的校验错误
+//
+// 使用示例：
+//
+//	report, err := comprx.Test("backup.zip", comprx.DefaultOptions())
+//	for _, failure := range report.Failures {
+//	    fmt.Printf("%s: %v\n", failure.Name, failure.Err)
+//	}
+package types
+
+import "fmt"
+
+// EntryError 单个条目的校验错误
+//
+// 字段说明:
+//   - Name: 条目名称，为空表示压缩包整体结构错误（无法定位到具体条目）
+//   - Err: 错误原因
+type EntryError struct {
+	Name string // 条目名称
+	Err  error  // 错误原因
+}
+
+// Error 实现 error 接口
+//
+// 返回:
+//   - string: 错误描述
+func (e EntryError) Error() string {
+	if e.Name == "" {
+		return e.Err.Error()
+	}
+	return fmt.Sprintf("%s: %v", e.Name, e.Err)
+}
+
+// Unwrap 返回错误原因
+//
+// 返回:
+//   - error: 错误原因
+func (e EntryError) Unwrap() error {
+	return e.Err
+}
+
+// VerifyReport 完整性校验报告
+type VerifyReport struct {
+	Type         CompressType // 压缩包类型
+	TotalEntries int          // 检查的条目数（含目录和链接）
+	TotalSize    int64        // 解码的原始数据总大小
+	Failures     []EntryError // 损坏或截断的条目
+}
+
+// OK 检查压缩包是否通过校验
+//
+// 返回:
+//   - bool: 没有任何损坏条目时返回 true
+func (r *VerifyReport) OK() bool {
+	return len(r.Failures) == 0
+}
+
+// AddFailure 记录条目校验错误
+//
+// 参数:
+//   - name: 条目名称，为空表示压缩包整体结构错误
+//   - err: 错误原因
+func (r *VerifyReport) AddFailure(name string, err error) {
+	r.Failures = append(r.Failures, EntryError{Name: name, Err: err})
+}
+
+// Err 汇总校验结果为错误
+//
+// 返回:
+//   - error: 通过校验返回 nil，否则返回包含首个损坏条目的错误
+func (r *VerifyReport) Err() error {
+	if r.OK() {
+		return nil
+	}
+	return fmt.Errorf("压缩包校验失败，共 %d 处错误，首个错误: %w", len(r.Failures), r.Failures[0])
+}
@@ -0,0 +1,46 @@
+// Package comprx 提供压缩包完整性校验功能。
+//
+// 该文件提供了类似 unzip -t 和 gzip -t 的完整性校验方法：
+// 完整解码每个条目但不写入任何文件，报告损坏或截断的条目名称。
+//
+// 主要功能：
+//   - 校验 ZIP 条目的 CRC32
+//   - 校验 GZIP/ZLIB 尾部和 BZIP2 数据块 CRC
+//   - 校验 TAR 文件头校验和并发现截断
+//
+// 使用示例：
+//
+//	report, err := comprx.Test("backup.tgz", comprx.DefaultOptions())
+//	if err != nil {
+//	    for _, failure := range report.Failures {
+//	        fmt.Println(failure.Error())
+//	    }
+//	}
+package comprx
+
+import "gitee.com/MM-Q/comprx/types"
+
+// Test 校验压缩包的完整性 - 线程安全
+//
+// 参数:
+//   - archivePath: 压缩包路径（支持 .zip、.tar、.tgz、.tar.gz、.gz、.bz2、.bzip2、.zlib）
+//   - opts: 配置选项，过滤器用于只校验部分条目，加密信封使用 EncryptWith 或 Password 中的口令解密
+//
+// 返回:
+//   - *types.VerifyReport: 校验报告，能够读取压缩包时总是返回（即使存在损坏条目）
+//   - error: 无法读取压缩包、加密信封口令缺失或错误、存在损坏条目时返回错误
+//
+// 使用示例:
+//
+//	report, err := Test("backup.zip", DefaultOptions())
+//	if report != nil && !report.OK() {
+//	    // report.Failures 中包含损坏条目的名称和原因
+//	}
+func Test(archivePath string, opts Options) (*types.VerifyReport, error) {
+	comprx, err := newUnpackComprx(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return comprx.Test(archivePath)
+}